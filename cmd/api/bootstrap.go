@@ -2,25 +2,42 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
 	"gostartkit/internal/application/usecase/userusecase"
 	"gostartkit/internal/config"
+	"gostartkit/internal/domain/user"
 	authinfra "gostartkit/internal/infras/auth"
 	infdb "gostartkit/internal/infras/db"
+	emailinfra "gostartkit/internal/infras/notify/email"
+	smsinfra "gostartkit/internal/infras/notify/sms"
+	oidcinfra "gostartkit/internal/infras/oidc"
 	"gostartkit/internal/infras/ratelimit"
 	"gostartkit/internal/infras/security"
+	localstore "gostartkit/internal/infras/storage/local"
 	pgstore "gostartkit/internal/infras/storage/postgres"
+	s3store "gostartkit/internal/infras/storage/s3"
 	httpiface "gostartkit/internal/interfaces/http"
 	"gostartkit/internal/interfaces/http/apidocs"
 	"gostartkit/internal/interfaces/http/handler"
 	"gostartkit/internal/interfaces/http/middleware"
+	httprouter "gostartkit/internal/interfaces/http/router"
+	"gostartkit/internal/interfaces/http/validation"
 	"gostartkit/pkg/i18n"
 	"gostartkit/pkg/logger"
 	"gostartkit/pkg/rbac"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -46,19 +63,68 @@ func initPostgresAndMigrate(cfg *config.Config) (*pgxpool.Pool, error) {
 }
 
 // initJWTService constructs the JWT service and applies optional hardening metadata.
-func initJWTService(cfg *config.Config) security.JWTService {
+// A key configuration error is returned so startup fails instead of signing with missing keys.
+func initJWTService(cfg *config.Config) (security.JWTService, error) {
 	jwtSvc := security.NewJWTService(cfg.JWT.Secret, cfg.JWT.ExpireSec)
 	if js, ok := jwtSvc.(interface {
 		SetMeta(iss, aud string, leewaySec int)
 	}); ok {
 		js.SetMeta(cfg.JWT.Issuer, cfg.JWT.Audience, cfg.JWT.LeewaySec)
 	}
+	if km, ok := jwtSvc.(interface{ SetKeyManifest(path string) }); ok && cfg.JWT.KeysManifestPath != "" {
+		km.SetKeyManifest(cfg.JWT.KeysManifestPath)
+	}
 	if ac, ok := jwtSvc.(interface {
 		ConfigureAlgorithm(alg, kid, privateKeyPath, privateKeyPEM, publicKeysDir string) error
 	}); ok {
-		_ = ac.ConfigureAlgorithm(cfg.JWT.Alg, cfg.JWT.KID, cfg.JWT.PrivateKeyPath, cfg.JWT.PrivateKeyPEM, cfg.JWT.PublicKeysDir)
+		if err := ac.ConfigureAlgorithm(cfg.JWT.Alg, cfg.JWT.KID, cfg.JWT.PrivateKeyPath, cfg.JWT.PrivateKeyPEM, cfg.JWT.PublicKeysDir); err != nil {
+			return nil, err
+		}
+	}
+	return jwtSvc, nil
+}
+
+// startJWTKeyReloader reloads JWT signing/verification keys on SIGHUP and, if configured, on an interval.
+// A failed reload is logged and the previously loaded keys stay in use.
+func startJWTKeyReloader(ctx context.Context, cfg *config.Config, jwtSvc security.JWTService) {
+	r, ok := jwtSvc.(interface {
+		Reload() error
+		KeyStates() map[string]security.KeyState
+	})
+	if !ok || strings.EqualFold(cfg.JWT.Alg, "HS256") {
+		return
 	}
-	return jwtSvc
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	var tick <-chan time.Time
+	var ticker *time.Ticker
+	if cfg.JWT.KeysReloadIntervalSec > 0 {
+		ticker = time.NewTicker(time.Duration(cfg.JWT.KeysReloadIntervalSec) * time.Second)
+		tick = ticker.C
+	}
+	reload := func(trigger string) {
+		if err := r.Reload(); err != nil {
+			logger.L().Error("jwt_keys_reload_failed", "trigger", trigger, "error", err)
+			return
+		}
+		logger.L().Info("jwt_keys_reloaded", "trigger", trigger, "keys", r.KeyStates())
+	}
+	go func() {
+		defer signal.Stop(hup)
+		if ticker != nil {
+			defer ticker.Stop()
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				reload("sighup")
+			case <-tick:
+				reload("interval")
+			}
+		}
+	}()
 }
 
 // initI18n loads locale catalogs from disk and sets default locale.
@@ -70,21 +136,432 @@ func initI18n(cfg *config.Config) {
 	_ = i18n.Init(cfg.I18nLocalesDir, cfg.I18nDefaultLocale)
 }
 
+// initBreachedPasswords plugs the BREACHED_PASSWORDS_FILE checker into the strong_password validator.
+// A missing or malformed file fails startup; lookup errors at request time are logged and let the password through.
+func initBreachedPasswords(cfg *config.Config) error {
+	if cfg.Security.BreachedPasswordsFile == "" {
+		return nil
+	}
+	checker, err := security.OpenBreachedPasswords(cfg.Security.BreachedPasswordsFile, cfg.Security.BreachedPasswordMinCount)
+	if err != nil {
+		return err
+	}
+	if bloom, ok := checker.(*security.BreachBloom); ok && bloom.MinCount() != max(cfg.Security.BreachedPasswordMinCount, 1) {
+		logger.L().Warn("breached_passwords_min_count_ignored", "configured", cfg.Security.BreachedPasswordMinCount, "bloom_min_count", bloom.MinCount())
+	}
+	validation.SetBreachedPasswordChecker(ports.BreachedPasswordCheckFunc(func(password string) (bool, error) {
+		breached, err := checker.IsBreached(password)
+		if err != nil {
+			logger.L().Error("breached_password_check_failed", "error", err)
+		}
+		return breached, err
+	}))
+	return nil
+}
+
+// buildPasswordHasher hashes new passwords with PASSWORD_HASH_ALGO and still verifies hashes of the
+// other algorithm, so existing users are upgraded on their next login instead of being locked out.
+func buildPasswordHasher(cfg *config.Config) userusecase.PasswordHasher {
+	bcryptHasher := security.NewBcryptHasher(cfg.Security.BcryptCost)
+	argon := security.NewArgon2idHasher(security.Argon2Params{
+		MemoryKiB:   uint32(max(cfg.Security.Argon2MemoryKiB, 0)),
+		Iterations:  uint32(max(cfg.Security.Argon2Iterations, 0)),
+		Parallelism: uint8(min(max(cfg.Security.Argon2Parallelism, 0), 255)),
+	})
+	switch algo := strings.ToLower(strings.TrimSpace(cfg.Security.PasswordHashAlgo)); algo {
+	case "bcrypt":
+		return security.NewMultiHasher(bcryptHasher, argon)
+	case "", "argon2id":
+		return security.NewMultiHasher(argon, bcryptHasher)
+	default:
+		logger.L().Error("password_hash_algo_unknown", "algo", algo, "note", "expected argon2id or bcrypt; using argon2id")
+		return security.NewMultiHasher(argon, bcryptHasher)
+	}
+}
+
 // buildUserComponents constructs repository, hasher, aggregated usecases and returns the HTTP handler.
-func buildUserComponents(pool *pgxpool.Pool, jwtSvc security.JWTService, cfg *config.Config) (*handler.UserHandler, *pgstore.UserRepository, userusecase.PasswordHasher) {
+// opts carries the optional account features (MFA, email verification); nil members are disabled.
+func buildUserComponents(pool *pgxpool.Pool, jwtSvc security.JWTService, refreshStore ports.RefreshTokenStore, cfg *config.Config, opts userusecase.UserUsecasesOptions) (*handler.UserHandler, *pgstore.UserRepository, userusecase.PasswordHasher) {
 	userRepo := pgstore.NewUserRepository(pool)
-	hasher := security.NewBcryptHasher(cfg.Security.BcryptCost)
-	var uc userusecase.UserUsecases
-	if cfg.RedisAddr != "" && cfg.Security.RefreshEnabled {
-		store := authinfra.NewRedisRefreshStore(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
-		uc = userusecase.NewUserUsecasesWithStore(userRepo, hasher, jwtSvc, store, cfg.Security.RefreshTTLSeconds)
-	} else {
-		uc = userusecase.NewUserUsecases(userRepo, hasher, jwtSvc)
-	}
+	hasher := buildPasswordHasher(cfg)
+	uc := userusecase.NewUserUsecasesWithOptions(userRepo, hasher, jwtSvc, refreshStore, cfg.Security.RefreshTTLSeconds, opts)
 	userHandler := handler.NewUserHandler(uc)
 	return userHandler, userRepo, hasher
 }
 
+// refreshStoreBackend resolves AUTH_REFRESH_STORE: explicit value, else redis when REDIS_ADDR is set, else postgres.
+func refreshStoreBackend(cfg *config.Config) string {
+	if b := strings.ToLower(strings.TrimSpace(cfg.Security.RefreshStore)); b != "" {
+		return b
+	}
+	if cfg.RedisAddr != "" {
+		return "redis"
+	}
+	return "postgres"
+}
+
+// buildRefreshStore returns the refresh-token store, or nil when refresh tokens are disabled. A backend that
+// is unknown or lacks its settings is an error rather than a silently disabled refresh.
+// main calls it once and hands the store to every use case, so they share one Redis client or, for the
+// memory backend, the same sessions.
+func buildRefreshStore(cfg *config.Config, pool *pgxpool.Pool) (ports.RefreshTokenStore, error) {
+	if !cfg.Security.RefreshEnabled {
+		return nil, nil
+	}
+	switch backend := refreshStoreBackend(cfg); backend {
+	case "redis":
+		if cfg.RedisAddr == "" {
+			return nil, errors.New("AUTH_REFRESH_STORE=redis needs REDIS_ADDR")
+		}
+		return authinfra.NewRedisRefreshStore(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB), nil
+	case "postgres":
+		return pgstore.NewRefreshTokenStore(pool), nil
+	case "memory":
+		if cfg.Env == "prod" {
+			logger.L().Warn("refresh_store_in_memory", "note", "in-memory refresh tokens are per-instance and lost on restart")
+		}
+		return authinfra.NewMemoryRefreshStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown AUTH_REFRESH_STORE %q (expected redis, postgres or memory)", backend)
+	}
+}
+
+// startRefreshTokenCleanup periodically purges expired refresh tokens when they live in Postgres.
+func startRefreshTokenCleanup(ctx context.Context, cfg *config.Config, pool *pgxpool.Pool) {
+	if !cfg.Security.RefreshEnabled || refreshStoreBackend(cfg) != "postgres" {
+		return
+	}
+	interval := time.Duration(cfg.Security.RefreshCleanupIntervalSec) * time.Second
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	store := pgstore.NewRefreshTokenStore(pool)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				n, err := store.PurgeExpired(ctx)
+				if err != nil {
+					logger.L().Warn("refresh_tokens_cleanup_failed", "error", err)
+					continue
+				}
+				if n > 0 {
+					logger.L().Info("refresh_tokens_cleaned", "rows", n)
+				}
+			}
+		}
+	}()
+}
+
+// initMFA builds the TOTP use case shared by login, OIDC and /v1/auth/mfa. It returns nil when MFA_ENCRYPTION_KEY
+// is unset, and an error when the key is invalid or MFA_REQUIRED_ROLES is set without a key.
+func initMFA(cfg *config.Config, pool *pgxpool.Pool, jwtSvc security.JWTService, refreshStore ports.RefreshTokenStore) (*userusecase.MFAUseCase, error) {
+	var roles []user.Role
+	for _, r := range cfg.MFA.RequiredRoles {
+		if r = strings.TrimSpace(r); r != "" {
+			roles = append(roles, user.Role(r))
+		}
+	}
+	if cfg.MFA.EncryptionKey == "" {
+		if len(roles) > 0 {
+			return nil, errors.New("MFA_REQUIRED_ROLES is set but MFA_ENCRYPTION_KEY is empty")
+		}
+		return nil, nil
+	}
+	box, err := security.NewAESGCMBox(cfg.MFA.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("MFA_ENCRYPTION_KEY: %w", err)
+	}
+	var (
+		challenges ports.MFAChallengeStore
+		failures   ports.FailureCounter
+	)
+	if cfg.RedisAddr != "" {
+		challenges = authinfra.NewRedisMFAChallengeStore(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
+		failures = authinfra.NewRedisFailureCounter(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
+	} else {
+		if cfg.Env == "prod" {
+			logger.L().Warn("mfa_challenges_in_memory", "note", "in-memory MFA challenges are per-instance; configure REDIS_ADDR for multi-instance")
+		}
+		challenges = authinfra.NewMemoryMFAChallengeStore()
+		failures = authinfra.NewMemoryFailureCounter()
+	}
+	issuer := cfg.MFA.Issuer
+	if issuer == "" {
+		issuer = cfg.JWT.Issuer
+	}
+	return userusecase.NewMFAUseCase(
+		pgstore.NewUserRepository(pool),
+		jwtSvc,
+		refreshStore,
+		cfg.Security.RefreshTTLSeconds,
+		pgstore.NewMFAStore(pool),
+		challenges,
+		failures,
+		box,
+		security.NewTOTP(),
+		userusecase.MFAOptions{
+			Issuer:        issuer,
+			RequiredRoles: roles,
+			ChallengeTTL:  time.Duration(cfg.MFA.ChallengeTTLSec) * time.Second,
+			MaxAttempts:   cfg.MFA.MaxAttempts,
+		},
+	), nil
+}
+
+// initEmailSender returns an SMTP sender when SMTP_HOST is set, a log-only sender outside prod, and nil otherwise.
+// Send failures are logged here because callers such as registration do not fail on them.
+func initEmailSender(cfg *config.Config) ports.EmailSender {
+	if cfg.Email.SMTPHost == "" {
+		if cfg.Env == "prod" {
+			return nil
+		}
+		return emailinfra.NewLogSender()
+	}
+	port := cfg.Email.SMTPPort
+	if port <= 0 {
+		port = 587
+	}
+	from := cfg.Email.SMTPFrom
+	if from == "" {
+		from = cfg.Email.SMTPUsername
+	}
+	smtp := emailinfra.NewSMTPSender(cfg.Email.SMTPHost, port, cfg.Email.SMTPUsername, cfg.Email.SMTPPassword, from, cfg.Email.SMTPTLS)
+	return ports.SendEmailFunc(func(ctx context.Context, to, subject, body string) error {
+		err := smtp.Send(ctx, to, subject, body)
+		if err != nil {
+			logger.L().Error("email_send_failed", "subject", subject, "error", err)
+		}
+		return err
+	})
+}
+
+// initEmailVerification returns nil when EMAIL_LINK_SECRET is unset, and an error when verification is required
+// but cannot work (no secret, no sender, no link target).
+func initEmailVerification(cfg *config.Config, pool *pgxpool.Pool, mailer ports.EmailSender) (*userusecase.EmailVerificationUseCase, error) {
+	if cfg.Email.LinkSecret == "" {
+		if cfg.Email.VerificationRequired {
+			return nil, errors.New("EMAIL_VERIFICATION_REQUIRED is set but EMAIL_LINK_SECRET is empty")
+		}
+		return nil, nil
+	}
+	tokens, err := security.NewHMACLinkTokens(cfg.Email.LinkSecret)
+	if err != nil {
+		return nil, fmt.Errorf("EMAIL_LINK_SECRET: %w", err)
+	}
+	if mailer == nil {
+		return nil, errors.New("email verification needs SMTP_HOST in prod")
+	}
+	if cfg.Email.VerificationURL == "" {
+		return nil, errors.New("EMAIL_VERIFICATION_URL is required when EMAIL_LINK_SECRET is set")
+	}
+	return userusecase.NewEmailVerificationUseCase(pgstore.NewUserRepository(pool), tokens, mailer, userusecase.EmailVerificationOptions{
+		LinkBaseURL: cfg.Email.VerificationURL,
+		TTL:         time.Duration(cfg.Email.VerificationTTLSec) * time.Second,
+	}), nil
+}
+
+// buildLoginLockout builds the failed-login backoff and lockout policy; state lives in Postgres so it holds across instances.
+func buildLoginLockout(cfg *config.Config, pool *pgxpool.Pool, mailer ports.EmailSender) *userusecase.LoginLockoutUseCase {
+	return userusecase.NewLoginLockoutUseCase(pgstore.NewUserRepository(pool), pgstore.NewLoginLockoutStore(pool), mailer, userusecase.LoginLockoutOptions{
+		Threshold:       cfg.Security.LoginLockoutThreshold,
+		LockDuration:    time.Duration(cfg.Security.LoginLockoutDurationSec) * time.Second,
+		MaxLockDuration: time.Duration(cfg.Security.LoginLockoutMaxDurationSec) * time.Second,
+		BackoffAfter:    cfg.Security.LoginBackoffAfter,
+		BackoffBase:     time.Duration(cfg.Security.LoginBackoffBaseMS) * time.Millisecond,
+		FailureWindow:   time.Duration(cfg.Security.LoginFailureWindowSec) * time.Second,
+	})
+}
+
+// buildPasswordResetHandler wires forgot/reset password when PASSWORD_RESET_URL is set. revocations is the
+// denylist checked by the auth middleware, so a reset also cuts off outstanding access tokens.
+func buildPasswordResetHandler(cfg *config.Config, pool *pgxpool.Pool, refreshStore ports.RefreshTokenStore, revocations ports.AccessTokenRevocationStore) *handler.PasswordResetHandler {
+	if cfg.Email.PasswordResetURL == "" {
+		return nil
+	}
+	mailer := initEmailSender(cfg)
+	if mailer == nil {
+		logger.L().Error("password_reset_misconfigured", "note", "SMTP_HOST is required in prod; password reset disabled")
+		return nil
+	}
+	uc := userusecase.NewPasswordResetUseCase(
+		pgstore.NewUserRepository(pool),
+		buildPasswordHasher(cfg),
+		pgstore.NewPasswordResetStore(pool),
+		mailer,
+		refreshStore,
+		revocations,
+		userusecase.PasswordResetOptions{
+			LinkBaseURL:    cfg.Email.PasswordResetURL,
+			TTL:            time.Duration(cfg.Email.PasswordResetTTLSec) * time.Second,
+			AccessTokenTTL: accessTokenMaxTTL(cfg),
+		},
+	)
+	return handler.NewPasswordResetHandler(uc)
+}
+
+// buildMagicLinkHandler wires passwordless sign-in when MAGIC_LINK_URL is set, or returns nil.
+func buildMagicLinkHandler(cfg *config.Config, pool *pgxpool.Pool, jwtSvc security.JWTService, refreshStore ports.RefreshTokenStore, mfa *userusecase.MFAUseCase) *handler.MagicLinkHandler {
+	if cfg.Email.MagicLinkURL == "" {
+		return nil
+	}
+	mailer := initEmailSender(cfg)
+	if mailer == nil {
+		logger.L().Error("magic_link_misconfigured", "note", "SMTP_HOST is required in prod; magic-link login disabled")
+		return nil
+	}
+	var links ports.MagicLinkStore
+	if cfg.RedisAddr != "" {
+		links = authinfra.NewRedisMagicLinkStore(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
+	} else {
+		if cfg.Env == "prod" {
+			logger.L().Warn("magic_link_in_memory", "note", "in-memory magic links are per-instance; configure REDIS_ADDR for multi-instance")
+		}
+		links = authinfra.NewMemoryMagicLinkStore()
+	}
+	ttl := time.Duration(cfg.Email.MagicLinkTTLSec) * time.Second
+	if ttl <= 0 {
+		ttl = 15 * time.Minute
+	}
+	uc := userusecase.NewMagicLinkUseCase(
+		pgstore.NewUserRepository(pool),
+		jwtSvc,
+		refreshStore,
+		cfg.Security.RefreshTTLSeconds,
+		links,
+		mailer,
+		mfa,
+		userusecase.MagicLinkOptions{LinkBaseURL: cfg.Email.MagicLinkURL, TTL: ttl},
+	)
+	return handler.NewMagicLinkHandler(uc, ttl, cfg.Env == "prod")
+}
+
+// initSMSSender returns the SMS_PROVIDER sender, nil when SMS is disabled, and an error for an incomplete or
+// development-only provider in prod. Delivery failures are logged here.
+func initSMSSender(cfg *config.Config) (ports.SMSSender, error) {
+	var sender ports.SMSSender
+	switch strings.ToLower(cfg.SMS.Provider) {
+	case "":
+		return nil, nil
+	case "twilio":
+		if cfg.SMS.TwilioAccountSID == "" || cfg.SMS.TwilioAuthToken == "" || cfg.SMS.TwilioFrom == "" {
+			return nil, errors.New("SMS_PROVIDER=twilio needs TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM")
+		}
+		sender = smsinfra.NewTwilioSender(cfg.SMS.TwilioAccountSID, cfg.SMS.TwilioAuthToken, cfg.SMS.TwilioFrom)
+	case "log":
+		if cfg.Env == "prod" {
+			return nil, errors.New("SMS_PROVIDER=log is for development only")
+		}
+		sender = smsinfra.NewLogSender()
+	case "file":
+		if cfg.Env == "prod" {
+			return nil, errors.New("SMS_PROVIDER=file is for development only")
+		}
+		path := cfg.SMS.FilePath
+		if path == "" {
+			path = "tmp/sms.log"
+		}
+		sender = smsinfra.NewFileSender(path)
+	default:
+		return nil, fmt.Errorf("unknown SMS_PROVIDER %q", cfg.SMS.Provider)
+	}
+	return ports.SendSMSFunc(func(ctx context.Context, to, message string) error {
+		err := sender.Send(ctx, to, message)
+		if err != nil {
+			logger.L().Error("sms_send_failed", "provider", cfg.SMS.Provider, "error", err)
+		}
+		return err
+	}), nil
+}
+
+// buildSMSHandler wires phone verification and SMS login when SMS_PROVIDER is set, or returns nil.
+func buildSMSHandler(cfg *config.Config, pool *pgxpool.Pool, jwtSvc security.JWTService, refreshStore ports.RefreshTokenStore, mfa *userusecase.MFAUseCase) *handler.SMSHandler {
+	sender, err := initSMSSender(cfg)
+	if err != nil {
+		logger.L().Error("sms_misconfigured", "error", err, "note", "phone verification and SMS login disabled")
+		return nil
+	}
+	if sender == nil {
+		return nil
+	}
+	var (
+		challenges ports.OTPChallengeStore
+		failures   ports.FailureCounter
+	)
+	if cfg.RedisAddr != "" {
+		challenges = authinfra.NewRedisOTPChallengeStore(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
+		failures = authinfra.NewRedisFailureCounter(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
+	} else {
+		if cfg.Env == "prod" {
+			logger.L().Warn("sms_codes_in_memory", "note", "in-memory SMS codes are per-instance; configure REDIS_ADDR for multi-instance")
+		}
+		challenges = authinfra.NewMemoryOTPChallengeStore()
+		failures = authinfra.NewMemoryFailureCounter()
+	}
+	uc := userusecase.NewSMSUseCase(
+		pgstore.NewUserRepository(pool),
+		jwtSvc,
+		refreshStore,
+		cfg.Security.RefreshTTLSeconds,
+		challenges,
+		failures,
+		sender,
+		mfa,
+		userusecase.SMSOptions{
+			CodeTTL:     time.Duration(cfg.SMS.CodeTTLSec) * time.Second,
+			MaxAttempts: cfg.SMS.MaxAttempts,
+		},
+	)
+	return handler.NewSMSHandler(uc, uc)
+}
+
+// buildOIDCHandler wires OpenID Connect login from OIDC_PROVIDERS_FILE, or returns nil when it is not configured.
+func buildOIDCHandler(cfg *config.Config, pool *pgxpool.Pool, jwtSvc security.JWTService, refreshStore ports.RefreshTokenStore, mfa *userusecase.MFAUseCase) *handler.OIDCHandler {
+	if cfg.OIDC.ProvidersFile == "" {
+		return nil
+	}
+	providers, err := oidcinfra.LoadProviders(cfg.OIDC.ProvidersFile, cfg.OIDC.RedirectBaseURL, nil)
+	if err != nil {
+		logger.L().Error("oidc_providers_load_failed", "path", cfg.OIDC.ProvidersFile, "error", err)
+		return nil
+	}
+	list := make([]ports.OIDCProvider, 0, len(providers))
+	for _, p := range providers {
+		list = append(list, p)
+	}
+	var states ports.OIDCStateStore
+	if cfg.RedisAddr != "" {
+		states = authinfra.NewRedisOIDCStateStore(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
+	} else {
+		if cfg.Env == "prod" {
+			logger.L().Warn("oidc_state_in_memory", "note", "in-memory OIDC state is per-instance; configure REDIS_ADDR for multi-instance")
+		}
+		states = authinfra.NewMemoryOIDCStateStore()
+	}
+	stateTTL := time.Duration(cfg.OIDC.StateTTLSec) * time.Second
+	if stateTTL <= 0 {
+		stateTTL = 10 * time.Minute
+	}
+	uc := userusecase.NewOIDCLoginUseCase(
+		pgstore.NewUserRepository(pool),
+		buildPasswordHasher(cfg),
+		jwtSvc,
+		refreshStore,
+		cfg.Security.RefreshTTLSeconds,
+		list,
+		states,
+		pgstore.NewIdentityStore(pool),
+		stateTTL,
+		mfa,
+	)
+	logger.L().Info("oidc_login_enabled", "providers", len(list))
+	return handler.NewOIDCHandler(uc, stateTTL, cfg.Env == "prod")
+}
+
 // loadRBACPolicy loads the RBAC policy from YAML if RBAC_POLICY_PATH is set.
 func loadRBACPolicy(cfg *config.Config) {
 	if cfg.RBAC.PolicyPath == "" {
@@ -95,17 +572,219 @@ func loadRBACPolicy(cfg *config.Config) {
 	}
 }
 
-// buildRouter constructs the Gin engine with middlewares, routes and readiness check.
-func buildRouter(cfg *config.Config, userHandler *handler.UserHandler, jwtSvc security.JWTService, pool *pgxpool.Pool) *gin.Engine {
+// buildAccessRevocationStore returns the access-token denylist: Redis when configured, otherwise in-memory (per instance).
+func buildAccessRevocationStore(cfg *config.Config) ports.AccessTokenRevocationStore {
+	if cfg.RedisAddr != "" {
+		return authinfra.NewRedisAccessRevocationStore(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
+	}
+	if cfg.Env == "prod" {
+		logger.L().Warn("access_revocation_in_memory", "note", "in-memory denylist is per-instance; configure REDIS_ADDR for multi-instance")
+	}
+	return authinfra.NewMemoryAccessRevocationStore()
+}
+
+// buildAuthMiddleware builds JWTAuth with full claims and the revocation (denylist) check. A failed check lets
+// the token through unless AUTH_REVOCATION_FAIL_CLOSED is set.
+func buildAuthMiddleware(cfg *config.Config, jwtSvc security.JWTService, revocations ports.AccessTokenRevocationStore) gin.HandlerFunc {
 	// Build a validator function to decouple middleware from concrete JWT service
-	validator := func(token string) (string, string, error) {
+	validator := func(token string) (middleware.AccessClaims, error) {
 		claims, err := jwtSvc.ValidateToken(token)
 		if err != nil {
-			return "", "", err
+			return middleware.AccessClaims{}, err
+		}
+		out := middleware.AccessClaims{Subject: claims.Subject, Role: claims.Role, ID: claims.ID}
+		if claims.IsClient() {
+			out.ClientID = claims.ClientID
+			out.Scopes = claims.Scopes()
+		}
+		if claims.IssuedAt != nil {
+			out.IssuedAt = claims.IssuedAt.Time
+		}
+		if claims.ExpiresAt != nil {
+			out.ExpiresAt = claims.ExpiresAt.Time
+		}
+		return out, nil
+	}
+	revoked := func(ctx context.Context, claims middleware.AccessClaims) (bool, error) {
+		isRevoked, err := revocations.IsRevoked(ctx, claims.ID, claims.Subject, claims.IssuedAt)
+		if err != nil {
+			logger.L().Warn("access_revocation_check_failed", "error", err, "fail_closed", cfg.Security.RevocationFailClosed)
+			if !cfg.Security.RevocationFailClosed {
+				return false, nil
+			}
+		}
+		return isRevoked, err
+	}
+	return middleware.JWTAuthWithClaims(validator, revoked)
+}
+
+// buildAPIKeyAuth wraps jwtAuth so requests may also authenticate with a personal API key.
+func buildAPIKeyAuth(jwtAuth gin.HandlerFunc, apiKeys *userusecase.APIKeyUseCase) gin.HandlerFunc {
+	authenticate := func(ctx context.Context, key string) (middleware.APIKeyPrincipal, error) {
+		p, err := apiKeys.Authenticate(ctx, key)
+		if err != nil {
+			if !errors.Is(err, apperr.ErrAPIKeyInvalid) {
+				logger.L().Warn("api_key_auth_failed", "error", err)
+			}
+			return middleware.APIKeyPrincipal{}, err
+		}
+		return middleware.APIKeyPrincipal{UserID: p.UserID, Role: p.Role, KeyID: p.KeyID, Scopes: p.Scopes}, nil
+	}
+	return middleware.JWTOrAPIKeyAuth(jwtAuth, userusecase.APIKeyPrefix, authenticate)
+}
+
+// accessTokenMaxTTL is the longest an access token can be accepted (lifetime + validation leeway).
+func accessTokenMaxTTL(cfg *config.Config) time.Duration {
+	return time.Duration(cfg.JWT.ExpireSec+cfg.JWT.LeewaySec) * time.Second
+}
+
+// oauthTokenTTL is the lifetime of client_credentials tokens; it defaults to the user access token lifetime.
+func oauthTokenTTL(cfg *config.Config) time.Duration {
+	if cfg.OAuth.TokenTTLSec > 0 {
+		return time.Duration(cfg.OAuth.TokenTTLSec) * time.Second
+	}
+	return time.Duration(cfg.JWT.ExpireSec) * time.Second
+}
+
+// buildObjectStorage opens the OBJECT_STORAGE backend. The local backend also returns the handler serving
+// its signed links; both are nil when storage is disabled or misconfigured.
+func buildObjectStorage(cfg *config.Config) (ports.ObjectStorage, http.Handler) {
+	c := cfg.ObjectStorage
+	switch c.Backend {
+	case "":
+		return nil, nil
+	case "local":
+		dir := c.LocalDir
+		if dir == "" {
+			dir = "tmp/objects"
+		}
+		if c.PublicURL == "" {
+			logger.L().Error("object_storage_init_failed", "backend", c.Backend, "error", "OBJECT_STORAGE_PUBLIC_URL is required")
+			return nil, nil
+		}
+		store, err := localstore.New(dir, strings.TrimRight(c.PublicURL, "/")+httprouter.SignedFilesPath, []byte(c.SigningSecret))
+		if err != nil {
+			logger.L().Error("object_storage_init_failed", "backend", c.Backend, "error", err)
+			return nil, nil
+		}
+		maxUpload := c.MaxUploadBytes
+		if maxUpload <= 0 {
+			maxUpload = 25 << 20
+		}
+		logger.L().Info("object_storage_enabled", "backend", c.Backend, "dir", dir)
+		return store.WithMaxUploadBytes(maxUpload), store
+	case "s3":
+		store, err := s3store.New(s3store.Config{
+			Endpoint:  c.S3Endpoint,
+			Region:    c.S3Region,
+			Bucket:    c.S3Bucket,
+			AccessKey: c.S3AccessKey,
+			SecretKey: c.S3SecretKey,
+			PathStyle: c.S3PathStyle,
+			PartSize:  c.S3PartSize,
+		})
+		if err != nil {
+			logger.L().Error("object_storage_init_failed", "backend", c.Backend, "error", err)
+			return nil, nil
 		}
-		return claims.Subject, claims.Role, nil
+		logger.L().Info("object_storage_enabled", "backend", c.Backend, "bucket", c.S3Bucket)
+		return store, nil
+	default:
+		logger.L().Error("object_storage_init_failed", "backend", c.Backend, "error", "unknown backend")
+		return nil, nil
+	}
+}
+
+// buildDataExport registers one exporter per store holding user data. Exports go out inline until an
+// ObjectStorage backend is configured; with one, large exports are emailed as an expiring link.
+func buildDataExport(cfg *config.Config, pool *pgxpool.Pool, refreshStore ports.RefreshTokenStore, storage ports.ObjectStorage, opts userusecase.UserUsecasesOptions) *userusecase.DataExportUseCase {
+	userRepo := pgstore.NewUserRepository(pool)
+	exporters := []ports.PersonalDataExporter{
+		userusecase.NewProfileExporter(userRepo),
+		userusecase.NewIdentitiesExporter(pgstore.NewIdentityStore(pool)),
+		userusecase.NewAPIKeysExporter(pgstore.NewAPIKeyStore(pool)),
+		userusecase.NewLoginSecurityExporter(pgstore.NewLoginLockoutStore(pool)),
+	}
+	if refreshStore != nil {
+		exporters = append(exporters, userusecase.NewSessionsExporter(refreshStore))
+	}
+	if opts.MFA != nil {
+		exporters = append(exporters, userusecase.NewMFAExporter(pgstore.NewMFAStore(pool)))
+	}
+	return userusecase.NewDataExportUseCase(userRepo, storage, initEmailSender(cfg), userusecase.DataExportOptions{
+		InlineMaxBytes: cfg.DataExport.InlineMaxBytes,
+		LinkTTL:        time.Duration(cfg.DataExport.LinkTTLSec) * time.Second,
+		OnBackgroundError: func(userID uuid.UUID, err error) {
+			logger.L().Error("data_export_failed", "user_id", userID.String(), "error", err)
+		},
+	}, exporters...)
+}
+
+// buildRouter constructs the Gin engine with middlewares, routes and readiness check. drain waits for work the
+// handlers started in the background (large data exports) and is called after the server stopped.
+func buildRouter(cfg *config.Config, userHandler *handler.UserHandler, jwtSvc security.JWTService, pool *pgxpool.Pool, refreshStore ports.RefreshTokenStore, opts userusecase.UserUsecasesOptions) (router *gin.Engine, drain func(context.Context) error) {
+	revocations := buildAccessRevocationStore(cfg)
+	userRepo := pgstore.NewUserRepository(pool)
+	apiKeys := userusecase.NewAPIKeyUseCase(pgstore.NewAPIKeyStore(pool), userRepo)
+	auth := buildAPIKeyAuth(buildAuthMiddleware(cfg, jwtSvc, revocations), apiKeys)
+	// Account-security routes refuse API keys, so a leaked key cannot escalate
+	interactive := []gin.HandlerFunc{auth, middleware.RequireUser(), middleware.DenyAPIKeys()}
+	router = httpiface.NewRouter(userHandler, cfg, auth)
+	revokeUC := userusecase.NewRevokeAccessUseCase(userRepo, revocations, accessTokenMaxTTL(cfg))
+	httprouter.RegisterAdminTokenRoutes(router, handler.NewTokenAdminHandler(revokeUC), cfg, auth)
+	// Self-service session management (list devices, sign out one or all)
+	if refreshStore != nil {
+		sessionsUC := userusecase.NewSessionsUseCase(refreshStore, revocations, accessTokenMaxTTL(cfg))
+		httprouter.RegisterSessionRoutes(router, handler.NewSessionHandler(sessionsUC), interactive...)
+	}
+	// Sign in with external OpenID Connect providers
+	if oidcHandler := buildOIDCHandler(cfg, pool, jwtSvc, refreshStore, opts.MFA); oidcHandler != nil {
+		httprouter.RegisterOIDCRoutes(router, oidcHandler)
+	}
+	// TOTP enrollment and the second login step
+	if opts.MFA != nil {
+		httprouter.RegisterMFARoutes(router, handler.NewMFAHandler(opts.MFA), cfg, interactive...)
+	}
+	// Forgot/reset password by email
+	if resetHandler := buildPasswordResetHandler(cfg, pool, refreshStore, revocations); resetHandler != nil {
+		httprouter.RegisterPasswordResetRoutes(router, resetHandler, cfg)
+	}
+	// Passwordless sign-in by emailed link
+	if magicHandler := buildMagicLinkHandler(cfg, pool, jwtSvc, refreshStore, opts.MFA); magicHandler != nil {
+		httprouter.RegisterMagicLinkRoutes(router, magicHandler, cfg)
+	}
+	// Phone verification and sign-in with SMS codes
+	if smsHandler := buildSMSHandler(cfg, pool, jwtSvc, refreshStore, opts.MFA); smsHandler != nil {
+		httprouter.RegisterSMSRoutes(router, smsHandler, cfg, interactive...)
+	}
+	// Admin account management (list, edit, disable, delete, lift failed-login lockouts)
+	userAdmin := userusecase.NewUserAdminUseCase(userRepo, refreshStore, revocations, accessTokenMaxTTL(cfg))
+	var lockouts userusecase.LoginLockoutAdmin
+	if opts.LoginLockout != nil {
+		lockouts = opts.LoginLockout
+	}
+	httprouter.RegisterAdminUserRoutes(router, handler.NewUserAdminHandler(userAdmin, lockouts), cfg, lockouts != nil, auth)
+	// Files (data exports) in a local directory or S3; local files are served through signed links
+	storage, signedFiles := buildObjectStorage(cfg)
+	if signedFiles != nil {
+		httprouter.RegisterSignedFileRoutes(router, signedFiles)
+	}
+	// Self-service copy of everything stored about the caller
+	dataExport := buildDataExport(cfg, pool, refreshStore, storage, opts)
+	httprouter.RegisterDataExportRoutes(router, handler.NewDataExportHandler(dataExport), cfg, interactive...)
+	// Personal API keys for scripts and machine clients
+	httprouter.RegisterAPIKeyRoutes(router, handler.NewAPIKeyHandler(apiKeys), cfg, auth)
+	// OAuth2 client credentials for service-to-service calls
+	oauthClients := userusecase.NewOAuthClientUseCase(pgstore.NewOAuthClientStore(pool), jwtSvc, oauthTokenTTL(cfg))
+	var introspection userusecase.TokenIntrospection
+	if verifier, ok := jwtSvc.(ports.AccessTokenVerifier); ok {
+		introspection = userusecase.NewTokenIntrospectionUseCase(oauthClients, verifier, revocations, refreshStore)
+	}
+	httprouter.RegisterOAuthRoutes(router, handler.NewOAuthHandler(oauthClients, oauthClients, introspection), cfg, introspection != nil, auth)
+	// Email verification links
+	if opts.EmailVerification != nil {
+		httprouter.RegisterEmailVerificationRoutes(router, handler.NewEmailVerificationHandler(opts.EmailVerification), cfg)
 	}
-	router := httpiface.NewRouter(userHandler, cfg, middleware.JWTAuth(validator))
 	ping := infdb.NewDBPingCheck(pool)
 	httpiface.AddReadiness(router, ping)
 	// Optional: swap in Redis-based rate limiter for login when Redis configured
@@ -116,9 +795,13 @@ func buildRouter(cfg *config.Config, userHandler *handler.UserHandler, jwtSvc se
 		// For clarity in this starter, we attach a global middleware that only triggers on /v1/auth/login
 		router.Use(rl.Middleware("/v1/auth/login", cfg.HTTP.LoginRateLimitRPS, cfg.HTTP.LoginRateLimitBurst))
 	}
+	// Public key discovery for other services verifying our tokens (JWKS + minimal OIDC discovery)
+	if ks, ok := jwtSvc.(ports.KeySetPublisher); ok {
+		httprouter.RegisterWellKnownRoutes(router, handler.NewWellKnownHandler(ks, cfg.JWT.Issuer, cfg.JWT.PublicBaseURL, cfg.JWT.JWKSMaxAgeSec))
+	}
 	// API Docs (dev-only)
 	if cfg.Env == "dev" {
 		apidocs.Mount(router)
 	}
-	return router
+	return router, dataExport.Shutdown
 }
@@ -8,6 +8,7 @@ import (
 	"syscall"
 	"time"
 
+	"gostartkit/internal/application/usecase/userusecase"
 	"gostartkit/internal/config"
 	"gostartkit/pkg/logger"
 )
@@ -20,6 +21,10 @@ func main() {
 
 	// Load i18n catalogs
 	initI18n(cfg)
+	if err := initBreachedPasswords(cfg); err != nil {
+		logger.L().Error("breached_passwords_config_failed", "error", err)
+		os.Exit(1)
+	}
 
 	// DB + migrations
 	pool, err := initPostgresAndMigrate(cfg)
@@ -28,11 +33,45 @@ func main() {
 		os.Exit(1)
 	}
 	// JWT service
-	jwtSvc := initJWTService(cfg)
+	jwtSvc, err := initJWTService(cfg)
+	if err != nil {
+		logger.L().Error("jwt_config_failed", "error", err)
+		os.Exit(1)
+	}
+	// Background workers stop when the server shuts down
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	startJWTKeyReloader(bgCtx, cfg, jwtSvc)
+	startRefreshTokenCleanup(bgCtx, cfg, pool)
+	// One refresh-token store shared by every sign-in path, sessions and introspection
+	refreshStore, err := buildRefreshStore(cfg, pool)
+	if err != nil {
+		logger.L().Error("refresh_store_config_failed", "error", err)
+		os.Exit(1)
+	}
+	// Optional TOTP MFA
+	mfa, err := initMFA(cfg, pool, jwtSvc, refreshStore)
+	if err != nil {
+		logger.L().Error("mfa_config_failed", "error", err)
+		os.Exit(1)
+	}
+	// Optional email verification
+	mailer := initEmailSender(cfg)
+	verification, err := initEmailVerification(cfg, pool, mailer)
+	if err != nil {
+		logger.L().Error("email_config_failed", "error", err)
+		os.Exit(1)
+	}
+	accountOpts := userusecase.UserUsecasesOptions{
+		MFA:                  mfa,
+		EmailVerification:    verification,
+		RequireVerifiedEmail: cfg.Email.VerificationRequired,
+		LoginLockout:         buildLoginLockout(cfg, pool, mailer),
+	}
 
 	// Optional: seed initial admin user
 	if cfg.Seed.Enable {
-		_, repo, hasher := buildUserComponents(pool, jwtSvc, cfg)
+		_, repo, hasher := buildUserComponents(pool, jwtSvc, refreshStore, cfg, accountOpts)
 		if err := seedInitialUser(pool, repo, hasher, cfg); err != nil {
 			logger.L().Warn("seed_error", "error", err)
 		}
@@ -42,8 +81,8 @@ func main() {
 	loadRBACPolicy(cfg)
 
 	// HTTP router
-	userHandler, _, _ := buildUserComponents(pool, jwtSvc, cfg)
-	router := buildRouter(cfg, userHandler, jwtSvc, pool)
+	userHandler, _, _ := buildUserComponents(pool, jwtSvc, refreshStore, cfg, accountOpts)
+	router, drainBackground := buildRouter(cfg, userHandler, jwtSvc, pool, refreshStore, accountOpts)
 
 	// HTTP server with timeouts
 	srv := &http.Server{
@@ -72,6 +111,11 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.L().Error("server_shutdown_error", "error", err)
 	}
+	// Let background exports started by requests finish within the same deadline
+	if err := drainBackground(ctx); err != nil {
+		logger.L().Error("background_drain_error", "error", err)
+	}
+	stopBackground()
 
 	// Close DB connection
 	pool.Close()
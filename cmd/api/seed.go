@@ -39,6 +39,8 @@ func seedInitialUser(_ any, repo *pgstore.UserRepository, hasher userusecase.Pas
 		return err
 	}
 	u := domuser.NewUser(cfg.Seed.FirstName, cfg.Seed.LastName, emailVO, hashed, role)
+	// The operator chose this address; do not lock the seeded account behind email verification
+	u.MarkEmailVerified(u.CreatedAt)
 	if err := repo.Save(ctx, u); err != nil {
 		return err
 	}
@@ -0,0 +1,51 @@
+// Command breachbloom builds a compact bloom filter from a HaveIBeenPwned "SHA-1 ordered by hash" file,
+// for use as BREACHED_PASSWORDS_FILE when the full file is too large to ship.
+//
+//	go run ./cmd/breachbloom -in pwned-passwords-sha1-ordered-by-hash-v8.txt -out pwned.bloom -min-count 10
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+
+	"gostartkit/internal/infras/security"
+)
+
+func main() {
+	in := flag.String("in", "", "HaveIBeenPwned SHA-1 HASH:COUNT file")
+	out := flag.String("out", "pwned.bloom", "output filter path")
+	minCount := flag.Int("min-count", 1, "keep only hashes seen at least this many times")
+	fpRate := flag.Float64("fp-rate", 0.001, "target false-positive rate")
+	flag.Parse()
+	if *in == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*in, *out, *minCount, *fpRate); err != nil {
+		fmt.Fprintln(os.Stderr, "breachbloom:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out string, minCount int, fpRate float64) error {
+	bloom, err := security.BuildBreachBloom(in, minCount, fpRate)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if _, err := bloom.WriteTo(w); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
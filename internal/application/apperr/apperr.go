@@ -4,7 +4,64 @@ import "errors"
 
 // Sentinel application-level errors for consistent handling/mapping.
 var (
-	ErrInvalidCredentials        = errors.New("invalid_credentials")
-	ErrInvalidRefreshToken       = errors.New("invalid_refresh_token")
-	ErrRefreshStoreNotConfigured = errors.New("refresh_store_not_configured")
+	ErrInvalidCredentials  = errors.New("invalid_credentials")
+	ErrInvalidRefreshToken = errors.New("invalid_refresh_token")
+	// ErrRefreshTokenReused means an already-rotated refresh token was presented again; its family was revoked.
+	ErrRefreshTokenReused           = errors.New("refresh_token_reused")
+	ErrRefreshStoreNotConfigured    = errors.New("refresh_store_not_configured")
+	ErrSessionNotFound              = errors.New("session_not_found")
+	ErrRevocationStoreNotConfigured = errors.New("revocation_store_not_configured")
+	ErrOIDCProviderNotFound         = errors.New("oidc_provider_not_found")
+	// ErrOIDCStateInvalid covers unknown, expired, replayed or cross-browser state parameters.
+	ErrOIDCStateInvalid = errors.New("oidc_state_invalid")
+	// ErrOIDCLoginFailed hides the details of a failed code exchange or ID token validation from clients.
+	ErrOIDCLoginFailed      = errors.New("oidc_login_failed")
+	ErrOIDCEmailNotVerified = errors.New("oidc_email_not_verified")
+	ErrOIDCSignupNotAllowed = errors.New("oidc_signup_not_allowed")
+	ErrMFANotEnrolled       = errors.New("mfa_not_enrolled")
+	ErrMFAAlreadyEnabled    = errors.New("mfa_already_enabled")
+	// ErrMFATokenInvalid covers unknown, expired or exhausted mfa_token values.
+	ErrMFATokenInvalid = errors.New("mfa_token_invalid")
+	ErrMFACodeInvalid  = errors.New("mfa_code_invalid")
+	// ErrMFARequired is returned when policy requires MFA for the user's role (e.g. on disable).
+	ErrMFARequired = errors.New("mfa_required")
+	// ErrEmailVerificationRequired is returned by login when verification is enforced and the email is unconfirmed.
+	ErrEmailVerificationRequired = errors.New("email_verification_required")
+	// ErrVerificationTokenInvalid covers tampered, expired or outdated email verification links.
+	ErrVerificationTokenInvalid = errors.New("verification_token_invalid")
+	// ErrResetTokenInvalid covers unknown, used or expired password reset tokens.
+	ErrResetTokenInvalid = errors.New("reset_token_invalid")
+	// ErrMagicLinkInvalid covers unknown, used or expired magic links and links opened in another browser.
+	ErrMagicLinkInvalid = errors.New("magic_link_invalid")
+	// ErrOTPInvalid covers wrong, expired or exhausted SMS codes.
+	ErrOTPInvalid = errors.New("otp_invalid")
+	// ErrAPIKeyNotFound is returned for unknown API keys and keys owned by someone else.
+	ErrAPIKeyNotFound = errors.New("api_key_not_found")
+	// ErrAPIKeyInvalid covers malformed, unknown, wrong-secret and expired API keys presented for authentication.
+	ErrAPIKeyInvalid = errors.New("api_key_invalid")
+	// ErrInvalidScope is returned for scopes that are not permission patterns, or that an OAuth client may not request.
+	ErrInvalidScope = errors.New("invalid_scope")
+	// ErrScopeNotHeld is returned when a caller tries to grant a scope (to an OAuth client) that it does not hold itself.
+	ErrScopeNotHeld = errors.New("scope_not_held")
+	// ErrOAuthClientNotFound is returned for unknown OAuth client IDs.
+	ErrOAuthClientNotFound = errors.New("oauth_client_not_found")
+	// ErrInvalidClient means OAuth client authentication failed (unknown client or wrong secret).
+	ErrInvalidClient = errors.New("invalid_client")
+	// ErrUnauthorizedClient is returned when a client lacks the scope to introspect or revoke a token, or the
+	// token was issued to another client.
+	ErrUnauthorizedClient = errors.New("unauthorized_client")
+	// ErrAccountDisabled is returned by sign-in and refresh for accounts an administrator has disabled.
+	ErrAccountDisabled = errors.New("account_disabled")
+	// ErrSelfAdminChange is returned when administrators try to disable, delete or demote their own account.
+	ErrSelfAdminChange = errors.New("self_admin_change")
+	// ErrLastAdmin is returned for changes that would leave no enabled administrator.
+	ErrLastAdmin = errors.New("last_admin")
+	// ErrInvalidCursor covers malformed or tampered pagination cursors.
+	ErrInvalidCursor = errors.New("invalid_cursor")
+	// ErrEmailChangeNotConfigured is returned for email changes when confirmation links cannot be sent (EMAIL_LINK_SECRET unset).
+	ErrEmailChangeNotConfigured = errors.New("email_change_not_configured")
+	// ErrObjectNotFound is returned by object storage for keys that hold no object.
+	ErrObjectNotFound = errors.New("object_not_found")
+	// ErrInvalidObjectKey covers object keys that are not clean relative paths (see ports.CheckObjectKey).
+	ErrInvalidObjectKey = errors.New("invalid_object_key")
 )
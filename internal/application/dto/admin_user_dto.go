@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// AdminUserResponse is the administrator's view of an account.
+type AdminUserResponse struct {
+	UserResponse
+	Role       string     `json:"role"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	Disabled   bool       `json:"disabled"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	ErasedAt   *time.Time `json:"erased_at,omitempty"`
+}
+
+// ListUsersRequest filters and pages GET /v1/admin/users (query string). Times are RFC 3339.
+type ListUsersRequest struct {
+	Role        string     `form:"role" binding:"omitempty,max=32"`
+	EmailPrefix string     `form:"email_prefix" binding:"omitempty,max=254"`
+	CreatedFrom *time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   *time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	// Cursor is next_cursor from the previous page
+	Cursor string `form:"cursor" binding:"omitempty,max=128"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=200"`
+	// IncludeDeleted also lists deleted and erased accounts
+	IncludeDeleted bool `form:"include_deleted"`
+}
+
+// PageMeta describes a keyset-paginated page; pass NextCursor as cursor to fetch the next one.
+type PageMeta struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// AdminUserPage is one page of GET /v1/admin/users.
+type AdminUserPage struct {
+	Users []AdminUserResponse
+	Page  PageMeta
+}
+
+// AdminUpdateUserRequest changes profile fields and the role; omitted fields are left as they are.
+type AdminUpdateUserRequest struct {
+	FirstName *string `json:"first_name" binding:"omitempty,min=1,max=100"`
+	LastName  *string `json:"last_name" binding:"omitempty,min=1,max=100"`
+	Role      *string `json:"role" binding:"omitempty,max=32"`
+}
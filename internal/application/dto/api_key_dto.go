@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+	// Scopes limit the key to these permission patterns (e.g. "users:read", "users:*"); empty = no permission-guarded routes
+	Scopes []string `json:"scopes" binding:"omitempty,max=20,dive,min=1,max=64"`
+	// ExpiresInDays of 0 means the key does not expire
+	ExpiresInDays int `json:"expires_in_days,omitempty" binding:"omitempty,min=1,max=3650"`
+}
+
+// UpdateAPIKeyRequest renames a key or replaces its scopes; omitted fields are left unchanged.
+type UpdateAPIKeyRequest struct {
+	Name   *string   `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Scopes *[]string `json:"scopes,omitempty" binding:"omitempty,max=20,dive,min=1,max=64"`
+}
+
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyResponse is the only response that carries the full key; it cannot be retrieved again.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// APIKeyPrincipal is the user an API key authenticates as, with the key's scopes.
+type APIKeyPrincipal struct {
+	UserID string
+	Role   string
+	KeyID  string
+	Scopes []string
+}
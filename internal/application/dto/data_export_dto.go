@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersonalDataExport is the document served by GET /v1/auth/me/export. Version changes whenever a section
+// changes shape incompatibly; new sections can appear without a version change.
+type PersonalDataExport struct {
+	Format      string         `json:"format"`
+	Version     int            `json:"version"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	UserID      uuid.UUID      `json:"user_id"`
+	Sections    map[string]any `json:"sections"`
+}
+
+// DataExportRequest selects the export packaging (query string); json is the default.
+type DataExportRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json zip"`
+}
+
+// DataExportResult is either the export file or, for large exports, a notice that it is being prepared
+// in the background and the download link will be emailed.
+type DataExportResult struct {
+	Pending     bool
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// DataExportPendingResponse is returned with 202 when the export is delivered by email.
+type DataExportPendingResponse struct {
+	Status   string `json:"status"`
+	Delivery string `json:"delivery"`
+}
+
+// ExportedProfile is the profile section of a personal data export.
+type ExportedProfile struct {
+	ID              uuid.UUID  `json:"id"`
+	Email           string     `json:"email"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	Role            string     `json:"role"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	Phone           string     `json:"phone,omitempty"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at,omitempty"`
+	DisabledAt      *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// ExportedIdentity is an external sign-in account (OIDC) linked to the user.
+type ExportedIdentity struct {
+	Provider string    `json:"provider"`
+	Subject  string    `json:"subject"`
+	Email    string    `json:"email,omitempty"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
+// ExportedMFA tells whether a second factor is set up; the TOTP seed and recovery codes are never exported.
+type ExportedMFA struct {
+	Enrolled  bool `json:"enrolled"`
+	Confirmed bool `json:"confirmed"`
+}
+
+// ExportedLoginSecurity is the failed-login state kept for lockouts.
+type ExportedLoginSecurity struct {
+	FailedAttempts int        `json:"failed_attempts"`
+	LastFailedAt   *time.Time `json:"last_failed_at,omitempty"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+}
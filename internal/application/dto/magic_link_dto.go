@@ -0,0 +1,21 @@
+package dto
+
+// MagicLinkRequest asks for a sign-in link; the response never reveals whether the email exists.
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,strict_email"`
+}
+
+// MagicLinkStartResponse carries the browser nonce, which the HTTP layer keeps in a cookie.
+type MagicLinkStartResponse struct {
+	Nonce string `json:"-"`
+}
+
+// MagicLinkConsumeRequest exchanges the token from a magic link for tokens.
+type MagicLinkConsumeRequest struct {
+	Token       string `json:"token" binding:"required,max=128"`
+	DeviceLabel string `json:"device_label,omitempty" binding:"omitempty,max=100"`
+	// Nonce, IP and UserAgent are filled by the HTTP layer.
+	Nonce     string `json:"-"`
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
+}
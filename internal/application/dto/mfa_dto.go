@@ -0,0 +1,23 @@
+package dto
+
+// MFAEnrollResponse is shown once so the user can add the secret to an authenticator app.
+type MFAEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// MFACodeRequest carries a TOTP code, or a recovery code where those are accepted.
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+// MFAVerifyRequest completes a login that returned mfa_required.
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required,max=128"`
+	Code     string `json:"code" binding:"required,max=32"`
+}
+
+// MFARecoveryCodesResponse lists new one-time recovery codes; they cannot be retrieved again.
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,70 @@
+package dto
+
+import "time"
+
+// ClientCredentialsRequest is an RFC 6749 section 4.4 token request. The handler fills it from the
+// form body and HTTP Basic authentication, so it has no binding tags.
+type ClientCredentialsRequest struct {
+	ClientID     string
+	ClientSecret string
+	// Scope is the space-separated scope parameter; empty requests every scope the client is allowed
+	Scope string
+}
+
+// OAuthTokenResponse is the RFC 6749 section 5.1 access token response (not wrapped in the usual envelope).
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+type CreateOAuthClientRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+	// Scopes are the permission patterns the client may request (e.g. "users:read", "users:*")
+	Scopes []string `json:"scopes" binding:"omitempty,max=20,dive,min=1,max=64"`
+}
+
+type OAuthClientResponse struct {
+	ClientID  string    `json:"client_id"`
+	Name      string    `json:"name"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateOAuthClientResponse is the only response that carries the client secret.
+type CreateOAuthClientResponse struct {
+	OAuthClientResponse
+	ClientSecret string `json:"client_secret"`
+}
+
+// TokenIntrospectionRequest is an RFC 7662 request, filled by the handler from the form body and client authentication.
+type TokenIntrospectionRequest struct {
+	ClientID     string
+	ClientSecret string
+	Token        string
+	// TokenTypeHint is "access_token" or "refresh_token"; other values are ignored
+	TokenTypeHint string
+}
+
+// TokenIntrospectionResponse is the RFC 7662 section 2.2 response. Inactive tokens only carry active=false.
+type TokenIntrospectionResponse struct {
+	Active    bool     `json:"active"`
+	Scope     string   `json:"scope,omitempty"`
+	ClientID  string   `json:"client_id,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  []string `json:"aud,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	JTI       string   `json:"jti,omitempty"`
+}
+
+// TokenRevocationRequest is an RFC 7009 request, filled like TokenIntrospectionRequest.
+type TokenRevocationRequest struct {
+	ClientID      string
+	ClientSecret  string
+	Token         string
+	TokenTypeHint string
+}
@@ -0,0 +1,18 @@
+package dto
+
+// OIDCStartResponse is returned to clients that ask for JSON instead of a redirect.
+type OIDCStartResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	// State is set as a browser-bound cookie by the HTTP layer; it is not part of the JSON body.
+	State string `json:"-"`
+}
+
+// OIDCCallbackRequest carries the provider redirect parameters.
+type OIDCCallbackRequest struct {
+	Code  string `form:"code" binding:"required"`
+	State string `form:"state" binding:"required"`
+	// Provider comes from the path; IP and UserAgent are filled by the HTTP layer for session metadata.
+	Provider  string `form:"-"`
+	IP        string `form:"-"`
+	UserAgent string `form:"-"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+// PhoneRequest names an E.164 phone number: to confirm for the signed-in user, or to receive a login code.
+type PhoneRequest struct {
+	Phone string `json:"phone" binding:"required,e164_phone"`
+}
+
+// SMSCodeRequest carries a code received by SMS.
+type SMSCodeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// SMSLoginRequest signs in with a code sent to a confirmed phone number.
+type SMSLoginRequest struct {
+	Phone       string `json:"phone" binding:"required,e164_phone"`
+	Code        string `json:"code" binding:"required,len=6,numeric"`
+	DeviceLabel string `json:"device_label,omitempty" binding:"omitempty,max=100"`
+	// IP and UserAgent are filled by the HTTP layer for session metadata.
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
+}
@@ -7,11 +7,13 @@ import (
 )
 
 type UserResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	CreatedAt time.Time `json:"created_at"`
+	ID            uuid.UUID `json:"id"`
+	Email         string    `json:"email"`
+	FirstName     string    `json:"first_name"`
+	LastName      string    `json:"last_name"`
+	EmailVerified bool      `json:"email_verified"`
+	Phone         string    `json:"phone,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 type CreateUserRequest struct {
@@ -23,14 +25,32 @@ type CreateUserRequest struct {
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,strict_email"`
-	Password string `json:"password" binding:"required"`
+	Email       string `json:"email" binding:"required,strict_email"`
+	Password    string `json:"password" binding:"required"`
+	DeviceLabel string `json:"device_label,omitempty" binding:"omitempty,max=100"`
+	// IP and UserAgent are filled by the HTTP layer for session metadata.
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+	// IP and UserAgent are filled by the HTTP layer for session metadata.
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+// LoginResponse carries either tokens or, when a second factor is needed, an mfa_token for /v1/auth/mfa/verify.
 type LoginResponse struct {
-	AccessToken  string       `json:"access_token"`
+	AccessToken  string       `json:"access_token,omitempty"`
 	RefreshToken string       `json:"refresh_token,omitempty"`
 	User         UserResponse `json:"user"`
+	MFARequired  bool         `json:"mfa_required,omitempty"`
+	MFAToken     string       `json:"mfa_token,omitempty"`
+	// MFASetup is set when policy requires MFA but the user has not enrolled yet.
+	MFASetup *MFAEnrollResponse `json:"mfa_setup,omitempty"`
+	// RecoveryCodes are returned once, when a login also completed MFA enrollment.
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
 }
 
 type RefreshResponse struct {
@@ -39,7 +59,61 @@ type RefreshResponse struct {
 	User         UserResponse `json:"user"`
 }
 
+// UpdateMeRequest changes the caller's own profile; omitted fields are left as they are. A new email
+// address needs CurrentPassword and only takes effect once it is confirmed through the link sent to it.
+type UpdateMeRequest struct {
+	FirstName       *string `json:"first_name" binding:"omitempty,min=1,max=100"`
+	LastName        *string `json:"last_name" binding:"omitempty,min=1,max=100"`
+	Email           *string `json:"email" binding:"omitempty,strict_email"`
+	CurrentPassword string  `json:"current_password"`
+}
+
+// UpdateMeResponse is the updated profile; PendingEmail is set while an email change awaits confirmation.
+type UpdateMeResponse struct {
+	UserResponse
+	PendingEmail string `json:"pending_email,omitempty"`
+}
+
 type ChangePasswordRequest struct {
 	CurrentPassword string `json:"current_password" binding:"required"`
 	NewPassword     string `json:"new_password" binding:"required,strong_password"`
 }
+
+// RevokeAccessRequest revokes access tokens either by jti or for a whole user.
+type RevokeAccessRequest struct {
+	JTI       string     `json:"jti" binding:"required_without=UserID"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	UserID    string     `json:"user_id" binding:"required_without=JTI,omitempty,uuid"`
+}
+
+// SessionResponse describes one refresh-token session (a login and its rotations).
+type SessionResponse struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	IP          string    `json:"ip,omitempty"`
+	UserAgent   string    `json:"user_agent,omitempty"`
+	DeviceLabel string    `json:"device_label,omitempty"`
+}
+
+// VerifyEmailRequest carries the token from an emailed verification link.
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required,max=512"`
+}
+
+// ResendVerificationRequest asks for a new verification link; the response never reveals whether the email exists.
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,strict_email"`
+}
+
+// ForgotPasswordRequest asks for a password reset link; the response never reveals whether the email exists.
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,strict_email"`
+}
+
+// ResetPasswordRequest sets a new password with the token from a reset link.
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required,max=128"`
+	NewPassword string `json:"new_password" binding:"required,strong_password"`
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// AccessTokenRevocationStore is a denylist for access tokens that must stop working before they expire.
+// Entries only need to live until the affected tokens would have expired anyway.
+type AccessTokenRevocationStore interface {
+	// RevokeJTI denylists a single token by its jti until expiresAt.
+	RevokeJTI(ctx context.Context, jti string, expiresAt time.Time) error
+	// RevokeUser rejects every token of userID issued in a second before the one of `before`; the entry is kept
+	// for ttl (the longest lifetime an access token can have). iat has whole-second precision, so tokens issued
+	// in the same second as the revocation stay valid: a sign-in right after logout-all or a reset must work.
+	RevokeUser(ctx context.Context, userID string, before time.Time, ttl time.Duration) error
+	// IsRevoked reports whether a token is denylisted by jti or by its user's revocation cutoff.
+	IsRevoked(ctx context.Context, jti, userID string, issuedAt time.Time) (bool, error)
+}
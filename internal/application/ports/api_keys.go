@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey is a user-owned credential for machine clients. Only a hash of the secret is kept.
+type APIKey struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	Prefix     string
+	SecretHash []byte
+	// Scopes are RBAC permission patterns the key is limited to ("users:read", "users:*", "*").
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// APIKeyStore persists API keys. Methods taking a userID only see that user's keys and return
+// apperr.ErrAPIKeyNotFound for anything else.
+type APIKeyStore interface {
+	Create(ctx context.Context, k APIKey) error
+	// GetByPrefix returns the key with this public prefix or apperr.ErrAPIKeyNotFound.
+	GetByPrefix(ctx context.Context, prefix string) (APIKey, error)
+	Get(ctx context.Context, userID, id uuid.UUID) (APIKey, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]APIKey, error)
+	Update(ctx context.Context, userID, id uuid.UUID, name string, scopes []string) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	// Touch records a successful use.
+	Touch(ctx context.Context, id uuid.UUID, at time.Time) error
+}
@@ -0,0 +1,11 @@
+package ports
+
+// BreachedPasswordChecker reports whether a password is known from public data breaches often enough to be refused.
+type BreachedPasswordChecker interface {
+	IsBreached(password string) (bool, error)
+}
+
+// BreachedPasswordCheckFunc adapts a function to the BreachedPasswordChecker interface.
+type BreachedPasswordCheckFunc func(password string) (bool, error)
+
+func (f BreachedPasswordCheckFunc) IsBreached(password string) (bool, error) { return f(password) }
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// FailureCounter counts wrong secrets per key (e.g. "mfa:<user id>") so callers can lock a key out after too many.
+// A key's window starts at its first failure; when it ends the count starts over.
+type FailureCounter interface {
+	// Count returns the failures in the key's current window (0 when there is none).
+	Count(ctx context.Context, key string) (int, error)
+	// Add counts one failure, opening a window of the given length when none is open, and returns the new count.
+	Add(ctx context.Context, key string, window time.Duration) (int, error)
+	// Reset forgets the key's failures.
+	Reset(ctx context.Context, key string) error
+}
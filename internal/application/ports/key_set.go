@@ -0,0 +1,27 @@
+package ports
+
+// JSONWebKey is the public half of a token verification key in RFC 7517 form.
+// Only the members needed for RSA, EC and OKP (Ed25519) signature keys are modelled.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC / OKP
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet is the document served at /.well-known/jwks.json.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// KeySetPublisher exposes the public verification keys of a token issuer so other services can verify its tokens.
+type KeySetPublisher interface {
+	PublicKeySet() JSONWebKeySet
+}
@@ -0,0 +1,11 @@
+package ports
+
+import "time"
+
+// LinkTokens issues and checks stateless, expiring tokens embedded in emailed links.
+// The purpose separates token kinds, so a token minted for one flow is rejected by another.
+type LinkTokens interface {
+	Sign(purpose, subject string, expiresAt time.Time) (string, error)
+	// Verify returns the signed subject, or an error for tampered, expired or foreign tokens.
+	Verify(purpose, token string, now time.Time) (string, error)
+}
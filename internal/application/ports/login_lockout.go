@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginLockout is the failed-login state of one account.
+type LoginLockout struct {
+	FailedAttempts int
+	LastFailedAt   time.Time
+	// LockedUntil is zero when the account has never been locked since the last reset.
+	LockedUntil time.Time
+}
+
+// LoginLockoutStore tracks failed password logins per user. Get returns the zero value for accounts without failures.
+type LoginLockoutStore interface {
+	Get(ctx context.Context, userID uuid.UUID) (LoginLockout, error)
+	// RecordFailure atomically counts one more failure at time at and returns the new state. Earlier failures
+	// before windowStart, when no lock reaches past it either, are forgotten and counting restarts at 1.
+	RecordFailure(ctx context.Context, userID uuid.UUID, at, windowStart time.Time) (LoginLockout, error)
+	// Lock refuses logins until the given time.
+	Lock(ctx context.Context, userID uuid.UUID, until time.Time) error
+	// Reset clears failures and any lock.
+	Reset(ctx context.Context, userID uuid.UUID) error
+}
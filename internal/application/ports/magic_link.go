@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// MagicLink is a pending passwordless sign-in. NonceHash binds it to the browser that asked for it.
+type MagicLink struct {
+	UserID    string `json:"user_id"`
+	NonceHash []byte `json:"nonce_hash"`
+}
+
+// MagicLinkStore keeps pending magic links keyed by a hash of the emailed token.
+type MagicLinkStore interface {
+	Save(ctx context.Context, tokenHash string, link MagicLink, ttl time.Duration) error
+	// Consume returns and deletes the link; apperr.ErrMagicLinkInvalid if it is unknown, used or expired.
+	Consume(ctx context.Context, tokenHash string) (MagicLink, error)
+}
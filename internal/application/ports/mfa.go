@@ -0,0 +1,68 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MFAEnrollment is a user's TOTP factor. The secret is only ever stored encrypted (see SecretBox).
+type MFAEnrollment struct {
+	UserID           uuid.UUID
+	SecretCiphertext []byte
+	// Confirmed is false between enrollment and the first valid code.
+	Confirmed bool
+	// LastUsedStep is the last accepted TOTP time step; codes at or before it are replays.
+	LastUsedStep int64
+}
+
+// MFAStore persists TOTP enrollments and one-time recovery codes.
+type MFAStore interface {
+	// Get returns the enrollment or apperr.ErrMFANotEnrolled.
+	Get(ctx context.Context, userID uuid.UUID) (MFAEnrollment, error)
+	// SavePending starts (or restarts) an unconfirmed enrollment. A confirmed enrollment is left untouched.
+	SavePending(ctx context.Context, userID uuid.UUID, secretCiphertext []byte) error
+	// Confirm activates a pending enrollment at step and replaces the recovery codes (stored as hashes).
+	Confirm(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes [][]byte) error
+	// UseStep records step as used; false when it is not newer than the last accepted step.
+	UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	// UseRecoveryCode marks a recovery code as used; false when unknown or already used.
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash []byte) (bool, error)
+	// Delete removes the enrollment and its recovery codes.
+	Delete(ctx context.Context, userID uuid.UUID) error
+}
+
+// MFAChallenge is a login that passed the password check and waits for a second factor.
+type MFAChallenge struct {
+	UserID string      `json:"user_id"`
+	Meta   SessionMeta `json:"meta"`
+	// Setup means the user had no confirmed factor; a valid code also confirms the enrollment.
+	Setup bool `json:"setup"`
+}
+
+// MFAChallengeStore keeps pending challenges keyed by the opaque mfa_token handed to the client.
+type MFAChallengeStore interface {
+	Save(ctx context.Context, token string, c MFAChallenge, ttl time.Duration) error
+	// Get returns the challenge or apperr.ErrMFATokenInvalid when unknown or expired.
+	Get(ctx context.Context, token string) (MFAChallenge, error)
+	// RecordFailure counts a wrong code and returns the number of failures so far.
+	RecordFailure(ctx context.Context, token string) (int, error)
+	Delete(ctx context.Context, token string) error
+}
+
+// TOTP generates and checks RFC 6238 codes.
+type TOTP interface {
+	// GenerateSecret returns a new base32 secret.
+	GenerateSecret() (string, error)
+	// KeyURI builds the otpauth:// URI that authenticator apps import (usually as a QR code).
+	KeyURI(issuer, account, secret string) string
+	// Verify checks code at time at (allowing clock drift) and returns the matching time step.
+	Verify(secret, code string, at time.Time) (step int64, ok bool)
+}
+
+// SecretBox encrypts small secrets at rest (authenticated encryption).
+type SecretBox interface {
+	Seal(plaintext []byte) ([]byte, error)
+	Open(ciphertext []byte) ([]byte, error)
+}
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// OAuthClient is a registered service that obtains tokens with the client_credentials grant.
+// Only a hash of the secret is kept.
+type OAuthClient struct {
+	ClientID   string
+	Name       string
+	SecretHash []byte
+	// Scopes are the RBAC permission patterns the client's tokens may carry.
+	Scopes    []string
+	CreatedAt time.Time
+}
+
+// OAuthClientStore persists registered clients; Get and Delete return apperr.ErrOAuthClientNotFound for unknown IDs.
+type OAuthClientStore interface {
+	Create(ctx context.Context, c OAuthClient) error
+	Get(ctx context.Context, clientID string) (OAuthClient, error)
+	List(ctx context.Context) ([]OAuthClient, error)
+	Delete(ctx context.Context, clientID string) error
+}
@@ -0,0 +1,61 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OIDCIdentity is the verified subset of an ID token used to sign a user in.
+type OIDCIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+}
+
+// OIDCProvider runs the authorization-code flow against one external identity provider.
+type OIDCProvider interface {
+	Name() string
+	// AuthCodeURL builds the authorization request; codeChallenge is the S256 PKCE challenge.
+	AuthCodeURL(state, nonce, codeChallenge string) (string, error)
+	// Exchange redeems the code with the PKCE verifier and returns the identity from the validated ID token.
+	// The ID token signature (provider JWKS), issuer, audience, expiry and nonce are all checked.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (OIDCIdentity, error)
+	// AllowSignup reports whether unknown users may be created just in time.
+	AllowSignup() bool
+}
+
+// OIDCAuthState is what the server remembers between /start and /callback.
+type OIDCAuthState struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+// OIDCStateStore keeps pending authorization requests keyed by the state parameter.
+type OIDCStateStore interface {
+	Save(ctx context.Context, state string, data OIDCAuthState, ttl time.Duration) error
+	// Consume returns and deletes the entry (single use). Unknown or expired state yields apperr.ErrOIDCStateInvalid.
+	Consume(ctx context.Context, state string) (OIDCAuthState, error)
+}
+
+// ExternalIdentityStore links provider subjects to local users.
+type ExternalIdentityStore interface {
+	// FindUserID returns the linked user or domain user.ErrUserNotFound.
+	FindUserID(ctx context.Context, provider, subject string) (uuid.UUID, error)
+	Link(ctx context.Context, provider, subject string, userID uuid.UUID, email string) error
+	// ListByUser returns the identities linked to the user, oldest first.
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]ExternalIdentity, error)
+}
+
+// ExternalIdentity is a provider account linked to a local user.
+type ExternalIdentity struct {
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
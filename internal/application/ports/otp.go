@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// OTPChallenge is a one-time code sent by SMS and waiting to be entered. Only a hash of the code is kept.
+type OTPChallenge struct {
+	UserID   string `json:"user_id"`
+	Phone    string `json:"phone"`
+	CodeHash []byte `json:"code_hash"`
+}
+
+// OTPChallengeStore keeps pending codes keyed by purpose and subject (e.g. "sms_login:+14155552671").
+// Saving under an existing key replaces the code and resets its failure count.
+type OTPChallengeStore interface {
+	Save(ctx context.Context, key string, c OTPChallenge, ttl time.Duration) error
+	// Get returns the challenge or apperr.ErrOTPInvalid when unknown or expired.
+	Get(ctx context.Context, key string) (OTPChallenge, error)
+	// RecordFailure counts a wrong code and returns the number of failures so far.
+	RecordFailure(ctx context.Context, key string) (int, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordResetStore keeps outstanding password reset tokens. Only token hashes are passed in, never raw tokens.
+type PasswordResetStore interface {
+	Save(ctx context.Context, tokenHash []byte, userID uuid.UUID, expiresAt time.Time) error
+	// Consume marks the token used and returns its user. Unknown, used or expired tokens yield apperr.ErrResetTokenInvalid.
+	Consume(ctx context.Context, tokenHash []byte, now time.Time) (uuid.UUID, error)
+	// DeleteByUser drops every outstanding token of the user (after a successful reset).
+	DeleteByUser(ctx context.Context, userID uuid.UUID) error
+}
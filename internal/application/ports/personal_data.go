@@ -0,0 +1,33 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// PersonalDataExporter contributes one section to a user's personal data export (GDPR Article 20).
+// Every module that stores records about users should register one, so the export stays complete.
+type PersonalDataExporter interface {
+	// Section is the key of this exporter's data in the export document; keys must be unique.
+	Section() string
+	// Export returns the JSON-serializable records held about userID (nil or empty when there are none).
+	// Secrets such as password hashes, TOTP seeds or key hashes must be left out.
+	Export(ctx context.Context, userID uuid.UUID) (any, error)
+}
+
+// PersonalDataExportFunc adapts a function to the PersonalDataExporter interface under the given section name.
+func PersonalDataExportFunc(section string, fn func(ctx context.Context, userID uuid.UUID) (any, error)) PersonalDataExporter {
+	return personalDataExportFunc{section: section, fn: fn}
+}
+
+type personalDataExportFunc struct {
+	section string
+	fn      func(ctx context.Context, userID uuid.UUID) (any, error)
+}
+
+func (f personalDataExportFunc) Section() string { return f.section }
+
+func (f personalDataExportFunc) Export(ctx context.Context, userID uuid.UUID) (any, error) {
+	return f.fn(ctx, userID)
+}
@@ -0,0 +1,223 @@
+package portstest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+)
+
+// ObjectStorageFactory returns the storage under test. Stores may be shared between subtests: every subtest
+// works below its own random key prefix. Presigned URLs must be reachable with http.DefaultClient.
+type ObjectStorageFactory func(t *testing.T) ports.ObjectStorage
+
+// LargeObjectSize is the size of the object the suite uploads to exercise chunked (multipart) uploads.
+const LargeObjectSize = 11<<20 + 123
+
+// TestObjectStorage runs the ObjectStorage conformance suite.
+func TestObjectStorage(t *testing.T, factory ObjectStorageFactory) {
+	t.Run("PutGetStatDelete", func(t *testing.T) {
+		store, prefix := factory(t), randomPrefix(t)
+		ctx := context.Background()
+		key := prefix + "docs/hello world.txt"
+		mustPut(t, store, key, []byte("hello"), "text/plain")
+		if got := mustGet(t, store, key); string(got) != "hello" {
+			t.Fatalf("get: %q", got)
+		}
+		info, err := store.Stat(ctx, key)
+		if err != nil || info.Key != key || info.Size != 5 || info.ContentType != "text/plain" || info.ETag == "" {
+			t.Fatalf("stat: %+v err=%v", info, err)
+		}
+		if d := time.Since(info.ModTime); d > time.Minute || d < -time.Minute {
+			t.Fatalf("stat: mod time %s is not recent", info.ModTime)
+		}
+		mustPut(t, store, key, []byte("replaced"), "text/plain")
+		if got := mustGet(t, store, key); string(got) != "replaced" {
+			t.Fatalf("get after overwrite: %q", got)
+		}
+		if err := store.Delete(ctx, key); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if _, err := store.Get(ctx, key); !errors.Is(err, apperr.ErrObjectNotFound) {
+			t.Fatalf("get after delete: got %v, want ErrObjectNotFound", err)
+		}
+		if _, err := store.Stat(ctx, key); !errors.Is(err, apperr.ErrObjectNotFound) {
+			t.Fatalf("stat after delete: got %v, want ErrObjectNotFound", err)
+		}
+		if err := store.Delete(ctx, key); err != nil {
+			t.Fatalf("delete missing object: %v", err)
+		}
+	})
+
+	t.Run("SizeHandling", func(t *testing.T) {
+		store, prefix := factory(t), randomPrefix(t)
+		ctx := context.Background()
+		if _, err := store.Put(ctx, prefix+"unknown", strings.NewReader("abc"), -1, ""); err != nil {
+			t.Fatalf("put with unknown size: %v", err)
+		}
+		if info, err := store.Stat(ctx, prefix+"unknown"); err != nil || info.Size != 3 {
+			t.Fatalf("stat: %+v err=%v", info, err)
+		}
+		if _, err := store.Put(ctx, prefix+"short", strings.NewReader("abc"), 10, ""); err == nil {
+			t.Fatalf("expected a size mismatch to fail")
+		}
+		if _, err := store.Stat(ctx, prefix+"short"); !errors.Is(err, apperr.ErrObjectNotFound) {
+			t.Fatalf("expected no object after a failed put, got %v", err)
+		}
+		mustPut(t, store, prefix+"empty", nil, "")
+		if got := mustGet(t, store, prefix+"empty"); len(got) != 0 {
+			t.Fatalf("expected an empty object, got %d bytes", len(got))
+		}
+	})
+
+	t.Run("LargeObject", func(t *testing.T) {
+		store, prefix := factory(t), randomPrefix(t)
+		data := make([]byte, LargeObjectSize)
+		_, _ = rand.Read(data)
+		// A reader without Len/Seek, like a network stream
+		if _, err := store.Put(context.Background(), prefix+"large.bin", struct{ io.Reader }{bytes.NewReader(data)}, int64(len(data)), "application/octet-stream"); err != nil {
+			t.Fatalf("put large: %v", err)
+		}
+		if got := mustGet(t, store, prefix+"large.bin"); !bytes.Equal(got, data) {
+			t.Fatalf("large object differs after round trip (%d bytes back)", len(got))
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		store, prefix := factory(t), randomPrefix(t)
+		for _, k := range []string{"p/b/c", "p/a", "p/bz", "q"} {
+			mustPut(t, store, prefix+k, []byte(k), "")
+		}
+		assertKeys := func(listPrefix string, want ...string) {
+			t.Helper()
+			got, err := store.List(context.Background(), prefix+listPrefix)
+			if err != nil {
+				t.Fatalf("list %q: %v", listPrefix, err)
+			}
+			var keys []string
+			for _, o := range got {
+				keys = append(keys, strings.TrimPrefix(o.Key, prefix))
+				if o.Size != int64(len(strings.TrimPrefix(o.Key, prefix))) {
+					t.Fatalf("list %q: wrong size for %+v", listPrefix, o)
+				}
+			}
+			if strings.Join(keys, ",") != strings.Join(want, ",") {
+				t.Fatalf("list %q: got %v, want %v", listPrefix, keys, want)
+			}
+		}
+		assertKeys("p/", "p/a", "p/b/c", "p/bz")
+		assertKeys("p/b", "p/b/c", "p/bz")
+		assertKeys("", "p/a", "p/b/c", "p/bz", "q")
+		assertKeys("nothing/")
+	})
+
+	t.Run("InvalidKeys", func(t *testing.T) {
+		store := factory(t)
+		ctx := context.Background()
+		for _, key := range []string{"", "/abs", "../up", "a/../../b", "a//b", "a/./b", `a\b`, "dir/", "a\x00b"} {
+			if _, err := store.Put(ctx, key, strings.NewReader("x"), 1, ""); !errors.Is(err, apperr.ErrInvalidObjectKey) {
+				t.Fatalf("put %q: got %v, want ErrInvalidObjectKey", key, err)
+			}
+			if _, err := store.Get(ctx, key); !errors.Is(err, apperr.ErrInvalidObjectKey) {
+				t.Fatalf("get %q: got %v, want ErrInvalidObjectKey", key, err)
+			}
+			if _, err := store.Stat(ctx, key); !errors.Is(err, apperr.ErrInvalidObjectKey) {
+				t.Fatalf("stat %q: got %v, want ErrInvalidObjectKey", key, err)
+			}
+			if err := store.Delete(ctx, key); !errors.Is(err, apperr.ErrInvalidObjectKey) {
+				t.Fatalf("delete %q: got %v, want ErrInvalidObjectKey", key, err)
+			}
+			if _, err := store.PresignGet(ctx, key, time.Minute); !errors.Is(err, apperr.ErrInvalidObjectKey) {
+				t.Fatalf("presign %q: got %v, want ErrInvalidObjectKey", key, err)
+			}
+		}
+	})
+
+	t.Run("PresignedURLs", func(t *testing.T) {
+		store, prefix := factory(t), randomPrefix(t)
+		ctx := context.Background()
+		key := prefix + "shared/report 1.csv"
+		mustPut(t, store, key, []byte("a,b\n"), "text/csv")
+		getURL, err := store.PresignGet(ctx, key, time.Minute)
+		if err != nil {
+			t.Fatalf("presign get: %v", err)
+		}
+		status, body := httpDo(t, http.MethodGet, getURL, nil)
+		if status != http.StatusOK || body != "a,b\n" {
+			t.Fatalf("download: status=%d body=%q", status, body)
+		}
+
+		upKey := prefix + "uploads/new.txt"
+		putURL, err := store.PresignPut(ctx, upKey, time.Minute)
+		if err != nil {
+			t.Fatalf("presign put: %v", err)
+		}
+		if status, body := httpDo(t, http.MethodPut, putURL, []byte("uploaded")); status != http.StatusOK {
+			t.Fatalf("upload: status=%d body=%q", status, body)
+		}
+		if got := mustGet(t, store, upKey); string(got) != "uploaded" {
+			t.Fatalf("get uploaded: %q", got)
+		}
+		// A download URL does not authorize uploads
+		if status, _ := httpDo(t, http.MethodPut, getURL, []byte("overwrite")); status < 400 {
+			t.Fatalf("expected PUT with a GET URL to be refused, got %d", status)
+		}
+		if got := mustGet(t, store, key); string(got) != "a,b\n" {
+			t.Fatalf("object changed through a download URL: %q", got)
+		}
+	})
+}
+
+func randomPrefix(t *testing.T) string {
+	t.Helper()
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return "conformance-" + hex.EncodeToString(b) + "/"
+}
+
+func mustPut(t *testing.T, store ports.ObjectStorage, key string, data []byte, contentType string) {
+	t.Helper()
+	if _, err := store.Put(context.Background(), key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		t.Fatalf("put %q: %v", key, err)
+	}
+}
+
+func mustGet(t *testing.T, store ports.ObjectStorage, key string) []byte {
+	t.Helper()
+	rc, err := store.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("get %q: %v", key, err)
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("read %q: %v", key, err)
+	}
+	return b
+}
+
+func httpDo(t *testing.T, method, url string, body []byte) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(b)
+}
@@ -0,0 +1,219 @@
+// Package portstest provides conformance suites that every implementation of an application port must pass.
+// Adapters call them from their own tests (unit tests for in-process stores, integration tests for Redis/Postgres).
+package portstest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+)
+
+// RefreshStoreFactory returns a fresh store and a function producing user IDs the store accepts
+// (stores with foreign keys must create the user rows).
+type RefreshStoreFactory func(t *testing.T) (store ports.RefreshTokenStore, newUserID func() string)
+
+// TestRefreshTokenStore runs the RefreshTokenStore conformance suite.
+// It sleeps just over a second to observe TTL expiry.
+func TestRefreshTokenStore(t *testing.T, factory RefreshStoreFactory) {
+	t.Run("IssueValidate", func(t *testing.T) {
+		store, newUser := factory(t)
+		ctx := context.Background()
+		uid := newUser()
+		tok := mustIssue(t, store, uid, 60, ports.SessionMeta{})
+		if got, err := store.Validate(ctx, tok); err != nil || got != uid {
+			t.Fatalf("validate: uid=%q err=%v, want %q", got, err, uid)
+		}
+	})
+
+	t.Run("UnknownToken", func(t *testing.T) {
+		store, _ := factory(t)
+		ctx := context.Background()
+		if _, err := store.Validate(ctx, "unknown-token"); !errors.Is(err, apperr.ErrInvalidRefreshToken) {
+			t.Fatalf("validate: got %v, want ErrInvalidRefreshToken", err)
+		}
+		if err := store.Revoke(ctx, "unknown-token"); !errors.Is(err, apperr.ErrInvalidRefreshToken) {
+			t.Fatalf("revoke: got %v, want ErrInvalidRefreshToken", err)
+		}
+		if _, _, err := store.Rotate(ctx, "unknown-token", 60, ports.SessionMeta{}); !errors.Is(err, apperr.ErrInvalidRefreshToken) {
+			t.Fatalf("rotate: got %v, want ErrInvalidRefreshToken", err)
+		}
+	})
+
+	t.Run("RotateReplacesToken", func(t *testing.T) {
+		store, newUser := factory(t)
+		ctx := context.Background()
+		uid := newUser()
+		old := mustIssue(t, store, uid, 60, ports.SessionMeta{})
+		next, got, err := store.Rotate(ctx, old, 60, ports.SessionMeta{})
+		if err != nil || got != uid || next == "" || next == old {
+			t.Fatalf("rotate: token=%q uid=%q err=%v", next, got, err)
+		}
+		if _, err := store.Validate(ctx, old); err == nil {
+			t.Fatalf("old token still valid after rotation")
+		}
+		if got, err := store.Validate(ctx, next); err != nil || got != uid {
+			t.Fatalf("validate new token: uid=%q err=%v", got, err)
+		}
+	})
+
+	t.Run("ReuseRevokesFamily", func(t *testing.T) {
+		store, newUser := factory(t)
+		ctx := context.Background()
+		uid := newUser()
+		old := mustIssue(t, store, uid, 60, ports.SessionMeta{})
+		next, _, err := store.Rotate(ctx, old, 60, ports.SessionMeta{})
+		if err != nil {
+			t.Fatalf("rotate: %v", err)
+		}
+		if _, _, err := store.Rotate(ctx, old, 60, ports.SessionMeta{}); !errors.Is(err, apperr.ErrRefreshTokenReused) {
+			t.Fatalf("replay: got %v, want ErrRefreshTokenReused", err)
+		}
+		if _, err := store.Validate(ctx, next); err == nil {
+			t.Fatalf("family member still valid after reuse")
+		}
+		if sessions := mustList(t, store, uid); len(sessions) != 0 {
+			t.Fatalf("sessions after reuse = %d, want 0", len(sessions))
+		}
+	})
+
+	t.Run("ConcurrentRotateSingleWinner", func(t *testing.T) {
+		store, newUser := factory(t)
+		ctx := context.Background()
+		tok := mustIssue(t, store, newUser(), 60, ports.SessionMeta{})
+		const workers = 8
+		var (
+			wg   sync.WaitGroup
+			mu   sync.Mutex
+			wins int
+		)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, _, err := store.Rotate(ctx, tok, 60, ports.SessionMeta{}); err == nil {
+					mu.Lock()
+					wins++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		if wins != 1 {
+			t.Fatalf("successful concurrent rotations = %d, want 1", wins)
+		}
+	})
+
+	t.Run("RevokeEndsSession", func(t *testing.T) {
+		store, newUser := factory(t)
+		ctx := context.Background()
+		uid := newUser()
+		tok := mustIssue(t, store, uid, 60, ports.SessionMeta{})
+		if err := store.Revoke(ctx, tok); err != nil {
+			t.Fatalf("revoke: %v", err)
+		}
+		if _, err := store.Validate(ctx, tok); err == nil {
+			t.Fatalf("token still valid after revoke")
+		}
+		if err := store.Revoke(ctx, tok); !errors.Is(err, apperr.ErrInvalidRefreshToken) {
+			t.Fatalf("second revoke: got %v, want ErrInvalidRefreshToken", err)
+		}
+		if sessions := mustList(t, store, uid); len(sessions) != 0 {
+			t.Fatalf("sessions after revoke = %d, want 0", len(sessions))
+		}
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		store, newUser := factory(t)
+		ctx := context.Background()
+		uid := newUser()
+		tok := mustIssue(t, store, uid, 1, ports.SessionMeta{})
+		time.Sleep(1100 * time.Millisecond)
+		if _, err := store.Validate(ctx, tok); err == nil {
+			t.Fatalf("token still valid after TTL")
+		}
+		if _, _, err := store.Rotate(ctx, tok, 60, ports.SessionMeta{}); err == nil {
+			t.Fatalf("expired token rotated")
+		}
+		if sessions := mustList(t, store, uid); len(sessions) != 0 {
+			t.Fatalf("sessions after TTL = %d, want 0", len(sessions))
+		}
+	})
+
+	t.Run("Sessions", func(t *testing.T) {
+		store, newUser := factory(t)
+		ctx := context.Background()
+		uid, other := newUser(), newUser()
+		laptop := mustIssue(t, store, uid, 60, ports.SessionMeta{IP: "10.0.0.1", UserAgent: "ua/1", DeviceLabel: "laptop"})
+		phone := mustIssue(t, store, uid, 60, ports.SessionMeta{DeviceLabel: "phone"})
+		mustIssue(t, store, other, 60, ports.SessionMeta{})
+
+		sessions := mustList(t, store, uid)
+		if len(sessions) != 2 {
+			t.Fatalf("sessions = %d, want 2", len(sessions))
+		}
+		byDevice := map[string]ports.RefreshSession{}
+		for _, s := range sessions {
+			if s.ID == "" || s.UserID != uid || s.ExpiresAt.IsZero() {
+				t.Fatalf("incomplete session: %+v", s)
+			}
+			byDevice[s.DeviceLabel] = s
+		}
+		if s := byDevice["laptop"]; s.IP != "10.0.0.1" || s.UserAgent != "ua/1" {
+			t.Fatalf("laptop metadata not stored: %+v", s)
+		}
+
+		// Rotation keeps the session and refreshes its metadata
+		if _, _, err := store.Rotate(ctx, laptop, 60, ports.SessionMeta{IP: "10.0.0.2"}); err != nil {
+			t.Fatalf("rotate: %v", err)
+		}
+		for _, s := range mustList(t, store, uid) {
+			if s.DeviceLabel == "laptop" && (s.ID != byDevice["laptop"].ID || s.IP != "10.0.0.2") {
+				t.Fatalf("rotated session: %+v", s)
+			}
+		}
+
+		phoneID := byDevice["phone"].ID
+		if err := store.RevokeSession(ctx, other, phoneID); !errors.Is(err, apperr.ErrSessionNotFound) {
+			t.Fatalf("revoke foreign session: got %v, want ErrSessionNotFound", err)
+		}
+		if err := store.RevokeSession(ctx, uid, phoneID); err != nil {
+			t.Fatalf("revoke session: %v", err)
+		}
+		if _, err := store.Validate(ctx, phone); err == nil {
+			t.Fatalf("phone token still valid after session revoke")
+		}
+
+		if err := store.RevokeAll(ctx, uid); err != nil {
+			t.Fatalf("revoke all: %v", err)
+		}
+		if sessions := mustList(t, store, uid); len(sessions) != 0 {
+			t.Fatalf("sessions after revoke all = %d, want 0", len(sessions))
+		}
+		if sessions := mustList(t, store, other); len(sessions) != 1 {
+			t.Fatalf("other user's sessions = %d, want 1", len(sessions))
+		}
+	})
+}
+
+func mustIssue(t *testing.T, store ports.RefreshTokenStore, userID string, ttl int, meta ports.SessionMeta) string {
+	t.Helper()
+	tok, err := store.Issue(context.Background(), userID, ttl, meta)
+	if err != nil || tok == "" {
+		t.Fatalf("issue: token=%q err=%v", tok, err)
+	}
+	return tok
+}
+
+func mustList(t *testing.T, store ports.RefreshTokenStore, userID string) []ports.RefreshSession {
+	t.Helper()
+	sessions, err := store.ListByUser(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("list sessions: %v", err)
+	}
+	return sessions
+}
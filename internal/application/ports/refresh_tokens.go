@@ -1,15 +1,46 @@
 package ports
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// SessionMeta describes the client a refresh token was issued to or last used from.
+type SessionMeta struct {
+	IP          string
+	UserAgent   string
+	DeviceLabel string
+}
+
+// RefreshSession is one login session (a refresh-token family) as listed to its owner.
+type RefreshSession struct {
+	ID          string
+	UserID      string
+	CreatedAt   time.Time
+	LastUsedAt  time.Time
+	ExpiresAt   time.Time
+	IP          string
+	UserAgent   string
+	DeviceLabel string
+}
 
 // RefreshTokenStore abstracts storing and rotating refresh tokens per user/session.
 type RefreshTokenStore interface {
 	// Issue creates a new refresh token for a user and returns the token string.
-	Issue(ctx context.Context, userID string, ttlSeconds int) (string, error)
-	// Rotate invalidates the old token and issues a new one atomically.
-	Rotate(ctx context.Context, oldToken string, ttlSeconds int) (newToken string, userID string, err error)
+	// Each issued token starts a new token family (one per login) which is the user's session.
+	Issue(ctx context.Context, userID string, ttlSeconds int, meta SessionMeta) (string, error)
+	// Rotate invalidates the old token and issues a new one in the same family atomically,
+	// refreshing the session's last-used time and client info.
+	// Presenting a token that was already rotated revokes the whole family and returns apperr.ErrRefreshTokenReused.
+	Rotate(ctx context.Context, oldToken string, ttlSeconds int, meta SessionMeta) (newToken string, userID string, err error)
 	// Revoke invalidates a specific token.
 	Revoke(ctx context.Context, token string) error
 	// Validate returns the userID if the token is valid (not revoked/expired).
 	Validate(ctx context.Context, token string) (userID string, err error)
+	// ListByUser returns the user's active sessions, most recently used first.
+	ListByUser(ctx context.Context, userID string) ([]RefreshSession, error)
+	// RevokeSession ends one session of the user; apperr.ErrSessionNotFound if it does not exist or belongs to someone else.
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	// RevokeAll ends every session of the user.
+	RevokeAll(ctx context.Context, userID string) error
 }
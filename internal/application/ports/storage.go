@@ -3,11 +3,58 @@ package ports
 import (
 	"context"
 	"io"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"gostartkit/internal/application/apperr"
 )
 
-// ObjectStorage abstracts object storage operations.
+// ObjectStorage abstracts object storage operations. Keys are slash-separated relative paths
+// (see CheckObjectKey); Get and Stat return apperr.ErrObjectNotFound for missing objects.
 type ObjectStorage interface {
+	// Put stores r under key, replacing any existing object. size is the exact length, or -1 when unknown.
+	// The returned URL addresses the object but needs credentials; hand out PresignGet URLs instead.
 	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (url string, err error)
 	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes key; deleting a missing object is not an error.
 	Delete(ctx context.Context, key string) error
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// List returns the objects whose key starts with prefix, sorted by key.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// PresignGet returns a URL that downloads key without credentials until ttl has passed.
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (url string, err error)
+	// PresignPut returns a URL that accepts HTTP PUT uploads of key's content without credentials until ttl has passed.
+	PresignPut(ctx context.Context, key string, ttl time.Duration) (url string, err error)
+}
+
+// ObjectInfo describes a stored object. ContentType may be empty in List results.
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+	ETag        string
+}
+
+// MaxObjectKeyLen is the longest key accepted, in bytes (the S3 limit).
+const MaxObjectKeyLen = 1024
+
+// CheckObjectKey returns apperr.ErrInvalidObjectKey unless key is a clean relative path: valid UTF-8 of at
+// most MaxObjectKeyLen bytes without control characters or backslashes, no leading or trailing slash, and
+// no empty, "." or ".." segments. Every backend enforces it, so keys behave the same everywhere.
+func CheckObjectKey(key string) error {
+	if key == "" || len(key) > MaxObjectKeyLen || !utf8.ValidString(key) || strings.ContainsRune(key, '\\') {
+		return apperr.ErrInvalidObjectKey
+	}
+	if strings.IndexFunc(key, unicode.IsControl) >= 0 {
+		return apperr.ErrInvalidObjectKey
+	}
+	for _, seg := range strings.Split(key, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return apperr.ErrInvalidObjectKey
+		}
+	}
+	return nil
 }
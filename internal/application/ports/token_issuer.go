@@ -1,6 +1,33 @@
 package ports
 
+import "time"
+
 // TokenIssuer abstracts token issuance for application layer
 type TokenIssuer interface {
 	GenerateToken(userID string, role string) (string, error)
 }
+
+// ClientTokenIssuer issues access tokens for service principals (OAuth2 client_credentials).
+// The tokens carry a client_id claim and scopes instead of a user role.
+type ClientTokenIssuer interface {
+	GenerateClientToken(clientID string, scopes []string, ttl time.Duration) (string, error)
+}
+
+// AccessTokenInfo is what the issuer knows about a valid access token (used for RFC 7662 introspection).
+type AccessTokenInfo struct {
+	ID        string // jti
+	Subject   string
+	Role      string
+	ClientID  string // set for service (client_credentials) tokens
+	Scopes    []string
+	Issuer    string
+	Audience  []string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// AccessTokenVerifier checks signature, lifetime, issuer and audience of an access token.
+// It does not consult the revocation denylist.
+type AccessTokenVerifier interface {
+	VerifyAccessToken(token string) (AccessTokenInfo, error)
+}
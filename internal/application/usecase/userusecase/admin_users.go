@@ -0,0 +1,319 @@
+package userusecase
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// UserAdmin is account management for administrators. actorID is the administrator making the change: nobody
+// can disable, delete or demote themselves, or the last enabled administrator.
+type UserAdmin interface {
+	List(ctx context.Context, input dto.ListUsersRequest) (*dto.AdminUserPage, error)
+	Get(ctx context.Context, userID string) (*dto.AdminUserResponse, error)
+	Update(ctx context.Context, actorID, userID string, input dto.AdminUpdateUserRequest) (*dto.AdminUserResponse, error)
+	Disable(ctx context.Context, actorID, userID string) (*dto.AdminUserResponse, error)
+	Enable(ctx context.Context, userID string) (*dto.AdminUserResponse, error)
+	Delete(ctx context.Context, actorID, userID string) error
+	// Erase anonymizes a user's personal data (GDPR erasure); it also works on deleted users
+	Erase(ctx context.Context, userID string) (*dto.AdminUserResponse, error)
+}
+
+const (
+	defaultUserPageSize = 50
+	maxUserPageSize     = 200
+)
+
+// UserAdminUseCase implements UserAdmin. Changes that must take effect at once (disable, delete, role change)
+// also cut off the user's tokens: refresh sessions through store and access tokens through revocations.
+type UserAdminUseCase struct {
+	repo        user.Repository
+	store       ports.RefreshTokenStore
+	revocations ports.AccessTokenRevocationStore
+	// accessTTL is the longest an access token stays valid; denylist entries never need to outlive it
+	accessTTL time.Duration
+}
+
+// NewUserAdminUseCase accepts nil store and revocations (refresh tokens or the denylist disabled).
+func NewUserAdminUseCase(repo user.Repository, store ports.RefreshTokenStore, revocations ports.AccessTokenRevocationStore, accessTTL time.Duration) *UserAdminUseCase {
+	return &UserAdminUseCase{repo: repo, store: store, revocations: revocations, accessTTL: accessTTL}
+}
+
+func (uc *UserAdminUseCase) List(ctx context.Context, input dto.ListUsersRequest) (*dto.AdminUserPage, error) {
+	filter := user.ListFilter{
+		Role:           user.Role(strings.TrimSpace(input.Role)),
+		EmailPrefix:    strings.TrimSpace(input.EmailPrefix),
+		Limit:          input.Limit,
+		IncludeDeleted: input.IncludeDeleted,
+	}
+	if filter.Role != "" && !filter.Role.IsValid() {
+		return nil, user.ErrInvalidRole
+	}
+	if input.CreatedFrom != nil {
+		filter.CreatedFrom = *input.CreatedFrom
+	}
+	if input.CreatedTo != nil {
+		filter.CreatedTo = *input.CreatedTo
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultUserPageSize
+	}
+	filter.Limit = min(filter.Limit, maxUserPageSize)
+	if input.Cursor != "" {
+		pos, err := decodeUserCursor(input.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = &pos
+	}
+
+	// One extra row tells whether another page follows
+	want := filter.Limit
+	filter.Limit++
+	users, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	page := &dto.AdminUserPage{Users: make([]dto.AdminUserResponse, 0, min(len(users), want)), Page: dto.PageMeta{Limit: want}}
+	if len(users) > want {
+		users = users[:want]
+		last := users[want-1]
+		page.Page.HasMore = true
+		page.Page.NextCursor = encodeUserCursor(user.ListPosition{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	for _, u := range users {
+		page.Users = append(page.Users, adminUserResponse(u))
+	}
+	return page, nil
+}
+
+func (uc *UserAdminUseCase) Get(ctx context.Context, userID string) (*dto.AdminUserResponse, error) {
+	u, err := uc.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := adminUserResponse(u)
+	return &resp, nil
+}
+
+func (uc *UserAdminUseCase) Update(ctx context.Context, actorID, userID string, input dto.AdminUpdateUserRequest) (*dto.AdminUserResponse, error) {
+	u, err := uc.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	roleChanged := false
+	if input.FirstName != nil {
+		name := strings.TrimSpace(*input.FirstName)
+		if name == "" {
+			return nil, user.ErrInvalidFirstName
+		}
+		u.FirstName = name
+	}
+	if input.LastName != nil {
+		name := strings.TrimSpace(*input.LastName)
+		if name == "" {
+			return nil, user.ErrInvalidLastName
+		}
+		u.LastName = name
+	}
+	if input.Role != nil {
+		role := user.Role(strings.TrimSpace(*input.Role))
+		if !role.IsValid() {
+			return nil, user.ErrInvalidRole
+		}
+		if u.Role == user.RoleAdmin && role != user.RoleAdmin {
+			if err := uc.guardAdminRemoval(ctx, actorID, u); err != nil {
+				return nil, err
+			}
+		}
+		roleChanged = role != u.Role
+		u.Role = role
+	}
+	now := time.Now()
+	u.UpdatedAt = now.UTC()
+	if err := uc.repo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+	// Access tokens carry the role, so outstanding ones must not keep the old permissions.
+	// Refresh sessions stay: refreshed tokens read the new role from the database.
+	if roleChanged && uc.revocations != nil {
+		if err := uc.revocations.RevokeUser(ctx, u.ID.String(), now, uc.accessTTL); err != nil {
+			return nil, err
+		}
+	}
+	resp := adminUserResponse(u)
+	return &resp, nil
+}
+
+func (uc *UserAdminUseCase) Disable(ctx context.Context, actorID, userID string) (*dto.AdminUserResponse, error) {
+	u, err := uc.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.guardAdminRemoval(ctx, actorID, u); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	u.Disable(now)
+	if err := uc.repo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+	if err := uc.signOutEverywhere(ctx, u.ID, now); err != nil {
+		return nil, err
+	}
+	resp := adminUserResponse(u)
+	return &resp, nil
+}
+
+func (uc *UserAdminUseCase) Enable(ctx context.Context, userID string) (*dto.AdminUserResponse, error) {
+	u, err := uc.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.IsDisabled() {
+		u.Enable(time.Now())
+		if err := uc.repo.Update(ctx, u); err != nil {
+			return nil, err
+		}
+	}
+	resp := adminUserResponse(u)
+	return &resp, nil
+}
+
+// Delete soft-deletes the user first and revokes their tokens after, so a failed delete leaves them signed in.
+func (uc *UserAdminUseCase) Delete(ctx context.Context, actorID, userID string) error {
+	u, err := uc.load(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := uc.guardAdminRemoval(ctx, actorID, u); err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := uc.repo.Delete(ctx, u.ID); err != nil {
+		return err
+	}
+	return uc.signOutEverywhere(ctx, u.ID, now)
+}
+
+// Erase keeps the user ID, role and timestamps so references stay valid; everything identifying is replaced
+// (see user.User.Erase). Erasing twice is a no-op.
+func (uc *UserAdminUseCase) Erase(ctx context.Context, userID string) (*dto.AdminUserResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, user.ErrInvalidID
+	}
+	u, err := uc.repo.GetByIDIncludingDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !u.IsErased() {
+		now := time.Now()
+		u.Erase(now)
+		if err := uc.repo.Erase(ctx, u); err != nil {
+			return nil, err
+		}
+		if err := uc.signOutEverywhere(ctx, u.ID, now); err != nil {
+			return nil, err
+		}
+	}
+	resp := adminUserResponse(u)
+	return &resp, nil
+}
+
+func (uc *UserAdminUseCase) load(ctx context.Context, userID string) (*user.User, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, user.ErrInvalidID
+	}
+	return uc.repo.GetByID(ctx, id)
+}
+
+// guardAdminRemoval refuses to let actorID take u's access away (disable, delete, demote) when u is the actor
+// or the last enabled administrator.
+func (uc *UserAdminUseCase) guardAdminRemoval(ctx context.Context, actorID string, u *user.User) error {
+	if u.ID.String() == actorID {
+		return apperr.ErrSelfAdminChange
+	}
+	if u.Role != user.RoleAdmin || u.IsDisabled() {
+		return nil
+	}
+	filter := user.ListFilter{Role: user.RoleAdmin, Limit: maxUserPageSize}
+	for {
+		admins, err := uc.repo.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+		for _, a := range admins {
+			if a.ID != u.ID && !a.IsDisabled() {
+				return nil
+			}
+		}
+		if len(admins) < filter.Limit {
+			return apperr.ErrLastAdmin
+		}
+		last := admins[len(admins)-1]
+		filter.After = &user.ListPosition{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+// signOutEverywhere ends refresh sessions and denylists access tokens issued before now.
+func (uc *UserAdminUseCase) signOutEverywhere(ctx context.Context, id uuid.UUID, now time.Time) error {
+	if uc.store != nil {
+		if err := uc.store.RevokeAll(ctx, id.String()); err != nil {
+			return err
+		}
+	}
+	if uc.revocations != nil {
+		return uc.revocations.RevokeUser(ctx, id.String(), now, uc.accessTTL)
+	}
+	return nil
+}
+
+// encodeUserCursor packs a list position into an opaque URL-safe token.
+func encodeUserCursor(pos user.ListPosition) string {
+	raw := pos.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + pos.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeUserCursor(cursor string) (user.ListPosition, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return user.ListPosition{}, apperr.ErrInvalidCursor
+	}
+	ts, rawID, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return user.ListPosition{}, apperr.ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return user.ListPosition{}, apperr.ErrInvalidCursor
+	}
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return user.ListPosition{}, apperr.ErrInvalidCursor
+	}
+	return user.ListPosition{CreatedAt: createdAt, ID: id}, nil
+}
+
+func adminUserResponse(u *user.User) dto.AdminUserResponse {
+	return dto.AdminUserResponse{
+		UserResponse: userResponse(u),
+		Role:         string(u.Role),
+		UpdatedAt:    u.UpdatedAt,
+		Disabled:     u.IsDisabled(),
+		DisabledAt:   u.DisabledAt,
+		DeletedAt:    u.DeletedAt,
+		ErasedAt:     u.ErasedAt,
+	}
+}
+
+var _ UserAdmin = (*UserAdminUseCase)(nil)
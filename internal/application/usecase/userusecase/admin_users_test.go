@@ -0,0 +1,328 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	domuser "gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// listingRepo keeps users in memory and applies ListFilter the way the Postgres query does.
+type listingRepo struct {
+	fakeRepo
+	users []*domuser.User
+}
+
+func (r *listingRepo) GetByID(ctx context.Context, id uuid.UUID) (*domuser.User, error) {
+	u, err := r.GetByIDIncludingDeleted(ctx, id)
+	if err == nil && u.IsDeleted() {
+		return nil, domuser.ErrUserNotFound
+	}
+	return u, err
+}
+
+func (r *listingRepo) GetByIDIncludingDeleted(_ context.Context, id uuid.UUID) (*domuser.User, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, domuser.ErrUserNotFound
+}
+
+func (r *listingRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	u, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	u.DeletedAt = &now
+	return nil
+}
+
+func (r *listingRepo) Erase(context.Context, *domuser.User) error { return nil }
+
+func (r *listingRepo) List(_ context.Context, f domuser.ListFilter) ([]*domuser.User, error) {
+	sorted := append([]*domuser.User(nil), r.users...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return newerThan(sorted[i].CreatedAt, sorted[i].ID, sorted[j].CreatedAt, sorted[j].ID)
+	})
+	var out []*domuser.User
+	for _, u := range sorted {
+		if u.IsDeleted() && !f.IncludeDeleted {
+			continue
+		}
+		if f.Role != "" && u.Role != f.Role {
+			continue
+		}
+		if f.EmailPrefix != "" && !strings.HasPrefix(u.Email.String(), strings.ToLower(f.EmailPrefix)) {
+			continue
+		}
+		if f.After != nil && !newerThan(f.After.CreatedAt, f.After.ID, u.CreatedAt, u.ID) {
+			continue
+		}
+		out = append(out, u)
+		if len(out) == f.Limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (r *listingRepo) Update(context.Context, *domuser.User) error { return nil }
+
+func newerThan(at time.Time, id uuid.UUID, otherAt time.Time, otherID uuid.UUID) bool {
+	if !at.Equal(otherAt) {
+		return at.After(otherAt)
+	}
+	return id.String() > otherID.String()
+}
+
+func seedUsers(n int) *listingRepo {
+	repo := &listingRepo{}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		role := domuser.RoleUser
+		if i%2 == 0 {
+			role = domuser.RoleAdmin
+		}
+		repo.users = append(repo.users, &domuser.User{
+			ID: uuid.New(), FirstName: "User", LastName: "Test", Email: domuser.Email("user" + string(rune('a'+i)) + "@example.com"),
+			Role: role, CreatedAt: base.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	return repo
+}
+
+func TestUserAdmin_ListPagesWithCursor(t *testing.T) {
+	uc := NewUserAdminUseCase(seedUsers(5), nil, nil, time.Minute)
+	ctx := context.Background()
+
+	first, err := uc.List(ctx, dto.ListUsersRequest{Limit: 2})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(first.Users) != 2 || !first.Page.HasMore || first.Page.NextCursor == "" {
+		t.Fatalf("expected a full first page with a cursor, got %+v", first.Page)
+	}
+	seen := map[uuid.UUID]bool{}
+	var pages int
+	for page := first; ; pages++ {
+		for _, u := range page.Users {
+			if seen[u.ID] {
+				t.Fatalf("user %s listed twice", u.ID)
+			}
+			seen[u.ID] = true
+		}
+		if !page.Page.HasMore {
+			break
+		}
+		if page, err = uc.List(ctx, dto.ListUsersRequest{Limit: 2, Cursor: page.Page.NextCursor}); err != nil {
+			t.Fatalf("next page: %v", err)
+		}
+	}
+	if len(seen) != 5 || pages != 2 {
+		t.Fatalf("expected 5 users over 3 pages, got %d users after %d more pages", len(seen), pages)
+	}
+	if first.Users[0].Email != "usere@example.com" {
+		t.Fatalf("expected newest first, got %s", first.Users[0].Email)
+	}
+}
+
+func TestUserAdmin_ListFiltersAndRejectsBadInput(t *testing.T) {
+	uc := NewUserAdminUseCase(seedUsers(5), nil, nil, time.Minute)
+	ctx := context.Background()
+
+	page, err := uc.List(ctx, dto.ListUsersRequest{Role: "admin"})
+	if err != nil || len(page.Users) != 3 || page.Page.HasMore || page.Page.Limit != defaultUserPageSize {
+		t.Fatalf("expected 3 admins on one default-size page, got %+v (%v)", page, err)
+	}
+	if _, err := uc.List(ctx, dto.ListUsersRequest{Role: "root"}); !errors.Is(err, domuser.ErrInvalidRole) {
+		t.Fatalf("expected invalid role, got %v", err)
+	}
+	if _, err := uc.List(ctx, dto.ListUsersRequest{Cursor: "not a cursor"}); !errors.Is(err, apperr.ErrInvalidCursor) {
+		t.Fatalf("expected invalid cursor, got %v", err)
+	}
+}
+
+// actorID is the administrator making changes in these tests; seedUsers never creates it.
+var actorID = uuid.NewString()
+
+func TestUserAdmin_DisableSignsOutAndBlocksLogin(t *testing.T) {
+	repo := seedUsers(2)
+	u := repo.users[1] // role user
+	store := newFakeRefreshStore()
+	revocations := &recordingRevocations{}
+	uc := NewUserAdminUseCase(repo, store, revocations, time.Minute)
+	ctx := context.Background()
+
+	token, _ := store.Issue(ctx, u.ID.String(), 60, ports.SessionMeta{})
+	resp, err := uc.Disable(ctx, actorID, u.ID.String())
+	if err != nil || !resp.Disabled || resp.DisabledAt == nil {
+		t.Fatalf("expected a disabled user, got %+v (%v)", resp, err)
+	}
+	if _, err := store.Validate(ctx, token); err == nil {
+		t.Fatalf("expected refresh sessions revoked")
+	}
+	if len(revocations.users) != 1 || revocations.users[0] != u.ID.String() {
+		t.Fatalf("expected access tokens revoked, got %v", revocations.users)
+	}
+	if _, err := issueLogin(ctx, fakeTokenIssuer{}, store, 60, u, ports.SessionMeta{}); !errors.Is(err, apperr.ErrAccountDisabled) {
+		t.Fatalf("expected disabled account refused at login, got %v", err)
+	}
+
+	if resp, err := uc.Enable(ctx, u.ID.String()); err != nil || resp.Disabled {
+		t.Fatalf("expected the user enabled again, got %+v (%v)", resp, err)
+	}
+	if _, err := issueLogin(ctx, fakeTokenIssuer{}, store, 60, u, ports.SessionMeta{}); err != nil {
+		t.Fatalf("expected login after enable, got %v", err)
+	}
+}
+
+func TestUserAdmin_UpdateRevokesOnRoleChangeOnly(t *testing.T) {
+	repo := seedUsers(2)
+	u := repo.users[1] // role user
+	revocations := &recordingRevocations{}
+	uc := NewUserAdminUseCase(repo, nil, revocations, time.Minute)
+	ctx := context.Background()
+
+	name := "Jane"
+	if _, err := uc.Update(ctx, actorID, u.ID.String(), dto.AdminUpdateUserRequest{FirstName: &name}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if len(revocations.users) != 0 {
+		t.Fatalf("expected no revocation for a profile edit")
+	}
+	role := "admin"
+	resp, err := uc.Update(ctx, actorID, u.ID.String(), dto.AdminUpdateUserRequest{Role: &role})
+	if err != nil || resp.Role != "admin" || resp.FirstName != "Jane" {
+		t.Fatalf("unexpected update result %+v (%v)", resp, err)
+	}
+	if len(revocations.users) != 1 {
+		t.Fatalf("expected access tokens revoked after a role change, got %v", revocations.users)
+	}
+	bad := "root"
+	if _, err := uc.Update(ctx, actorID, u.ID.String(), dto.AdminUpdateUserRequest{Role: &bad}); !errors.Is(err, domuser.ErrInvalidRole) {
+		t.Fatalf("expected invalid role, got %v", err)
+	}
+	if _, err := uc.Get(ctx, "nope"); !errors.Is(err, domuser.ErrInvalidID) {
+		t.Fatalf("expected invalid id, got %v", err)
+	}
+}
+
+func TestUserAdmin_DeleteHidesAndEraseAnonymizes(t *testing.T) {
+	repo := seedUsers(2)
+	u := repo.users[1] // role user
+	revocations := &recordingRevocations{}
+	uc := NewUserAdminUseCase(repo, nil, revocations, time.Minute)
+	ctx := context.Background()
+
+	if err := uc.Delete(ctx, actorID, u.ID.String()); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := uc.Get(ctx, u.ID.String()); !errors.Is(err, domuser.ErrUserNotFound) {
+		t.Fatalf("expected a deleted user to be hidden, got %v", err)
+	}
+	if page, _ := uc.List(ctx, dto.ListUsersRequest{}); len(page.Users) != 1 {
+		t.Fatalf("expected the deleted user left out of the listing, got %d users", len(page.Users))
+	}
+	if page, _ := uc.List(ctx, dto.ListUsersRequest{IncludeDeleted: true}); len(page.Users) != 2 {
+		t.Fatalf("expected include_deleted to list both users, got %d", len(page.Users))
+	}
+
+	resp, err := uc.Erase(ctx, u.ID.String())
+	if err != nil {
+		t.Fatalf("erase: %v", err)
+	}
+	if resp.ID != u.ID || resp.ErasedAt == nil || resp.DeletedAt == nil || resp.FirstName != "" {
+		t.Fatalf("expected an anonymized user with the same ID, got %+v", resp)
+	}
+	if resp.Email != domuser.TombstoneEmail(u.ID).String() || u.Password != "" || u.HasPhone() {
+		t.Fatalf("expected PII removed, got %+v", u)
+	}
+	erasedAt := *resp.ErasedAt
+	if again, err := uc.Erase(ctx, u.ID.String()); err != nil || !again.ErasedAt.Equal(erasedAt) {
+		t.Fatalf("expected a second erase to change nothing, got %+v (%v)", again, err)
+	}
+	if len(revocations.users) != 2 {
+		t.Fatalf("expected delete and erase to revoke access tokens once each, got %v", revocations.users)
+	}
+}
+
+func TestUserAdmin_RefusesSelfAndLastAdminRemoval(t *testing.T) {
+	repo := seedUsers(3) // admins 0 and 2, user 1
+	first, second := repo.users[0], repo.users[2]
+	revocations := &recordingRevocations{}
+	uc := NewUserAdminUseCase(repo, nil, revocations, time.Minute)
+	ctx := context.Background()
+	demote := "user"
+
+	if _, err := uc.Disable(ctx, first.ID.String(), first.ID.String()); !errors.Is(err, apperr.ErrSelfAdminChange) {
+		t.Fatalf("expected self-disable refused, got %v", err)
+	}
+	if err := uc.Delete(ctx, first.ID.String(), first.ID.String()); !errors.Is(err, apperr.ErrSelfAdminChange) {
+		t.Fatalf("expected self-delete refused, got %v", err)
+	}
+	if _, err := uc.Update(ctx, first.ID.String(), first.ID.String(), dto.AdminUpdateUserRequest{Role: &demote}); !errors.Is(err, apperr.ErrSelfAdminChange) {
+		t.Fatalf("expected self-demotion refused, got %v", err)
+	}
+	name := "Ada"
+	if _, err := uc.Update(ctx, first.ID.String(), first.ID.String(), dto.AdminUpdateUserRequest{FirstName: &name}); err != nil {
+		t.Fatalf("expected admins to edit their own name, got %v", err)
+	}
+
+	// With two admins one may go; the other is then the last
+	if _, err := uc.Disable(ctx, actorID, second.ID.String()); err != nil {
+		t.Fatalf("disable the second admin: %v", err)
+	}
+	if _, err := uc.Update(ctx, actorID, first.ID.String(), dto.AdminUpdateUserRequest{Role: &demote}); !errors.Is(err, apperr.ErrLastAdmin) {
+		t.Fatalf("expected demoting the last enabled admin refused, got %v", err)
+	}
+	if _, err := uc.Disable(ctx, actorID, first.ID.String()); !errors.Is(err, apperr.ErrLastAdmin) {
+		t.Fatalf("expected disabling the last enabled admin refused, got %v", err)
+	}
+	if err := uc.Delete(ctx, actorID, first.ID.String()); !errors.Is(err, apperr.ErrLastAdmin) {
+		t.Fatalf("expected deleting the last enabled admin refused, got %v", err)
+	}
+	if first.IsDisabled() || first.IsDeleted() || first.Role != domuser.RoleAdmin {
+		t.Fatalf("expected the last admin untouched, got %+v", first)
+	}
+	// A disabled admin no longer counts, so deleting it is fine
+	if err := uc.Delete(ctx, actorID, second.ID.String()); err != nil {
+		t.Fatalf("delete the disabled admin: %v", err)
+	}
+	if len(revocations.users) != 2 {
+		t.Fatalf("expected only the changes that went through to revoke tokens, got %v", revocations.users)
+	}
+}
+
+// failingDeleteRepo refuses every delete.
+type failingDeleteRepo struct{ *listingRepo }
+
+func (failingDeleteRepo) Delete(context.Context, uuid.UUID) error { return errors.New("db down") }
+
+func TestUserAdmin_DeleteRevokesOnlyAfterDeleting(t *testing.T) {
+	repo := seedUsers(2)
+	u := repo.users[1]
+	store := newFakeRefreshStore()
+	revocations := &recordingRevocations{}
+	uc := NewUserAdminUseCase(failingDeleteRepo{repo}, store, revocations, time.Minute)
+	ctx := context.Background()
+
+	token, _ := store.Issue(ctx, u.ID.String(), 60, ports.SessionMeta{})
+	if err := uc.Delete(ctx, actorID, u.ID.String()); err == nil {
+		t.Fatalf("expected the failed delete reported")
+	}
+	if _, err := store.Validate(ctx, token); err != nil || len(revocations.users) != 0 {
+		t.Fatalf("expected the user kept signed in when the delete failed, got %v, revoked %v", err, revocations.users)
+	}
+}
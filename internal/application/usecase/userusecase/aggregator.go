@@ -13,37 +13,63 @@ type UserUsecases interface {
 	Register(ctx context.Context, input dto.CreateUserRequest) (*dto.UserResponse, error)
 	Login(ctx context.Context, input dto.LoginRequest) (*dto.LoginResponse, error)
 	GetMe(ctx context.Context, userID string) (*dto.UserResponse, error)
+	UpdateMe(ctx context.Context, userID string, input dto.UpdateMeRequest) (*dto.UpdateMeResponse, error)
 	ChangePassword(ctx context.Context, userID string, input dto.ChangePasswordRequest) error
-	Refresh(ctx context.Context, refreshToken string) (*dto.LoginResponse, error)
+	Refresh(ctx context.Context, input dto.RefreshRequest) (*dto.LoginResponse, error)
 	Logout(ctx context.Context, refreshToken string) error
 }
 
 // userUsecasesAggregator is a thin wrapper delegating to concrete use cases.
 type userUsecasesAggregator struct {
-	create  *CreateUserUseCase
-	login   *LoginUserUseCase
-	getMe   *GetMeUseCase
-	change  *ChangePasswordUseCase
-	refresh *RefreshUseCase
+	create   *CreateUserUseCase
+	login    *LoginUserUseCase
+	getMe    *GetMeUseCase
+	updateMe *UpdateMeUseCase
+	change   *ChangePasswordUseCase
+	refresh  *RefreshUseCase
 }
 
 func NewUserUsecases(repo user.Repository, hasher PasswordHasher, jwt ports.TokenIssuer) UserUsecases {
 	return &userUsecasesAggregator{
-		create:  NewCreateUserUseCase(repo, hasher),
-		login:   NewLoginUserUseCase(repo, hasher, jwt, nil),
-		getMe:   NewGetMeUseCase(repo),
-		change:  NewChangePasswordUseCase(repo, hasher),
-		refresh: NewRefreshUseCase(repo, jwt),
+		create:   NewCreateUserUseCase(repo, hasher),
+		login:    NewLoginUserUseCase(repo, hasher, jwt, nil),
+		getMe:    NewGetMeUseCase(repo),
+		updateMe: NewUpdateMeUseCase(repo, hasher, nil),
+		change:   NewChangePasswordUseCase(repo, hasher),
+		refresh:  NewRefreshUseCase(repo, jwt),
 	}
 }
 
 func NewUserUsecasesWithStore(repo user.Repository, hasher PasswordHasher, jwt ports.TokenIssuer, store ports.RefreshTokenStore, refreshTTLSeconds int) UserUsecases {
+	return NewUserUsecasesWithMFA(repo, hasher, jwt, store, refreshTTLSeconds, nil)
+}
+
+// NewUserUsecasesWithMFA is NewUserUsecasesWithStore with an MFA gate on login (nil disables MFA).
+func NewUserUsecasesWithMFA(repo user.Repository, hasher PasswordHasher, jwt ports.TokenIssuer, store ports.RefreshTokenStore, refreshTTLSeconds int, mfa *MFAUseCase) UserUsecases {
+	return NewUserUsecasesWithOptions(repo, hasher, jwt, store, refreshTTLSeconds, UserUsecasesOptions{MFA: mfa})
+}
+
+// UserUsecasesOptions enables optional account features; the zero value disables all of them.
+type UserUsecasesOptions struct {
+	// MFA gates logins behind a second factor
+	MFA *MFAUseCase
+	// EmailVerification sends a verification link on registration and confirms email changes
+	EmailVerification *EmailVerificationUseCase
+	// RequireVerifiedEmail makes login refuse accounts with an unconfirmed email
+	RequireVerifiedEmail bool
+	// LoginLockout throttles and locks accounts after repeated wrong passwords
+	LoginLockout *LoginLockoutUseCase
+}
+
+func NewUserUsecasesWithOptions(repo user.Repository, hasher PasswordHasher, jwt ports.TokenIssuer, store ports.RefreshTokenStore, refreshTTLSeconds int, opts UserUsecasesOptions) UserUsecases {
 	return &userUsecasesAggregator{
-		create:  NewCreateUserUseCase(repo, hasher),
-		login:   &LoginUserUseCase{repo: repo, hasher: hasher, jwt: jwt, store: store, refreshTTLSeconds: refreshTTLSeconds},
-		getMe:   NewGetMeUseCase(repo),
-		change:  NewChangePasswordUseCase(repo, hasher),
-		refresh: NewRefreshUseCaseWithStore(repo, jwt, store, refreshTTLSeconds),
+		create: &CreateUserUseCase{repo: repo, hasher: hasher, verifier: opts.EmailVerification},
+		login: &LoginUserUseCase{repo: repo, hasher: hasher, jwt: jwt, store: store, refreshTTLSeconds: refreshTTLSeconds,
+			mfa: opts.MFA, requireVerifiedEmail: opts.RequireVerifiedEmail, lockout: opts.LoginLockout},
+		getMe:    NewGetMeUseCase(repo),
+		updateMe: NewUpdateMeUseCase(repo, hasher, opts.EmailVerification),
+		change:   NewChangePasswordUseCase(repo, hasher),
+		refresh:  NewRefreshUseCaseWithStore(repo, jwt, store, refreshTTLSeconds),
 	}
 }
 
@@ -59,12 +85,16 @@ func (u *userUsecasesAggregator) GetMe(ctx context.Context, userID string) (*dto
 	return u.getMe.Execute(ctx, userID)
 }
 
+func (u *userUsecasesAggregator) UpdateMe(ctx context.Context, userID string, input dto.UpdateMeRequest) (*dto.UpdateMeResponse, error) {
+	return u.updateMe.Execute(ctx, userID, input)
+}
+
 func (u *userUsecasesAggregator) ChangePassword(ctx context.Context, userID string, input dto.ChangePasswordRequest) error {
 	return u.change.Execute(ctx, userID, input)
 }
 
-func (u *userUsecasesAggregator) Refresh(ctx context.Context, refreshToken string) (*dto.LoginResponse, error) {
-	return u.refresh.Execute(ctx, refreshToken)
+func (u *userUsecasesAggregator) Refresh(ctx context.Context, input dto.RefreshRequest) (*dto.LoginResponse, error) {
+	return u.refresh.Execute(ctx, input)
 }
 
 func (u *userUsecasesAggregator) Logout(ctx context.Context, refreshToken string) error {
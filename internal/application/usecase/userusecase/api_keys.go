@@ -0,0 +1,261 @@
+package userusecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyUsecases lets a signed-in user manage their own API keys.
+type APIKeyUsecases interface {
+	Create(ctx context.Context, userID string, input dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error)
+	List(ctx context.Context, userID string) ([]dto.APIKeyResponse, error)
+	Get(ctx context.Context, userID, keyID string) (*dto.APIKeyResponse, error)
+	Update(ctx context.Context, userID, keyID string, input dto.UpdateAPIKeyRequest) (*dto.APIKeyResponse, error)
+	Delete(ctx context.Context, userID, keyID string) error
+}
+
+const (
+	// APIKeyPrefix starts every key, so clients and secret scanners can tell keys from JWTs.
+	APIKeyPrefix = "gsk_"
+	// apiKeyIDLen is the length of the public part after APIKeyPrefix; a "_" and the secret follow.
+	apiKeyIDLen = 8
+	// apiKeyTouchInterval limits last_used_at writes to one per key per interval.
+	apiKeyTouchInterval = time.Minute
+)
+
+// scopePattern accepts "*" and RBAC permissions such as "users:read" or "users:*".
+var scopePattern = regexp.MustCompile(`^(\*|[a-z][a-z0-9_-]*(:([a-z0-9_-]+|\*))*)$`)
+
+// APIKeyUseCase implements APIKeyUsecases and authenticates keys for the HTTP layer.
+// Keys look like gsk_<8-char prefix>_<secret>; the secret is random and stored as SHA-256 only.
+type APIKeyUseCase struct {
+	keys ports.APIKeyStore
+	repo user.Repository
+}
+
+func NewAPIKeyUseCase(keys ports.APIKeyStore, repo user.Repository) *APIKeyUseCase {
+	return &APIKeyUseCase{keys: keys, repo: repo}
+}
+
+func (uc *APIKeyUseCase) Create(ctx context.Context, userID string, input dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, user.ErrInvalidID
+	}
+	scopes, err := normalizeScopes(input.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := newAPIKeyID()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomURLToken(32)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	k := ports.APIKey{
+		ID:         uuid.New(),
+		UserID:     uid,
+		Name:       strings.TrimSpace(input.Name),
+		Prefix:     prefix,
+		SecretHash: hashRandomSecret(secret),
+		Scopes:     scopes,
+		CreatedAt:  now,
+	}
+	if input.ExpiresInDays > 0 {
+		exp := now.AddDate(0, 0, input.ExpiresInDays)
+		k.ExpiresAt = &exp
+	}
+	if err := uc.keys.Create(ctx, k); err != nil {
+		return nil, err
+	}
+	return &dto.CreateAPIKeyResponse{APIKeyResponse: apiKeyResponse(k), Key: APIKeyPrefix + prefix + "_" + secret}, nil
+}
+
+func (uc *APIKeyUseCase) List(ctx context.Context, userID string) ([]dto.APIKeyResponse, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, user.ErrInvalidID
+	}
+	keys, err := uc.keys.ListByUser(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, apiKeyResponse(k))
+	}
+	return out, nil
+}
+
+func (uc *APIKeyUseCase) Get(ctx context.Context, userID, keyID string) (*dto.APIKeyResponse, error) {
+	uid, kid, err := parseAPIKeyIDs(userID, keyID)
+	if err != nil {
+		return nil, err
+	}
+	k, err := uc.keys.Get(ctx, uid, kid)
+	if err != nil {
+		return nil, err
+	}
+	resp := apiKeyResponse(k)
+	return &resp, nil
+}
+
+func (uc *APIKeyUseCase) Update(ctx context.Context, userID, keyID string, input dto.UpdateAPIKeyRequest) (*dto.APIKeyResponse, error) {
+	uid, kid, err := parseAPIKeyIDs(userID, keyID)
+	if err != nil {
+		return nil, err
+	}
+	k, err := uc.keys.Get(ctx, uid, kid)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name != nil {
+		k.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Scopes != nil {
+		if k.Scopes, err = normalizeScopes(*input.Scopes); err != nil {
+			return nil, err
+		}
+	}
+	if err := uc.keys.Update(ctx, uid, kid, k.Name, k.Scopes); err != nil {
+		return nil, err
+	}
+	resp := apiKeyResponse(k)
+	return &resp, nil
+}
+
+func (uc *APIKeyUseCase) Delete(ctx context.Context, userID, keyID string) error {
+	uid, kid, err := parseAPIKeyIDs(userID, keyID)
+	if err != nil {
+		return err
+	}
+	return uc.keys.Delete(ctx, uid, kid)
+}
+
+// Authenticate resolves a presented key to its owner. The role is read from the user on every request,
+// so role changes apply to existing keys; every failure is reported as apperr.ErrAPIKeyInvalid.
+func (uc *APIKeyUseCase) Authenticate(ctx context.Context, rawKey string) (*dto.APIKeyPrincipal, error) {
+	prefix, secret, ok := splitAPIKey(rawKey)
+	if !ok {
+		return nil, apperr.ErrAPIKeyInvalid
+	}
+	k, err := uc.keys.GetByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, apperr.ErrAPIKeyNotFound) {
+			return nil, apperr.ErrAPIKeyInvalid
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(hashRandomSecret(secret), k.SecretHash) != 1 {
+		return nil, apperr.ErrAPIKeyInvalid
+	}
+	now := time.Now()
+	if k.ExpiresAt != nil && !k.ExpiresAt.After(now) {
+		return nil, apperr.ErrAPIKeyInvalid
+	}
+	u, err := uc.repo.GetByID(ctx, k.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, apperr.ErrAPIKeyInvalid
+		}
+		return nil, err
+	}
+	if u.IsDisabled() {
+		return nil, apperr.ErrAPIKeyInvalid
+	}
+	if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= apiKeyTouchInterval {
+		// Best effort: a failed timestamp write must not reject a valid key
+		_ = uc.keys.Touch(ctx, k.ID, now)
+	}
+	return &dto.APIKeyPrincipal{UserID: u.ID.String(), Role: string(u.Role), KeyID: k.ID.String(), Scopes: k.Scopes}, nil
+}
+
+func apiKeyResponse(k ports.APIKey) dto.APIKeyResponse {
+	scopes := k.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return dto.APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     APIKeyPrefix + k.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+func parseAPIKeyIDs(userID, keyID string) (uuid.UUID, uuid.UUID, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, user.ErrInvalidID
+	}
+	kid, err := uuid.Parse(keyID)
+	if err != nil {
+		// Not a key ID this user could own
+		return uuid.Nil, uuid.Nil, apperr.ErrAPIKeyNotFound
+	}
+	return uid, kid, nil
+}
+
+// normalizeScopes trims, validates and de-duplicates scopes, keeping their order.
+func normalizeScopes(in []string) ([]string, error) {
+	out := make([]string, 0, len(in))
+	seen := make(map[string]struct{}, len(in))
+	for _, s := range in {
+		s = strings.TrimSpace(s)
+		if !scopePattern.MatchString(s) {
+			return nil, apperr.ErrInvalidScope
+		}
+		if _, dup := seen[s]; dup {
+			continue
+		}
+		seen[s] = struct{}{}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// newAPIKeyID returns apiKeyIDLen lowercase base32 characters (40 random bits).
+func newAPIKeyID() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:apiKeyIDLen], nil
+}
+
+// splitAPIKey parses gsk_<prefix>_<secret>. The secret is base64url and may itself contain "_".
+func splitAPIKey(raw string) (prefix, secret string, ok bool) {
+	rest, found := strings.CutPrefix(strings.TrimSpace(raw), APIKeyPrefix)
+	if !found || len(rest) < apiKeyIDLen+2 || rest[apiKeyIDLen] != '_' {
+		return "", "", false
+	}
+	return rest[:apiKeyIDLen], rest[apiKeyIDLen+1:], true
+}
+
+// hashRandomSecret hashes API key and OAuth client secrets with plain SHA-256: they are 256-bit random values, unlike passwords.
+func hashRandomSecret(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
+
+var _ APIKeyUsecases = (*APIKeyUseCase)(nil)
@@ -0,0 +1,89 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	domuser "gostartkit/internal/domain/user"
+)
+
+func TestAPIKeys_CreateAuthenticateAndDelete(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Kai", "Lee", domuser.Email("kai@example.com"), "hashed:x", domuser.RoleAdmin)
+	uc := NewAPIKeyUseCase(fakeAPIKeyStore{}, &fakeRepo{user: u})
+
+	created, err := uc.Create(ctx, u.ID.String(), dto.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"users:read", "users:read"}, ExpiresInDays: 30})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(created.Key) <= len(created.Prefix) || created.Key[:len(created.Prefix)] != created.Prefix || created.ExpiresAt == nil {
+		t.Fatalf("unexpected key %q with prefix %q", created.Key, created.Prefix)
+	}
+	if len(created.Scopes) != 1 {
+		t.Fatalf("expected duplicate scopes to collapse, got %v", created.Scopes)
+	}
+
+	p, err := uc.Authenticate(ctx, created.Key)
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if p.UserID != u.ID.String() || p.Role != string(domuser.RoleAdmin) || p.Scopes[0] != "users:read" {
+		t.Fatalf("unexpected principal %+v", p)
+	}
+	if _, err := uc.Authenticate(ctx, created.Key+"x"); !errors.Is(err, apperr.ErrAPIKeyInvalid) {
+		t.Fatalf("expected wrong secret to be rejected, got %v", err)
+	}
+	got, err := uc.Get(ctx, u.ID.String(), created.ID.String())
+	if err != nil || got.LastUsedAt == nil {
+		t.Fatalf("expected last_used_at after use: %+v err=%v", got, err)
+	}
+
+	u.Disable(time.Now())
+	if _, err := uc.Authenticate(ctx, created.Key); !errors.Is(err, apperr.ErrAPIKeyInvalid) {
+		t.Fatalf("expected the key of a disabled user to be rejected, got %v", err)
+	}
+	u.Enable(time.Now())
+
+	if err := uc.Delete(ctx, u.ID.String(), created.ID.String()); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := uc.Authenticate(ctx, created.Key); !errors.Is(err, apperr.ErrAPIKeyInvalid) {
+		t.Fatalf("expected deleted key to be rejected, got %v", err)
+	}
+}
+
+func TestAPIKeys_ScopesAndOwnership(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Mo", "Ng", domuser.Email("mo@example.com"), "hashed:x", domuser.RoleUser)
+	other := domuser.NewUser("Ola", "Po", domuser.Email("ola@example.com"), "hashed:x", domuser.RoleUser)
+	uc := NewAPIKeyUseCase(fakeAPIKeyStore{}, &fakeRepo{user: u})
+
+	if _, err := uc.Create(ctx, u.ID.String(), dto.CreateAPIKeyRequest{Name: "bad", Scopes: []string{"Users Read"}}); !errors.Is(err, apperr.ErrInvalidScope) {
+		t.Fatalf("expected invalid scope, got %v", err)
+	}
+	created, err := uc.Create(ctx, u.ID.String(), dto.CreateAPIKeyRequest{Name: "script"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.Get(ctx, other.ID.String(), created.ID.String()); !errors.Is(err, apperr.ErrAPIKeyNotFound) {
+		t.Fatalf("expected another user's key to be hidden, got %v", err)
+	}
+
+	scopes := []string{"users:*"}
+	name := "renamed"
+	updated, err := uc.Update(ctx, u.ID.String(), created.ID.String(), dto.UpdateAPIKeyRequest{Name: &name, Scopes: &scopes})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.Name != "renamed" || len(updated.Scopes) != 1 || updated.Scopes[0] != "users:*" {
+		t.Fatalf("unexpected update result %+v", updated)
+	}
+	list, err := uc.List(ctx, u.ID.String())
+	if err != nil || len(list) != 1 || list[0].Name != "renamed" {
+		t.Fatalf("unexpected list %+v err=%v", list, err)
+	}
+}
@@ -2,6 +2,7 @@ package userusecase
 
 import (
 	"context"
+	"time"
 
 	"gostartkit/internal/application/dto"
 	domuser "gostartkit/internal/domain/user"
@@ -34,6 +35,6 @@ func (uc *ChangePasswordUseCase) Execute(ctx context.Context, userID string, inp
 	if err != nil {
 		return err
 	}
-	u.Password = hashed
+	u.SetPassword(hashed, time.Now())
 	return uc.repo.Update(ctx, u)
 }
@@ -10,6 +10,8 @@ import (
 type CreateUserUseCase struct {
 	repo   user.Repository
 	hasher PasswordHasher
+	// verifier, when set, emails a verification link after registration
+	verifier *EmailVerificationUseCase
 }
 
 func (uc *CreateUserUseCase) Execute(ctx context.Context, input dto.CreateUserRequest) (*dto.UserResponse, error) {
@@ -36,11 +38,10 @@ func (uc *CreateUserUseCase) Execute(ctx context.Context, input dto.CreateUserRe
 	if err := uc.repo.Save(ctx, newUser); err != nil {
 		return nil, err
 	}
-	return &dto.UserResponse{
-		ID:        newUser.ID,
-		Email:     newUser.Email.String(),
-		FirstName: newUser.FirstName,
-		LastName:  newUser.LastName,
-		CreatedAt: newUser.CreatedAt,
-	}, nil
+	if uc.verifier != nil {
+		// The account exists either way; a lost email can be requested again via resend-verification
+		_ = uc.verifier.Send(ctx, newUser)
+	}
+	resp := userResponse(newUser)
+	return &resp, nil
 }
@@ -0,0 +1,212 @@
+package userusecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// PersonalDataExportFormat identifies export documents; PersonalDataExportVersion is bumped on incompatible changes.
+	PersonalDataExportFormat  = "gostartkit.personal-data-export"
+	PersonalDataExportVersion = 1
+)
+
+// exportBackgroundTimeout bounds packaging, upload and the email of a background export.
+const exportBackgroundTimeout = 5 * time.Minute
+
+// DataExportUsecases serves a user a copy of their own data.
+type DataExportUsecases interface {
+	Export(ctx context.Context, userID string, format string) (*dto.DataExportResult, error)
+}
+
+// DataExportOptions tunes DataExportUseCase.
+type DataExportOptions struct {
+	// InlineMaxBytes is the largest export (as JSON) returned in the response; larger ones are packaged in the
+	// background, stored and emailed as a link when storage and a mailer are configured (default 5 MiB)
+	InlineMaxBytes int
+	// LinkTTL is how long the emailed download link works (default 24 hours)
+	LinkTTL time.Duration
+	// OnBackgroundError receives failures of background exports, which have no response to report them in
+	OnBackgroundError func(userID uuid.UUID, err error)
+}
+
+// DataExportUseCase assembles everything held about a user (GDPR Article 20) from the registered exporters.
+type DataExportUseCase struct {
+	repo      user.Repository
+	storage   ports.ObjectStorage
+	mailer    ports.EmailSender
+	opts      DataExportOptions
+	exporters []ports.PersonalDataExporter
+	// background tracks running background exports (Shutdown and tests wait on it)
+	background sync.WaitGroup
+	// mu guards pending, the users with a background export in progress (at most one each)
+	mu      sync.Mutex
+	pending map[uuid.UUID]bool
+}
+
+// NewDataExportUseCase builds the export from exporters in the given order. storage and mailer may be nil;
+// exports are then always returned inline.
+func NewDataExportUseCase(repo user.Repository, storage ports.ObjectStorage, mailer ports.EmailSender, opts DataExportOptions, exporters ...ports.PersonalDataExporter) *DataExportUseCase {
+	if opts.InlineMaxBytes <= 0 {
+		opts.InlineMaxBytes = 5 << 20
+	}
+	if opts.LinkTTL <= 0 {
+		opts.LinkTTL = 24 * time.Hour
+	}
+	return &DataExportUseCase{repo: repo, storage: storage, mailer: mailer, opts: opts, exporters: exporters, pending: map[uuid.UUID]bool{}}
+}
+
+// Export collects the user's data and returns it packaged as format ("json" or "zip"). Exports larger than
+// InlineMaxBytes are packaged and delivered in the background when possible; the result is then Pending.
+// While a user's background export is running, further requests report Pending without starting another.
+func (uc *DataExportUseCase) Export(ctx context.Context, userID string, format string) (*dto.DataExportResult, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, user.ErrInvalidID
+	}
+	u, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if uc.isPending(u.ID) {
+		return &dto.DataExportResult{Pending: true}, nil
+	}
+	doc, err := uc.collect(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > uc.opts.InlineMaxBytes && uc.storage != nil && uc.mailer != nil {
+		if !uc.claim(u.ID) {
+			return &dto.DataExportResult{Pending: true}, nil
+		}
+		uc.background.Add(1)
+		go func() {
+			defer uc.background.Done()
+			defer uc.release(u.ID)
+			bctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), exportBackgroundTimeout)
+			defer cancel()
+			if err := uc.deliver(bctx, u, format, doc.GeneratedAt, body); err != nil && uc.opts.OnBackgroundError != nil {
+				uc.opts.OnBackgroundError(u.ID, err)
+			}
+		}()
+		return &dto.DataExportResult{Pending: true}, nil
+	}
+	return packageExport(format, doc.GeneratedAt, body)
+}
+
+// Shutdown waits for running background exports to finish, or until ctx is done. Call it once the server has
+// stopped taking requests.
+func (uc *DataExportUseCase) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		uc.background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (uc *DataExportUseCase) isPending(userID uuid.UUID) bool {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	return uc.pending[userID]
+}
+
+// claim marks userID as having a background export; it reports false when one is already running.
+func (uc *DataExportUseCase) claim(userID uuid.UUID) bool {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if uc.pending[userID] {
+		return false
+	}
+	uc.pending[userID] = true
+	return true
+}
+
+func (uc *DataExportUseCase) release(userID uuid.UUID) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	delete(uc.pending, userID)
+}
+
+// collect runs every exporter; one failing exporter fails the export rather than silently leaving data out.
+func (uc *DataExportUseCase) collect(ctx context.Context, userID uuid.UUID) (*dto.PersonalDataExport, error) {
+	doc := &dto.PersonalDataExport{
+		Format:      PersonalDataExportFormat,
+		Version:     PersonalDataExportVersion,
+		GeneratedAt: time.Now().UTC(),
+		UserID:      userID,
+		Sections:    make(map[string]any, len(uc.exporters)),
+	}
+	for _, e := range uc.exporters {
+		data, err := e.Export(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", e.Section(), err)
+		}
+		doc.Sections[e.Section()] = data
+	}
+	return doc, nil
+}
+
+// deliver stores the packaged export under a random key and emails the owner an expiring download link.
+func (uc *DataExportUseCase) deliver(ctx context.Context, u *user.User, format string, generatedAt time.Time, body []byte) error {
+	file, err := packageExport(format, generatedAt, body)
+	if err != nil {
+		return err
+	}
+	suffix, err := randomURLToken(16)
+	if err != nil {
+		return err
+	}
+	key := "exports/" + u.ID.String() + "/" + suffix + "/" + file.FileName
+	if _, err := uc.storage.Put(ctx, key, bytes.NewReader(file.Content), int64(len(file.Content)), file.ContentType); err != nil {
+		return fmt.Errorf("store export: %w", err)
+	}
+	link, err := uc.storage.PresignGet(ctx, key, uc.opts.LinkTTL)
+	if err != nil {
+		return fmt.Errorf("presign export: %w", err)
+	}
+	message := fmt.Sprintf("Hello %s,\n\nThe copy of your personal data you asked for is ready. Download it here:\n\n%s\n\nThe link expires in %s. If you did not ask for this export, change your password and sign out of all sessions.\n",
+		u.FirstName, link, uc.opts.LinkTTL)
+	return uc.mailer.Send(ctx, u.Email.String(), "Your data export is ready", message)
+}
+
+// packageExport wraps the JSON document as the requested file type.
+func packageExport(format string, generatedAt time.Time, body []byte) (*dto.DataExportResult, error) {
+	name := "personal-data-" + generatedAt.Format("20060102-150405")
+	if format != "zip" {
+		return &dto.DataExportResult{FileName: name + ".json", ContentType: "application/json", Content: body}, nil
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name + ".json", Method: zip.Deflate, Modified: generatedAt})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return &dto.DataExportResult{FileName: name + ".zip", ContentType: "application/zip", Content: buf.Bytes()}, nil
+}
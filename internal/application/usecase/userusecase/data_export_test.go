@@ -0,0 +1,201 @@
+package userusecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+	domuser "gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// memStorage keeps objects in memory and presigns them as fake URLs.
+type memStorage map[string][]byte
+
+func (s memStorage) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) (string, error) {
+	b, err := io.ReadAll(r)
+	s[key] = b
+	return "mem://" + key, err
+}
+
+func (s memStorage) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(s[key])), nil
+}
+
+func (s memStorage) Delete(_ context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+func (s memStorage) Stat(_ context.Context, key string) (ports.ObjectInfo, error) {
+	b, ok := s[key]
+	if !ok {
+		return ports.ObjectInfo{}, apperr.ErrObjectNotFound
+	}
+	return ports.ObjectInfo{Key: key, Size: int64(len(b))}, nil
+}
+
+func (s memStorage) List(_ context.Context, prefix string) ([]ports.ObjectInfo, error) {
+	var out []ports.ObjectInfo
+	for k, b := range s {
+		if strings.HasPrefix(k, prefix) {
+			out = append(out, ports.ObjectInfo{Key: k, Size: int64(len(b))})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+func (s memStorage) PresignGet(_ context.Context, key string, ttl time.Duration) (string, error) {
+	return "https://files.example.com/" + key + "?expires=" + ttl.String(), nil
+}
+
+func (s memStorage) PresignPut(_ context.Context, key string, ttl time.Duration) (string, error) {
+	return "https://files.example.com/" + key + "?upload&expires=" + ttl.String(), nil
+}
+
+func newExportFixture() (*domuser.User, *fakeRepo, *fakeRefreshStore) {
+	u := &domuser.User{ID: uuid.New(), FirstName: "John", LastName: "Doe", Email: domuser.Email("john@example.com"), Password: "hashed:pass", Role: domuser.RoleUser, CreatedAt: time.Now()}
+	return u, &fakeRepo{user: u}, newFakeRefreshStore()
+}
+
+func TestDataExport_InlineJSONHasVersionedSections(t *testing.T) {
+	u, repo, store := newExportFixture()
+	ctx := context.Background()
+	_, _ = store.Issue(ctx, u.ID.String(), 60, ports.SessionMeta{IP: "203.0.113.7", UserAgent: "curl/8"})
+	uc := NewDataExportUseCase(repo, nil, nil, DataExportOptions{}, NewProfileExporter(repo), NewSessionsExporter(store))
+
+	res, err := uc.Export(ctx, u.ID.String(), "")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if res.Pending || res.ContentType != "application/json" || !strings.HasSuffix(res.FileName, ".json") {
+		t.Fatalf("expected an inline JSON file, got %+v", res)
+	}
+	var doc struct {
+		Format   string                     `json:"format"`
+		Version  int                        `json:"version"`
+		UserID   uuid.UUID                  `json:"user_id"`
+		Sections map[string]json.RawMessage `json:"sections"`
+	}
+	if err := json.Unmarshal(res.Content, &doc); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if doc.Format != PersonalDataExportFormat || doc.Version != PersonalDataExportVersion || doc.UserID != u.ID {
+		t.Fatalf("unexpected header %+v", doc)
+	}
+	if !strings.Contains(string(doc.Sections["profile"]), "john@example.com") || !strings.Contains(string(doc.Sections["sessions"]), "203.0.113.7") {
+		t.Fatalf("expected profile and sessions sections, got %s", res.Content)
+	}
+	if strings.Contains(string(res.Content), "hashed:pass") {
+		t.Fatalf("password hash must not be exported")
+	}
+}
+
+func TestDataExport_ZipAndFailingExporter(t *testing.T) {
+	u, repo, _ := newExportFixture()
+	ctx := context.Background()
+	uc := NewDataExportUseCase(repo, nil, nil, DataExportOptions{}, NewProfileExporter(repo))
+
+	res, err := uc.Export(ctx, u.ID.String(), "zip")
+	if err != nil || res.ContentType != "application/zip" {
+		t.Fatalf("expected a zip, got %+v (%v)", res, err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(res.Content), int64(len(res.Content)))
+	if err != nil || len(zr.File) != 1 || !strings.HasSuffix(zr.File[0].Name, ".json") {
+		t.Fatalf("expected one JSON entry in the zip (%v)", err)
+	}
+
+	broken := errors.New("store down")
+	uc = NewDataExportUseCase(repo, nil, nil, DataExportOptions{}, NewProfileExporter(repo),
+		ports.PersonalDataExportFunc("orders", func(context.Context, uuid.UUID) (any, error) { return nil, broken }))
+	if _, err := uc.Export(ctx, u.ID.String(), "json"); !errors.Is(err, broken) || !strings.Contains(err.Error(), "orders") {
+		t.Fatalf("expected the exporter failure to fail the export, got %v", err)
+	}
+	if _, err := uc.Export(ctx, "nope", "json"); !errors.Is(err, domuser.ErrInvalidID) {
+		t.Fatalf("expected invalid id, got %v", err)
+	}
+}
+
+func TestDataExport_LargeExportIsEmailedAsLink(t *testing.T) {
+	u, repo, _ := newExportFixture()
+	storage := memStorage{}
+	mails := outbox{}
+	uc := NewDataExportUseCase(repo, storage, mails.sender(), DataExportOptions{InlineMaxBytes: 16, LinkTTL: time.Hour}, NewProfileExporter(repo))
+
+	res, err := uc.Export(context.Background(), u.ID.String(), "zip")
+	if err != nil || !res.Pending || res.Content != nil {
+		t.Fatalf("expected a pending export, got %+v (%v)", res, err)
+	}
+	uc.background.Wait()
+	if len(storage) != 1 {
+		t.Fatalf("expected the export stored once, got %d objects", len(storage))
+	}
+	var key string
+	for k := range storage {
+		key = k
+	}
+	if !strings.HasPrefix(key, "exports/"+u.ID.String()+"/") || !strings.HasSuffix(key, ".zip") {
+		t.Fatalf("unexpected object key %q", key)
+	}
+	sent := mails["john@example.com"]
+	if len(sent) != 1 || !strings.Contains(sent[0], "https://files.example.com/"+key) {
+		t.Fatalf("expected the download link emailed, got %v", sent)
+	}
+}
+
+// gatedStorage holds every Put until release is closed.
+type gatedStorage struct {
+	memStorage
+	puts    chan string
+	release chan struct{}
+}
+
+func (s gatedStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	s.puts <- key
+	<-s.release
+	return s.memStorage.Put(ctx, key, r, size, contentType)
+}
+
+func TestDataExport_OneBackgroundExportPerUserAndShutdownWaits(t *testing.T) {
+	u, repo, _ := newExportFixture()
+	storage := gatedStorage{memStorage: memStorage{}, puts: make(chan string, 4), release: make(chan struct{})}
+	mails := outbox{}
+	uc := NewDataExportUseCase(repo, storage, mails.sender(), DataExportOptions{InlineMaxBytes: 16}, NewProfileExporter(repo))
+	ctx := context.Background()
+
+	if res, err := uc.Export(ctx, u.ID.String(), "json"); err != nil || !res.Pending {
+		t.Fatalf("expected a pending export, got %+v (%v)", res, err)
+	}
+	<-storage.puts
+	if res, err := uc.Export(ctx, u.ID.String(), "json"); err != nil || !res.Pending {
+		t.Fatalf("expected the running export reported as pending, got %+v (%v)", res, err)
+	}
+	expired, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := uc.Shutdown(expired); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected Shutdown to wait for the running export, got %v", err)
+	}
+
+	close(storage.release)
+	if err := uc.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if len(storage.puts) != 0 || len(storage.memStorage) != 1 || len(mails["john@example.com"]) != 1 {
+		t.Fatalf("expected exactly one export stored and emailed, got %d puts queued, %d objects, %d emails",
+			len(storage.puts), len(storage.memStorage), len(mails["john@example.com"]))
+	}
+	if uc.isPending(u.ID) {
+		t.Fatalf("expected the user free to ask again once the export was delivered")
+	}
+}
@@ -0,0 +1,115 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// Built-in personal data exporters. Each covers one store; bootstrap registers those that are configured.
+
+// NewProfileExporter exports the account itself ("profile"). The password hash is left out.
+func NewProfileExporter(repo user.Repository) ports.PersonalDataExporter {
+	return ports.PersonalDataExportFunc("profile", func(ctx context.Context, userID uuid.UUID) (any, error) {
+		u, err := repo.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return dto.ExportedProfile{
+			ID:              u.ID,
+			Email:           u.Email.String(),
+			FirstName:       u.FirstName,
+			LastName:        u.LastName,
+			Role:            string(u.Role),
+			EmailVerifiedAt: u.EmailVerifiedAt,
+			Phone:           u.Phone.String(),
+			PhoneVerifiedAt: u.PhoneVerifiedAt,
+			DisabledAt:      u.DisabledAt,
+			CreatedAt:       u.CreatedAt,
+			UpdatedAt:       u.UpdatedAt,
+		}, nil
+	})
+}
+
+// NewSessionsExporter exports active sign-in sessions with the IP address and user agent they were last used from ("sessions").
+func NewSessionsExporter(store ports.RefreshTokenStore) ports.PersonalDataExporter {
+	return ports.PersonalDataExportFunc("sessions", func(ctx context.Context, userID uuid.UUID) (any, error) {
+		sessions, err := store.ListByUser(ctx, userID.String())
+		if err != nil {
+			return nil, err
+		}
+		out := make([]dto.SessionResponse, 0, len(sessions))
+		for _, s := range sessions {
+			out = append(out, sessionResponse(s))
+		}
+		return out, nil
+	})
+}
+
+// NewAPIKeysExporter exports personal API keys without their secrets ("api_keys").
+func NewAPIKeysExporter(keys ports.APIKeyStore) ports.PersonalDataExporter {
+	return ports.PersonalDataExportFunc("api_keys", func(ctx context.Context, userID uuid.UUID) (any, error) {
+		list, err := keys.ListByUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]dto.APIKeyResponse, 0, len(list))
+		for _, k := range list {
+			out = append(out, apiKeyResponse(k))
+		}
+		return out, nil
+	})
+}
+
+// NewMFAExporter exports whether TOTP is set up ("mfa"); the seed and recovery codes stay secret.
+func NewMFAExporter(store ports.MFAStore) ports.PersonalDataExporter {
+	return ports.PersonalDataExportFunc("mfa", func(ctx context.Context, userID uuid.UUID) (any, error) {
+		e, err := store.Get(ctx, userID)
+		if errors.Is(err, apperr.ErrMFANotEnrolled) {
+			return dto.ExportedMFA{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return dto.ExportedMFA{Enrolled: true, Confirmed: e.Confirmed}, nil
+	})
+}
+
+// NewIdentitiesExporter exports linked OpenID Connect accounts ("linked_identities").
+func NewIdentitiesExporter(identities ports.ExternalIdentityStore) ports.PersonalDataExporter {
+	return ports.PersonalDataExportFunc("linked_identities", func(ctx context.Context, userID uuid.UUID) (any, error) {
+		list, err := identities.ListByUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]dto.ExportedIdentity, 0, len(list))
+		for _, i := range list {
+			out = append(out, dto.ExportedIdentity{Provider: i.Provider, Subject: i.Subject, Email: i.Email, LinkedAt: i.CreatedAt})
+		}
+		return out, nil
+	})
+}
+
+// NewLoginSecurityExporter exports the failed-login counters kept for lockouts ("login_security").
+func NewLoginSecurityExporter(store ports.LoginLockoutStore) ports.PersonalDataExporter {
+	return ports.PersonalDataExportFunc("login_security", func(ctx context.Context, userID uuid.UUID) (any, error) {
+		state, err := store.Get(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		out := dto.ExportedLoginSecurity{FailedAttempts: state.FailedAttempts}
+		if !state.LastFailedAt.IsZero() {
+			out.LastFailedAt = &state.LastFailedAt
+		}
+		if !state.LockedUntil.IsZero() {
+			out.LockedUntil = &state.LockedUntil
+		}
+		return out, nil
+	})
+}
@@ -0,0 +1,198 @@
+package userusecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// EmailVerificationUsecases confirms that users own the email address they registered with.
+type EmailVerificationUsecases interface {
+	Verify(ctx context.Context, input dto.VerifyEmailRequest) error
+	Resend(ctx context.Context, input dto.ResendVerificationRequest) error
+}
+
+// EmailVerificationOptions tunes EmailVerificationUseCase.
+type EmailVerificationOptions struct {
+	// LinkBaseURL is the page that receives the token, e.g. https://app.example.com/verify-email;
+	// the emailed link is LinkBaseURL?token=...
+	LinkBaseURL string
+	// TTL bounds how long a link stays valid (default 24 hours)
+	TTL time.Duration
+}
+
+// verifyEmailPurpose and changeEmailPurpose scope link tokens to their flow.
+const (
+	verifyEmailPurpose = "verify_email"
+	changeEmailPurpose = "change_email"
+)
+
+// EmailVerificationUseCase implements EmailVerificationUsecases with stateless signed links.
+// The token binds the user ID to the email address, so a link stops working once the email changes.
+// It also confirms email changes: the pending address lives only in the link sent to it.
+type EmailVerificationUseCase struct {
+	repo   user.Repository
+	tokens ports.LinkTokens
+	mailer ports.EmailSender
+	opts   EmailVerificationOptions
+}
+
+func NewEmailVerificationUseCase(repo user.Repository, tokens ports.LinkTokens, mailer ports.EmailSender, opts EmailVerificationOptions) *EmailVerificationUseCase {
+	if opts.TTL <= 0 {
+		opts.TTL = 24 * time.Hour
+	}
+	return &EmailVerificationUseCase{repo: repo, tokens: tokens, mailer: mailer, opts: opts}
+}
+
+// Send emails a verification link to u; already verified users are skipped.
+func (uc *EmailVerificationUseCase) Send(ctx context.Context, u *user.User) error {
+	if u.IsEmailVerified() {
+		return nil
+	}
+	token, err := uc.tokens.Sign(verifyEmailPurpose, verificationSubject(u), time.Now().Add(uc.opts.TTL))
+	if err != nil {
+		return err
+	}
+	link := uc.opts.LinkBaseURL + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Hello %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in %s. If you did not create an account, you can ignore this email.\n",
+		u.FirstName, link, uc.opts.TTL)
+	if err := uc.mailer.Send(ctx, u.Email.String(), "Confirm your email address", body); err != nil {
+		return fmt.Errorf("send verification email: %w", err)
+	}
+	return nil
+}
+
+// RequestEmailChange emails a confirmation link to newEmail and tells the current address about the change.
+// Nothing is stored: the change happens when the link is verified, if the current address is still the same
+// and the password has not changed since, so the owner cancels a change they did not ask for by changing it.
+func (uc *EmailVerificationUseCase) RequestEmailChange(ctx context.Context, u *user.User, newEmail user.Email) error {
+	token, err := uc.tokens.Sign(changeEmailPurpose, emailChangeSubject(u, newEmail), time.Now().Add(uc.opts.TTL))
+	if err != nil {
+		return err
+	}
+	notice := fmt.Sprintf("Hello %s,\n\nSomeone asked to change the email address of your account to %s. The change takes effect once the new address is confirmed.\n\nIf this was not you, change your password now: that cancels the change, and this address stays on the account.\n",
+		u.FirstName, newEmail)
+	if err := uc.mailer.Send(ctx, u.Email.String(), "Your email address is being changed", notice); err != nil {
+		return fmt.Errorf("send email change notice: %w", err)
+	}
+	link := uc.opts.LinkBaseURL + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Hello %s,\n\nPlease confirm your new email address by opening the link below:\n\n%s\n\nThe link expires in %s. If you did not ask for this change, you can ignore this email.\n",
+		u.FirstName, link, uc.opts.TTL)
+	if err := uc.mailer.Send(ctx, newEmail.String(), "Confirm your new email address", body); err != nil {
+		return fmt.Errorf("send email change confirmation: %w", err)
+	}
+	return nil
+}
+
+// Verify accepts both verification links and email change links, so both can land on the same page.
+func (uc *EmailVerificationUseCase) Verify(ctx context.Context, input dto.VerifyEmailRequest) error {
+	token := strings.TrimSpace(input.Token)
+	subject, err := uc.tokens.Verify(verifyEmailPurpose, token, time.Now())
+	if err != nil {
+		if subject, err := uc.tokens.Verify(changeEmailPurpose, token, time.Now()); err == nil {
+			return uc.confirmEmailChange(ctx, subject)
+		}
+		return apperr.ErrVerificationTokenInvalid
+	}
+	rawID, email, ok := strings.Cut(subject, "|")
+	if !ok {
+		return apperr.ErrVerificationTokenInvalid
+	}
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return apperr.ErrVerificationTokenInvalid
+	}
+	u, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return apperr.ErrVerificationTokenInvalid
+		}
+		return err
+	}
+	if u.Email.String() != email {
+		return apperr.ErrVerificationTokenInvalid
+	}
+	if u.IsEmailVerified() {
+		return nil
+	}
+	u.MarkEmailVerified(time.Now())
+	return uc.repo.Update(ctx, u)
+}
+
+// confirmEmailChange switches to the new address named in subject (id|old|new|password stamp). Links go stale
+// once the address or the password changed in between; an address taken by someone else since the request is a conflict.
+func (uc *EmailVerificationUseCase) confirmEmailChange(ctx context.Context, subject string) error {
+	parts := strings.Split(subject, "|")
+	if len(parts) != 4 {
+		return apperr.ErrVerificationTokenInvalid
+	}
+	id, err := uuid.Parse(parts[0])
+	if err != nil {
+		return apperr.ErrVerificationTokenInvalid
+	}
+	u, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return apperr.ErrVerificationTokenInvalid
+		}
+		return err
+	}
+	newEmail := user.Email(parts[2])
+	if u.Email == newEmail {
+		return nil
+	}
+	if u.Email.String() != parts[1] || subtle.ConstantTimeCompare([]byte(passwordStamp(u)), []byte(parts[3])) != 1 {
+		return apperr.ErrVerificationTokenInvalid
+	}
+	if err := ensureEmailAvailable(ctx, uc.repo, newEmail); err != nil {
+		return err
+	}
+	u.SetVerifiedEmail(newEmail, time.Now())
+	return uc.repo.Update(ctx, u)
+}
+
+// Resend succeeds whether or not the email belongs to an unverified account, so it cannot be used to probe for users.
+// Send failures are not reported either (the email sender logs them), since only existing accounts reach the sender.
+func (uc *EmailVerificationUseCase) Resend(ctx context.Context, input dto.ResendVerificationRequest) error {
+	emailVO, err := user.NewEmail(input.Email)
+	if err != nil {
+		return err
+	}
+	u, err := uc.repo.GetByEmail(ctx, emailVO)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+	_ = uc.Send(ctx, u)
+	return nil
+}
+
+func verificationSubject(u *user.User) string {
+	return u.ID.String() + "|" + u.Email.String()
+}
+
+func emailChangeSubject(u *user.User, newEmail user.Email) string {
+	return verificationSubject(u) + "|" + newEmail.String() + "|" + passwordStamp(u)
+}
+
+// passwordStamp changes whenever the owner sets a new password, but not when a login merely rehashes the
+// same one. Postgres keeps microseconds, so finer digits would not survive a round trip.
+func passwordStamp(u *user.User) string {
+	return strconv.FormatInt(u.PasswordChangedAt.UnixMicro(), 36)
+}
+
+var _ EmailVerificationUsecases = (*EmailVerificationUseCase)(nil)
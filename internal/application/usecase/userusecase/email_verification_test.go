@@ -0,0 +1,131 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	domuser "gostartkit/internal/domain/user"
+)
+
+// outbox records sent emails by recipient.
+type outbox map[string][]string
+
+func (o outbox) sender() ports.EmailSender {
+	return ports.SendEmailFunc(func(ctx context.Context, to, subject, body string) error {
+		o[to] = append(o[to], body)
+		return nil
+	})
+}
+
+// linkToken extracts the token query parameter from the last email sent to addr.
+func (o outbox) linkToken(t *testing.T, addr string) string {
+	t.Helper()
+	mails := o[addr]
+	if len(mails) == 0 {
+		t.Fatalf("no email sent to %s", addr)
+	}
+	body := mails[len(mails)-1]
+	i := strings.Index(body, "?token=")
+	if i < 0 {
+		t.Fatalf("no link in email: %q", body)
+	}
+	raw := strings.Fields(body[i+len("?token="):])[0]
+	token, err := url.QueryUnescape(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func newEmailVerification(t *testing.T, repo domuser.Repository, mail outbox) *EmailVerificationUseCase {
+	t.Helper()
+	return NewEmailVerificationUseCase(repo, fakeLinkTokens{}, mail.sender(), EmailVerificationOptions{LinkBaseURL: "https://app.example/verify"})
+}
+
+func TestEmailVerification_RegisterVerifyThenLogin(t *testing.T) {
+	ctx := context.Background()
+	repo, mail := usersByEmail{}, outbox{}
+	verifier := newEmailVerification(t, repo, mail)
+	uc := NewUserUsecasesWithOptions(repo, fakeHasher{}, fakeTokenIssuer{}, nil, 0,
+		UserUsecasesOptions{EmailVerification: verifier, RequireVerifiedEmail: true})
+
+	created, err := uc.Register(ctx, dto.CreateUserRequest{FirstName: "Ann", LastName: "Lee", Email: "ann@example.com", Password: "pass", Role: "user"})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if created.EmailVerified {
+		t.Fatalf("new account must start unverified")
+	}
+	login := dto.LoginRequest{Email: "ann@example.com", Password: "pass"}
+	if _, err := uc.Login(ctx, login); !errors.Is(err, apperr.ErrEmailVerificationRequired) {
+		t.Fatalf("expected verification to be required, got %v", err)
+	}
+	// A wrong password still reports invalid credentials
+	if _, err := uc.Login(ctx, dto.LoginRequest{Email: "ann@example.com", Password: "nope"}); !errors.Is(err, apperr.ErrInvalidCredentials) {
+		t.Fatalf("expected invalid credentials, got %v", err)
+	}
+
+	if err := verifier.Verify(ctx, dto.VerifyEmailRequest{Token: mail.linkToken(t, "ann@example.com")}); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	resp, err := uc.Login(ctx, login)
+	if err != nil {
+		t.Fatalf("login after verification: %v", err)
+	}
+	if !resp.User.EmailVerified {
+		t.Fatalf("expected email_verified in login response")
+	}
+}
+
+func TestEmailVerification_TokenBoundToEmail(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Bo", "Ng", domuser.Email("bo@example.com"), "hashed:x", domuser.RoleUser)
+	repo, mail := usersByEmail{u.Email: u}, outbox{}
+	verifier := newEmailVerification(t, repo, mail)
+
+	if err := verifier.Resend(ctx, dto.ResendVerificationRequest{Email: "bo@example.com"}); err != nil {
+		t.Fatalf("resend: %v", err)
+	}
+	token := mail.linkToken(t, "bo@example.com")
+	u.Email = domuser.Email("new@example.com")
+	if err := verifier.Verify(ctx, dto.VerifyEmailRequest{Token: token}); !errors.Is(err, apperr.ErrVerificationTokenInvalid) {
+		t.Fatalf("expected link for the old address to be rejected, got %v", err)
+	}
+	if err := verifier.Verify(ctx, dto.VerifyEmailRequest{Token: "garbage"}); !errors.Is(err, apperr.ErrVerificationTokenInvalid) {
+		t.Fatalf("expected garbage token to be rejected, got %v", err)
+	}
+}
+
+func TestEmailVerification_ResendDoesNotRevealAccounts(t *testing.T) {
+	ctx := context.Background()
+	verified := domuser.NewUser("Cy", "Ho", domuser.Email("cy@example.com"), "hashed:x", domuser.RoleUser)
+	verified.MarkEmailVerified(verified.CreatedAt)
+	mail := outbox{}
+	verifier := newEmailVerification(t, usersByEmail{verified.Email: verified}, mail)
+
+	for _, email := range []string{"cy@example.com", "nobody@example.com"} {
+		if err := verifier.Resend(ctx, dto.ResendVerificationRequest{Email: email}); err != nil {
+			t.Fatalf("resend %s: %v", email, err)
+		}
+	}
+	if len(mail) != 0 {
+		t.Fatalf("expected no emails, got %v", mail)
+	}
+}
+
+func TestEmailVerification_ResendHidesSendFailures(t *testing.T) {
+	u := domuser.NewUser("Gil", "Ho", domuser.Email("gil@example.com"), "hashed:x", domuser.RoleUser)
+	failing := ports.SendEmailFunc(func(context.Context, string, string, string) error { return errors.New("smtp down") })
+	verifier := NewEmailVerificationUseCase(usersByEmail{u.Email: u}, fakeLinkTokens{}, failing, EmailVerificationOptions{LinkBaseURL: "https://app.example/verify"})
+
+	// An error here would tell an unverified account from an unknown address
+	if err := verifier.Resend(context.Background(), dto.ResendVerificationRequest{Email: "gil@example.com"}); err != nil {
+		t.Fatalf("expected success despite the failed send, got %v", err)
+	}
+}
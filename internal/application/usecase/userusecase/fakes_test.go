@@ -0,0 +1,435 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+
+	"github.com/google/uuid"
+)
+
+// In-memory fakes of the ports used by the use cases under test. They keep only the behaviour the
+// use cases rely on; the real stores are covered by their own packages and the portstest suites.
+
+// fakeRefreshStore hands out sequential tokens. Each Issue starts a session (token family); a rotated
+// token presented again revokes its family and reports reuse.
+type fakeRefreshStore struct {
+	next     int
+	live     map[string]string // token -> family
+	rotated  map[string]string // token -> family
+	sessions map[string]*ports.RefreshSession
+}
+
+func newFakeRefreshStore() *fakeRefreshStore {
+	return &fakeRefreshStore{live: map[string]string{}, rotated: map[string]string{}, sessions: map[string]*ports.RefreshSession{}}
+}
+
+func (s *fakeRefreshStore) token() string {
+	s.next++
+	return "rt-" + strconv.Itoa(s.next)
+}
+
+func (s *fakeRefreshStore) Issue(_ context.Context, userID string, ttlSeconds int, meta ports.SessionMeta) (string, error) {
+	now := time.Now()
+	t := s.token()
+	s.live[t] = t
+	s.sessions[t] = &ports.RefreshSession{
+		ID: t, UserID: userID, CreatedAt: now, LastUsedAt: now, ExpiresAt: now.Add(time.Duration(ttlSeconds) * time.Second),
+		IP: meta.IP, UserAgent: meta.UserAgent, DeviceLabel: meta.DeviceLabel,
+	}
+	return t, nil
+}
+
+func (s *fakeRefreshStore) Rotate(_ context.Context, oldToken string, ttlSeconds int, meta ports.SessionMeta) (string, string, error) {
+	family, ok := s.live[oldToken]
+	if !ok {
+		if family, reused := s.rotated[oldToken]; reused {
+			s.revokeFamily(family)
+			return "", "", apperr.ErrRefreshTokenReused
+		}
+		return "", "", apperr.ErrInvalidRefreshToken
+	}
+	delete(s.live, oldToken)
+	s.rotated[oldToken] = family
+	t := s.token()
+	s.live[t] = family
+	sess := s.sessions[family]
+	sess.LastUsedAt, sess.ExpiresAt = time.Now(), time.Now().Add(time.Duration(ttlSeconds)*time.Second)
+	sess.IP, sess.UserAgent = meta.IP, meta.UserAgent
+	return t, sess.UserID, nil
+}
+
+func (s *fakeRefreshStore) Revoke(_ context.Context, token string) error {
+	if _, ok := s.live[token]; !ok {
+		return apperr.ErrInvalidRefreshToken
+	}
+	delete(s.live, token)
+	return nil
+}
+
+func (s *fakeRefreshStore) Validate(_ context.Context, token string) (string, error) {
+	family, ok := s.live[token]
+	if !ok {
+		return "", apperr.ErrInvalidRefreshToken
+	}
+	return s.sessions[family].UserID, nil
+}
+
+func (s *fakeRefreshStore) ListByUser(_ context.Context, userID string) ([]ports.RefreshSession, error) {
+	out := []ports.RefreshSession{}
+	for _, sess := range s.sessions {
+		if sess.UserID == userID {
+			out = append(out, *sess)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].LastUsedAt.After(out[j].LastUsedAt) })
+	return out, nil
+}
+
+func (s *fakeRefreshStore) RevokeSession(_ context.Context, userID, sessionID string) error {
+	sess, ok := s.sessions[sessionID]
+	if !ok || sess.UserID != userID {
+		return apperr.ErrSessionNotFound
+	}
+	s.revokeFamily(sessionID)
+	return nil
+}
+
+func (s *fakeRefreshStore) RevokeAll(_ context.Context, userID string) error {
+	for family, sess := range s.sessions {
+		if sess.UserID == userID {
+			s.revokeFamily(family)
+		}
+	}
+	return nil
+}
+
+func (s *fakeRefreshStore) revokeFamily(family string) {
+	for t, f := range s.live {
+		if f == family {
+			delete(s.live, t)
+		}
+	}
+	delete(s.sessions, family)
+}
+
+// fakeAccessRevocations denylists jtis and per-user cutoffs without expiry.
+type fakeAccessRevocations struct {
+	jtis    map[string]bool
+	cutoffs map[string]time.Time
+}
+
+func newFakeAccessRevocations() *fakeAccessRevocations {
+	return &fakeAccessRevocations{jtis: map[string]bool{}, cutoffs: map[string]time.Time{}}
+}
+
+func (r *fakeAccessRevocations) RevokeJTI(_ context.Context, jti string, _ time.Time) error {
+	r.jtis[jti] = true
+	return nil
+}
+
+func (r *fakeAccessRevocations) RevokeUser(_ context.Context, userID string, before time.Time, _ time.Duration) error {
+	r.cutoffs[userID] = before
+	return nil
+}
+
+func (r *fakeAccessRevocations) IsRevoked(_ context.Context, jti, userID string, issuedAt time.Time) (bool, error) {
+	cutoff, ok := r.cutoffs[userID]
+	return r.jtis[jti] || (ok && issuedAt.Unix() < cutoff.Unix()), nil
+}
+
+type fakeAPIKeyStore map[uuid.UUID]ports.APIKey
+
+func (s fakeAPIKeyStore) Create(_ context.Context, k ports.APIKey) error { s[k.ID] = k; return nil }
+
+func (s fakeAPIKeyStore) GetByPrefix(_ context.Context, prefix string) (ports.APIKey, error) {
+	for _, k := range s {
+		if k.Prefix == prefix {
+			return k, nil
+		}
+	}
+	return ports.APIKey{}, apperr.ErrAPIKeyNotFound
+}
+
+func (s fakeAPIKeyStore) Get(_ context.Context, userID, id uuid.UUID) (ports.APIKey, error) {
+	k, ok := s[id]
+	if !ok || k.UserID != userID {
+		return ports.APIKey{}, apperr.ErrAPIKeyNotFound
+	}
+	return k, nil
+}
+
+func (s fakeAPIKeyStore) ListByUser(_ context.Context, userID uuid.UUID) ([]ports.APIKey, error) {
+	out := []ports.APIKey{}
+	for _, k := range s {
+		if k.UserID == userID {
+			out = append(out, k)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (s fakeAPIKeyStore) Update(ctx context.Context, userID, id uuid.UUID, name string, scopes []string) error {
+	k, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	k.Name, k.Scopes = name, scopes
+	s[id] = k
+	return nil
+}
+
+func (s fakeAPIKeyStore) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	delete(s, id)
+	return nil
+}
+
+func (s fakeAPIKeyStore) Touch(_ context.Context, id uuid.UUID, at time.Time) error {
+	if k, ok := s[id]; ok {
+		k.LastUsedAt = &at
+		s[id] = k
+	}
+	return nil
+}
+
+type fakeOAuthClientStore map[string]ports.OAuthClient
+
+func (s fakeOAuthClientStore) Create(_ context.Context, c ports.OAuthClient) error {
+	s[c.ClientID] = c
+	return nil
+}
+
+func (s fakeOAuthClientStore) Get(_ context.Context, clientID string) (ports.OAuthClient, error) {
+	c, ok := s[clientID]
+	if !ok {
+		return ports.OAuthClient{}, apperr.ErrOAuthClientNotFound
+	}
+	return c, nil
+}
+
+func (s fakeOAuthClientStore) List(_ context.Context) ([]ports.OAuthClient, error) {
+	out := make([]ports.OAuthClient, 0, len(s))
+	for _, c := range s {
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func (s fakeOAuthClientStore) Delete(_ context.Context, clientID string) error {
+	if _, ok := s[clientID]; !ok {
+		return apperr.ErrOAuthClientNotFound
+	}
+	delete(s, clientID)
+	return nil
+}
+
+// fakeLockoutStore mirrors the window rule of ports.LoginLockoutStore.RecordFailure.
+type fakeLockoutStore map[uuid.UUID]ports.LoginLockout
+
+func (s fakeLockoutStore) Get(_ context.Context, userID uuid.UUID) (ports.LoginLockout, error) {
+	return s[userID], nil
+}
+
+func (s fakeLockoutStore) RecordFailure(_ context.Context, userID uuid.UUID, at, windowStart time.Time) (ports.LoginLockout, error) {
+	e, ok := s[userID]
+	if !ok || (e.LastFailedAt.Before(windowStart) && e.LockedUntil.Before(windowStart)) {
+		e.FailedAttempts = 0
+	}
+	e.FailedAttempts++
+	e.LastFailedAt = at
+	s[userID] = e
+	return e, nil
+}
+
+func (s fakeLockoutStore) Lock(_ context.Context, userID uuid.UUID, until time.Time) error {
+	if e, ok := s[userID]; ok {
+		e.LockedUntil = until
+		s[userID] = e
+	}
+	return nil
+}
+
+func (s fakeLockoutStore) Reset(_ context.Context, userID uuid.UUID) error {
+	delete(s, userID)
+	return nil
+}
+
+type fakeMagicLink struct {
+	link    ports.MagicLink
+	expires time.Time
+}
+
+type fakeMagicLinkStore map[string]fakeMagicLink
+
+func (s fakeMagicLinkStore) Save(_ context.Context, tokenHash string, link ports.MagicLink, ttl time.Duration) error {
+	s[tokenHash] = fakeMagicLink{link: link, expires: time.Now().Add(ttl)}
+	return nil
+}
+
+func (s fakeMagicLinkStore) Consume(_ context.Context, tokenHash string) (ports.MagicLink, error) {
+	l, ok := s[tokenHash]
+	delete(s, tokenHash)
+	if !ok || !time.Now().Before(l.expires) {
+		return ports.MagicLink{}, apperr.ErrMagicLinkInvalid
+	}
+	return l.link, nil
+}
+
+type fakeResetToken struct {
+	userID  uuid.UUID
+	expires time.Time
+}
+
+type fakePasswordResetStore map[string]fakeResetToken
+
+func (s fakePasswordResetStore) Save(_ context.Context, tokenHash []byte, userID uuid.UUID, expiresAt time.Time) error {
+	s[string(tokenHash)] = fakeResetToken{userID: userID, expires: expiresAt}
+	return nil
+}
+
+func (s fakePasswordResetStore) Consume(_ context.Context, tokenHash []byte, now time.Time) (uuid.UUID, error) {
+	t, ok := s[string(tokenHash)]
+	delete(s, string(tokenHash))
+	if !ok || !t.expires.After(now) {
+		return uuid.Nil, apperr.ErrResetTokenInvalid
+	}
+	return t.userID, nil
+}
+
+func (s fakePasswordResetStore) DeleteByUser(_ context.Context, userID uuid.UUID) error {
+	for k, t := range s {
+		if t.userID == userID {
+			delete(s, k)
+		}
+	}
+	return nil
+}
+
+type fakeOTPEntry struct {
+	c        ports.OTPChallenge
+	failures int
+}
+
+type fakeOTPStore map[string]*fakeOTPEntry
+
+func (s fakeOTPStore) Save(_ context.Context, key string, c ports.OTPChallenge, _ time.Duration) error {
+	s[key] = &fakeOTPEntry{c: c}
+	return nil
+}
+
+func (s fakeOTPStore) Get(_ context.Context, key string) (ports.OTPChallenge, error) {
+	e, ok := s[key]
+	if !ok {
+		return ports.OTPChallenge{}, apperr.ErrOTPInvalid
+	}
+	return e.c, nil
+}
+
+func (s fakeOTPStore) RecordFailure(_ context.Context, key string) (int, error) {
+	e, ok := s[key]
+	if !ok {
+		return 0, apperr.ErrOTPInvalid
+	}
+	e.failures++
+	return e.failures, nil
+}
+
+func (s fakeOTPStore) Delete(_ context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+type fakeMFAChallengeEntry struct {
+	c        ports.MFAChallenge
+	failures int
+}
+
+type fakeMFAChallengeStore map[string]*fakeMFAChallengeEntry
+
+func (s fakeMFAChallengeStore) Save(_ context.Context, token string, c ports.MFAChallenge, _ time.Duration) error {
+	s[token] = &fakeMFAChallengeEntry{c: c}
+	return nil
+}
+
+func (s fakeMFAChallengeStore) Get(_ context.Context, token string) (ports.MFAChallenge, error) {
+	e, ok := s[token]
+	if !ok {
+		return ports.MFAChallenge{}, apperr.ErrMFATokenInvalid
+	}
+	return e.c, nil
+}
+
+func (s fakeMFAChallengeStore) RecordFailure(_ context.Context, token string) (int, error) {
+	e, ok := s[token]
+	if !ok {
+		return 0, apperr.ErrMFATokenInvalid
+	}
+	e.failures++
+	return e.failures, nil
+}
+
+func (s fakeMFAChallengeStore) Delete(_ context.Context, token string) error {
+	delete(s, token)
+	return nil
+}
+
+type fakeOIDCStateStore map[string]ports.OIDCAuthState
+
+func (s fakeOIDCStateStore) Save(_ context.Context, state string, data ports.OIDCAuthState, _ time.Duration) error {
+	s[state] = data
+	return nil
+}
+
+func (s fakeOIDCStateStore) Consume(_ context.Context, state string) (ports.OIDCAuthState, error) {
+	data, ok := s[state]
+	if !ok {
+		return ports.OIDCAuthState{}, apperr.ErrOIDCStateInvalid
+	}
+	delete(s, state)
+	return data, nil
+}
+
+// fakeLinkTokens signs nothing: tokens are "purpose|expiry|subject" in clear, which is enough to test
+// purpose separation, subject binding and expiry in the use cases.
+type fakeLinkTokens struct{}
+
+func (fakeLinkTokens) Sign(purpose, subject string, expiresAt time.Time) (string, error) {
+	return fmt.Sprintf("%s|%d|%s", purpose, expiresAt.Unix(), subject), nil
+}
+
+func (fakeLinkTokens) Verify(purpose, token string, now time.Time) (string, error) {
+	parts := strings.SplitN(token, "|", 3)
+	if len(parts) != 3 || parts[0] != purpose {
+		return "", errors.New("invalid link token")
+	}
+	exp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || !now.Before(time.Unix(exp, 0)) {
+		return "", errors.New("invalid link token")
+	}
+	return parts[2], nil
+}
+
+var (
+	_ ports.RefreshTokenStore          = (*fakeRefreshStore)(nil)
+	_ ports.AccessTokenRevocationStore = (*fakeAccessRevocations)(nil)
+	_ ports.APIKeyStore                = fakeAPIKeyStore{}
+	_ ports.OAuthClientStore           = fakeOAuthClientStore{}
+	_ ports.LoginLockoutStore          = fakeLockoutStore{}
+	_ ports.MagicLinkStore             = fakeMagicLinkStore{}
+	_ ports.PasswordResetStore         = fakePasswordResetStore{}
+	_ ports.OTPChallengeStore          = fakeOTPStore{}
+	_ ports.MFAChallengeStore          = fakeMFAChallengeStore{}
+	_ ports.OIDCStateStore             = fakeOIDCStateStore{}
+	_ ports.LinkTokens                 = fakeLinkTokens{}
+)
@@ -23,12 +23,7 @@ func (uc *GetMeUseCase) Execute(ctx context.Context, userID string) (*dto.UserRe
     if err != nil {
         return nil, err
     }
-    return &dto.UserResponse{
-        ID:        u.ID,
-        Email:     u.Email.String(),
-        FirstName: u.FirstName,
-        LastName:  u.LastName,
-        CreatedAt: u.CreatedAt,
-    }, nil
+    resp := userResponse(u)
+    return &resp, nil
 }
 
@@ -17,11 +17,21 @@ func (r repoOne) GetByID(ctx context.Context, id uuid.UUID) (*domuser.User, erro
 func (r repoOne) GetByEmail(ctx context.Context, email domuser.Email) (*domuser.User, error) {
 	return r.u, nil
 }
-func (r repoOne) GetAll(ctx context.Context) ([]*domuser.User, error) {
+func (r repoOne) GetByPhone(ctx context.Context, phone domuser.Phone) (*domuser.User, error) {
+	return r.u, nil
+}
+func (r repoOne) List(ctx context.Context, filter domuser.ListFilter) ([]*domuser.User, error) {
 	return []*domuser.User{r.u}, nil
 }
 func (r repoOne) Update(ctx context.Context, u *domuser.User) error { return nil }
 func (r repoOne) Delete(ctx context.Context, id uuid.UUID) error    { return nil }
+func (r repoOne) UpdatePasswordHash(ctx context.Context, id uuid.UUID, hash string, at time.Time) error {
+	return nil
+}
+func (r repoOne) GetByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*domuser.User, error) {
+	return r.u, nil
+}
+func (r repoOne) Erase(ctx context.Context, u *domuser.User) error { return nil }
 
 func TestGetMe_Success(t *testing.T) {
 	uid := uuid.New()
@@ -6,3 +6,9 @@ type PasswordHasher interface {
 	Hash(raw string) (string, error)
 	Compare(hashed string, raw string) bool
 }
+
+// PasswordRehasher is optionally implemented by a PasswordHasher that can tell when a stored hash
+// uses an outdated algorithm or parameters; login then saves a fresh hash of the verified password.
+type PasswordRehasher interface {
+	NeedsRehash(hashed string) bool
+}
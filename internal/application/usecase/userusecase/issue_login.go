@@ -0,0 +1,65 @@
+package userusecase
+
+import (
+	"context"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/domain/user"
+)
+
+// defaultRefreshTTLSeconds applies when REFRESH_TTL_SEC is unset (7 days).
+const defaultRefreshTTLSeconds = 3600 * 24 * 7
+
+// issueLogin signs an access token and, when a store is configured, starts a refresh-token session.
+// Every sign-in method (password, OIDC, ...) ends here once the user is authenticated, so disabled accounts are refused here.
+func issueLogin(ctx context.Context, jwt ports.TokenIssuer, store ports.RefreshTokenStore, refreshTTLSeconds int, u *user.User, meta ports.SessionMeta) (*dto.LoginResponse, error) {
+	if u.IsDisabled() {
+		return nil, apperr.ErrAccountDisabled
+	}
+	token, err := jwt.GenerateToken(u.ID.String(), string(u.Role))
+	if err != nil {
+		return nil, err
+	}
+	var refresh string
+	if store != nil {
+		ttl := refreshTTLSeconds
+		if ttl <= 0 {
+			ttl = defaultRefreshTTLSeconds
+		}
+		refresh, _ = store.Issue(ctx, u.ID.String(), ttl, meta)
+	}
+	return &dto.LoginResponse{
+		AccessToken:  token,
+		RefreshToken: refresh,
+		User:         userResponse(u),
+	}, nil
+}
+
+// completeLogin asks for a second factor when mfa is configured and the user needs one, otherwise issues tokens.
+func completeLogin(ctx context.Context, mfa *MFAUseCase, jwt ports.TokenIssuer, store ports.RefreshTokenStore, refreshTTLSeconds int, u *user.User, meta ports.SessionMeta) (*dto.LoginResponse, error) {
+	// Refused before the MFA challenge too, so a disabled user is not asked for a code that cannot help
+	if u.IsDisabled() {
+		return nil, apperr.ErrAccountDisabled
+	}
+	if mfa != nil {
+		resp, err := mfa.Challenge(ctx, u, meta)
+		if err != nil || resp != nil {
+			return resp, err
+		}
+	}
+	return issueLogin(ctx, jwt, store, refreshTTLSeconds, u, meta)
+}
+
+func userResponse(u *user.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:            u.ID,
+		Email:         u.Email.String(),
+		FirstName:     u.FirstName,
+		LastName:      u.LastName,
+		EmailVerified: u.IsEmailVerified(),
+		Phone:         u.Phone.String(),
+		CreatedAt:     u.CreatedAt,
+	}
+}
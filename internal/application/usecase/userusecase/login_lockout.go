@@ -0,0 +1,154 @@
+package userusecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// LoginLockoutAdmin lets administrators lift a lockout before it expires.
+type LoginLockoutAdmin interface {
+	Unlock(ctx context.Context, userID string) error
+}
+
+// LoginLockoutOptions tunes LoginLockoutUseCase.
+type LoginLockoutOptions struct {
+	// Threshold is the number of consecutive failures that locks the account; 0 never locks, backoff still applies
+	Threshold int
+	// LockDuration is the first lock's length; every further failure after the lock expires doubles it (default 15 minutes)
+	LockDuration time.Duration
+	// MaxLockDuration caps the doubling (default 24 hours)
+	MaxLockDuration time.Duration
+	// BackoffAfter is the number of failures before attempts are spaced out (default 3)
+	BackoffAfter int
+	// BackoffBase is the first required pause; it doubles with every further failure (default 1 second)
+	BackoffBase time.Duration
+	// FailureWindow forgets failures older than this (default 1 hour)
+	FailureWindow time.Duration
+}
+
+// LoginLockoutUseCase slows down and finally blocks password guessing against a single account.
+// Attempts refused by backoff or a lock are reported as ordinary invalid credentials, and the password is
+// not checked, so a locked account cannot be probed for the right password. It covers password logins only:
+// magic links, SMS codes and OIDC prove control of the mailbox, number or identity provider, have their own
+// attempt limits, and stay available so the owner can still get in while someone guesses the password.
+type LoginLockoutUseCase struct {
+	repo   user.Repository
+	store  ports.LoginLockoutStore
+	mailer ports.EmailSender
+	opts   LoginLockoutOptions
+}
+
+// NewLoginLockoutUseCase builds the lockout policy; mailer may be nil to skip the "account locked" email.
+func NewLoginLockoutUseCase(repo user.Repository, store ports.LoginLockoutStore, mailer ports.EmailSender, opts LoginLockoutOptions) *LoginLockoutUseCase {
+	if opts.LockDuration <= 0 {
+		opts.LockDuration = 15 * time.Minute
+	}
+	if opts.MaxLockDuration <= 0 {
+		opts.MaxLockDuration = 24 * time.Hour
+	}
+	if opts.MaxLockDuration < opts.LockDuration {
+		opts.MaxLockDuration = opts.LockDuration
+	}
+	if opts.BackoffAfter <= 0 {
+		opts.BackoffAfter = 3
+	}
+	if opts.BackoffBase <= 0 {
+		opts.BackoffBase = time.Second
+	}
+	if opts.FailureWindow <= 0 {
+		opts.FailureWindow = time.Hour
+	}
+	return &LoginLockoutUseCase{repo: repo, store: store, mailer: mailer, opts: opts}
+}
+
+// allowed reports whether userID may try a password at now, i.e. it is neither locked nor inside a backoff pause.
+func (uc *LoginLockoutUseCase) allowed(ctx context.Context, userID uuid.UUID, now time.Time) (bool, error) {
+	state, err := uc.store.Get(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if now.Before(state.LockedUntil) {
+		return false, nil
+	}
+	if state.LastFailedAt.Before(now.Add(-uc.opts.FailureWindow)) {
+		return true, nil
+	}
+	wait := uc.backoff(state.FailedAttempts)
+	return wait == 0 || !now.Before(state.LastFailedAt.Add(wait)), nil
+}
+
+// recordFailure counts a wrong password and locks the account once the threshold is reached. The owner is
+// emailed when a lock starts, not on every extension. It is best effort: login already fails either way.
+func (uc *LoginLockoutUseCase) recordFailure(ctx context.Context, u *user.User, now time.Time) {
+	state, err := uc.store.RecordFailure(ctx, u.ID, now, now.Add(-uc.opts.FailureWindow))
+	if err != nil || uc.opts.Threshold <= 0 || state.FailedAttempts < uc.opts.Threshold {
+		return
+	}
+	until := now.Add(uc.lockDuration(state.FailedAttempts))
+	if err := uc.store.Lock(ctx, u.ID, until); err != nil {
+		return
+	}
+	if state.FailedAttempts == uc.opts.Threshold {
+		_ = uc.notifyLocked(ctx, u, state.FailedAttempts, until)
+	}
+}
+
+// recordSuccess clears the failure count after a correct password.
+func (uc *LoginLockoutUseCase) recordSuccess(ctx context.Context, userID uuid.UUID) {
+	_ = uc.store.Reset(ctx, userID)
+}
+
+// Unlock clears failures and any active lock for userID.
+func (uc *LoginLockoutUseCase) Unlock(ctx context.Context, userID string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return user.ErrInvalidID
+	}
+	if _, err := uc.repo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return uc.store.Reset(ctx, id)
+}
+
+// backoff is the pause required after failures consecutive failures: none below BackoffAfter,
+// then BackoffBase doubling per failure, never longer than LockDuration.
+func (uc *LoginLockoutUseCase) backoff(failures int) time.Duration {
+	if failures < uc.opts.BackoffAfter {
+		return 0
+	}
+	return doubled(uc.opts.BackoffBase, failures-uc.opts.BackoffAfter, uc.opts.LockDuration)
+}
+
+// lockDuration is LockDuration for the failure that reaches the threshold, doubling for each later one up to MaxLockDuration.
+func (uc *LoginLockoutUseCase) lockDuration(failures int) time.Duration {
+	return doubled(uc.opts.LockDuration, failures-uc.opts.Threshold, uc.opts.MaxLockDuration)
+}
+
+// doubled returns base*2^n capped at limit without overflowing.
+func doubled(base time.Duration, n int, limit time.Duration) time.Duration {
+	d := base
+	for i := 0; i < n && d < limit; i++ {
+		d *= 2
+	}
+	if d > limit {
+		return limit
+	}
+	return d
+}
+
+func (uc *LoginLockoutUseCase) notifyLocked(ctx context.Context, u *user.User, failures int, until time.Time) error {
+	if uc.mailer == nil {
+		return nil
+	}
+	body := fmt.Sprintf("Hello %s,\n\nYour account was locked after %d failed sign-in attempts. You can sign in again after %s.\n\nIf this was not you, someone may be guessing your password; consider changing it once you are signed in. If you need access sooner, contact an administrator.\n",
+		u.FirstName, failures, until.UTC().Format("2006-01-02 15:04 MST"))
+	return uc.mailer.Send(ctx, u.Email.String(), "Your account was temporarily locked", body)
+}
+
+var _ LoginLockoutAdmin = (*LoginLockoutUseCase)(nil)
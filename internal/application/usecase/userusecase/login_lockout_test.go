@@ -0,0 +1,139 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	domuser "gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+type sentEmail struct{ to, subject, body string }
+
+func newLockoutLogin(t *testing.T, opts LoginLockoutOptions) (*LoginUserUseCase, fakeLockoutStore, *[]sentEmail) {
+	t.Helper()
+	u := &domuser.User{ID: uuid.New(), FirstName: "John", Email: domuser.Email("john@example.com"), Password: "hashed:pass", Role: domuser.RoleUser, CreatedAt: time.Now()}
+	repo := &fakeRepo{user: u}
+	store := fakeLockoutStore{}
+	var sent []sentEmail
+	mailer := ports.SendEmailFunc(func(_ context.Context, to, subject, body string) error {
+		sent = append(sent, sentEmail{to, subject, body})
+		return nil
+	})
+	lockout := NewLoginLockoutUseCase(repo, store, mailer, opts)
+	return &LoginUserUseCase{repo: repo, hasher: fakeHasher{}, jwt: fakeTokenIssuer{}, lockout: lockout}, store, &sent
+}
+
+func TestLoginLockout_LocksAfterThresholdAndHidesIt(t *testing.T) {
+	uc, store, sent := newLockoutLogin(t, LoginLockoutOptions{Threshold: 3, BackoffAfter: 100, LockDuration: time.Minute})
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := uc.Execute(ctx, dto.LoginRequest{Email: "john@example.com", Password: "wrong"}); !errors.Is(err, apperr.ErrInvalidCredentials) {
+			t.Fatalf("attempt %d: expected invalid credentials, got %v", i+1, err)
+		}
+	}
+	state, _ := store.Get(ctx, uc.repo.(*fakeRepo).user.ID)
+	if state.LockedUntil.IsZero() {
+		t.Fatalf("expected the account to be locked after 3 failures")
+	}
+	// The right password is refused with the same generic error while locked
+	if _, err := uc.Execute(ctx, dto.LoginRequest{Email: "john@example.com", Password: "pass"}); !errors.Is(err, apperr.ErrInvalidCredentials) {
+		t.Fatalf("expected invalid credentials while locked, got %v", err)
+	}
+	if len(*sent) != 1 || (*sent)[0].to != "john@example.com" || !strings.Contains((*sent)[0].body, "3 failed") {
+		t.Fatalf("expected one lock notification, got %+v", *sent)
+	}
+}
+
+func TestLoginLockout_BackoffRefusesRapidRetries(t *testing.T) {
+	uc, store, _ := newLockoutLogin(t, LoginLockoutOptions{Threshold: 10, BackoffAfter: 2, BackoffBase: time.Hour})
+	ctx := context.Background()
+	id := uc.repo.(*fakeRepo).user.ID
+	for i := 0; i < 2; i++ {
+		_, _ = uc.Execute(ctx, dto.LoginRequest{Email: "john@example.com", Password: "wrong"})
+	}
+	if _, err := uc.Execute(ctx, dto.LoginRequest{Email: "john@example.com", Password: "pass"}); !errors.Is(err, apperr.ErrInvalidCredentials) {
+		t.Fatalf("expected the attempt inside the backoff pause to fail, got %v", err)
+	}
+	// Refused attempts are not counted as failures
+	if state, _ := store.Get(ctx, id); state.FailedAttempts != 2 {
+		t.Fatalf("expected 2 recorded failures, got %d", state.FailedAttempts)
+	}
+}
+
+func TestLoginLockout_ZeroThresholdNeverLocks(t *testing.T) {
+	uc, store, sent := newLockoutLogin(t, LoginLockoutOptions{Threshold: 0, BackoffAfter: 100})
+	ctx := context.Background()
+	id := uc.repo.(*fakeRepo).user.ID
+	for i := 0; i < 20; i++ {
+		_, _ = uc.Execute(ctx, dto.LoginRequest{Email: "john@example.com", Password: "wrong"})
+	}
+	if state, _ := store.Get(ctx, id); !state.LockedUntil.IsZero() || state.FailedAttempts != 20 {
+		t.Fatalf("expected 20 counted failures and no lock, got %+v", state)
+	}
+	if len(*sent) != 0 {
+		t.Fatalf("expected no lock email, got %d", len(*sent))
+	}
+	if _, err := uc.Execute(ctx, dto.LoginRequest{Email: "john@example.com", Password: "pass"}); err != nil {
+		t.Fatalf("expected login to succeed with locking disabled, got %v", err)
+	}
+}
+
+func TestLoginLockout_SuccessResetsAndAdminUnlocks(t *testing.T) {
+	uc, store, _ := newLockoutLogin(t, LoginLockoutOptions{Threshold: 2, BackoffAfter: 100})
+	ctx := context.Background()
+	id := uc.repo.(*fakeRepo).user.ID
+
+	_, _ = uc.Execute(ctx, dto.LoginRequest{Email: "john@example.com", Password: "wrong"})
+	if _, err := uc.Execute(ctx, dto.LoginRequest{Email: "john@example.com", Password: "pass"}); err != nil {
+		t.Fatalf("expected login below the threshold to succeed, got %v", err)
+	}
+	if state, _ := store.Get(ctx, id); state.FailedAttempts != 0 {
+		t.Fatalf("expected failures cleared by a successful login, got %d", state.FailedAttempts)
+	}
+
+	for i := 0; i < 2; i++ {
+		_, _ = uc.Execute(ctx, dto.LoginRequest{Email: "john@example.com", Password: "wrong"})
+	}
+	if err := uc.lockout.Unlock(ctx, "not-a-uuid"); !errors.Is(err, domuser.ErrInvalidID) {
+		t.Fatalf("expected invalid id, got %v", err)
+	}
+	if err := uc.lockout.Unlock(ctx, id.String()); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	if _, err := uc.Execute(ctx, dto.LoginRequest{Email: "john@example.com", Password: "pass"}); err != nil {
+		t.Fatalf("expected login after unlock, got %v", err)
+	}
+}
+
+func TestLoginLockout_DurationsGrowAndCap(t *testing.T) {
+	uc := NewLoginLockoutUseCase(nil, nil, nil, LoginLockoutOptions{Threshold: 5, LockDuration: time.Minute, MaxLockDuration: 5 * time.Minute, BackoffAfter: 2, BackoffBase: time.Second})
+	cases := []struct {
+		failures int
+		backoff  time.Duration
+		lock     time.Duration
+	}{
+		{1, 0, time.Minute},
+		{2, time.Second, time.Minute},
+		{4, 4 * time.Second, time.Minute},
+		{5, 8 * time.Second, time.Minute},
+		{6, 16 * time.Second, 2 * time.Minute},
+		{8, time.Minute, 5 * time.Minute},
+		{1000, time.Minute, 5 * time.Minute},
+	}
+	for _, c := range cases {
+		if got := uc.backoff(c.failures); got != c.backoff {
+			t.Errorf("backoff(%d) = %s, want %s", c.failures, got, c.backoff)
+		}
+		if got := uc.lockDuration(c.failures); got != c.lock {
+			t.Errorf("lockDuration(%d) = %s, want %s", c.failures, got, c.lock)
+		}
+	}
+}
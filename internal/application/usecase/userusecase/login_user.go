@@ -2,6 +2,7 @@ package userusecase
 
 import (
 	"context"
+	"time"
 
 	"gostartkit/internal/application/apperr"
 	"gostartkit/internal/application/dto"
@@ -15,6 +16,12 @@ type LoginUserUseCase struct {
 	jwt               ports.TokenIssuer
 	store             ports.RefreshTokenStore
 	refreshTTLSeconds int
+	// mfa, when set, may turn a successful password check into an MFA challenge
+	mfa *MFAUseCase
+	// requireVerifiedEmail refuses accounts whose email address is not confirmed yet
+	requireVerifiedEmail bool
+	// lockout, when set, spaces out and eventually blocks repeated wrong passwords
+	lockout *LoginLockoutUseCase
 }
 
 func (uc *LoginUserUseCase) Execute(ctx context.Context, input dto.LoginRequest) (*dto.LoginResponse, error) {
@@ -27,32 +34,53 @@ func (uc *LoginUserUseCase) Execute(ctx context.Context, input dto.LoginRequest)
 	if err != nil {
 		return nil, apperr.ErrInvalidCredentials
 	}
+	now := time.Now()
+	if uc.lockout != nil {
+		ok, err := uc.lockout.allowed(ctx, u.ID, now)
+		if err != nil {
+			return nil, err
+		}
+		// Locked and backed-off attempts look like a wrong password, so they do not reveal the lock
+		if !ok {
+			return nil, apperr.ErrInvalidCredentials
+		}
+	}
 	if !uc.hasher.Compare(u.Password, input.Password) {
+		if uc.lockout != nil {
+			uc.lockout.recordFailure(ctx, u, now)
+		}
 		return nil, apperr.ErrInvalidCredentials
 	}
+	if uc.lockout != nil {
+		uc.lockout.recordSuccess(ctx, u.ID)
+	}
+	uc.upgradePasswordHash(ctx, u, input.Password)
+	// Checked after the password so the distinct error does not reveal which emails are registered
+	if uc.requireVerifiedEmail && !u.IsEmailVerified() {
+		return nil, apperr.ErrEmailVerificationRequired
+	}
 
-	token, err := uc.jwt.GenerateToken(u.ID.String(), string(u.Role))
+	return completeLogin(ctx, uc.mfa, uc.jwt, uc.store, uc.refreshTTLSeconds, u, ports.SessionMeta{
+		IP:          input.IP,
+		UserAgent:   input.UserAgent,
+		DeviceLabel: input.DeviceLabel,
+	})
+}
+
+// upgradePasswordHash re-hashes a just-verified password when its stored hash is outdated (e.g. bcrypt
+// after switching to Argon2id). It is best effort: the login succeeds even if saving the new hash fails.
+func (uc *LoginUserUseCase) upgradePasswordHash(ctx context.Context, u *user.User, raw string) {
+	rh, ok := uc.hasher.(PasswordRehasher)
+	if !ok || !rh.NeedsRehash(u.Password) {
+		return
+	}
+	hashed, err := uc.hasher.Hash(raw)
 	if err != nil {
-		return nil, err
+		return
 	}
-	var refresh string
-	if uc.store != nil {
-		ttl := uc.refreshTTLSeconds
-		if ttl <= 0 {
-			ttl = 3600 * 24 * 7
-		}
-		refresh, _ = uc.store.Issue(ctx, u.ID.String(), ttl)
+	now := time.Now()
+	if err := uc.repo.UpdatePasswordHash(ctx, u.ID, hashed, now); err != nil {
+		return
 	}
-
-	return &dto.LoginResponse{
-		AccessToken:  token,
-		RefreshToken: refresh,
-		User: dto.UserResponse{
-			ID:        u.ID,
-			Email:     u.Email.String(),
-			FirstName: u.FirstName,
-			LastName:  u.LastName,
-			CreatedAt: u.CreatedAt,
-		},
-	}, nil
+	u.Password, u.UpdatedAt = hashed, now
 }
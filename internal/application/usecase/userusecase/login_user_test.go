@@ -2,6 +2,8 @@ package userusecase
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,11 +23,22 @@ func (f *fakeRepo) GetByID(ctx context.Context, id uuid.UUID) (*domuser.User, er
 func (f *fakeRepo) GetByEmail(ctx context.Context, email domuser.Email) (*domuser.User, error) {
 	return f.user, nil
 }
-func (f *fakeRepo) GetAll(ctx context.Context) ([]*domuser.User, error) {
+func (f *fakeRepo) GetByPhone(ctx context.Context, phone domuser.Phone) (*domuser.User, error) {
+	return f.user, nil
+}
+func (f *fakeRepo) List(ctx context.Context, filter domuser.ListFilter) ([]*domuser.User, error) {
 	return []*domuser.User{f.user}, nil
 }
 func (f *fakeRepo) Update(ctx context.Context, u *domuser.User) error { f.user = u; return nil }
-func (f *fakeRepo) Delete(ctx context.Context, id uuid.UUID) error    { return nil }
+func (f *fakeRepo) UpdatePasswordHash(ctx context.Context, id uuid.UUID, hash string, at time.Time) error {
+	f.user.Password, f.user.UpdatedAt = hash, at
+	return nil
+}
+func (f *fakeRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
+func (f *fakeRepo) GetByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*domuser.User, error) {
+	return f.user, nil
+}
+func (f *fakeRepo) Erase(ctx context.Context, u *domuser.User) error { f.user = u; return nil }
 
 type fakeHasher struct{}
 
@@ -38,27 +51,6 @@ func (fakeTokenIssuer) GenerateToken(userID string, role string) (string, error)
 	return "token:" + userID, nil
 }
 
-type fakeRefreshStore struct{ issued map[string]time.Time }
-
-func (s *fakeRefreshStore) Issue(ctx context.Context, userID string, ttlSeconds int) (string, error) {
-	if s.issued == nil {
-		s.issued = make(map[string]time.Time)
-	}
-	tok := "rftoken:" + userID
-	s.issued[tok] = time.Now().Add(time.Duration(ttlSeconds) * time.Second)
-	return tok, nil
-}
-func (s *fakeRefreshStore) Rotate(ctx context.Context, oldToken string, ttlSeconds int) (string, string, error) {
-	return "", "", nil
-}
-func (s *fakeRefreshStore) Revoke(ctx context.Context, token string) error {
-	delete(s.issued, token)
-	return nil
-}
-func (s *fakeRefreshStore) Validate(ctx context.Context, token string) (string, error) {
-	return "", nil
-}
-
 var _ ports.TokenIssuer = (*fakeTokenIssuer)(nil)
 
 func TestLoginUserUseCase_Success(t *testing.T) {
@@ -68,7 +60,7 @@ func TestLoginUserUseCase_Success(t *testing.T) {
 	repo := &fakeRepo{user: u}
 	hasher := fakeHasher{}
 	jwt := fakeTokenIssuer{}
-	store := &fakeRefreshStore{}
+	store := newFakeRefreshStore()
 	uc := &LoginUserUseCase{repo: repo, hasher: hasher, jwt: jwt, store: store, refreshTTLSeconds: 60}
 
 	// Act
@@ -81,8 +73,8 @@ func TestLoginUserUseCase_Success(t *testing.T) {
 	if resp.AccessToken == "" {
 		t.Fatalf("expected access token")
 	}
-	if resp.RefreshToken == "" {
-		t.Fatalf("expected refresh token")
+	if got, err := store.Validate(context.Background(), resp.RefreshToken); err != nil || got != uid.String() {
+		t.Fatalf("expected refresh token issued for user, got %q (%v)", got, err)
 	}
 	if resp.User.Email != "john@example.com" {
 		t.Fatalf("unexpected user email: %s", resp.User.Email)
@@ -102,3 +94,38 @@ func TestLoginUserUseCase_InvalidPassword(t *testing.T) {
 		t.Fatalf("expected error for invalid credentials")
 	}
 }
+
+// upgradingHasher verifies legacy "hashed:" values and writes "v2:" ones.
+type upgradingHasher struct{}
+
+func (upgradingHasher) Hash(raw string) (string, error) { return "v2:" + raw, nil }
+func (upgradingHasher) Compare(hashed string, raw string) bool {
+	return hashed == "hashed:"+raw || hashed == "v2:"+raw
+}
+func (upgradingHasher) NeedsRehash(hashed string) bool { return !strings.HasPrefix(hashed, "v2:") }
+
+// noFullUpdates fails whole-row updates, so only targeted writes can succeed.
+type noFullUpdates struct{ *fakeRepo }
+
+func (noFullUpdates) Update(context.Context, *domuser.User) error {
+	return errors.New("unexpected full-row update")
+}
+
+func TestLoginUserUseCase_RehashesOutdatedHash(t *testing.T) {
+	u := &domuser.User{ID: uuid.New(), Email: domuser.Email("john@example.com"), Password: "hashed:pass", Role: domuser.RoleUser, CreatedAt: time.Now()}
+	repo := &fakeRepo{user: u}
+	uc := &LoginUserUseCase{repo: noFullUpdates{repo}, hasher: upgradingHasher{}, jwt: fakeTokenIssuer{}}
+
+	if _, err := uc.Execute(context.Background(), dto.LoginRequest{Email: "john@example.com", Password: "pass"}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if repo.user.Password != "v2:pass" || repo.user.UpdatedAt.IsZero() {
+		t.Fatalf("expected upgraded hash to be saved with updated_at, got %q at %v", repo.user.Password, repo.user.UpdatedAt)
+	}
+	// Wrong passwords never trigger a rehash
+	repo.user.Password = "hashed:pass"
+	_, _ = uc.Execute(context.Background(), dto.LoginRequest{Email: "john@example.com", Password: "nope"})
+	if repo.user.Password != "hashed:pass" {
+		t.Fatalf("expected hash untouched after a failed login, got %q", repo.user.Password)
+	}
+}
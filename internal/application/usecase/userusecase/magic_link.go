@@ -0,0 +1,138 @@
+package userusecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// MagicLinkLogin signs users in through a one-time link sent to their email address.
+type MagicLinkLogin interface {
+	Request(ctx context.Context, input dto.MagicLinkRequest) (*dto.MagicLinkStartResponse, error)
+	Consume(ctx context.Context, input dto.MagicLinkConsumeRequest) (*dto.LoginResponse, error)
+}
+
+// MagicLinkOptions tunes MagicLinkUseCase.
+type MagicLinkOptions struct {
+	// LinkBaseURL is the page that posts the token back to /consume; the emailed link is LinkBaseURL?token=...
+	LinkBaseURL string
+	// TTL bounds how long a link stays valid (default 15 minutes)
+	TTL time.Duration
+}
+
+// MagicLinkUseCase implements MagicLinkLogin. Each link is single-use and only works together with the
+// nonce handed to the browser that requested it, so a forwarded or intercepted email alone is not enough.
+type MagicLinkUseCase struct {
+	repo              user.Repository
+	jwt               ports.TokenIssuer
+	store             ports.RefreshTokenStore
+	refreshTTLSeconds int
+	links             ports.MagicLinkStore
+	mailer            ports.EmailSender
+	mfa               *MFAUseCase
+	opts              MagicLinkOptions
+}
+
+// NewMagicLinkUseCase accepts a nil mfa (no second factor) and a nil store (access tokens only).
+func NewMagicLinkUseCase(repo user.Repository, jwt ports.TokenIssuer, store ports.RefreshTokenStore, refreshTTLSeconds int,
+	links ports.MagicLinkStore, mailer ports.EmailSender, mfa *MFAUseCase, opts MagicLinkOptions) *MagicLinkUseCase {
+	if opts.TTL <= 0 {
+		opts.TTL = 15 * time.Minute
+	}
+	return &MagicLinkUseCase{
+		repo: repo, jwt: jwt, store: store, refreshTTLSeconds: refreshTTLSeconds,
+		links: links, mailer: mailer, mfa: mfa, opts: opts,
+	}
+}
+
+// Request always returns a fresh nonce, and only emails a link when the address belongs to an account.
+// A failed send is not reported (the sender logs it), since an error would only occur for registered addresses.
+func (uc *MagicLinkUseCase) Request(ctx context.Context, input dto.MagicLinkRequest) (*dto.MagicLinkStartResponse, error) {
+	emailVO, err := user.NewEmail(input.Email)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomURLToken(32)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.MagicLinkStartResponse{Nonce: nonce}
+	u, err := uc.repo.GetByEmail(ctx, emailVO)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return resp, nil
+		}
+		return nil, err
+	}
+	token, err := randomURLToken(32)
+	if err != nil {
+		return nil, err
+	}
+	nonceHash := sha256.Sum256([]byte(nonce))
+	if err := uc.links.Save(ctx, magicLinkHash(token), ports.MagicLink{UserID: u.ID.String(), NonceHash: nonceHash[:]}, uc.opts.TTL); err != nil {
+		return nil, err
+	}
+	link := uc.opts.LinkBaseURL + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Hello %s,\n\nUse the link below to sign in. Open it in the same browser where you asked for it:\n\n%s\n\nThe link expires in %s and works once. If you did not ask for it, you can ignore this email.\n",
+		u.FirstName, link, uc.opts.TTL)
+	_ = uc.mailer.Send(ctx, u.Email.String(), "Your sign-in link", body)
+	return resp, nil
+}
+
+func (uc *MagicLinkUseCase) Consume(ctx context.Context, input dto.MagicLinkConsumeRequest) (*dto.LoginResponse, error) {
+	if input.Nonce == "" {
+		return nil, apperr.ErrMagicLinkInvalid
+	}
+	link, err := uc.links.Consume(ctx, magicLinkHash(strings.TrimSpace(input.Token)))
+	if err != nil {
+		return nil, err
+	}
+	nonceHash := sha256.Sum256([]byte(input.Nonce))
+	if subtle.ConstantTimeCompare(nonceHash[:], link.NonceHash) != 1 {
+		return nil, apperr.ErrMagicLinkInvalid
+	}
+	id, err := uuid.Parse(link.UserID)
+	if err != nil {
+		return nil, apperr.ErrMagicLinkInvalid
+	}
+	u, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, apperr.ErrMagicLinkInvalid
+		}
+		return nil, err
+	}
+	if !u.IsEmailVerified() {
+		// Opening the emailed link proves ownership of the address
+		u.MarkEmailVerified(time.Now())
+		if err := uc.repo.Update(ctx, u); err != nil {
+			return nil, err
+		}
+	}
+	return completeLogin(ctx, uc.mfa, uc.jwt, uc.store, uc.refreshTTLSeconds, u, ports.SessionMeta{
+		IP:          input.IP,
+		UserAgent:   input.UserAgent,
+		DeviceLabel: input.DeviceLabel,
+	})
+}
+
+// magicLinkHash keys stored links, so the store never holds a usable token.
+func magicLinkHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+var _ MagicLinkLogin = (*MagicLinkUseCase)(nil)
@@ -0,0 +1,79 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	domuser "gostartkit/internal/domain/user"
+)
+
+func TestMagicLink_SignsInOnceFromRequestingBrowser(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Gil", "Hart", domuser.Email("gil@example.com"), "hashed:x", domuser.RoleUser)
+	mail := outbox{}
+	uc := NewMagicLinkUseCase(usersByEmail{u.Email: u}, fakeTokenIssuer{}, nil, 0, fakeMagicLinkStore{}, mail.sender(), nil,
+		MagicLinkOptions{LinkBaseURL: "https://app.example/magic"})
+
+	started, err := uc.Request(ctx, dto.MagicLinkRequest{Email: "gil@example.com"})
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	token := mail.linkToken(t, "gil@example.com")
+
+	// A link requested from another browser does not work with this browser's nonce, and the attempt burns it
+	if _, err := uc.Request(ctx, dto.MagicLinkRequest{Email: "gil@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.Consume(ctx, dto.MagicLinkConsumeRequest{Token: mail.linkToken(t, "gil@example.com"), Nonce: started.Nonce}); !errors.Is(err, apperr.ErrMagicLinkInvalid) {
+		t.Fatalf("expected nonce mismatch to be rejected, got %v", err)
+	}
+
+	resp, err := uc.Consume(ctx, dto.MagicLinkConsumeRequest{Token: token, Nonce: started.Nonce})
+	if err != nil {
+		t.Fatalf("consume: %v", err)
+	}
+	if resp.AccessToken == "" || !resp.User.EmailVerified {
+		t.Fatalf("expected access token and verified email, got %+v", resp)
+	}
+	if _, err := uc.Consume(ctx, dto.MagicLinkConsumeRequest{Token: token, Nonce: started.Nonce}); !errors.Is(err, apperr.ErrMagicLinkInvalid) {
+		t.Fatalf("expected link to be single use, got %v", err)
+	}
+}
+
+func TestMagicLink_UnknownEmailAndExpiredLink(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Ivy", "Jo", domuser.Email("ivy@example.com"), "hashed:x", domuser.RoleUser)
+	mail := outbox{}
+	uc := NewMagicLinkUseCase(usersByEmail{u.Email: u}, fakeTokenIssuer{}, nil, 0, fakeMagicLinkStore{}, mail.sender(), nil,
+		MagicLinkOptions{LinkBaseURL: "https://app.example/magic", TTL: time.Nanosecond})
+
+	res, err := uc.Request(ctx, dto.MagicLinkRequest{Email: "nobody@example.com"})
+	if err != nil || res.Nonce == "" || len(mail) != 0 {
+		t.Fatalf("expected a nonce and no email for unknown address: err=%v mails=%d", err, len(mail))
+	}
+	started, err := uc.Request(ctx, dto.MagicLinkRequest{Email: "ivy@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, err := uc.Consume(ctx, dto.MagicLinkConsumeRequest{Token: mail.linkToken(t, "ivy@example.com"), Nonce: started.Nonce}); !errors.Is(err, apperr.ErrMagicLinkInvalid) {
+		t.Fatalf("expected expired link to be rejected, got %v", err)
+	}
+}
+
+func TestMagicLink_RequestHidesSendFailures(t *testing.T) {
+	u := domuser.NewUser("Kim", "Lee", domuser.Email("kim@example.com"), "hashed:x", domuser.RoleUser)
+	failing := ports.SendEmailFunc(func(context.Context, string, string, string) error { return errors.New("smtp down") })
+	uc := NewMagicLinkUseCase(usersByEmail{u.Email: u}, fakeTokenIssuer{}, nil, 0, fakeMagicLinkStore{}, failing, nil,
+		MagicLinkOptions{LinkBaseURL: "https://app.example/magic"})
+
+	// An error here would tell a registered address from an unknown one
+	if res, err := uc.Request(context.Background(), dto.MagicLinkRequest{Email: "kim@example.com"}); err != nil || res.Nonce == "" {
+		t.Fatalf("expected a nonce despite the failed send, got %+v %v", res, err)
+	}
+}
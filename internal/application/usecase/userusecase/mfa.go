@@ -0,0 +1,350 @@
+package userusecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// MFAUsecases manages TOTP enrollment and completes logins that require a second factor.
+type MFAUsecases interface {
+	Enroll(ctx context.Context, userID string) (*dto.MFAEnrollResponse, error)
+	Confirm(ctx context.Context, userID string, input dto.MFACodeRequest) (*dto.MFARecoveryCodesResponse, error)
+	Disable(ctx context.Context, userID string, input dto.MFACodeRequest) error
+	Verify(ctx context.Context, input dto.MFAVerifyRequest) (*dto.LoginResponse, error)
+}
+
+// MFAOptions tunes MFAUseCase.
+type MFAOptions struct {
+	// Issuer is the account label shown in authenticator apps
+	Issuer string
+	// RequiredRoles must complete MFA on every login; unenrolled users are enrolled during login.
+	RequiredRoles []user.Role
+	// ChallengeTTL bounds how long an mfa_token stays valid (default 5 minutes)
+	ChallengeTTL time.Duration
+	// MaxAttempts wrong codes invalidate the mfa_token, and lock out confirm and disable for LockoutWindow (default 5)
+	MaxAttempts int
+	// LockoutWindow is how long wrong codes on confirm and disable are counted (default 15 minutes)
+	LockoutWindow time.Duration
+}
+
+const (
+	recoveryCodeCount = 10
+	defaultMFAIssuer  = "gostartkit"
+)
+
+// MFAUseCase implements MFAUsecases. Login use cases call Challenge before issuing tokens.
+type MFAUseCase struct {
+	repo              user.Repository
+	jwt               ports.TokenIssuer
+	store             ports.RefreshTokenStore
+	refreshTTLSeconds int
+	enrollments       ports.MFAStore
+	challenges        ports.MFAChallengeStore
+	failures          ports.FailureCounter
+	box               ports.SecretBox
+	totp              ports.TOTP
+	opts              MFAOptions
+}
+
+func NewMFAUseCase(repo user.Repository, jwt ports.TokenIssuer, store ports.RefreshTokenStore, refreshTTLSeconds int,
+	enrollments ports.MFAStore, challenges ports.MFAChallengeStore, failures ports.FailureCounter, box ports.SecretBox, totp ports.TOTP, opts MFAOptions) *MFAUseCase {
+	if opts.Issuer == "" {
+		opts.Issuer = defaultMFAIssuer
+	}
+	if opts.ChallengeTTL <= 0 {
+		opts.ChallengeTTL = 5 * time.Minute
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.LockoutWindow <= 0 {
+		opts.LockoutWindow = 15 * time.Minute
+	}
+	return &MFAUseCase{
+		repo: repo, jwt: jwt, store: store, refreshTTLSeconds: refreshTTLSeconds,
+		enrollments: enrollments, challenges: challenges, failures: failures, box: box, totp: totp, opts: opts,
+	}
+}
+
+// Requires reports whether policy forces MFA for role.
+func (uc *MFAUseCase) Requires(role user.Role) bool {
+	for _, r := range uc.opts.RequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Challenge is called once the first factor succeeded. It returns nil when no second factor is needed;
+// otherwise a response carrying an mfa_token (and a fresh secret when policy forces enrollment).
+func (uc *MFAUseCase) Challenge(ctx context.Context, u *user.User, meta ports.SessionMeta) (*dto.LoginResponse, error) {
+	e, err := uc.enrollments.Get(ctx, u.ID)
+	if err != nil && !errors.Is(err, apperr.ErrMFANotEnrolled) {
+		return nil, err
+	}
+	enrolled := err == nil && e.Confirmed
+	if !enrolled && !uc.Requires(u.Role) {
+		return nil, nil
+	}
+	resp := &dto.LoginResponse{User: userResponse(u), MFARequired: true}
+	if !enrolled {
+		if resp.MFASetup, err = uc.startEnrollment(ctx, u); err != nil {
+			return nil, err
+		}
+	}
+	token, err := randomURLToken(32)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.challenges.Save(ctx, token, ports.MFAChallenge{UserID: u.ID.String(), Meta: meta, Setup: !enrolled}, uc.opts.ChallengeTTL); err != nil {
+		return nil, err
+	}
+	resp.MFAToken = token
+	return resp, nil
+}
+
+func (uc *MFAUseCase) Verify(ctx context.Context, input dto.MFAVerifyRequest) (*dto.LoginResponse, error) {
+	c, err := uc.challenges.Get(ctx, input.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+	uid, err := uuid.Parse(c.UserID)
+	if err != nil {
+		return nil, apperr.ErrMFATokenInvalid
+	}
+	step, ok, err := uc.checkCode(ctx, uid, input.Code, !c.Setup)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		n, err := uc.challenges.RecordFailure(ctx, input.MFAToken)
+		if err != nil {
+			return nil, err
+		}
+		if n >= uc.opts.MaxAttempts {
+			_ = uc.challenges.Delete(ctx, input.MFAToken)
+		}
+		return nil, apperr.ErrMFACodeInvalid
+	}
+	if err := uc.challenges.Delete(ctx, input.MFAToken); err != nil {
+		return nil, err
+	}
+	var codes []string
+	if c.Setup {
+		if codes, err = uc.confirm(ctx, uid, step); err != nil {
+			return nil, err
+		}
+	}
+	u, err := uc.repo.GetByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := issueLogin(ctx, uc.jwt, uc.store, uc.refreshTTLSeconds, u, c.Meta)
+	if err != nil {
+		return nil, err
+	}
+	resp.RecoveryCodes = codes
+	return resp, nil
+}
+
+func (uc *MFAUseCase) Enroll(ctx context.Context, userID string) (*dto.MFAEnrollResponse, error) {
+	u, err := uc.loadUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.startEnrollment(ctx, u)
+}
+
+func (uc *MFAUseCase) Confirm(ctx context.Context, userID string, input dto.MFACodeRequest) (*dto.MFARecoveryCodesResponse, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, user.ErrInvalidID
+	}
+	e, err := uc.enrollments.Get(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if e.Confirmed {
+		return nil, apperr.ErrMFAAlreadyEnabled
+	}
+	step, err := uc.checkCodeLimited(ctx, uid, input.Code, false)
+	if err != nil {
+		return nil, err
+	}
+	codes, err := uc.confirm(ctx, uid, step)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable removes the factor after checking a current code, unless policy requires MFA for the user's role.
+func (uc *MFAUseCase) Disable(ctx context.Context, userID string, input dto.MFACodeRequest) error {
+	u, err := uc.loadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if uc.Requires(u.Role) {
+		return apperr.ErrMFARequired
+	}
+	e, err := uc.enrollments.Get(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	if !e.Confirmed {
+		return uc.enrollments.Delete(ctx, u.ID)
+	}
+	if _, err := uc.checkCodeLimited(ctx, u.ID, input.Code, true); err != nil {
+		return err
+	}
+	return uc.enrollments.Delete(ctx, u.ID)
+}
+
+func (uc *MFAUseCase) startEnrollment(ctx context.Context, u *user.User) (*dto.MFAEnrollResponse, error) {
+	e, err := uc.enrollments.Get(ctx, u.ID)
+	if err == nil && e.Confirmed {
+		return nil, apperr.ErrMFAAlreadyEnabled
+	}
+	if err != nil && !errors.Is(err, apperr.ErrMFANotEnrolled) {
+		return nil, err
+	}
+	secret, err := uc.totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := uc.box.Seal([]byte(secret))
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.enrollments.SavePending(ctx, u.ID, sealed); err != nil {
+		return nil, err
+	}
+	return &dto.MFAEnrollResponse{Secret: secret, OTPAuthURI: uc.totp.KeyURI(uc.opts.Issuer, u.Email.String(), secret)}, nil
+}
+
+// checkCodeLimited is checkCode for signed-in users, where no mfa_token bounds the guesses: after MaxAttempts
+// wrong codes within LockoutWindow every code is refused. Like login lockout, the lock looks like a wrong code.
+func (uc *MFAUseCase) checkCodeLimited(ctx context.Context, userID uuid.UUID, code string, allowRecovery bool) (int64, error) {
+	key := "mfa:" + userID.String()
+	n, err := uc.failures.Count(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if n >= uc.opts.MaxAttempts {
+		return 0, apperr.ErrMFACodeInvalid
+	}
+	step, ok, err := uc.checkCode(ctx, userID, code, allowRecovery)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		if _, err := uc.failures.Add(ctx, key, uc.opts.LockoutWindow); err != nil {
+			return 0, err
+		}
+		return 0, apperr.ErrMFACodeInvalid
+	}
+	_ = uc.failures.Reset(ctx, key)
+	return step, nil
+}
+
+// checkCode accepts a TOTP code (each time step only once) or, when allowRecovery, an unused recovery code.
+// The returned step is 0 for recovery codes.
+func (uc *MFAUseCase) checkCode(ctx context.Context, userID uuid.UUID, code string, allowRecovery bool) (int64, bool, error) {
+	e, err := uc.enrollments.Get(ctx, userID)
+	if err != nil {
+		return 0, false, err
+	}
+	code = normalizeMFACode(code)
+	if isTOTPCode(code) {
+		secret, err := uc.box.Open(e.SecretCiphertext)
+		if err != nil {
+			return 0, false, err
+		}
+		step, ok := uc.totp.Verify(string(secret), code, time.Now())
+		if !ok || step <= e.LastUsedStep {
+			return 0, false, nil
+		}
+		if !e.Confirmed {
+			return step, true, nil // recorded by Confirm
+		}
+		used, err := uc.enrollments.UseStep(ctx, userID, step)
+		return step, used, err
+	}
+	if !allowRecovery || !e.Confirmed {
+		return 0, false, nil
+	}
+	used, err := uc.enrollments.UseRecoveryCode(ctx, userID, hashRecoveryCode(code))
+	return 0, used, err
+}
+
+func (uc *MFAUseCase) confirm(ctx context.Context, userID uuid.UUID, step int64) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([][]byte, recoveryCodeCount)
+	for i := range codes {
+		c, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = c
+		hashes[i] = hashRecoveryCode(normalizeMFACode(c))
+	}
+	if err := uc.enrollments.Confirm(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (uc *MFAUseCase) loadUser(ctx context.Context, userID string) (*user.User, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, user.ErrInvalidID
+	}
+	return uc.repo.GetByID(ctx, uid)
+}
+
+// newRecoveryCode returns 50 random bits as "xxxxx-xxxxx" (lowercase base32).
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	s := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+	return s[:5] + "-" + s[5:], nil
+}
+
+func normalizeMFACode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// hashRecoveryCode uses a plain SHA-256: codes are random and high-entropy, unlike passwords.
+func hashRecoveryCode(normalized string) []byte {
+	sum := sha256.Sum256([]byte(normalized))
+	return sum[:]
+}
+
+var _ MFAUsecases = (*MFAUseCase)(nil)
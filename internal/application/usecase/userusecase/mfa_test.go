@@ -0,0 +1,254 @@
+package userusecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	domuser "gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// fakeTOTP accepts "123456" at the current step, which tests move forward to allow a new code.
+type fakeTOTP struct{ step int64 }
+
+func (f *fakeTOTP) GenerateSecret() (string, error) { return "SECRET", nil }
+func (f *fakeTOTP) KeyURI(issuer, account, s string) string {
+	return "otpauth://totp/" + issuer + ":" + account
+}
+func (f *fakeTOTP) Verify(secret, code string, at time.Time) (int64, bool) {
+	return f.step, secret == "SECRET" && code == "123456"
+}
+
+type plainBox struct{}
+
+func (plainBox) Seal(p []byte) ([]byte, error) { return append([]byte("sealed:"), p...), nil }
+func (plainBox) Open(c []byte) ([]byte, error) { return bytes.TrimPrefix(c, []byte("sealed:")), nil }
+
+type fakeMFAStore struct {
+	enrollments map[uuid.UUID]*ports.MFAEnrollment
+	recovery    map[string]bool // code hash -> used
+}
+
+func newFakeMFAStore() *fakeMFAStore {
+	return &fakeMFAStore{enrollments: map[uuid.UUID]*ports.MFAEnrollment{}, recovery: map[string]bool{}}
+}
+
+func (f *fakeMFAStore) Get(ctx context.Context, id uuid.UUID) (ports.MFAEnrollment, error) {
+	if e, ok := f.enrollments[id]; ok {
+		return *e, nil
+	}
+	return ports.MFAEnrollment{}, apperr.ErrMFANotEnrolled
+}
+func (f *fakeMFAStore) SavePending(ctx context.Context, id uuid.UUID, secret []byte) error {
+	if e, ok := f.enrollments[id]; ok && e.Confirmed {
+		return nil
+	}
+	f.enrollments[id] = &ports.MFAEnrollment{UserID: id, SecretCiphertext: secret}
+	return nil
+}
+func (f *fakeMFAStore) Confirm(ctx context.Context, id uuid.UUID, step int64, hashes [][]byte) error {
+	e := f.enrollments[id]
+	e.Confirmed, e.LastUsedStep = true, step
+	for _, h := range hashes {
+		f.recovery[string(h)] = false
+	}
+	return nil
+}
+func (f *fakeMFAStore) UseStep(ctx context.Context, id uuid.UUID, step int64) (bool, error) {
+	e := f.enrollments[id]
+	if step <= e.LastUsedStep {
+		return false, nil
+	}
+	e.LastUsedStep = step
+	return true, nil
+}
+func (f *fakeMFAStore) UseRecoveryCode(ctx context.Context, id uuid.UUID, h []byte) (bool, error) {
+	used, ok := f.recovery[string(h)]
+	if !ok || used {
+		return false, nil
+	}
+	f.recovery[string(h)] = true
+	return true, nil
+}
+func (f *fakeMFAStore) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(f.enrollments, id)
+	return nil
+}
+
+// fakeFailureCounter counts without windows; tests never run long enough for one to close.
+type fakeFailureCounter map[string]int
+
+func (f fakeFailureCounter) Count(_ context.Context, key string) (int, error) { return f[key], nil }
+func (f fakeFailureCounter) Add(_ context.Context, key string, _ time.Duration) (int, error) {
+	f[key]++
+	return f[key], nil
+}
+func (f fakeFailureCounter) Reset(_ context.Context, key string) error {
+	delete(f, key)
+	return nil
+}
+
+type mfaFixture struct {
+	login *LoginUserUseCase
+	mfa   *MFAUseCase
+	totp  *fakeTOTP
+	store *fakeMFAStore
+	user  *domuser.User
+}
+
+func newMFAFixture(role domuser.Role, required ...domuser.Role) *mfaFixture {
+	u := domuser.NewUser("Ada", "Admin", domuser.Email("ada@example.com"), "hashed:pass", role)
+	repo := &fakeRepo{user: u}
+	totp, store := &fakeTOTP{step: 100}, newFakeMFAStore()
+	refresh := newFakeRefreshStore()
+	mfa := NewMFAUseCase(repo, fakeTokenIssuer{}, refresh, 60, store, fakeMFAChallengeStore{}, fakeFailureCounter{}, plainBox{}, totp,
+		MFAOptions{RequiredRoles: required, MaxAttempts: 3})
+	login := &LoginUserUseCase{repo: repo, hasher: fakeHasher{}, jwt: fakeTokenIssuer{}, store: refresh, refreshTTLSeconds: 60, mfa: mfa}
+	return &mfaFixture{login: login, mfa: mfa, totp: totp, store: store, user: u}
+}
+
+func (f *mfaFixture) passwordLogin(t *testing.T) *dto.LoginResponse {
+	t.Helper()
+	resp, err := f.login.Execute(context.Background(), dto.LoginRequest{Email: "ada@example.com", Password: "pass"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	return resp
+}
+
+func TestMFA_NotEnrolledAndNotRequired_IssuesTokens(t *testing.T) {
+	f := newMFAFixture(domuser.RoleUser, domuser.RoleAdmin)
+	if resp := f.passwordLogin(t); resp.MFARequired || resp.AccessToken == "" {
+		t.Fatalf("expected tokens without MFA, got %+v", resp)
+	}
+}
+
+func TestMFA_RequiredRoleEnrollsDuringLogin(t *testing.T) {
+	ctx := context.Background()
+	f := newMFAFixture(domuser.RoleAdmin, domuser.RoleAdmin)
+
+	resp := f.passwordLogin(t)
+	if !resp.MFARequired || resp.AccessToken != "" || resp.MFASetup == nil || resp.MFAToken == "" {
+		t.Fatalf("expected an enrollment challenge, got %+v", resp)
+	}
+	done, err := f.mfa.Verify(ctx, dto.MFAVerifyRequest{MFAToken: resp.MFAToken, Code: "123456"})
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if done.AccessToken == "" || len(done.RecoveryCodes) != recoveryCodeCount {
+		t.Fatalf("expected tokens and recovery codes, got %+v", done)
+	}
+	if e, _ := f.store.Get(ctx, f.user.ID); !e.Confirmed {
+		t.Fatalf("expected enrollment to be confirmed")
+	}
+	// Policy keeps MFA on for admins
+	if err := f.mfa.Disable(ctx, f.user.ID.String(), dto.MFACodeRequest{Code: done.RecoveryCodes[0]}); !errors.Is(err, apperr.ErrMFARequired) {
+		t.Fatalf("expected disable to be refused, got %v", err)
+	}
+}
+
+func TestMFA_EnrolledUserVerification(t *testing.T) {
+	ctx := context.Background()
+	f := newMFAFixture(domuser.RoleUser)
+	if _, err := f.mfa.Enroll(ctx, f.user.ID.String()); err != nil {
+		t.Fatalf("enroll: %v", err)
+	}
+	codes, err := f.mfa.Confirm(ctx, f.user.ID.String(), dto.MFACodeRequest{Code: "123456"})
+	if err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+
+	// The code used for confirmation cannot be replayed in the same time step
+	challenge := f.passwordLogin(t)
+	if !challenge.MFARequired || challenge.MFASetup != nil {
+		t.Fatalf("expected a plain challenge, got %+v", challenge)
+	}
+	if _, err := f.mfa.Verify(ctx, dto.MFAVerifyRequest{MFAToken: challenge.MFAToken, Code: "123456"}); !errors.Is(err, apperr.ErrMFACodeInvalid) {
+		t.Fatalf("expected replayed code to fail, got %v", err)
+	}
+	// Recovery codes work once; surrounding whitespace is ignored
+	recovery := codes.RecoveryCodes[0]
+	if _, err := f.mfa.Verify(ctx, dto.MFAVerifyRequest{MFAToken: challenge.MFAToken, Code: " " + recovery + " "}); err != nil {
+		t.Fatalf("recovery code: %v", err)
+	}
+	if _, err := f.mfa.Verify(ctx, dto.MFAVerifyRequest{MFAToken: challenge.MFAToken, Code: "123456"}); !errors.Is(err, apperr.ErrMFATokenInvalid) {
+		t.Fatalf("expected mfa_token to be single use, got %v", err)
+	}
+	again := f.passwordLogin(t)
+	if _, err := f.mfa.Verify(ctx, dto.MFAVerifyRequest{MFAToken: again.MFAToken, Code: recovery}); !errors.Is(err, apperr.ErrMFACodeInvalid) {
+		t.Fatalf("expected used recovery code to fail, got %v", err)
+	}
+	f.totp.step++
+	if resp, err := f.mfa.Verify(ctx, dto.MFAVerifyRequest{MFAToken: again.MFAToken, Code: "123456"}); err != nil || resp.AccessToken == "" {
+		t.Fatalf("expected fresh code to succeed: %v", err)
+	}
+}
+
+func TestMFA_TooManyWrongCodesInvalidateToken(t *testing.T) {
+	ctx := context.Background()
+	f := newMFAFixture(domuser.RoleAdmin, domuser.RoleAdmin)
+	challenge := f.passwordLogin(t)
+	for i := 0; i < 3; i++ {
+		if _, err := f.mfa.Verify(ctx, dto.MFAVerifyRequest{MFAToken: challenge.MFAToken, Code: "000000"}); !errors.Is(err, apperr.ErrMFACodeInvalid) {
+			t.Fatalf("attempt %d: expected invalid code, got %v", i, err)
+		}
+	}
+	if _, err := f.mfa.Verify(ctx, dto.MFAVerifyRequest{MFAToken: challenge.MFAToken, Code: "123456"}); !errors.Is(err, apperr.ErrMFATokenInvalid) {
+		t.Fatalf("expected token to be invalidated, got %v", err)
+	}
+}
+
+func TestMFA_TooManyWrongCodesLockDisable(t *testing.T) {
+	ctx := context.Background()
+	f := newMFAFixture(domuser.RoleUser)
+	if _, err := f.mfa.Enroll(ctx, f.user.ID.String()); err != nil {
+		t.Fatalf("enroll: %v", err)
+	}
+	codes, err := f.mfa.Confirm(ctx, f.user.ID.String(), dto.MFACodeRequest{Code: "123456"})
+	if err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := f.mfa.Disable(ctx, f.user.ID.String(), dto.MFACodeRequest{Code: "000000"}); !errors.Is(err, apperr.ErrMFACodeInvalid) {
+			t.Fatalf("attempt %d: expected invalid code, got %v", i, err)
+		}
+	}
+	// Locked out: even a valid recovery code is refused and not spent
+	if err := f.mfa.Disable(ctx, f.user.ID.String(), dto.MFACodeRequest{Code: codes.RecoveryCodes[0]}); !errors.Is(err, apperr.ErrMFACodeInvalid) {
+		t.Fatalf("expected the lock to refuse a valid code, got %v", err)
+	}
+	if _, err := f.store.Get(ctx, f.user.ID); err != nil {
+		t.Fatalf("expected the factor to stay enrolled: %v", err)
+	}
+	// Once the window is over the same recovery code still works
+	_ = f.mfa.failures.Reset(ctx, "mfa:"+f.user.ID.String())
+	if err := f.mfa.Disable(ctx, f.user.ID.String(), dto.MFACodeRequest{Code: codes.RecoveryCodes[0]}); err != nil {
+		t.Fatalf("disable after the lock: %v", err)
+	}
+}
+
+func TestMFA_WrongConfirmCodesAreCountedAndResetOnSuccess(t *testing.T) {
+	ctx := context.Background()
+	f := newMFAFixture(domuser.RoleUser)
+	if _, err := f.mfa.Enroll(ctx, f.user.ID.String()); err != nil {
+		t.Fatalf("enroll: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := f.mfa.Confirm(ctx, f.user.ID.String(), dto.MFACodeRequest{Code: "000000"}); !errors.Is(err, apperr.ErrMFACodeInvalid) {
+			t.Fatalf("attempt %d: expected invalid code, got %v", i, err)
+		}
+	}
+	if _, err := f.mfa.Confirm(ctx, f.user.ID.String(), dto.MFACodeRequest{Code: "123456"}); err != nil {
+		t.Fatalf("confirm below the limit: %v", err)
+	}
+	if n, _ := f.mfa.failures.Count(ctx, "mfa:"+f.user.ID.String()); n != 0 {
+		t.Fatalf("expected success to reset the failures, got %d", n)
+	}
+}
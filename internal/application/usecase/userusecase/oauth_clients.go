@@ -0,0 +1,159 @@
+package userusecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	"gostartkit/pkg/rbac"
+)
+
+// OAuthClientCredentials issues tokens to registered services (OAuth2 client_credentials grant).
+type OAuthClientCredentials interface {
+	IssueToken(ctx context.Context, input dto.ClientCredentialsRequest) (*dto.OAuthTokenResponse, error)
+}
+
+// OAuthClientAdmin registers and removes OAuth clients.
+type OAuthClientAdmin interface {
+	// CreateClient refuses scopes the caller does not hold; holds reports the caller's own permissions.
+	CreateClient(ctx context.Context, input dto.CreateOAuthClientRequest, holds func(scope string) bool) (*dto.CreateOAuthClientResponse, error)
+	ListClients(ctx context.Context) ([]dto.OAuthClientResponse, error)
+	DeleteClient(ctx context.Context, clientID string) error
+}
+
+// OAuthClientUseCase implements OAuthClientCredentials and OAuthClientAdmin.
+// Client secrets are random and stored as SHA-256 only, like API key secrets.
+type OAuthClientUseCase struct {
+	clients  ports.OAuthClientStore
+	issuer   ports.ClientTokenIssuer
+	tokenTTL time.Duration
+}
+
+// NewOAuthClientUseCase wires the use case; tokenTTL <= 0 uses the issuer's default access token lifetime.
+func NewOAuthClientUseCase(clients ports.OAuthClientStore, issuer ports.ClientTokenIssuer, tokenTTL time.Duration) *OAuthClientUseCase {
+	return &OAuthClientUseCase{clients: clients, issuer: issuer, tokenTTL: tokenTTL}
+}
+
+func (uc *OAuthClientUseCase) IssueToken(ctx context.Context, input dto.ClientCredentialsRequest) (*dto.OAuthTokenResponse, error) {
+	client, err := uc.AuthenticateClient(ctx, input.ClientID, input.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	scopes := client.Scopes
+	if requested := strings.Fields(input.Scope); len(requested) > 0 {
+		if scopes, err = normalizeScopes(requested); err != nil {
+			return nil, err
+		}
+		for _, s := range scopes {
+			if !rbac.AnyMatches(client.Scopes, s) {
+				return nil, apperr.ErrInvalidScope
+			}
+		}
+	}
+	token, err := uc.issuer.GenerateClientToken(client.ClientID, scopes, uc.tokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.OAuthTokenResponse{AccessToken: token, TokenType: "Bearer", Scope: strings.Join(scopes, " ")}
+	if uc.tokenTTL > 0 {
+		resp.ExpiresIn = int(uc.tokenTTL / time.Second)
+	}
+	return resp, nil
+}
+
+// AuthenticateClient checks a client ID and secret; every failure is apperr.ErrInvalidClient.
+func (uc *OAuthClientUseCase) AuthenticateClient(ctx context.Context, clientID, secret string) (ports.OAuthClient, error) {
+	if clientID == "" || secret == "" {
+		return ports.OAuthClient{}, apperr.ErrInvalidClient
+	}
+	client, err := uc.clients.Get(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrOAuthClientNotFound) {
+			return ports.OAuthClient{}, apperr.ErrInvalidClient
+		}
+		return ports.OAuthClient{}, err
+	}
+	if subtle.ConstantTimeCompare(hashRandomSecret(secret), client.SecretHash) != 1 {
+		return ports.OAuthClient{}, apperr.ErrInvalidClient
+	}
+	return client, nil
+}
+
+// CreateClient registers a client. Every scope must be held by the caller (holds), so a principal allowed to
+// register clients cannot mint one with more access than its own.
+func (uc *OAuthClientUseCase) CreateClient(ctx context.Context, input dto.CreateOAuthClientRequest, holds func(scope string) bool) (*dto.CreateOAuthClientResponse, error) {
+	scopes, err := normalizeScopes(input.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range scopes {
+		if holds == nil || !holds(s) {
+			return nil, apperr.ErrScopeNotHeld
+		}
+	}
+	clientID, err := newOAuthClientID()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomURLToken(32)
+	if err != nil {
+		return nil, err
+	}
+	c := ports.OAuthClient{
+		ClientID:   clientID,
+		Name:       strings.TrimSpace(input.Name),
+		SecretHash: hashRandomSecret(secret),
+		Scopes:     scopes,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := uc.clients.Create(ctx, c); err != nil {
+		return nil, err
+	}
+	return &dto.CreateOAuthClientResponse{OAuthClientResponse: oauthClientResponse(c), ClientSecret: secret}, nil
+}
+
+func (uc *OAuthClientUseCase) ListClients(ctx context.Context) ([]dto.OAuthClientResponse, error) {
+	clients, err := uc.clients.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.OAuthClientResponse, 0, len(clients))
+	for _, c := range clients {
+		out = append(out, oauthClientResponse(c))
+	}
+	return out, nil
+}
+
+// DeleteClient removes the client; tokens it already holds stay valid until they expire.
+func (uc *OAuthClientUseCase) DeleteClient(ctx context.Context, clientID string) error {
+	return uc.clients.Delete(ctx, clientID)
+}
+
+func oauthClientResponse(c ports.OAuthClient) dto.OAuthClientResponse {
+	scopes := c.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return dto.OAuthClientResponse{ClientID: c.ClientID, Name: c.Name, Scopes: scopes, CreatedAt: c.CreatedAt}
+}
+
+// newOAuthClientID returns "svc_" and 20 lowercase base32 characters (100 random bits).
+func newOAuthClientID() (string, error) {
+	b := make([]byte, 15)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "svc_" + strings.ToLower(base32.StdEncoding.EncodeToString(b))[:20], nil
+}
+
+var (
+	_ OAuthClientCredentials = (*OAuthClientUseCase)(nil)
+	_ OAuthClientAdmin       = (*OAuthClientUseCase)(nil)
+)
@@ -0,0 +1,79 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+)
+
+func (fakeTokenIssuer) GenerateClientToken(clientID string, scopes []string, _ time.Duration) (string, error) {
+	return "client:" + clientID + ":" + strings.Join(scopes, ","), nil
+}
+
+var _ ports.ClientTokenIssuer = (*fakeTokenIssuer)(nil)
+
+func TestOAuthClients_ClientCredentialsGrant(t *testing.T) {
+	ctx := context.Background()
+	uc := NewOAuthClientUseCase(fakeOAuthClientStore{}, fakeTokenIssuer{}, 10*time.Minute)
+
+	created, err := uc.CreateClient(ctx, dto.CreateOAuthClientRequest{Name: "billing", Scopes: []string{"users:*", "tokens:revoke"}}, holdsAll)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !strings.HasPrefix(created.ClientID, "svc_") || created.ClientSecret == "" {
+		t.Fatalf("unexpected client %+v", created)
+	}
+
+	all, err := uc.IssueToken(ctx, dto.ClientCredentialsRequest{ClientID: created.ClientID, ClientSecret: created.ClientSecret})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if all.TokenType != "Bearer" || all.ExpiresIn != 600 || all.Scope != "users:* tokens:revoke" {
+		t.Fatalf("expected every allowed scope by default, got %+v", all)
+	}
+	narrowed, err := uc.IssueToken(ctx, dto.ClientCredentialsRequest{ClientID: created.ClientID, ClientSecret: created.ClientSecret, Scope: "users:read"})
+	if err != nil || narrowed.Scope != "users:read" || narrowed.AccessToken != "client:"+created.ClientID+":users:read" {
+		t.Fatalf("expected narrowed scope: %+v err=%v", narrowed, err)
+	}
+	if _, err := uc.IssueToken(ctx, dto.ClientCredentialsRequest{ClientID: created.ClientID, ClientSecret: created.ClientSecret, Scope: "admin:read"}); !errors.Is(err, apperr.ErrInvalidScope) {
+		t.Fatalf("expected scope outside the registration to be refused, got %v", err)
+	}
+	if _, err := uc.IssueToken(ctx, dto.ClientCredentialsRequest{ClientID: created.ClientID, ClientSecret: "wrong"}); !errors.Is(err, apperr.ErrInvalidClient) {
+		t.Fatalf("expected wrong secret to fail, got %v", err)
+	}
+
+	if err := uc.DeleteClient(ctx, created.ClientID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := uc.IssueToken(ctx, dto.ClientCredentialsRequest{ClientID: created.ClientID, ClientSecret: created.ClientSecret}); !errors.Is(err, apperr.ErrInvalidClient) {
+		t.Fatalf("expected deleted client to fail, got %v", err)
+	}
+}
+
+func holdsAll(string) bool { return true }
+
+func TestOAuthClients_CreateRefusesScopesTheCallerLacks(t *testing.T) {
+	ctx := context.Background()
+	store := fakeOAuthClientStore{}
+	uc := NewOAuthClientUseCase(store, fakeTokenIssuer{}, time.Minute)
+	// The caller only holds oauth_clients:write and users:read
+	holds := func(scope string) bool { return scope == "oauth_clients:write" || scope == "users:read" }
+
+	for _, scopes := range [][]string{{"*"}, {"users:*"}, {"users:read", "tokens:revoke"}} {
+		if _, err := uc.CreateClient(ctx, dto.CreateOAuthClientRequest{Name: "evil", Scopes: scopes}, holds); !errors.Is(err, apperr.ErrScopeNotHeld) {
+			t.Fatalf("scopes %v: expected ErrScopeNotHeld, got %v", scopes, err)
+		}
+	}
+	if clients, _ := store.List(ctx); len(clients) != 0 {
+		t.Fatalf("expected no client to be stored, got %d", len(clients))
+	}
+	if _, err := uc.CreateClient(ctx, dto.CreateOAuthClientRequest{Name: "reader", Scopes: []string{"users:read"}}, holds); err != nil {
+		t.Fatalf("expected a held scope to be accepted: %v", err)
+	}
+}
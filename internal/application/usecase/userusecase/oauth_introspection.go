@@ -0,0 +1,179 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	"gostartkit/pkg/rbac"
+)
+
+// TokenIntrospection answers RFC 7662 introspection and RFC 7009 revocation requests from registered clients.
+type TokenIntrospection interface {
+	Introspect(ctx context.Context, input dto.TokenIntrospectionRequest) (*dto.TokenIntrospectionResponse, error)
+	Revoke(ctx context.Context, input dto.TokenRevocationRequest) error
+}
+
+// ClientAuthenticator checks OAuth client credentials; OAuthClientUseCase implements it.
+type ClientAuthenticator interface {
+	AuthenticateClient(ctx context.Context, clientID, secret string) (ports.OAuthClient, error)
+}
+
+const (
+	tokenTypeHintAccess  = "access_token"
+	tokenTypeHintRefresh = "refresh_token"
+
+	// ScopeTokensIntrospect lets a client introspect any token.
+	ScopeTokensIntrospect = "tokens:introspect"
+	// ScopeTokensRevoke lets a client revoke tokens it was not issued (user access and refresh tokens, other clients').
+	ScopeTokensRevoke = "tokens:revoke"
+)
+
+// TokenIntrospectionUseCase implements TokenIntrospection for our access tokens (JWTs, checked against the
+// revocation denylist) and refresh tokens (opaque, checked in the refresh store).
+type TokenIntrospectionUseCase struct {
+	clients     ClientAuthenticator
+	access      ports.AccessTokenVerifier
+	revocations ports.AccessTokenRevocationStore
+	refresh     ports.RefreshTokenStore
+}
+
+// NewTokenIntrospectionUseCase wires the use case; refresh may be nil when refresh tokens are disabled,
+// in which case every refresh token is reported inactive.
+func NewTokenIntrospectionUseCase(clients ClientAuthenticator, access ports.AccessTokenVerifier, revocations ports.AccessTokenRevocationStore, refresh ports.RefreshTokenStore) *TokenIntrospectionUseCase {
+	return &TokenIntrospectionUseCase{clients: clients, access: access, revocations: revocations, refresh: refresh}
+}
+
+// Introspect reports whether a token is active. Unknown, expired, revoked and malformed tokens all
+// give {"active": false} so callers learn nothing else about them. The client needs the tokens:introspect scope.
+func (uc *TokenIntrospectionUseCase) Introspect(ctx context.Context, input dto.TokenIntrospectionRequest) (*dto.TokenIntrospectionResponse, error) {
+	client, err := uc.clients.AuthenticateClient(ctx, input.ClientID, input.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if !rbac.AnyMatches(client.Scopes, ScopeTokensIntrospect) {
+		return nil, apperr.ErrUnauthorizedClient
+	}
+	inactive := &dto.TokenIntrospectionResponse{Active: false}
+	if input.Token == "" {
+		return inactive, nil
+	}
+	for _, kind := range tokenKinds(input.TokenTypeHint) {
+		var (
+			resp *dto.TokenIntrospectionResponse
+			err  error
+		)
+		if kind == tokenTypeHintAccess {
+			resp, err = uc.introspectAccess(ctx, input.Token)
+		} else {
+			resp, err = uc.introspectRefresh(ctx, input.Token)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if resp != nil {
+			return resp, nil
+		}
+	}
+	return inactive, nil
+}
+
+// Revoke invalidates a refresh token, or denylists an access token until it expires. Invalid tokens
+// are not an error (RFC 7009 section 2.2). A client may always revoke tokens issued to itself (RFC 7009
+// section 2.1); any other token needs the tokens:revoke scope, and service tokens are never revoked by another client.
+func (uc *TokenIntrospectionUseCase) Revoke(ctx context.Context, input dto.TokenRevocationRequest) error {
+	client, err := uc.clients.AuthenticateClient(ctx, input.ClientID, input.ClientSecret)
+	if err != nil {
+		return err
+	}
+	if input.Token == "" {
+		return nil
+	}
+	mayRevoke := rbac.AnyMatches(client.Scopes, ScopeTokensRevoke)
+	for _, kind := range tokenKinds(input.TokenTypeHint) {
+		if kind == tokenTypeHintAccess {
+			info, err := uc.access.VerifyAccessToken(input.Token)
+			if err != nil {
+				continue
+			}
+			own := info.ClientID == client.ClientID
+			if !own && (info.ClientID != "" || !mayRevoke) {
+				return apperr.ErrUnauthorizedClient
+			}
+			return uc.revocations.RevokeJTI(ctx, info.ID, info.ExpiresAt)
+		}
+		if uc.refresh == nil {
+			continue
+		}
+		if _, err := uc.refresh.Validate(ctx, input.Token); err != nil {
+			continue
+		}
+		// Refresh tokens belong to users, never to the requesting client
+		if !mayRevoke {
+			return apperr.ErrUnauthorizedClient
+		}
+		return uc.refresh.Revoke(ctx, input.Token)
+	}
+	return nil
+}
+
+func (uc *TokenIntrospectionUseCase) introspectAccess(ctx context.Context, token string) (*dto.TokenIntrospectionResponse, error) {
+	info, err := uc.access.VerifyAccessToken(token)
+	if err != nil {
+		return nil, nil
+	}
+	revoked, err := uc.revocations.IsRevoked(ctx, info.ID, info.Subject, info.IssuedAt)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, nil
+	}
+	return &dto.TokenIntrospectionResponse{
+		Active:    true,
+		Scope:     strings.Join(info.Scopes, " "),
+		ClientID:  info.ClientID,
+		TokenType: "Bearer",
+		ExpiresAt: unixOrZero(info.ExpiresAt),
+		IssuedAt:  unixOrZero(info.IssuedAt),
+		Subject:   info.Subject,
+		Audience:  info.Audience,
+		Issuer:    info.Issuer,
+		JTI:       info.ID,
+	}, nil
+}
+
+func (uc *TokenIntrospectionUseCase) introspectRefresh(ctx context.Context, token string) (*dto.TokenIntrospectionResponse, error) {
+	if uc.refresh == nil {
+		return nil, nil
+	}
+	userID, err := uc.refresh.Validate(ctx, token)
+	if errors.Is(err, apperr.ErrInvalidRefreshToken) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TokenIntrospectionResponse{Active: true, TokenType: tokenTypeHintRefresh, Subject: userID}, nil
+}
+
+// tokenKinds orders the lookups by the client's hint; the other kind is still tried (RFC 7662 section 2.1).
+func tokenKinds(hint string) []string {
+	if hint == tokenTypeHintRefresh {
+		return []string{tokenTypeHintRefresh, tokenTypeHintAccess}
+	}
+	return []string{tokenTypeHintAccess, tokenTypeHintRefresh}
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+var _ TokenIntrospection = (*TokenIntrospectionUseCase)(nil)
@@ -0,0 +1,150 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+)
+
+// fakeVerifier accepts tokens that are keys of the map.
+type fakeVerifier map[string]ports.AccessTokenInfo
+
+func (f fakeVerifier) VerifyAccessToken(token string) (ports.AccessTokenInfo, error) {
+	info, ok := f[token]
+	if !ok {
+		return ports.AccessTokenInfo{}, errors.New("invalid token")
+	}
+	return info, nil
+}
+
+func newIntrospectionFixture(t *testing.T) (*TokenIntrospectionUseCase, *dto.CreateOAuthClientResponse, ports.RefreshTokenStore) {
+	uc, gateway, _, refresh := newIntrospectionFixtureWithScopes(t, []string{ScopeTokensIntrospect, ScopeTokensRevoke})
+	return uc, gateway, refresh
+}
+
+// newIntrospectionFixtureWithScopes registers the calling client with scopes; verifier holds "own-svc", a token issued to it.
+func newIntrospectionFixtureWithScopes(t *testing.T, scopes []string) (*TokenIntrospectionUseCase, *dto.CreateOAuthClientResponse, fakeVerifier, ports.RefreshTokenStore) {
+	t.Helper()
+	ctx := context.Background()
+	clients := NewOAuthClientUseCase(fakeOAuthClientStore{}, fakeTokenIssuer{}, time.Minute)
+	gateway, err := clients.CreateClient(ctx, dto.CreateOAuthClientRequest{Name: "gateway", Scopes: scopes}, holdsAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	verifier := fakeVerifier{
+		"user-jwt":  {ID: "jti-1", Subject: "user-1", Role: "user", IssuedAt: now, ExpiresAt: now.Add(time.Hour)},
+		"other-svc": {ID: "jti-2", Subject: "svc_other", ClientID: "svc_other", Scopes: []string{"users:read"}, IssuedAt: now, ExpiresAt: now.Add(time.Hour)},
+		"own-svc":   {ID: "jti-3", Subject: gateway.ClientID, ClientID: gateway.ClientID, IssuedAt: now, ExpiresAt: now.Add(time.Hour)},
+	}
+	refresh := newFakeRefreshStore()
+	uc := NewTokenIntrospectionUseCase(clients, verifier, newFakeAccessRevocations(), refresh)
+	return uc, gateway, verifier, refresh
+}
+
+func TestTokenIntrospection_AccessAndRefreshTokens(t *testing.T) {
+	ctx := context.Background()
+	uc, gw, refresh := newIntrospectionFixture(t)
+	rt, err := refresh.Issue(ctx, "user-1", 60, ports.SessionMeta{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := func(token, hint string) dto.TokenIntrospectionRequest {
+		return dto.TokenIntrospectionRequest{ClientID: gw.ClientID, ClientSecret: gw.ClientSecret, Token: token, TokenTypeHint: hint}
+	}
+
+	res, err := uc.Introspect(ctx, req("other-svc", ""))
+	if err != nil || !res.Active || res.ClientID != "svc_other" || res.Scope != "users:read" || res.TokenType != "Bearer" || res.ExpiresAt == 0 {
+		t.Fatalf("access token: %+v err=%v", res, err)
+	}
+	res, err = uc.Introspect(ctx, req(rt, "refresh_token"))
+	if err != nil || !res.Active || res.Subject != "user-1" || res.TokenType != "refresh_token" {
+		t.Fatalf("refresh token: %+v err=%v", res, err)
+	}
+	// A wrong hint still finds the token
+	if res, _ := uc.Introspect(ctx, req(rt, "access_token")); !res.Active {
+		t.Fatalf("expected refresh token to be found despite the hint")
+	}
+	if res, _ := uc.Introspect(ctx, req("garbage", "")); res.Active || res.Subject != "" {
+		t.Fatalf("expected only active=false for unknown token, got %+v", res)
+	}
+	if _, err := uc.Introspect(ctx, dto.TokenIntrospectionRequest{ClientID: gw.ClientID, ClientSecret: "wrong", Token: rt}); !errors.Is(err, apperr.ErrInvalidClient) {
+		t.Fatalf("expected client authentication to be required, got %v", err)
+	}
+}
+
+// downRefreshStore fails Validate the way a store outage does: with an error other than ErrInvalidRefreshToken.
+type downRefreshStore struct{ *fakeRefreshStore }
+
+func (downRefreshStore) Validate(context.Context, string) (string, error) {
+	return "", errors.New("dial tcp: connection refused")
+}
+
+func TestTokenIntrospection_RefreshStoreOutageIsAnError(t *testing.T) {
+	ctx := context.Background()
+	uc, gw, _ := newIntrospectionFixture(t)
+	uc.refresh = downRefreshStore{newFakeRefreshStore()}
+
+	res, err := uc.Introspect(ctx, dto.TokenIntrospectionRequest{ClientID: gw.ClientID, ClientSecret: gw.ClientSecret, Token: "rt-1", TokenTypeHint: "refresh_token"})
+	if err == nil || errors.Is(err, apperr.ErrInvalidRefreshToken) {
+		t.Fatalf("expected the store error instead of active=false, got %+v err=%v", res, err)
+	}
+}
+
+func TestTokenIntrospection_Revoke(t *testing.T) {
+	ctx := context.Background()
+	uc, gw, refresh := newIntrospectionFixture(t)
+	rt, _ := refresh.Issue(ctx, "user-1", 60, ports.SessionMeta{})
+	revoke := func(token string) error {
+		return uc.Revoke(ctx, dto.TokenRevocationRequest{ClientID: gw.ClientID, ClientSecret: gw.ClientSecret, Token: token})
+	}
+
+	if err := revoke(rt); err != nil {
+		t.Fatalf("revoke refresh: %v", err)
+	}
+	if _, err := refresh.Validate(ctx, rt); err == nil {
+		t.Fatalf("expected refresh token to be revoked")
+	}
+	if err := revoke("user-jwt"); err != nil {
+		t.Fatalf("revoke access: %v", err)
+	}
+	res, err := uc.Introspect(ctx, dto.TokenIntrospectionRequest{ClientID: gw.ClientID, ClientSecret: gw.ClientSecret, Token: "user-jwt"})
+	if err != nil || res.Active {
+		t.Fatalf("expected denylisted access token to be inactive: %+v err=%v", res, err)
+	}
+	if err := revoke("garbage"); err != nil {
+		t.Fatalf("invalid tokens are not an error, got %v", err)
+	}
+	if err := revoke("other-svc"); !errors.Is(err, apperr.ErrUnauthorizedClient) {
+		t.Fatalf("expected another client's token to be refused, got %v", err)
+	}
+}
+
+func TestTokenIntrospection_UnscopedClient(t *testing.T) {
+	ctx := context.Background()
+	uc, gw, _, refresh := newIntrospectionFixtureWithScopes(t, nil)
+	rt, _ := refresh.Issue(ctx, "user-1", 60, ports.SessionMeta{})
+
+	if _, err := uc.Introspect(ctx, dto.TokenIntrospectionRequest{ClientID: gw.ClientID, ClientSecret: gw.ClientSecret, Token: "user-jwt"}); !errors.Is(err, apperr.ErrUnauthorizedClient) {
+		t.Fatalf("expected introspection without tokens:introspect to be refused, got %v", err)
+	}
+	revoke := func(token string) error {
+		return uc.Revoke(ctx, dto.TokenRevocationRequest{ClientID: gw.ClientID, ClientSecret: gw.ClientSecret, Token: token})
+	}
+	for _, token := range []string{"user-jwt", rt, "other-svc"} {
+		if err := revoke(token); !errors.Is(err, apperr.ErrUnauthorizedClient) {
+			t.Fatalf("revoke %q without tokens:revoke: expected ErrUnauthorizedClient, got %v", token, err)
+		}
+	}
+	if _, err := refresh.Validate(ctx, rt); err != nil {
+		t.Fatalf("expected the user's refresh token to survive, got %v", err)
+	}
+	if err := revoke("own-svc"); err != nil {
+		t.Fatalf("expected a client to revoke its own token, got %v", err)
+	}
+}
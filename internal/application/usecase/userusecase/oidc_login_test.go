@@ -0,0 +1,201 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	domuser "gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// fakeOIDCProvider returns ident for any code, after checking the nonce it is handed.
+type fakeOIDCProvider struct {
+	ident  ports.OIDCIdentity
+	signup bool
+	nonce  string // captured from AuthCodeURL
+}
+
+func (p *fakeOIDCProvider) Name() string      { return "stub" }
+func (p *fakeOIDCProvider) AllowSignup() bool { return p.signup }
+func (p *fakeOIDCProvider) AuthCodeURL(state, nonce, challenge string) (string, error) {
+	p.nonce = nonce
+	return "https://idp.example/authorize?state=" + state, nil
+}
+func (p *fakeOIDCProvider) Exchange(ctx context.Context, code, verifier, nonce string) (ports.OIDCIdentity, error) {
+	if nonce != p.nonce || verifier == "" {
+		return ports.OIDCIdentity{}, errors.New("nonce mismatch")
+	}
+	return p.ident, nil
+}
+
+// usersByEmail is a repository that reports missing users, unlike fakeRepo.
+type usersByEmail map[domuser.Email]*domuser.User
+
+func (r usersByEmail) Save(ctx context.Context, u *domuser.User) error { r[u.Email] = u; return nil }
+func (r usersByEmail) GetByID(ctx context.Context, id uuid.UUID) (*domuser.User, error) {
+	for _, u := range r {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, domuser.ErrUserNotFound
+}
+func (r usersByEmail) GetByEmail(ctx context.Context, email domuser.Email) (*domuser.User, error) {
+	if u, ok := r[email]; ok {
+		return u, nil
+	}
+	return nil, domuser.ErrUserNotFound
+}
+func (r usersByEmail) GetByPhone(ctx context.Context, phone domuser.Phone) (*domuser.User, error) {
+	for _, u := range r {
+		if u.Phone == phone {
+			return u, nil
+		}
+	}
+	return nil, domuser.ErrUserNotFound
+}
+func (r usersByEmail) List(ctx context.Context, filter domuser.ListFilter) ([]*domuser.User, error) {
+	return nil, nil
+}
+func (r usersByEmail) Update(ctx context.Context, u *domuser.User) error {
+	for email, existing := range r {
+		if existing.ID == u.ID {
+			delete(r, email)
+		}
+	}
+	r[u.Email] = u
+	return nil
+}
+func (r usersByEmail) UpdatePasswordHash(ctx context.Context, id uuid.UUID, hash string, at time.Time) error {
+	u, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	u.Password, u.UpdatedAt = hash, at
+	return nil
+}
+func (r usersByEmail) Delete(ctx context.Context, id uuid.UUID) error { return nil }
+func (r usersByEmail) GetByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*domuser.User, error) {
+	return r.GetByID(ctx, id)
+}
+func (r usersByEmail) Erase(ctx context.Context, u *domuser.User) error { r[u.Email] = u; return nil }
+
+type fakeIdentities map[string]uuid.UUID
+
+func (f fakeIdentities) FindUserID(ctx context.Context, provider, subject string) (uuid.UUID, error) {
+	if id, ok := f[provider+"|"+subject]; ok {
+		return id, nil
+	}
+	return uuid.Nil, domuser.ErrUserNotFound
+}
+func (f fakeIdentities) Link(ctx context.Context, provider, subject string, userID uuid.UUID, email string) error {
+	f[provider+"|"+subject] = userID
+	return nil
+}
+func (f fakeIdentities) ListByUser(ctx context.Context, userID uuid.UUID) ([]ports.ExternalIdentity, error) {
+	var out []ports.ExternalIdentity
+	for key, id := range f {
+		if id == userID {
+			provider, subject, _ := strings.Cut(key, "|")
+			out = append(out, ports.ExternalIdentity{Provider: provider, Subject: subject})
+		}
+	}
+	return out, nil
+}
+
+func newOIDCLogin(p *fakeOIDCProvider, repo usersByEmail, ids fakeIdentities) *OIDCLoginUseCase {
+	return NewOIDCLoginUseCase(repo, fakeHasher{}, fakeTokenIssuer{}, newFakeRefreshStore(), 60,
+		[]ports.OIDCProvider{p}, fakeOIDCStateStore{}, ids, 0, nil)
+}
+
+func oidcRoundTrip(t *testing.T, uc *OIDCLoginUseCase) (*dto.LoginResponse, error) {
+	t.Helper()
+	start, err := uc.Start(context.Background(), "stub")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	return uc.Callback(context.Background(), dto.OIDCCallbackRequest{Provider: "stub", Code: "code", State: start.State})
+}
+
+func TestOIDCLogin_CreatesThenReusesLinkedUser(t *testing.T) {
+	p := &fakeOIDCProvider{signup: true, ident: ports.OIDCIdentity{Provider: "stub", Subject: "sub-1", Email: "New.User@Example.com", EmailVerified: true, GivenName: "New"}}
+	repo, ids := usersByEmail{}, fakeIdentities{}
+	uc := newOIDCLogin(p, repo, ids)
+
+	first, err := oidcRoundTrip(t, uc)
+	if err != nil {
+		t.Fatalf("first login: %v", err)
+	}
+	if first.User.Email != "new.user@example.com" || first.RefreshToken == "" || len(repo) != 1 {
+		t.Fatalf("expected a new user with tokens, got %+v (users=%d)", first, len(repo))
+	}
+	// The link wins over email on later logins, even if the provider email changes
+	p.ident.Email = "renamed@example.com"
+	second, err := oidcRoundTrip(t, uc)
+	if err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+	if second.User.ID != first.User.ID || len(repo) != 1 {
+		t.Fatalf("expected the linked user, got %s", second.User.ID)
+	}
+}
+
+func TestOIDCLogin_LinksExistingUserByVerifiedEmail(t *testing.T) {
+	existing := domuser.NewUser("Jane", "Doe", domuser.Email("jane@example.com"), "hashed:x", domuser.RoleUser)
+	p := &fakeOIDCProvider{ident: ports.OIDCIdentity{Provider: "stub", Subject: "sub-2", Email: "jane@example.com", EmailVerified: true}}
+	ids := fakeIdentities{}
+	uc := newOIDCLogin(p, usersByEmail{existing.Email: existing}, ids)
+
+	resp, err := oidcRoundTrip(t, uc)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if resp.User.ID != existing.ID || ids["stub|sub-2"] != existing.ID {
+		t.Fatalf("expected existing user to be linked")
+	}
+}
+
+func TestOIDCLogin_Rejections(t *testing.T) {
+	cases := []struct {
+		name  string
+		ident ports.OIDCIdentity
+		want  error
+	}{
+		{"unverified email", ports.OIDCIdentity{Provider: "stub", Subject: "s", Email: "a@example.com"}, apperr.ErrOIDCEmailNotVerified},
+		{"signup disabled", ports.OIDCIdentity{Provider: "stub", Subject: "s", Email: "a@example.com", EmailVerified: true}, apperr.ErrOIDCSignupNotAllowed},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			uc := newOIDCLogin(&fakeOIDCProvider{ident: c.ident}, usersByEmail{}, fakeIdentities{})
+			if _, err := oidcRoundTrip(t, uc); !errors.Is(err, c.want) {
+				t.Fatalf("expected %v, got %v", c.want, err)
+			}
+		})
+	}
+}
+
+func TestOIDCLogin_StateIsSingleUse(t *testing.T) {
+	p := &fakeOIDCProvider{signup: true, ident: ports.OIDCIdentity{Provider: "stub", Subject: "s", Email: "a@example.com", EmailVerified: true}}
+	uc := newOIDCLogin(p, usersByEmail{}, fakeIdentities{})
+	start, err := uc.Start(context.Background(), "stub")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	req := dto.OIDCCallbackRequest{Provider: "stub", Code: "code", State: start.State}
+	if _, err := uc.Callback(context.Background(), req); err != nil {
+		t.Fatalf("callback: %v", err)
+	}
+	if _, err := uc.Callback(context.Background(), req); !errors.Is(err, apperr.ErrOIDCStateInvalid) {
+		t.Fatalf("expected replayed state to be rejected, got %v", err)
+	}
+	if _, err := uc.Start(context.Background(), "unknown"); !errors.Is(err, apperr.ErrOIDCProviderNotFound) {
+		t.Fatalf("expected unknown provider error, got %v", err)
+	}
+}
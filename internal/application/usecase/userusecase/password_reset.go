@@ -0,0 +1,129 @@
+package userusecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/domain/user"
+)
+
+// PasswordResetUsecases lets users who forgot their password set a new one through an emailed link.
+type PasswordResetUsecases interface {
+	Forgot(ctx context.Context, input dto.ForgotPasswordRequest) error
+	Reset(ctx context.Context, input dto.ResetPasswordRequest) error
+}
+
+// PasswordResetOptions tunes PasswordResetUseCase.
+type PasswordResetOptions struct {
+	// LinkBaseURL is the page that asks for the new password; the emailed link is LinkBaseURL?token=...
+	LinkBaseURL string
+	// TTL bounds how long a reset link stays valid (default 1 hour)
+	TTL time.Duration
+	// AccessTokenTTL is how long revocation entries for outstanding access tokens are kept
+	AccessTokenTTL time.Duration
+}
+
+// PasswordResetUseCase implements PasswordResetUsecases with random single-use tokens stored as SHA-256 hashes.
+// A successful reset signs the user out everywhere: refresh sessions are revoked and, when a denylist is
+// configured, access tokens issued before the reset stop working.
+type PasswordResetUseCase struct {
+	repo        user.Repository
+	hasher      PasswordHasher
+	resets      ports.PasswordResetStore
+	mailer      ports.EmailSender
+	store       ports.RefreshTokenStore
+	revocations ports.AccessTokenRevocationStore
+	opts        PasswordResetOptions
+}
+
+// NewPasswordResetUseCase accepts nil store and revocations (refresh tokens or the denylist disabled).
+func NewPasswordResetUseCase(repo user.Repository, hasher PasswordHasher, resets ports.PasswordResetStore, mailer ports.EmailSender,
+	store ports.RefreshTokenStore, revocations ports.AccessTokenRevocationStore, opts PasswordResetOptions) *PasswordResetUseCase {
+	if opts.TTL <= 0 {
+		opts.TTL = time.Hour
+	}
+	return &PasswordResetUseCase{repo: repo, hasher: hasher, resets: resets, mailer: mailer, store: store, revocations: revocations, opts: opts}
+}
+
+// Forgot emails a reset link when the address belongs to an account and succeeds silently otherwise,
+// so the endpoint cannot be used to find out which emails are registered. For the same reason a failed
+// send is not reported (the sender logs it).
+func (uc *PasswordResetUseCase) Forgot(ctx context.Context, input dto.ForgotPasswordRequest) error {
+	emailVO, err := user.NewEmail(input.Email)
+	if err != nil {
+		return err
+	}
+	u, err := uc.repo.GetByEmail(ctx, emailVO)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+	token, err := randomURLToken(32)
+	if err != nil {
+		return err
+	}
+	if err := uc.resets.Save(ctx, hashResetToken(token), u.ID, time.Now().Add(uc.opts.TTL)); err != nil {
+		return err
+	}
+	link := uc.opts.LinkBaseURL + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Hello %s,\n\nSomeone asked to reset the password of your account. To choose a new password, open the link below:\n\n%s\n\nThe link expires in %s and works once. If you did not ask for this, you can ignore this email; your password stays unchanged.\n",
+		u.FirstName, link, uc.opts.TTL)
+	_ = uc.mailer.Send(ctx, u.Email.String(), "Reset your password", body)
+	return nil
+}
+
+func (uc *PasswordResetUseCase) Reset(ctx context.Context, input dto.ResetPasswordRequest) error {
+	id, err := uc.resets.Consume(ctx, hashResetToken(strings.TrimSpace(input.Token)), time.Now())
+	if err != nil {
+		return err
+	}
+	u, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return apperr.ErrResetTokenInvalid
+		}
+		return err
+	}
+	hashed, err := uc.hasher.Hash(input.NewPassword)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	u.SetPassword(hashed, now)
+	if !u.IsEmailVerified() {
+		// Following the emailed link proves ownership of the address
+		u.MarkEmailVerified(now)
+	}
+	if err := uc.repo.Update(ctx, u); err != nil {
+		return err
+	}
+	if err := uc.resets.DeleteByUser(ctx, u.ID); err != nil {
+		return err
+	}
+	if uc.store != nil {
+		if err := uc.store.RevokeAll(ctx, u.ID.String()); err != nil {
+			return err
+		}
+	}
+	if uc.revocations != nil {
+		return uc.revocations.RevokeUser(ctx, u.ID.String(), now, uc.opts.AccessTokenTTL)
+	}
+	return nil
+}
+
+func hashResetToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+var _ PasswordResetUsecases = (*PasswordResetUseCase)(nil)
@@ -0,0 +1,81 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	domuser "gostartkit/internal/domain/user"
+)
+
+func TestPasswordReset_ResetsPasswordAndSignsOutEverywhere(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Dee", "Ray", domuser.Email("dee@example.com"), "hashed:old", domuser.RoleUser)
+	repo, mail := usersByEmail{u.Email: u}, outbox{}
+	refresh, revocations := newFakeRefreshStore(), newFakeAccessRevocations()
+	session, err := refresh.Issue(ctx, u.ID.String(), 60, ports.SessionMeta{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc := NewPasswordResetUseCase(repo, fakeHasher{}, fakePasswordResetStore{}, mail.sender(), refresh, revocations,
+		PasswordResetOptions{LinkBaseURL: "https://app.example/reset", AccessTokenTTL: time.Hour})
+	issuedBefore := time.Now().Add(-time.Second)
+
+	if err := uc.Forgot(ctx, dto.ForgotPasswordRequest{Email: "dee@example.com"}); err != nil {
+		t.Fatalf("forgot: %v", err)
+	}
+	token := mail.linkToken(t, "dee@example.com")
+	if err := uc.Reset(ctx, dto.ResetPasswordRequest{Token: token, NewPassword: "new"}); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if u.Password != "hashed:new" || !u.IsEmailVerified() {
+		t.Fatalf("expected new password and verified email, got %q verified=%v", u.Password, u.IsEmailVerified())
+	}
+	if _, err := refresh.Validate(ctx, session); err == nil {
+		t.Fatalf("expected refresh sessions to be revoked")
+	}
+	if revoked, _ := revocations.IsRevoked(ctx, "jti", u.ID.String(), issuedBefore); !revoked {
+		t.Fatalf("expected earlier access tokens to be revoked")
+	}
+	if err := uc.Reset(ctx, dto.ResetPasswordRequest{Token: token, NewPassword: "again"}); !errors.Is(err, apperr.ErrResetTokenInvalid) {
+		t.Fatalf("expected token to be single use, got %v", err)
+	}
+}
+
+func TestPasswordReset_UnknownEmailAndExpiredToken(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Eve", "Fox", domuser.Email("eve@example.com"), "hashed:old", domuser.RoleUser)
+	mail := outbox{}
+	uc := NewPasswordResetUseCase(usersByEmail{u.Email: u}, fakeHasher{}, fakePasswordResetStore{}, mail.sender(), nil, nil,
+		PasswordResetOptions{LinkBaseURL: "https://app.example/reset", TTL: time.Nanosecond})
+
+	if err := uc.Forgot(ctx, dto.ForgotPasswordRequest{Email: "nobody@example.com"}); err != nil || len(mail) != 0 {
+		t.Fatalf("expected silent success for unknown email: err=%v mails=%d", err, len(mail))
+	}
+	if err := uc.Forgot(ctx, dto.ForgotPasswordRequest{Email: "eve@example.com"}); err != nil {
+		t.Fatalf("forgot: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if err := uc.Reset(ctx, dto.ResetPasswordRequest{Token: mail.linkToken(t, "eve@example.com"), NewPassword: "new"}); !errors.Is(err, apperr.ErrResetTokenInvalid) {
+		t.Fatalf("expected expired token to be rejected, got %v", err)
+	}
+	if u.Password != "hashed:old" {
+		t.Fatalf("password must not change")
+	}
+}
+
+func TestPasswordReset_ForgotHidesSendFailures(t *testing.T) {
+	u := domuser.NewUser("Fay", "Gee", domuser.Email("fay@example.com"), "hashed:old", domuser.RoleUser)
+	failing := ports.SendEmailFunc(func(context.Context, string, string, string) error { return errors.New("smtp down") })
+	uc := NewPasswordResetUseCase(usersByEmail{u.Email: u}, fakeHasher{}, fakePasswordResetStore{}, failing, nil, nil,
+		PasswordResetOptions{LinkBaseURL: "https://app.example/reset"})
+
+	// An error here would tell a registered address from an unknown one
+	if err := uc.Forgot(context.Background(), dto.ForgotPasswordRequest{Email: "fay@example.com"}); err != nil {
+		t.Fatalf("expected success despite the failed send, got %v", err)
+	}
+}
@@ -30,7 +30,7 @@ func NewRefreshUseCaseWithStore(repo user.Repository, jwt ports.TokenIssuer, sto
 	return &RefreshUseCase{repo: repo, jwt: jwt, store: store, refreshTTLSeconds: refreshTTLSeconds}
 }
 
-func (uc *RefreshUseCase) Execute(ctx context.Context, refreshToken string) (*dto.LoginResponse, error) {
+func (uc *RefreshUseCase) Execute(ctx context.Context, input dto.RefreshRequest) (*dto.LoginResponse, error) {
 	if uc.store == nil {
 		return nil, apperr.ErrRefreshStoreNotConfigured
 	}
@@ -38,7 +38,7 @@ func (uc *RefreshUseCase) Execute(ctx context.Context, refreshToken string) (*dt
 	if ttl <= 0 {
 		ttl = 3600 * 24 * 7 // default 7 days
 	}
-	newRefresh, userID, err := uc.store.Rotate(ctx, refreshToken, ttl)
+	newRefresh, userID, err := uc.store.Rotate(ctx, input.RefreshToken, ttl, ports.SessionMeta{IP: input.IP, UserAgent: input.UserAgent})
 	if err != nil {
 		return nil, apperr.ErrInvalidRefreshToken
 	}
@@ -46,11 +46,15 @@ func (uc *RefreshUseCase) Execute(ctx context.Context, refreshToken string) (*dt
 	if err != nil {
 		return nil, apperr.ErrInvalidRefreshToken
 	}
+	if u.IsDisabled() {
+		_ = uc.store.Revoke(ctx, newRefresh)
+		return nil, apperr.ErrAccountDisabled
+	}
 	access, err := uc.jwt.GenerateToken(u.ID.String(), string(u.Role))
 	if err != nil {
 		return nil, err
 	}
-	return &dto.LoginResponse{AccessToken: access, RefreshToken: newRefresh, User: dto.UserResponse{ID: u.ID, Email: u.Email.String(), FirstName: u.FirstName, LastName: u.LastName, CreatedAt: u.CreatedAt}}, nil
+	return &dto.LoginResponse{AccessToken: access, RefreshToken: newRefresh, User: userResponse(u)}, nil
 }
 
 func (uc *RefreshUseCase) Revoke(ctx context.Context, refreshToken string) error {
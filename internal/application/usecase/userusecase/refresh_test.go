@@ -0,0 +1,38 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	domuser "gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+func TestRefreshUseCase_RotatesAndRejectsOldToken(t *testing.T) {
+	ctx := context.Background()
+	uid := uuid.New()
+	repo := &fakeRepo{user: &domuser.User{ID: uid, Email: domuser.Email("john@example.com"), Role: domuser.Role("user"), CreatedAt: time.Now()}}
+	store := newFakeRefreshStore()
+	old, err := store.Issue(ctx, uid.String(), 60, ports.SessionMeta{})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	uc := NewRefreshUseCaseWithStore(repo, fakeTokenIssuer{}, store, 60)
+
+	resp, err := uc.Execute(ctx, dto.RefreshRequest{RefreshToken: old})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.RefreshToken == "" || resp.RefreshToken == old {
+		t.Fatalf("expected a rotated refresh token")
+	}
+	if _, err := uc.Execute(ctx, dto.RefreshRequest{RefreshToken: old}); !errors.Is(err, apperr.ErrInvalidRefreshToken) {
+		t.Fatalf("expected invalid refresh token on replay, got %v", err)
+	}
+}
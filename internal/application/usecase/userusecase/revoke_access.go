@@ -0,0 +1,60 @@
+package userusecase
+
+import (
+	"context"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+	domuser "gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// AccessRevoker revokes access tokens before their natural expiry.
+type AccessRevoker interface {
+	// RevokeByJTI denylists one token. If expiresAt is zero, the longest access-token lifetime is assumed.
+	RevokeByJTI(ctx context.Context, jti string, expiresAt time.Time) error
+	// RevokeByUser invalidates every access token issued to the user so far.
+	RevokeByUser(ctx context.Context, userID string) error
+}
+
+// RevokeAccessUseCase writes access-token revocations to the denylist store.
+type RevokeAccessUseCase struct {
+	repo  domuser.Repository
+	store ports.AccessTokenRevocationStore
+	// accessTTL is the maximum lifetime of an access token (JWT_EXPIRE_SEC + leeway);
+	// entries never need to outlive it.
+	accessTTL time.Duration
+}
+
+func NewRevokeAccessUseCase(repo domuser.Repository, store ports.AccessTokenRevocationStore, accessTTL time.Duration) *RevokeAccessUseCase {
+	return &RevokeAccessUseCase{repo: repo, store: store, accessTTL: accessTTL}
+}
+
+func (uc *RevokeAccessUseCase) RevokeByJTI(ctx context.Context, jti string, expiresAt time.Time) error {
+	if uc.store == nil {
+		return apperr.ErrRevocationStoreNotConfigured
+	}
+	maxExp := time.Now().Add(uc.accessTTL)
+	if expiresAt.IsZero() || expiresAt.After(maxExp) {
+		expiresAt = maxExp
+	}
+	return uc.store.RevokeJTI(ctx, jti, expiresAt)
+}
+
+func (uc *RevokeAccessUseCase) RevokeByUser(ctx context.Context, userID string) error {
+	if uc.store == nil {
+		return apperr.ErrRevocationStoreNotConfigured
+	}
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return domuser.ErrInvalidID
+	}
+	if _, err := uc.repo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return uc.store.RevokeUser(ctx, id.String(), time.Now(), uc.accessTTL)
+}
+
+var _ AccessRevoker = (*RevokeAccessUseCase)(nil)
@@ -0,0 +1,79 @@
+package userusecase
+
+import (
+	"context"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+)
+
+// SessionUsecases lets a user inspect and end their own refresh-token sessions.
+type SessionUsecases interface {
+	List(ctx context.Context, userID string) ([]dto.SessionResponse, error)
+	Revoke(ctx context.Context, userID, sessionID string) error
+	// LogoutAll ends every session and, when a denylist is configured, invalidates outstanding access tokens.
+	LogoutAll(ctx context.Context, userID string) error
+}
+
+// SessionsUseCase implements SessionUsecases on top of the refresh-token store.
+type SessionsUseCase struct {
+	store       ports.RefreshTokenStore
+	revocations ports.AccessTokenRevocationStore
+	accessTTL   time.Duration
+}
+
+// NewSessionsUseCase builds the use case; revocations may be nil, in which case LogoutAll only ends refresh sessions.
+func NewSessionsUseCase(store ports.RefreshTokenStore, revocations ports.AccessTokenRevocationStore, accessTTL time.Duration) *SessionsUseCase {
+	return &SessionsUseCase{store: store, revocations: revocations, accessTTL: accessTTL}
+}
+
+func (uc *SessionsUseCase) List(ctx context.Context, userID string) ([]dto.SessionResponse, error) {
+	if uc.store == nil {
+		return nil, apperr.ErrRefreshStoreNotConfigured
+	}
+	sessions, err := uc.store.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		out = append(out, sessionResponse(s))
+	}
+	return out, nil
+}
+
+func sessionResponse(s ports.RefreshSession) dto.SessionResponse {
+	return dto.SessionResponse{
+		ID:          s.ID,
+		CreatedAt:   s.CreatedAt,
+		LastUsedAt:  s.LastUsedAt,
+		ExpiresAt:   s.ExpiresAt,
+		IP:          s.IP,
+		UserAgent:   s.UserAgent,
+		DeviceLabel: s.DeviceLabel,
+	}
+}
+
+func (uc *SessionsUseCase) Revoke(ctx context.Context, userID, sessionID string) error {
+	if uc.store == nil {
+		return apperr.ErrRefreshStoreNotConfigured
+	}
+	return uc.store.RevokeSession(ctx, userID, sessionID)
+}
+
+func (uc *SessionsUseCase) LogoutAll(ctx context.Context, userID string) error {
+	if uc.store == nil {
+		return apperr.ErrRefreshStoreNotConfigured
+	}
+	if err := uc.store.RevokeAll(ctx, userID); err != nil {
+		return err
+	}
+	if uc.revocations == nil {
+		return nil
+	}
+	return uc.revocations.RevokeUser(ctx, userID, time.Now(), uc.accessTTL)
+}
+
+var _ SessionUsecases = (*SessionsUseCase)(nil)
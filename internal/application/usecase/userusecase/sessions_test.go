@@ -0,0 +1,43 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+)
+
+type recordingRevocations struct{ users []string }
+
+func (r *recordingRevocations) RevokeJTI(context.Context, string, time.Time) error { return nil }
+func (r *recordingRevocations) RevokeUser(_ context.Context, userID string, _ time.Time, _ time.Duration) error {
+	r.users = append(r.users, userID)
+	return nil
+}
+func (r *recordingRevocations) IsRevoked(context.Context, string, string, time.Time) (bool, error) {
+	return false, nil
+}
+
+var _ ports.AccessTokenRevocationStore = (*recordingRevocations)(nil)
+
+func TestSessionsUseCase_LogoutAllRevokesAccessTokens(t *testing.T) {
+	revocations := &recordingRevocations{}
+	uc := NewSessionsUseCase(newFakeRefreshStore(), revocations, time.Minute)
+
+	if err := uc.LogoutAll(context.Background(), "u1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(revocations.users) != 1 || revocations.users[0] != "u1" {
+		t.Fatalf("expected access tokens of u1 revoked, got %v", revocations.users)
+	}
+}
+
+func TestSessionsUseCase_NoStore(t *testing.T) {
+	uc := NewSessionsUseCase(nil, nil, time.Minute)
+	if _, err := uc.List(context.Background(), "u1"); !errors.Is(err, apperr.ErrRefreshStoreNotConfigured) {
+		t.Fatalf("expected not configured, got %v", err)
+	}
+}
@@ -0,0 +1,266 @@
+package userusecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// PhoneUsecases lets a signed-in user confirm, replace or remove their phone number.
+type PhoneUsecases interface {
+	StartVerification(ctx context.Context, userID string, input dto.PhoneRequest) error
+	ConfirmVerification(ctx context.Context, userID string, input dto.SMSCodeRequest) (*dto.UserResponse, error)
+	Remove(ctx context.Context, userID string) error
+}
+
+// SMSLogin signs users in with a one-time code sent to their confirmed phone number.
+type SMSLogin interface {
+	SendLoginCode(ctx context.Context, input dto.PhoneRequest) error
+	Login(ctx context.Context, input dto.SMSLoginRequest) (*dto.LoginResponse, error)
+}
+
+// SMSOptions tunes SMSUseCase.
+type SMSOptions struct {
+	// CodeTTL bounds how long a code stays valid (default 5 minutes)
+	CodeTTL time.Duration
+	// MaxAttempts wrong codes invalidate the pending code, and refuse every code for the number for LockoutWindow (default 5)
+	MaxAttempts int
+	// LockoutWindow is how long wrong codes are counted per number, across re-sent codes (default 15 minutes)
+	LockoutWindow time.Duration
+}
+
+const (
+	smsCodeDigits     = 6
+	otpPurposeLogin   = "sms_login:"
+	otpPurposeConfirm = "verify_phone:"
+)
+
+// SMSUseCase implements PhoneUsecases and SMSLogin. Codes are stored hashed, expire, and are dropped after
+// MaxAttempts wrong guesses; requesting a new code replaces the previous one but not the failures counted for the number.
+type SMSUseCase struct {
+	repo              user.Repository
+	jwt               ports.TokenIssuer
+	store             ports.RefreshTokenStore
+	refreshTTLSeconds int
+	challenges        ports.OTPChallengeStore
+	failures          ports.FailureCounter
+	sender            ports.SMSSender
+	mfa               *MFAUseCase
+	opts              SMSOptions
+}
+
+// NewSMSUseCase accepts a nil mfa (no second factor) and a nil store (access tokens only).
+func NewSMSUseCase(repo user.Repository, jwt ports.TokenIssuer, store ports.RefreshTokenStore, refreshTTLSeconds int,
+	challenges ports.OTPChallengeStore, failures ports.FailureCounter, sender ports.SMSSender, mfa *MFAUseCase, opts SMSOptions) *SMSUseCase {
+	if opts.CodeTTL <= 0 {
+		opts.CodeTTL = 5 * time.Minute
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.LockoutWindow <= 0 {
+		opts.LockoutWindow = 15 * time.Minute
+	}
+	return &SMSUseCase{
+		repo: repo, jwt: jwt, store: store, refreshTTLSeconds: refreshTTLSeconds,
+		challenges: challenges, failures: failures, sender: sender, mfa: mfa, opts: opts,
+	}
+}
+
+// StartVerification texts a code to the number; it only replaces the user's phone once confirmed.
+func (uc *SMSUseCase) StartVerification(ctx context.Context, userID string, input dto.PhoneRequest) error {
+	phone, err := user.NewPhone(input.Phone)
+	if err != nil {
+		return err
+	}
+	u, err := uc.loadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	owner, err := uc.repo.GetByPhone(ctx, phone)
+	if err == nil && owner.ID != u.ID {
+		return user.ErrPhoneAlreadyExists
+	}
+	if err != nil && !errors.Is(err, user.ErrUserNotFound) {
+		return err
+	}
+	return uc.sendCode(ctx, otpPurposeConfirm+u.ID.String(), u.ID, phone, "Your phone verification code is %s. It expires in %s.")
+}
+
+func (uc *SMSUseCase) ConfirmVerification(ctx context.Context, userID string, input dto.SMSCodeRequest) (*dto.UserResponse, error) {
+	u, err := uc.loadUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	c, err := uc.checkCode(ctx, otpPurposeConfirm+u.ID.String(), input.Code)
+	if err != nil {
+		return nil, err
+	}
+	u.SetVerifiedPhone(user.Phone(c.Phone), time.Now())
+	if err := uc.repo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+	resp := userResponse(u)
+	return &resp, nil
+}
+
+func (uc *SMSUseCase) Remove(ctx context.Context, userID string) error {
+	u, err := uc.loadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !u.HasPhone() {
+		return nil
+	}
+	u.RemovePhone(time.Now())
+	return uc.repo.Update(ctx, u)
+}
+
+// SendLoginCode texts a code only when the number belongs to an account, and reports success either way.
+// A failed send is not reported either (the sender logs it), since only registered numbers reach the sender.
+func (uc *SMSUseCase) SendLoginCode(ctx context.Context, input dto.PhoneRequest) error {
+	phone, err := user.NewPhone(input.Phone)
+	if err != nil {
+		return err
+	}
+	u, err := uc.repo.GetByPhone(ctx, phone)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+	err = uc.sendCode(ctx, otpPurposeLogin+phone.String(), u.ID, phone, "Your sign-in code is %s. It expires in %s. Do not share it.")
+	if errors.Is(err, errSMSNotSent) {
+		return nil
+	}
+	return err
+}
+
+func (uc *SMSUseCase) Login(ctx context.Context, input dto.SMSLoginRequest) (*dto.LoginResponse, error) {
+	phone, err := user.NewPhone(input.Phone)
+	if err != nil {
+		return nil, apperr.ErrOTPInvalid
+	}
+	c, err := uc.checkCode(ctx, otpPurposeLogin+phone.String(), input.Code)
+	if err != nil {
+		return nil, err
+	}
+	uid, err := uuid.Parse(c.UserID)
+	if err != nil {
+		return nil, apperr.ErrOTPInvalid
+	}
+	u, err := uc.repo.GetByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, apperr.ErrOTPInvalid
+		}
+		return nil, err
+	}
+	// The number may have been removed or moved to another account since the code was sent
+	if u.Phone != phone {
+		return nil, apperr.ErrOTPInvalid
+	}
+	return completeLogin(ctx, uc.mfa, uc.jwt, uc.store, uc.refreshTTLSeconds, u, ports.SessionMeta{
+		IP:          input.IP,
+		UserAgent:   input.UserAgent,
+		DeviceLabel: input.DeviceLabel,
+	})
+}
+
+// errSMSNotSent marks sendCode failures of the SMS sender itself; the pending code has been dropped by then.
+var errSMSNotSent = errors.New("send sms")
+
+func (uc *SMSUseCase) sendCode(ctx context.Context, key string, userID uuid.UUID, phone user.Phone, format string) error {
+	code, err := newSMSCode()
+	if err != nil {
+		return err
+	}
+	c := ports.OTPChallenge{UserID: userID.String(), Phone: phone.String(), CodeHash: hashSMSCode(key, code)}
+	if err := uc.challenges.Save(ctx, key, c, uc.opts.CodeTTL); err != nil {
+		return err
+	}
+	if err := uc.sender.Send(ctx, phone.String(), fmt.Sprintf(format, code, uc.opts.CodeTTL)); err != nil {
+		_ = uc.challenges.Delete(ctx, key)
+		return fmt.Errorf("%w: %w", errSMSNotSent, err)
+	}
+	return nil
+}
+
+// checkCode consumes the pending code under key when code matches, and counts a failure otherwise. Failures
+// are also counted per number, so re-sending codes does not buy more guesses: once the number reaches
+// MaxAttempts within LockoutWindow, its pending code is dropped and no code is accepted until the window ends.
+func (uc *SMSUseCase) checkCode(ctx context.Context, key, code string) (ports.OTPChallenge, error) {
+	c, err := uc.challenges.Get(ctx, key)
+	if err != nil {
+		return ports.OTPChallenge{}, err
+	}
+	numberKey := "sms:" + c.Phone
+	n, err := uc.failures.Count(ctx, numberKey)
+	if err != nil {
+		return ports.OTPChallenge{}, err
+	}
+	if n >= uc.opts.MaxAttempts {
+		_ = uc.challenges.Delete(ctx, key)
+		return ports.OTPChallenge{}, apperr.ErrOTPInvalid
+	}
+	if subtle.ConstantTimeCompare(hashSMSCode(key, code), c.CodeHash) != 1 {
+		perCode, err := uc.challenges.RecordFailure(ctx, key)
+		if err != nil {
+			return ports.OTPChallenge{}, err
+		}
+		perNumber, err := uc.failures.Add(ctx, numberKey, uc.opts.LockoutWindow)
+		if err != nil {
+			return ports.OTPChallenge{}, err
+		}
+		if perCode >= uc.opts.MaxAttempts || perNumber >= uc.opts.MaxAttempts {
+			_ = uc.challenges.Delete(ctx, key)
+		}
+		return ports.OTPChallenge{}, apperr.ErrOTPInvalid
+	}
+	if err := uc.challenges.Delete(ctx, key); err != nil {
+		return ports.OTPChallenge{}, err
+	}
+	_ = uc.failures.Reset(ctx, numberKey)
+	return c, nil
+}
+
+func (uc *SMSUseCase) loadUser(ctx context.Context, userID string) (*user.User, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, user.ErrInvalidID
+	}
+	return uc.repo.GetByID(ctx, uid)
+}
+
+// newSMSCode returns a uniformly random numeric code of smsCodeDigits digits.
+func newSMSCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", smsCodeDigits, n.Int64()), nil
+}
+
+// hashSMSCode binds the code to its key. Codes are short, so the attempt limit and TTL are what protect them.
+func hashSMSCode(key, code string) []byte {
+	sum := sha256.Sum256([]byte(key + "\x00" + code))
+	return sum[:]
+}
+
+var (
+	_ PhoneUsecases = (*SMSUseCase)(nil)
+	_ SMSLogin      = (*SMSUseCase)(nil)
+)
@@ -0,0 +1,174 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	domuser "gostartkit/internal/domain/user"
+)
+
+// textbox records sent text messages by recipient.
+type textbox map[string][]string
+
+func (b textbox) sender() ports.SMSSender {
+	return ports.SendSMSFunc(func(ctx context.Context, to, message string) error {
+		b[to] = append(b[to], message)
+		return nil
+	})
+}
+
+var smsCodePattern = regexp.MustCompile(`\b\d{6}\b`)
+
+// code extracts the code from the last message sent to phone.
+func (b textbox) code(t *testing.T, phone string) string {
+	t.Helper()
+	msgs := b[phone]
+	if len(msgs) == 0 {
+		t.Fatalf("no SMS sent to %s", phone)
+	}
+	code := smsCodePattern.FindString(msgs[len(msgs)-1])
+	if code == "" {
+		t.Fatalf("no code in SMS: %q", msgs[len(msgs)-1])
+	}
+	return code
+}
+
+// wrongCode returns a well-formed code different from code.
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+func TestSMS_ConfirmPhoneThenSignIn(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Kai", "Lo", domuser.Email("kai@example.com"), "hashed:x", domuser.RoleUser)
+	texts := textbox{}
+	uc := NewSMSUseCase(usersByEmail{u.Email: u}, fakeTokenIssuer{}, nil, 0, fakeOTPStore{}, fakeFailureCounter{}, texts.sender(), nil, SMSOptions{})
+	const phone = "+14155552671"
+
+	if err := uc.StartVerification(ctx, u.ID.String(), dto.PhoneRequest{Phone: phone}); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	code := texts.code(t, phone)
+	if _, err := uc.ConfirmVerification(ctx, u.ID.String(), dto.SMSCodeRequest{Code: wrongCode(code)}); !errors.Is(err, apperr.ErrOTPInvalid) {
+		t.Fatalf("expected wrong code to be rejected, got %v", err)
+	}
+	if u.HasPhone() {
+		t.Fatalf("phone must stay unset until confirmed")
+	}
+	res, err := uc.ConfirmVerification(ctx, u.ID.String(), dto.SMSCodeRequest{Code: code})
+	if err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	if res.Phone != phone || u.PhoneVerifiedAt == nil {
+		t.Fatalf("expected confirmed phone, got %+v", res)
+	}
+
+	if err := uc.SendLoginCode(ctx, dto.PhoneRequest{Phone: phone}); err != nil {
+		t.Fatalf("send login code: %v", err)
+	}
+	login := dto.SMSLoginRequest{Phone: phone, Code: texts.code(t, phone)}
+	resp, err := uc.Login(ctx, login)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if resp.AccessToken != "token:"+u.ID.String() {
+		t.Fatalf("unexpected access token %q", resp.AccessToken)
+	}
+	if _, err := uc.Login(ctx, login); !errors.Is(err, apperr.ErrOTPInvalid) {
+		t.Fatalf("expected code to be single use, got %v", err)
+	}
+}
+
+func TestSMS_AttemptLimitAndUnknownPhone(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Lee", "Ma", domuser.Email("lee@example.com"), "hashed:x", domuser.RoleUser)
+	u.SetVerifiedPhone("+442071838750", u.CreatedAt)
+	texts := textbox{}
+	uc := NewSMSUseCase(usersByEmail{u.Email: u}, fakeTokenIssuer{}, nil, 0, fakeOTPStore{}, fakeFailureCounter{}, texts.sender(), nil, SMSOptions{MaxAttempts: 2})
+
+	if err := uc.SendLoginCode(ctx, dto.PhoneRequest{Phone: "+15550000000"}); err != nil || len(texts) != 0 {
+		t.Fatalf("expected silent success for unknown phone: err=%v texts=%d", err, len(texts))
+	}
+	if err := uc.SendLoginCode(ctx, dto.PhoneRequest{Phone: "+442071838750"}); err != nil {
+		t.Fatal(err)
+	}
+	code := texts.code(t, "+442071838750")
+	for i := 0; i < 2; i++ {
+		if _, err := uc.Login(ctx, dto.SMSLoginRequest{Phone: "+442071838750", Code: wrongCode(code)}); !errors.Is(err, apperr.ErrOTPInvalid) {
+			t.Fatalf("attempt %d: expected invalid code, got %v", i, err)
+		}
+	}
+	if _, err := uc.Login(ctx, dto.SMSLoginRequest{Phone: "+442071838750", Code: code}); !errors.Is(err, apperr.ErrOTPInvalid) {
+		t.Fatalf("expected code to be invalidated after too many attempts, got %v", err)
+	}
+}
+
+func TestSMS_PhoneOwnedByAnotherAccount(t *testing.T) {
+	ctx := context.Background()
+	owner := domuser.NewUser("Mo", "Ng", domuser.Email("mo@example.com"), "hashed:x", domuser.RoleUser)
+	owner.SetVerifiedPhone("+14155552671", owner.CreatedAt)
+	other := domuser.NewUser("Ny", "Oh", domuser.Email("ny@example.com"), "hashed:x", domuser.RoleUser)
+	texts := textbox{}
+	uc := NewSMSUseCase(usersByEmail{owner.Email: owner, other.Email: other}, fakeTokenIssuer{}, nil, 0,
+		fakeOTPStore{}, fakeFailureCounter{}, texts.sender(), nil, SMSOptions{})
+
+	if err := uc.StartVerification(ctx, other.ID.String(), dto.PhoneRequest{Phone: "+14155552671"}); !errors.Is(err, domuser.ErrPhoneAlreadyExists) {
+		t.Fatalf("expected phone conflict, got %v", err)
+	}
+	if len(texts) != 0 {
+		t.Fatalf("no SMS expected, got %v", texts)
+	}
+}
+
+func TestSMS_SendLoginCodeHidesSendFailures(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Noa", "Ri", domuser.Email("noa@example.com"), "hashed:x", domuser.RoleUser)
+	u.SetVerifiedPhone(domuser.Phone("+14155552671"), time.Now())
+	challenges := fakeOTPStore{}
+	failing := ports.SendSMSFunc(func(context.Context, string, string) error { return errors.New("provider down") })
+	uc := NewSMSUseCase(usersByEmail{u.Email: u}, fakeTokenIssuer{}, nil, 0, challenges, fakeFailureCounter{}, failing, nil, SMSOptions{})
+
+	// A registered and an unknown number must answer alike
+	for _, phone := range []string{"+14155552671", "+14155550000"} {
+		if err := uc.SendLoginCode(ctx, dto.PhoneRequest{Phone: phone}); err != nil {
+			t.Fatalf("%s: expected the send failure hidden, got %v", phone, err)
+		}
+	}
+	if len(challenges) != 0 {
+		t.Fatalf("expected the unsent code dropped, got %d pending", len(challenges))
+	}
+}
+
+func TestSMS_ResendingDoesNotResetFailures(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Ola", "Pe", domuser.Email("ola@example.com"), "hashed:x", domuser.RoleUser)
+	u.SetVerifiedPhone("+442071838751", u.CreatedAt)
+	texts := textbox{}
+	uc := NewSMSUseCase(usersByEmail{u.Email: u}, fakeTokenIssuer{}, nil, 0, fakeOTPStore{}, fakeFailureCounter{}, texts.sender(), nil, SMSOptions{MaxAttempts: 3})
+
+	// One wrong guess per fresh code still adds up for the number
+	for i := 0; i < 3; i++ {
+		if err := uc.SendLoginCode(ctx, dto.PhoneRequest{Phone: "+442071838751"}); err != nil {
+			t.Fatal(err)
+		}
+		code := texts.code(t, "+442071838751")
+		if _, err := uc.Login(ctx, dto.SMSLoginRequest{Phone: "+442071838751", Code: wrongCode(code)}); !errors.Is(err, apperr.ErrOTPInvalid) {
+			t.Fatalf("attempt %d: expected invalid code, got %v", i, err)
+		}
+	}
+	if err := uc.SendLoginCode(ctx, dto.PhoneRequest{Phone: "+442071838751"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.Login(ctx, dto.SMSLoginRequest{Phone: "+442071838751", Code: texts.code(t, "+442071838751")}); !errors.Is(err, apperr.ErrOTPInvalid) {
+		t.Fatalf("expected the number to be locked out, got %v", err)
+	}
+}
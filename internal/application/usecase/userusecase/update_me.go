@@ -0,0 +1,102 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	domuser "gostartkit/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// UpdateMeUseCase lets users edit their own profile. Names change at once; a new email address needs the
+// current password, is only requested here and replaces the current one when the confirmation link is opened.
+type UpdateMeUseCase struct {
+	repo   domuser.Repository
+	hasher PasswordHasher
+	// verifier sends the confirmation link; nil refuses email changes
+	verifier *EmailVerificationUseCase
+}
+
+func NewUpdateMeUseCase(repo domuser.Repository, hasher PasswordHasher, verifier *EmailVerificationUseCase) *UpdateMeUseCase {
+	return &UpdateMeUseCase{repo: repo, hasher: hasher, verifier: verifier}
+}
+
+func (uc *UpdateMeUseCase) Execute(ctx context.Context, userID string, input dto.UpdateMeRequest) (*dto.UpdateMeResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domuser.ErrInvalidID
+	}
+	u, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var newEmail domuser.Email
+	if input.Email != nil {
+		if newEmail, err = domuser.NewEmail(*input.Email); err != nil {
+			return nil, err
+		}
+		if newEmail == u.Email {
+			newEmail = ""
+		} else if uc.verifier == nil {
+			return nil, apperr.ErrEmailChangeNotConfigured
+		} else if !uc.hasher.Compare(u.Password, input.CurrentPassword) {
+			// A stolen access token alone must not be enough to move the account to another address
+			return nil, domuser.ErrInvalidPassword
+		} else if err := ensureEmailAvailable(ctx, uc.repo, newEmail); err != nil {
+			return nil, err
+		}
+	}
+	changed := false
+	if input.FirstName != nil {
+		name := strings.TrimSpace(*input.FirstName)
+		if name == "" {
+			return nil, domuser.ErrInvalidFirstName
+		}
+		changed = changed || name != u.FirstName
+		u.FirstName = name
+	}
+	if input.LastName != nil {
+		name := strings.TrimSpace(*input.LastName)
+		if name == "" {
+			return nil, domuser.ErrInvalidLastName
+		}
+		changed = changed || name != u.LastName
+		u.LastName = name
+	}
+	// Mail goes out before anything is saved, so a delivery failure leaves the profile untouched
+	if newEmail != "" {
+		if err := uc.verifier.RequestEmailChange(ctx, u, newEmail); err != nil {
+			return nil, err
+		}
+	}
+	if changed {
+		u.UpdatedAt = time.Now().UTC()
+		if err := uc.repo.Update(ctx, u); err != nil {
+			return nil, err
+		}
+	}
+	resp := &dto.UpdateMeResponse{UserResponse: userResponse(u)}
+	if newEmail != "" {
+		resp.PendingEmail = newEmail.String()
+	}
+	return resp, nil
+}
+
+// ensureEmailAvailable fails with ErrEmailAlreadyExists when another live account uses email. The unique
+// index still decides races; Update maps its violation to the same error.
+func ensureEmailAvailable(ctx context.Context, repo domuser.Repository, email domuser.Email) error {
+	_, err := repo.GetByEmail(ctx, email)
+	switch {
+	case err == nil:
+		return domuser.ErrEmailAlreadyExists
+	case errors.Is(err, domuser.ErrUserNotFound):
+		return nil
+	default:
+		return err
+	}
+}
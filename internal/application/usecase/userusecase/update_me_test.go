@@ -0,0 +1,189 @@
+package userusecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	domuser "gostartkit/internal/domain/user"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestUpdateMe_NamesApplyAndEmailWaitsForConfirmation(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Ann", "Lee", domuser.Email("ann@example.com"), "hashed:x", domuser.RoleUser)
+	repo, mail := usersByEmail{u.Email: u}, outbox{}
+	verifier := newEmailVerification(t, repo, mail)
+	uc := NewUpdateMeUseCase(repo, fakeHasher{}, verifier)
+
+	resp, err := uc.Execute(ctx, u.ID.String(), dto.UpdateMeRequest{FirstName: strPtr(" Anna "), Email: strPtr(" anna@example.com "), CurrentPassword: "x"})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if resp.FirstName != "Anna" || resp.Email != "ann@example.com" || resp.PendingEmail != "anna@example.com" {
+		t.Fatalf("expected the name changed and the email pending, got %+v", resp)
+	}
+	if notice := mail["ann@example.com"]; len(notice) != 1 || !strings.Contains(notice[0], "anna@example.com") {
+		t.Fatalf("expected the current address notified, got %v", notice)
+	}
+
+	if err := verifier.Verify(ctx, dto.VerifyEmailRequest{Token: mail.linkToken(t, "anna@example.com")}); err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	if u.Email != "anna@example.com" || !u.IsEmailVerified() {
+		t.Fatalf("expected the confirmed address in place, got %s (verified %v)", u.Email, u.IsEmailVerified())
+	}
+	if _, err := repo.GetByEmail(ctx, "ann@example.com"); !errors.Is(err, domuser.ErrUserNotFound) {
+		t.Fatalf("expected the old address released, got %v", err)
+	}
+}
+
+func TestUpdateMe_EmailConflictsAndStaleLinks(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Bo", "Ng", domuser.Email("bo@example.com"), "hashed:x", domuser.RoleUser)
+	other := domuser.NewUser("Cy", "Ho", domuser.Email("cy@example.com"), "hashed:x", domuser.RoleUser)
+	repo, mail := usersByEmail{u.Email: u, other.Email: other}, outbox{}
+	verifier := newEmailVerification(t, repo, mail)
+	uc := NewUpdateMeUseCase(repo, fakeHasher{}, verifier)
+
+	if _, err := uc.Execute(ctx, u.ID.String(), dto.UpdateMeRequest{Email: strPtr("cy@example.com"), CurrentPassword: "x"}); !errors.Is(err, domuser.ErrEmailAlreadyExists) {
+		t.Fatalf("expected a taken address refused, got %v", err)
+	}
+
+	// Taken between request and confirmation
+	if _, err := uc.Execute(ctx, u.ID.String(), dto.UpdateMeRequest{Email: strPtr("dee@example.com"), CurrentPassword: "x"}); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	raced := domuser.NewUser("Dee", "Oh", domuser.Email("dee@example.com"), "hashed:x", domuser.RoleUser)
+	_ = repo.Save(ctx, raced)
+	if err := verifier.Verify(ctx, dto.VerifyEmailRequest{Token: mail.linkToken(t, "dee@example.com")}); !errors.Is(err, domuser.ErrEmailAlreadyExists) {
+		t.Fatalf("expected a conflict at confirmation, got %v", err)
+	}
+
+	// A link goes stale once the address changed another way
+	if _, err := uc.Execute(ctx, u.ID.String(), dto.UpdateMeRequest{Email: strPtr("eve@example.com"), CurrentPassword: "x"}); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if _, err := uc.Execute(ctx, u.ID.String(), dto.UpdateMeRequest{Email: strPtr("fay@example.com"), CurrentPassword: "x"}); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if err := verifier.Verify(ctx, dto.VerifyEmailRequest{Token: mail.linkToken(t, "fay@example.com")}); err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	if err := verifier.Verify(ctx, dto.VerifyEmailRequest{Token: mail.linkToken(t, "eve@example.com")}); !errors.Is(err, apperr.ErrVerificationTokenInvalid) {
+		t.Fatalf("expected the older link rejected, got %v", err)
+	}
+	if u.Email != "fay@example.com" {
+		t.Fatalf("expected fay@example.com, got %s", u.Email)
+	}
+}
+
+func TestUpdateMe_EmailChangeNeedsLinks(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Gus", "Ray", domuser.Email("gus@example.com"), "hashed:x", domuser.RoleUser)
+	uc := NewUpdateMeUseCase(usersByEmail{u.Email: u}, fakeHasher{}, nil)
+
+	if _, err := uc.Execute(ctx, u.ID.String(), dto.UpdateMeRequest{Email: strPtr("new@example.com"), CurrentPassword: "x"}); !errors.Is(err, apperr.ErrEmailChangeNotConfigured) {
+		t.Fatalf("expected email change refused without links, got %v", err)
+	}
+	resp, err := uc.Execute(ctx, u.ID.String(), dto.UpdateMeRequest{LastName: strPtr("Roy"), Email: strPtr("gus@example.com")})
+	if err != nil || resp.LastName != "Roy" || resp.PendingEmail != "" {
+		t.Fatalf("expected the unchanged email ignored, got %+v (%v)", resp, err)
+	}
+	if _, err := uc.Execute(ctx, u.ID.String(), dto.UpdateMeRequest{FirstName: strPtr("  ")}); !errors.Is(err, domuser.ErrInvalidFirstName) {
+		t.Fatalf("expected a blank name refused, got %v", err)
+	}
+}
+
+func TestUpdateMe_EmailChangeNeedsCurrentPassword(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Hal", "Sun", domuser.Email("hal@example.com"), "hashed:x", domuser.RoleUser)
+	repo, mail := usersByEmail{u.Email: u}, outbox{}
+	uc := NewUpdateMeUseCase(repo, fakeHasher{}, newEmailVerification(t, repo, mail))
+
+	for _, pw := range []string{"", "wrong"} {
+		if _, err := uc.Execute(ctx, u.ID.String(), dto.UpdateMeRequest{FirstName: strPtr("Hank"), Email: strPtr("new@example.com"), CurrentPassword: pw}); !errors.Is(err, domuser.ErrInvalidPassword) {
+			t.Fatalf("password %q: expected ErrInvalidPassword, got %v", pw, err)
+		}
+	}
+	if len(mail) != 0 || u.FirstName != "Hal" {
+		t.Fatalf("expected nothing sent or saved, got mail %v and name %q", mail, u.FirstName)
+	}
+}
+
+func TestUpdateMe_PasswordChangeCancelsEmailChange(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Ida", "Lin", domuser.Email("ida@example.com"), "hashed:x", domuser.RoleUser)
+	repo, mail := usersByEmail{u.Email: u}, outbox{}
+	verifier := newEmailVerification(t, repo, mail)
+	uc := NewUpdateMeUseCase(repo, fakeHasher{}, verifier)
+
+	if _, err := uc.Execute(ctx, u.ID.String(), dto.UpdateMeRequest{Email: strPtr("thief@example.com"), CurrentPassword: "x"}); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	// The owner follows the notice and changes the password
+	if err := NewChangePasswordUseCase(repo, fakeHasher{}).Execute(ctx, u.ID.String(), dto.ChangePasswordRequest{CurrentPassword: "x", NewPassword: "y"}); err != nil {
+		t.Fatalf("change password: %v", err)
+	}
+	if err := verifier.Verify(ctx, dto.VerifyEmailRequest{Token: mail.linkToken(t, "thief@example.com")}); !errors.Is(err, apperr.ErrVerificationTokenInvalid) {
+		t.Fatalf("expected the link rejected after a password change, got %v", err)
+	}
+	if u.Email != "ida@example.com" {
+		t.Fatalf("expected the address unchanged, got %s", u.Email)
+	}
+}
+
+func TestUpdateMe_LoginRehashKeepsEmailChange(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Kai", "Moe", domuser.Email("kai@example.com"), "hashed:x", domuser.RoleUser)
+	repo, mail := usersByEmail{u.Email: u}, outbox{}
+	verifier := newEmailVerification(t, repo, mail)
+	uc := NewUpdateMeUseCase(repo, fakeHasher{}, verifier)
+
+	if _, err := uc.Execute(ctx, u.ID.String(), dto.UpdateMeRequest{Email: strPtr("kai.moe@example.com"), CurrentPassword: "x"}); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	// Signing in upgrades the stored hash of the same password
+	login := &LoginUserUseCase{repo: repo, hasher: upgradingHasher{}, jwt: fakeTokenIssuer{}}
+	if _, err := login.Execute(ctx, dto.LoginRequest{Email: "kai@example.com", Password: "x"}); err != nil || u.Password != "v2:x" {
+		t.Fatalf("expected a login with a rehash, got hash %q (%v)", u.Password, err)
+	}
+	if err := verifier.Verify(ctx, dto.VerifyEmailRequest{Token: mail.linkToken(t, "kai.moe@example.com")}); err != nil {
+		t.Fatalf("expected the link to survive a rehash, got %v", err)
+	}
+	if u.Email != "kai.moe@example.com" {
+		t.Fatalf("expected the new address, got %s", u.Email)
+	}
+}
+
+func TestUpdateMe_MailFailureSavesNothing(t *testing.T) {
+	ctx := context.Background()
+	u := domuser.NewUser("Jo", "Kim", domuser.Email("jo@example.com"), "hashed:x", domuser.RoleUser)
+	repo := &countingUpdates{usersByEmail: usersByEmail{u.Email: u}}
+	failing := ports.SendEmailFunc(func(context.Context, string, string, string) error { return errors.New("smtp down") })
+	verifier := NewEmailVerificationUseCase(repo, fakeLinkTokens{}, failing, EmailVerificationOptions{LinkBaseURL: "https://app.example/verify"})
+	uc := NewUpdateMeUseCase(repo, fakeHasher{}, verifier)
+
+	if _, err := uc.Execute(ctx, u.ID.String(), dto.UpdateMeRequest{FirstName: strPtr("Joe"), Email: strPtr("joe@example.com"), CurrentPassword: "x"}); err == nil {
+		t.Fatalf("expected the mail failure reported")
+	}
+	if repo.updates != 0 {
+		t.Fatalf("expected the name change not saved, got %d updates", repo.updates)
+	}
+}
+
+// countingUpdates counts repository updates.
+type countingUpdates struct {
+	usersByEmail
+	updates int
+}
+
+func (r *countingUpdates) Update(ctx context.Context, u *domuser.User) error {
+	r.updates++
+	return r.usersByEmail.Update(ctx, u)
+}
@@ -13,6 +13,9 @@ type HTTPConfig struct {
 	LoginRateLimitBurst int     `env:"HTTP_LOGIN_RATELIMIT_BURST" default:"5"`
 	// When true, Redis rate limiter will deny requests on Redis errors (fail-closed). Default false (fail-open).
 	LoginRateLimitFailClosed bool `env:"HTTP_LOGIN_RATELIMIT_FAIL_CLOSED" default:"false"`
+	// Rate limit for endpoints that send email or SMS (resend verification, forgot password, SMS codes), per IP
+	EmailRateLimitRPS   float64 `env:"HTTP_EMAIL_RATELIMIT_RPS" default:"0.05"`
+	EmailRateLimitBurst int     `env:"HTTP_EMAIL_RATELIMIT_BURST" default:"3"`
 	// Max body size for JSON requests (bytes)
 	MaxBodyBytes int64 `env:"HTTP_MAX_BODY_BYTES" default:"1048576"`
 }
@@ -37,15 +40,24 @@ type JWTConfig struct {
 	Issuer    string `env:"JWT_ISSUER" default:"app"`
 	Audience  string `env:"JWT_AUDIENCE" default:"app-clients"`
 	LeewaySec int    `env:"JWT_LEEWAY_SEC" default:"30"`
-	// Algorithm: HS256 (default), RS256, EdDSA
+	// Algorithm: HS256 (default), RS256, PS256, ES256, ES384, EdDSA
 	Alg string `env:"JWT_ALG" default:"HS256"`
 	// Optional key id to attach in JWT header when signing (useful for rotation)
 	KID string `env:"JWT_KID"`
-	// Private key for RS256/EdDSA (PEM content or file path). Prefer path in production.
+	// Private key for asymmetric algorithms (PEM content or file path). Prefer path in production.
 	PrivateKeyPath string `env:"JWT_PRIVATE_KEY_PATH"`
 	PrivateKeyPEM  string `env:"JWT_PRIVATE_KEY_PEM"`
 	// Directory containing public key PEM files for verification and rotation. Filename (without extension) is treated as kid.
 	PublicKeysDir string `env:"JWT_PUBLIC_KEYS_DIR"`
+	// Optional YAML manifest listing several keys with rotation states (next/active/retiring).
+	// When set it replaces JWT_KID/JWT_PRIVATE_KEY_*/JWT_PUBLIC_KEYS_DIR as the key source.
+	KeysManifestPath string `env:"JWT_KEYS_MANIFEST"`
+	// Reload keys from disk every N seconds (0 = only on SIGHUP)
+	KeysReloadIntervalSec int `env:"JWT_KEYS_RELOAD_INTERVAL_SEC" default:"0"`
+	// Cache lifetime (seconds) advertised on /.well-known/jwks.json and /.well-known/openid-configuration
+	JWKSMaxAgeSec int `env:"JWT_JWKS_MAX_AGE_SEC" default:"300"`
+	// Public base URL of this API used for jwks_uri when JWT_ISSUER is not an absolute URL (e.g. https://api.example.com)
+	PublicBaseURL string `env:"JWT_PUBLIC_BASE_URL"`
 }
 
 type RBACConfig struct {
@@ -65,10 +77,138 @@ type SeedConfig struct {
 type SecurityConfig struct {
 	// BcryptCost allows tuning password hashing cost per environment (4-31). 0 = use library default
 	BcryptCost int `env:"BCRYPT_COST" default:"0"`
+	// Algorithm for new password hashes: "argon2id" (default) or "bcrypt". Stored hashes of either
+	// algorithm keep verifying and are upgraded to the current algorithm/parameters on the next login
+	PasswordHashAlgo string `env:"PASSWORD_HASH_ALGO" default:"argon2id"`
+	// Argon2id cost parameters (0 = 65536 KiB memory, 3 iterations, parallelism 2)
+	Argon2MemoryKiB   int `env:"ARGON2_MEMORY_KIB" default:"65536"`
+	Argon2Iterations  int `env:"ARGON2_ITERATIONS" default:"3"`
+	Argon2Parallelism int `env:"ARGON2_PARALLELISM" default:"2"`
 	// Refresh token TTL in seconds
 	RefreshTTLSeconds int `env:"REFRESH_TTL_SEC" default:"604800"`
 	// Enable refresh token flow and endpoints
 	RefreshEnabled bool `env:"AUTH_REFRESH_ENABLED" default:"false"`
+	// Refresh token backend: "redis", "postgres" or "memory" (dev/tests). Empty = redis when REDIS_ADDR is set, otherwise postgres.
+	RefreshStore string `env:"AUTH_REFRESH_STORE"`
+	// How often expired refresh tokens are purged from Postgres, in seconds (postgres backend only)
+	RefreshCleanupIntervalSec int `env:"AUTH_REFRESH_CLEANUP_INTERVAL_SEC" default:"3600"`
+	// When true, requests are rejected if the access-token denylist cannot be checked (e.g. Redis down). Default false (fail-open).
+	RevocationFailClosed bool `env:"AUTH_REVOCATION_FAIL_CLOSED" default:"false"`
+	// HaveIBeenPwned "SHA-1 ordered by hash" file, or a bloom filter built from one with cmd/breachbloom.
+	// When set, strong_password also refuses breached passwords (register, change and reset). Empty = disabled
+	BreachedPasswordsFile string `env:"BREACHED_PASSWORDS_FILE"`
+	// Refuse passwords seen at least this many times in breaches (0 = 1). Bloom filters keep the value they were built with
+	BreachedPasswordMinCount int `env:"BREACHED_PASSWORD_MIN_COUNT" default:"1"`
+	// Consecutive wrong passwords that lock an account (0 = never lock; backoff still applies). Password logins only
+	LoginLockoutThreshold int `env:"LOGIN_LOCKOUT_THRESHOLD" default:"10"`
+	// First lock length in seconds; each further failure after it doubles the lock up to the max (0 = 900 / 86400)
+	LoginLockoutDurationSec    int `env:"LOGIN_LOCKOUT_DURATION_SEC" default:"900"`
+	LoginLockoutMaxDurationSec int `env:"LOGIN_LOCKOUT_MAX_DURATION_SEC" default:"86400"`
+	// Failures before attempts must be spaced out, and the first pause in milliseconds, doubling per failure (0 = 3 / 1000)
+	LoginBackoffAfter  int `env:"LOGIN_BACKOFF_AFTER" default:"3"`
+	LoginBackoffBaseMS int `env:"LOGIN_BACKOFF_BASE_MS" default:"1000"`
+	// Failures older than this many seconds are forgotten (0 = 3600)
+	LoginFailureWindowSec int `env:"LOGIN_FAILURE_WINDOW_SEC" default:"3600"`
+}
+
+type OIDCConfig struct {
+	// YAML file listing OpenID Connect providers (see internal/infras/oidc). Empty = OIDC login disabled
+	ProvidersFile string `env:"OIDC_PROVIDERS_FILE"`
+	// Public base URL of this API; a provider's redirect_url defaults to <base>/v1/auth/oidc/<name>/callback
+	RedirectBaseURL string `env:"OIDC_REDIRECT_BASE_URL"`
+	// How long a started login may take before the callback is rejected, in seconds
+	StateTTLSec int `env:"OIDC_STATE_TTL_SEC" default:"600"`
+}
+
+type MFAConfig struct {
+	// Base64 32-byte key (AES-256-GCM) encrypting TOTP secrets at rest. Empty = MFA disabled
+	EncryptionKey string `env:"MFA_ENCRYPTION_KEY"`
+	// Issuer label shown in authenticator apps (default: JWT_ISSUER)
+	Issuer string `env:"MFA_ISSUER"`
+	// Roles that must use MFA on every login (comma-separated, e.g. "admin")
+	RequiredRoles []string `env:"MFA_REQUIRED_ROLES" envSeparator:","`
+	// Lifetime of the mfa_token returned by login, in seconds
+	ChallengeTTLSec int `env:"MFA_CHALLENGE_TTL_SEC" default:"300"`
+	// Wrong codes allowed per mfa_token before it is invalidated
+	MaxAttempts int `env:"MFA_MAX_ATTEMPTS" default:"5"`
+}
+
+type EmailConfig struct {
+	// SMTP relay; empty host logs emails instead of sending them (not allowed with ENV=prod)
+	SMTPHost     string `env:"SMTP_HOST"`
+	SMTPPort     int    `env:"SMTP_PORT" default:"587"`
+	SMTPUsername string `env:"SMTP_USERNAME"`
+	SMTPPassword string `env:"SMTP_PASSWORD"`
+	SMTPFrom     string `env:"SMTP_FROM"`
+	// Use implicit TLS (port 465); otherwise plain SMTP with STARTTLS when offered
+	SMTPTLS bool `env:"SMTP_TLS" default:"false"`
+	// Secret (>= 32 bytes) signing emailed links. Empty = email verification disabled
+	LinkSecret string `env:"EMAIL_LINK_SECRET"`
+	// Frontend page receiving verification tokens as ?token=...
+	VerificationURL string `env:"EMAIL_VERIFICATION_URL"`
+	// How long a verification link stays valid, in seconds
+	VerificationTTLSec int `env:"EMAIL_VERIFICATION_TTL_SEC" default:"86400"`
+	// Refuse password logins until the email address is verified
+	VerificationRequired bool `env:"EMAIL_VERIFICATION_REQUIRED" default:"false"`
+	// Frontend page receiving password reset tokens as ?token=... Empty = password reset disabled
+	PasswordResetURL string `env:"PASSWORD_RESET_URL"`
+	// How long a password reset link stays valid, in seconds
+	PasswordResetTTLSec int `env:"PASSWORD_RESET_TTL_SEC" default:"3600"`
+	// Frontend page receiving sign-in tokens as ?token=... Empty = magic-link login disabled
+	MagicLinkURL string `env:"MAGIC_LINK_URL"`
+	// How long a magic link stays valid, in seconds
+	MagicLinkTTLSec int `env:"MAGIC_LINK_TTL_SEC" default:"900"`
+}
+
+type SMSConfig struct {
+	// Provider: "twilio", or "log"/"file" for development. Empty = phone numbers and SMS login disabled
+	Provider         string `env:"SMS_PROVIDER"`
+	TwilioAccountSID string `env:"TWILIO_ACCOUNT_SID"`
+	TwilioAuthToken  string `env:"TWILIO_AUTH_TOKEN"`
+	TwilioFrom       string `env:"TWILIO_FROM"`
+	// File the "file" provider appends messages to
+	FilePath string `env:"SMS_FILE_PATH" default:"tmp/sms.log"`
+	// Allow signing in with a code sent to a confirmed phone number
+	LoginEnabled bool `env:"SMS_LOGIN_ENABLED" default:"false"`
+	// How long a code stays valid, in seconds
+	CodeTTLSec int `env:"SMS_CODE_TTL_SEC" default:"300"`
+	// Wrong codes allowed before the code is invalidated
+	MaxAttempts int `env:"SMS_MAX_ATTEMPTS" default:"5"`
+}
+
+type OAuthConfig struct {
+	// Lifetime of client_credentials access tokens in seconds (0 = JWT_EXPIRE_SEC)
+	TokenTTLSec int `env:"OAUTH_TOKEN_TTL_SEC" default:"0"`
+}
+
+type DataExportConfig struct {
+	// Largest export in bytes returned in the response; larger ones are emailed as a link (0 = 5 MiB)
+	InlineMaxBytes int `env:"DATA_EXPORT_INLINE_MAX_BYTES" default:"0"`
+	// Lifetime of the emailed download link in seconds (0 = 24 hours)
+	LinkTTLSec int `env:"DATA_EXPORT_LINK_TTL_SEC" default:"0"`
+}
+
+type ObjectStorageConfig struct {
+	// Backend: "local" or "s3". Empty = none (data exports are always returned inline)
+	Backend string `env:"OBJECT_STORAGE"`
+	// Directory of the local backend (empty = tmp/objects)
+	LocalDir string `env:"OBJECT_STORAGE_LOCAL_DIR"`
+	// Public base URL of this API; the local backend serves signed links under <url>/v1/files
+	PublicURL string `env:"OBJECT_STORAGE_PUBLIC_URL"`
+	// Secret signing local links, at least 32 bytes
+	SigningSecret string `env:"OBJECT_STORAGE_SIGNING_SECRET"`
+	// Largest body accepted by a signed upload link of the local backend (0 = 25 MiB)
+	MaxUploadBytes int64 `env:"OBJECT_STORAGE_MAX_UPLOAD_BYTES" default:"0"`
+	// S3 or S3-compatible service (MinIO, R2, ...). The bucket must exist
+	S3Endpoint  string `env:"S3_ENDPOINT"`
+	S3Region    string `env:"S3_REGION"`
+	S3Bucket    string `env:"S3_BUCKET"`
+	S3AccessKey string `env:"S3_ACCESS_KEY"`
+	S3SecretKey string `env:"S3_SECRET_KEY"`
+	// Address objects as <endpoint>/<bucket>/<key>, as MinIO expects
+	S3PathStyle bool `env:"S3_PATH_STYLE" default:"false"`
+	// Multipart chunk size in bytes (0 = 8 MiB, minimum 5 MiB)
+	S3PartSize int64 `env:"S3_PART_SIZE" default:"0"`
 }
 
 type Config struct {
@@ -83,6 +223,20 @@ type Config struct {
 	MigrationsPath string `env:"MIGRATIONS_PATH" default:"migrations"`
 	// Security-related tunables
 	Security SecurityConfig
+	// OpenID Connect login
+	OIDC OIDCConfig
+	// TOTP multi-factor authentication
+	MFA MFAConfig
+	// Outgoing email and emailed links (verification, password reset, magic-link login)
+	Email EmailConfig
+	// Text messages: phone verification and SMS login
+	SMS SMSConfig
+	// OAuth2 client credentials for service-to-service calls
+	OAuth OAuthConfig
+	// Personal data export (GET /v1/auth/me/export)
+	DataExport DataExportConfig
+	// Object storage for files such as data exports (local directory or S3)
+	ObjectStorage ObjectStorageConfig
 	// Optional Redis for distributed features (rate limit, refresh tokens)
 	RedisAddr     string `env:"REDIS_ADDR"`
 	RedisPassword string `env:"REDIS_PASSWORD"`
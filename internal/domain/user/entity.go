@@ -15,6 +15,20 @@ type User struct {
 	Role      Role
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	// EmailVerifiedAt is nil until the user proved ownership of Email
+	EmailVerifiedAt *time.Time
+	// Phone is empty unless the user confirmed a number by SMS; PhoneVerifiedAt records when
+	Phone           Phone
+	PhoneVerifiedAt *time.Time
+	// DisabledAt is set while an administrator has disabled the account; disabled users cannot sign in
+	DisabledAt *time.Time
+	// DeletedAt is set once the account is deleted; the row stays so the ID remains valid for references
+	DeletedAt *time.Time
+	// ErasedAt is set once personal data has been anonymized (see Erase)
+	ErasedAt *time.Time
+	// PasswordChangedAt is when the owner last set a password (sign-up, change or reset); rehashing the same
+	// password does not move it
+	PasswordChangedAt time.Time
 }
 
 func NewUser(firstName, lastName string, email Email, password string, role Role) *User {
@@ -30,5 +44,100 @@ func NewUser(firstName, lastName string, email Email, password string, role Role
         CreatedAt: time.Now(),
         UpdatedAt: time.Now(),
     }
+    u.PasswordChangedAt = u.CreatedAt
     return u
-}
\ No newline at end of file
+}
+
+// IsEmailVerified reports whether the current email address has been confirmed.
+func (u *User) IsEmailVerified() bool { return u.EmailVerifiedAt != nil }
+
+// MarkEmailVerified records the confirmation time of the current email address.
+func (u *User) MarkEmailVerified(at time.Time) {
+	at = at.UTC()
+	u.EmailVerifiedAt = &at
+	u.UpdatedAt = at
+}
+
+// SetVerifiedEmail replaces the email address with one the user has just confirmed.
+func (u *User) SetVerifiedEmail(email Email, at time.Time) {
+	at = at.UTC()
+	u.Email = email
+	u.EmailVerifiedAt = &at
+	u.UpdatedAt = at
+}
+
+// SetPassword stores the hash of a new password chosen by the owner.
+func (u *User) SetPassword(hash string, at time.Time) {
+	at = at.UTC()
+	u.Password = hash
+	u.PasswordChangedAt = at
+	u.UpdatedAt = at
+}
+
+// HasPhone reports whether the user has a confirmed phone number.
+func (u *User) HasPhone() bool { return u.Phone != "" }
+
+// SetVerifiedPhone records phone as confirmed at the given time.
+func (u *User) SetVerifiedPhone(phone Phone, at time.Time) {
+	at = at.UTC()
+	u.Phone = phone
+	u.PhoneVerifiedAt = &at
+	u.UpdatedAt = at
+}
+
+// RemovePhone clears the phone number.
+func (u *User) RemovePhone(at time.Time) {
+	u.Phone = ""
+	u.PhoneVerifiedAt = nil
+	u.UpdatedAt = at.UTC()
+}
+
+// IsDisabled reports whether the account is disabled.
+func (u *User) IsDisabled() bool { return u.DisabledAt != nil }
+
+// Disable blocks sign-in from the given time; disabling an already disabled user keeps the original time.
+func (u *User) Disable(at time.Time) {
+	if u.DisabledAt != nil {
+		return
+	}
+	at = at.UTC()
+	u.DisabledAt = &at
+	u.UpdatedAt = at
+}
+
+// Enable lifts a disable.
+func (u *User) Enable(at time.Time) {
+	u.DisabledAt = nil
+	u.UpdatedAt = at.UTC()
+}
+
+// IsDeleted reports whether the account has been deleted.
+func (u *User) IsDeleted() bool { return u.DeletedAt != nil }
+
+// IsErased reports whether the user's personal data has been anonymized.
+func (u *User) IsErased() bool { return u.ErasedAt != nil }
+
+// Erase anonymizes personal data for a GDPR erasure request: names are cleared, the email becomes
+// TombstoneEmail(ID) and password and phone are removed. ID, role and timestamps are kept, and an
+// erased user counts as deleted.
+func (u *User) Erase(at time.Time) {
+	at = at.UTC()
+	u.FirstName = ""
+	u.LastName = ""
+	u.Email = TombstoneEmail(u.ID)
+	u.Password = ""
+	u.EmailVerifiedAt = nil
+	u.Phone = ""
+	u.PhoneVerifiedAt = nil
+	if u.DeletedAt == nil {
+		u.DeletedAt = &at
+	}
+	u.ErasedAt = &at
+	u.UpdatedAt = at
+}
+
+// TombstoneEmail is the placeholder address of an erased user. It is unique per user and uses the
+// reserved .invalid TLD, so it can never receive mail or collide with a real sign-up.
+func TombstoneEmail(id uuid.UUID) Email {
+	return Email("erased-" + id.String() + "@deleted.invalid")
+}
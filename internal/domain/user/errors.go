@@ -12,4 +12,6 @@ var (
 	ErrInvalidLastName    = errors.New("invalid last name")
 	ErrInvalidRole        = errors.New("invalid role")
 	ErrInvalidID          = errors.New("invalid ID")
+	ErrInvalidPhone       = errors.New("invalid phone number")
+	ErrPhoneAlreadyExists = errors.New("phone number already in use")
 )
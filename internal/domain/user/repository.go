@@ -2,15 +2,51 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// Repository persists users. Reads never return deleted users unless the method or filter says otherwise.
 type Repository interface {
 	Save(ctx context.Context, u *User) error
 	GetByID(ctx context.Context, id uuid.UUID) (*User, error)
+	// GetByIDIncludingDeleted is GetByID that also finds deleted and erased users
+	GetByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*User, error)
 	GetByEmail(ctx context.Context, email Email) (*User, error)
-	GetAll(ctx context.Context) ([]*User, error)
+	// GetByPhone finds the user with this confirmed phone number
+	GetByPhone(ctx context.Context, phone Phone) (*User, error)
+	// List returns one page of users matching filter, newest first
+	List(ctx context.Context, filter ListFilter) ([]*User, error)
+	// Update saves changes to a user that is not deleted; it returns ErrUserNotFound otherwise
 	Update(ctx context.Context, u *User) error
+	// UpdatePasswordHash replaces only the password hash and updated_at, leaving concurrent changes to other
+	// fields intact; it returns ErrUserNotFound for missing or deleted users
+	UpdatePasswordHash(ctx context.Context, id uuid.UUID, hash string, at time.Time) error
+	// Delete soft-deletes the user: the row is kept but hidden from reads, and its sign-in methods
+	// (linked identities, MFA, API keys, reset tokens, sessions) are removed
 	Delete(ctx context.Context, id uuid.UUID) error
+	// Erase stores the anonymized fields of a user after User.Erase and removes the same sign-in methods as Delete
+	Erase(ctx context.Context, u *User) error
+}
+
+// ListFilter selects users for Repository.List; zero-valued fields do not filter.
+type ListFilter struct {
+	Role Role
+	// EmailPrefix matches the start of the email address, ignoring case
+	EmailPrefix string
+	// CreatedFrom is inclusive, CreatedTo exclusive
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// After continues a listing behind this position (keyset pagination)
+	After *ListPosition
+	Limit int
+	// IncludeDeleted also lists deleted and erased users
+	IncludeDeleted bool
+}
+
+// ListPosition is a user's place in the (created_at, id) descending order used by List.
+type ListPosition struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
 }
@@ -24,6 +24,25 @@ func NewEmail(s string) (Email, error) {
 	return email, nil
 }
 
+// Phone is an E.164 number, e.g. +14155552671
+type Phone string
+
+func (p Phone) String() string {
+	return string(p)
+}
+
+func (p Phone) IsValid() bool {
+	return appval.IsE164Phone(string(p))
+}
+
+func NewPhone(s string) (Phone, error) {
+	phone := Phone(strings.TrimSpace(s))
+	if !phone.IsValid() {
+		return "", ErrInvalidPhone
+	}
+	return phone, nil
+}
+
 // Password
 type Password struct {
 	hash string
@@ -32,6 +32,17 @@ func TestNewEmail_Invalid(t *testing.T) {
 	}
 }
 
+func TestNewPhone(t *testing.T) {
+	if p, err := NewPhone(" +14155552671 "); err != nil || p.String() != "+14155552671" {
+		t.Fatalf("expected trimmed valid phone, got %q, %v", p, err)
+	}
+	for _, in := range []string{"4155552671", "+1 415 555 2671", "+0123"} {
+		if _, err := NewPhone(in); err != ErrInvalidPhone {
+			t.Fatalf("expected ErrInvalidPhone for %q, got %v", in, err)
+		}
+	}
+}
+
 func TestRole_IsValid(t *testing.T) {
 	cases := []struct {
 		in Role
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gostartkit/internal/application/ports"
+)
+
+// MemoryAccessRevocationStore is an in-process AccessTokenRevocationStore.
+// It is per-instance: use the Redis store when running more than one replica.
+type MemoryAccessRevocationStore struct {
+	mu    sync.Mutex
+	jtis  map[string]time.Time // jti -> expiry
+	users map[string]userCutoff
+}
+
+type userCutoff struct {
+	before  time.Time
+	expires time.Time
+}
+
+func NewMemoryAccessRevocationStore() *MemoryAccessRevocationStore {
+	return &MemoryAccessRevocationStore{jtis: map[string]time.Time{}, users: map[string]userCutoff{}}
+}
+
+func (s *MemoryAccessRevocationStore) RevokeJTI(_ context.Context, jti string, expiresAt time.Time) error {
+	now := time.Now()
+	if !expiresAt.After(now) {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeLocked(now)
+	s.jtis[jti] = expiresAt
+	return nil
+}
+
+func (s *MemoryAccessRevocationStore) RevokeUser(_ context.Context, userID string, before time.Time, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeLocked(now)
+	s.users[userID] = userCutoff{before: before, expires: now.Add(ttl)}
+	return nil
+}
+
+func (s *MemoryAccessRevocationStore) IsRevoked(_ context.Context, jti, userID string, issuedAt time.Time) (bool, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if exp, ok := s.jtis[jti]; ok && jti != "" && now.Before(exp) {
+		return true, nil
+	}
+	if uc, ok := s.users[userID]; ok && now.Before(uc.expires) && issuedAt.Unix() < uc.before.Unix() {
+		return true, nil
+	}
+	return false, nil
+}
+
+// purgeLocked drops entries whose tokens have expired anyway.
+func (s *MemoryAccessRevocationStore) purgeLocked(now time.Time) {
+	for k, exp := range s.jtis {
+		if !now.Before(exp) {
+			delete(s.jtis, k)
+		}
+	}
+	for k, uc := range s.users {
+		if !now.Before(uc.expires) {
+			delete(s.users, k)
+		}
+	}
+}
+
+var _ ports.AccessTokenRevocationStore = (*MemoryAccessRevocationStore)(nil)
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryAccessRevocationStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryAccessRevocationStore()
+	now := time.Now()
+
+	if err := s.RevokeJTI(ctx, "jti-1", now.Add(time.Minute)); err != nil {
+		t.Fatalf("revoke jti: %v", err)
+	}
+	if ok, _ := s.IsRevoked(ctx, "jti-1", "u1", now); !ok {
+		t.Fatalf("expected jti-1 revoked")
+	}
+	if ok, _ := s.IsRevoked(ctx, "jti-2", "u1", now); ok {
+		t.Fatalf("jti-2 must not be revoked")
+	}
+
+	// Revoking a user cuts off tokens issued up to now, not later ones
+	if err := s.RevokeUser(ctx, "u2", now, time.Minute); err != nil {
+		t.Fatalf("revoke user: %v", err)
+	}
+	if ok, _ := s.IsRevoked(ctx, "jti-3", "u2", now.Add(-time.Second)); !ok {
+		t.Fatalf("expected older token of u2 revoked")
+	}
+	if ok, _ := s.IsRevoked(ctx, "jti-4", "u2", now.Add(2*time.Second)); ok {
+		t.Fatalf("token issued after cutoff must stay valid")
+	}
+	// iat has whole seconds: a sign-in in the same second as the revocation must keep working
+	if ok, _ := s.IsRevoked(ctx, "jti-5", "u2", now.Truncate(time.Second)); ok {
+		t.Fatalf("token issued in the revocation's second must stay valid")
+	}
+
+	// Entries are not kept past the token's own expiry
+	_ = s.RevokeJTI(ctx, "jti-old", now.Add(-time.Second))
+	if ok, _ := s.IsRevoked(ctx, "jti-old", "u1", now); ok {
+		t.Fatalf("already expired token should not be stored")
+	}
+}
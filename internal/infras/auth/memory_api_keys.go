@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+
+	"github.com/google/uuid"
+)
+
+// MemoryAPIKeyStore is an in-process APIKeyStore for development and tests.
+type MemoryAPIKeyStore struct {
+	mu   sync.Mutex
+	keys map[uuid.UUID]ports.APIKey
+}
+
+func NewMemoryAPIKeyStore() *MemoryAPIKeyStore {
+	return &MemoryAPIKeyStore{keys: map[uuid.UUID]ports.APIKey{}}
+}
+
+func (s *MemoryAPIKeyStore) Create(_ context.Context, k ports.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.keys {
+		if existing.Prefix == k.Prefix {
+			return errors.New("api key prefix already exists")
+		}
+	}
+	s.keys[k.ID] = k
+	return nil
+}
+
+func (s *MemoryAPIKeyStore) GetByPrefix(_ context.Context, prefix string) (ports.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, k := range s.keys {
+		if k.Prefix == prefix {
+			return k, nil
+		}
+	}
+	return ports.APIKey{}, apperr.ErrAPIKeyNotFound
+}
+
+func (s *MemoryAPIKeyStore) Get(_ context.Context, userID, id uuid.UUID) (ports.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k, ok := s.keys[id]
+	if !ok || k.UserID != userID {
+		return ports.APIKey{}, apperr.ErrAPIKeyNotFound
+	}
+	return k, nil
+}
+
+func (s *MemoryAPIKeyStore) ListByUser(_ context.Context, userID uuid.UUID) ([]ports.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []ports.APIKey{}
+	for _, k := range s.keys {
+		if k.UserID == userID {
+			out = append(out, k)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (s *MemoryAPIKeyStore) Update(_ context.Context, userID, id uuid.UUID, name string, scopes []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k, ok := s.keys[id]
+	if !ok || k.UserID != userID {
+		return apperr.ErrAPIKeyNotFound
+	}
+	k.Name, k.Scopes = name, scopes
+	s.keys[id] = k
+	return nil
+}
+
+func (s *MemoryAPIKeyStore) Delete(_ context.Context, userID, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k, ok := s.keys[id]
+	if !ok || k.UserID != userID {
+		return apperr.ErrAPIKeyNotFound
+	}
+	delete(s.keys, id)
+	return nil
+}
+
+func (s *MemoryAPIKeyStore) Touch(_ context.Context, id uuid.UUID, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if k, ok := s.keys[id]; ok {
+		at = at.UTC()
+		k.LastUsedAt = &at
+		s.keys[id] = k
+	}
+	return nil
+}
+
+var _ ports.APIKeyStore = (*MemoryAPIKeyStore)(nil)
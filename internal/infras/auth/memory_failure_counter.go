@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gostartkit/internal/application/ports"
+)
+
+// MemoryFailureCounter is an in-process FailureCounter.
+// It is per-instance, so behind a load balancer each replica allows its own share of failures; use Redis there.
+type MemoryFailureCounter struct {
+	mu      sync.Mutex
+	entries map[string]*memFailures
+}
+
+type memFailures struct {
+	count   int
+	expires time.Time
+}
+
+func NewMemoryFailureCounter() *MemoryFailureCounter {
+	return &MemoryFailureCounter{entries: map[string]*memFailures{}}
+}
+
+func (s *MemoryFailureCounter) Count(_ context.Context, key string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.liveLocked(key, time.Now()); ok {
+		return v.count, nil
+	}
+	return 0, nil
+}
+
+func (s *MemoryFailureCounter) Add(_ context.Context, key string, window time.Duration) (int, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.liveLocked(key, now)
+	if !ok {
+		for k, e := range s.entries {
+			if !e.expires.After(now) {
+				delete(s.entries, k)
+			}
+		}
+		v = &memFailures{expires: now.Add(window)}
+		s.entries[key] = v
+	}
+	v.count++
+	return v.count, nil
+}
+
+func (s *MemoryFailureCounter) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *MemoryFailureCounter) liveLocked(key string, now time.Time) (*memFailures, bool) {
+	v, ok := s.entries[key]
+	if !ok || !v.expires.After(now) {
+		delete(s.entries, key)
+		return nil, false
+	}
+	return v, true
+}
+
+var _ ports.FailureCounter = (*MemoryFailureCounter)(nil)
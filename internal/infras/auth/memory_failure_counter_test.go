@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryFailureCounter(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryFailureCounter()
+
+	for want := 1; want <= 3; want++ {
+		if n, err := s.Add(ctx, "k", time.Minute); err != nil || n != want {
+			t.Fatalf("add: n=%d err=%v, want %d", n, err, want)
+		}
+	}
+	if n, _ := s.Count(ctx, "k"); n != 3 {
+		t.Fatalf("count: got %d, want 3", n)
+	}
+	if n, _ := s.Count(ctx, "other"); n != 0 {
+		t.Fatalf("other key: got %d, want 0", n)
+	}
+	if err := s.Reset(ctx, "k"); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if n, _ := s.Count(ctx, "k"); n != 0 {
+		t.Fatalf("after reset: got %d, want 0", n)
+	}
+
+	// A closed window starts the count over
+	if _, err := s.Add(ctx, "short", time.Millisecond); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if n, _ := s.Count(ctx, "short"); n != 0 {
+		t.Fatalf("expired window: got %d, want 0", n)
+	}
+	if n, _ := s.Add(ctx, "short", time.Minute); n != 1 {
+		t.Fatalf("new window: got %d, want 1", n)
+	}
+}
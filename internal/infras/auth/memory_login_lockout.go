@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gostartkit/internal/application/ports"
+
+	"github.com/google/uuid"
+)
+
+// MemoryLoginLockoutStore is an in-process LoginLockoutStore for development and tests.
+type MemoryLoginLockoutStore struct {
+	mu      sync.Mutex
+	entries map[uuid.UUID]ports.LoginLockout
+}
+
+func NewMemoryLoginLockoutStore() *MemoryLoginLockoutStore {
+	return &MemoryLoginLockoutStore{entries: map[uuid.UUID]ports.LoginLockout{}}
+}
+
+func (s *MemoryLoginLockoutStore) Get(_ context.Context, userID uuid.UUID) (ports.LoginLockout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.entries[userID], nil
+}
+
+func (s *MemoryLoginLockoutStore) RecordFailure(_ context.Context, userID uuid.UUID, at, windowStart time.Time) (ports.LoginLockout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[userID]
+	if !ok || (e.LastFailedAt.Before(windowStart) && e.LockedUntil.Before(windowStart)) {
+		e.FailedAttempts = 0
+	}
+	e.FailedAttempts++
+	e.LastFailedAt = at
+	s.entries[userID] = e
+	return e, nil
+}
+
+func (s *MemoryLoginLockoutStore) Lock(_ context.Context, userID uuid.UUID, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[userID]; ok {
+		e.LockedUntil = until
+		s.entries[userID] = e
+	}
+	return nil
+}
+
+func (s *MemoryLoginLockoutStore) Reset(_ context.Context, userID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, userID)
+	return nil
+}
+
+var _ ports.LoginLockoutStore = (*MemoryLoginLockoutStore)(nil)
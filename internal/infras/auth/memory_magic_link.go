@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+)
+
+// MemoryMagicLinkStore is an in-process MagicLinkStore.
+// It is per-instance: the consume request must reach the replica that sent the link, so use Redis behind a load balancer.
+type MemoryMagicLinkStore struct {
+	mu      sync.Mutex
+	pending map[string]memMagicLink
+}
+
+type memMagicLink struct {
+	link    ports.MagicLink
+	expires time.Time
+}
+
+func NewMemoryMagicLinkStore() *MemoryMagicLinkStore {
+	return &MemoryMagicLinkStore{pending: map[string]memMagicLink{}}
+}
+
+func (s *MemoryMagicLinkStore) Save(_ context.Context, tokenHash string, link ports.MagicLink, ttl time.Duration) error {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.pending {
+		if !v.expires.After(now) {
+			delete(s.pending, k)
+		}
+	}
+	s.pending[tokenHash] = memMagicLink{link: link, expires: now.Add(ttl)}
+	return nil
+}
+
+func (s *MemoryMagicLinkStore) Consume(_ context.Context, tokenHash string) (ports.MagicLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.pending[tokenHash]
+	delete(s.pending, tokenHash)
+	if !ok || !v.expires.After(time.Now()) {
+		return ports.MagicLink{}, apperr.ErrMagicLinkInvalid
+	}
+	return v.link, nil
+}
+
+var _ ports.MagicLinkStore = (*MemoryMagicLinkStore)(nil)
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+)
+
+// MemoryMFAChallengeStore is an in-process MFAChallengeStore.
+// It is per-instance: /v1/auth/mfa/verify must reach the replica that served the login, so use Redis behind a load balancer.
+type MemoryMFAChallengeStore struct {
+	mu      sync.Mutex
+	pending map[string]*memMFAChallenge
+}
+
+type memMFAChallenge struct {
+	challenge ports.MFAChallenge
+	failures  int
+	expires   time.Time
+}
+
+func NewMemoryMFAChallengeStore() *MemoryMFAChallengeStore {
+	return &MemoryMFAChallengeStore{pending: map[string]*memMFAChallenge{}}
+}
+
+func (s *MemoryMFAChallengeStore) Save(_ context.Context, token string, c ports.MFAChallenge, ttl time.Duration) error {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.pending {
+		if !v.expires.After(now) {
+			delete(s.pending, k)
+		}
+	}
+	s.pending[token] = &memMFAChallenge{challenge: c, expires: now.Add(ttl)}
+	return nil
+}
+
+func (s *MemoryMFAChallengeStore) Get(_ context.Context, token string) (ports.MFAChallenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.liveLocked(token)
+	if !ok {
+		return ports.MFAChallenge{}, apperr.ErrMFATokenInvalid
+	}
+	return v.challenge, nil
+}
+
+func (s *MemoryMFAChallengeStore) RecordFailure(_ context.Context, token string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.liveLocked(token)
+	if !ok {
+		return 0, apperr.ErrMFATokenInvalid
+	}
+	v.failures++
+	return v.failures, nil
+}
+
+func (s *MemoryMFAChallengeStore) Delete(_ context.Context, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, token)
+	return nil
+}
+
+func (s *MemoryMFAChallengeStore) liveLocked(token string) (*memMFAChallenge, bool) {
+	v, ok := s.pending[token]
+	if !ok || !v.expires.After(time.Now()) {
+		delete(s.pending, token)
+		return nil, false
+	}
+	return v, true
+}
+
+var _ ports.MFAChallengeStore = (*MemoryMFAChallengeStore)(nil)
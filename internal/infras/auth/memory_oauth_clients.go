@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+)
+
+// MemoryOAuthClientStore is an in-process OAuthClientStore for development and tests.
+type MemoryOAuthClientStore struct {
+	mu      sync.Mutex
+	clients map[string]ports.OAuthClient
+}
+
+func NewMemoryOAuthClientStore() *MemoryOAuthClientStore {
+	return &MemoryOAuthClientStore{clients: map[string]ports.OAuthClient{}}
+}
+
+func (s *MemoryOAuthClientStore) Create(_ context.Context, c ports.OAuthClient) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.clients[c.ClientID]; exists {
+		return errors.New("oauth client already exists")
+	}
+	s.clients[c.ClientID] = c
+	return nil
+}
+
+func (s *MemoryOAuthClientStore) Get(_ context.Context, clientID string) (ports.OAuthClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.clients[clientID]
+	if !ok {
+		return ports.OAuthClient{}, apperr.ErrOAuthClientNotFound
+	}
+	return c, nil
+}
+
+func (s *MemoryOAuthClientStore) List(_ context.Context) ([]ports.OAuthClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]ports.OAuthClient, 0, len(s.clients))
+	for _, c := range s.clients {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (s *MemoryOAuthClientStore) Delete(_ context.Context, clientID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.clients[clientID]; !ok {
+		return apperr.ErrOAuthClientNotFound
+	}
+	delete(s.clients, clientID)
+	return nil
+}
+
+var _ ports.OAuthClientStore = (*MemoryOAuthClientStore)(nil)
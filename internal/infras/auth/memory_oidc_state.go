@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+)
+
+// MemoryOIDCStateStore is an in-process OIDCStateStore.
+// It is per-instance: the callback must reach the replica that served /start, so use Redis behind a load balancer.
+type MemoryOIDCStateStore struct {
+	mu      sync.Mutex
+	pending map[string]memOIDCState
+}
+
+type memOIDCState struct {
+	data    ports.OIDCAuthState
+	expires time.Time
+}
+
+func NewMemoryOIDCStateStore() *MemoryOIDCStateStore {
+	return &MemoryOIDCStateStore{pending: map[string]memOIDCState{}}
+}
+
+func (s *MemoryOIDCStateStore) Save(_ context.Context, state string, data ports.OIDCAuthState, ttl time.Duration) error {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.pending {
+		if !v.expires.After(now) {
+			delete(s.pending, k)
+		}
+	}
+	s.pending[state] = memOIDCState{data: data, expires: now.Add(ttl)}
+	return nil
+}
+
+func (s *MemoryOIDCStateStore) Consume(_ context.Context, state string) (ports.OIDCAuthState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.pending[state]
+	delete(s.pending, state)
+	if !ok || !v.expires.After(time.Now()) {
+		return ports.OIDCAuthState{}, apperr.ErrOIDCStateInvalid
+	}
+	return v.data, nil
+}
+
+var _ ports.OIDCStateStore = (*MemoryOIDCStateStore)(nil)
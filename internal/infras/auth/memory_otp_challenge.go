@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+)
+
+// MemoryOTPChallengeStore is an in-process OTPChallengeStore.
+// It is per-instance: the code must be entered on the replica that sent it, so use Redis behind a load balancer.
+type MemoryOTPChallengeStore struct {
+	mu      sync.Mutex
+	pending map[string]*memOTPChallenge
+}
+
+type memOTPChallenge struct {
+	challenge ports.OTPChallenge
+	failures  int
+	expires   time.Time
+}
+
+func NewMemoryOTPChallengeStore() *MemoryOTPChallengeStore {
+	return &MemoryOTPChallengeStore{pending: map[string]*memOTPChallenge{}}
+}
+
+func (s *MemoryOTPChallengeStore) Save(_ context.Context, key string, c ports.OTPChallenge, ttl time.Duration) error {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.pending {
+		if !v.expires.After(now) {
+			delete(s.pending, k)
+		}
+	}
+	s.pending[key] = &memOTPChallenge{challenge: c, expires: now.Add(ttl)}
+	return nil
+}
+
+func (s *MemoryOTPChallengeStore) Get(_ context.Context, key string) (ports.OTPChallenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.liveLocked(key)
+	if !ok {
+		return ports.OTPChallenge{}, apperr.ErrOTPInvalid
+	}
+	return v.challenge, nil
+}
+
+func (s *MemoryOTPChallengeStore) RecordFailure(_ context.Context, key string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.liveLocked(key)
+	if !ok {
+		return 0, apperr.ErrOTPInvalid
+	}
+	v.failures++
+	return v.failures, nil
+}
+
+func (s *MemoryOTPChallengeStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, key)
+	return nil
+}
+
+func (s *MemoryOTPChallengeStore) liveLocked(key string) (*memOTPChallenge, bool) {
+	v, ok := s.pending[key]
+	if !ok || !v.expires.After(time.Now()) {
+		delete(s.pending, key)
+		return nil, false
+	}
+	return v, true
+}
+
+var _ ports.OTPChallengeStore = (*MemoryOTPChallengeStore)(nil)
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+
+	"github.com/google/uuid"
+)
+
+// MemoryPasswordResetStore is an in-process PasswordResetStore for development and tests.
+type MemoryPasswordResetStore struct {
+	mu     sync.Mutex
+	tokens map[string]memResetToken
+}
+
+type memResetToken struct {
+	userID  uuid.UUID
+	expires time.Time
+}
+
+func NewMemoryPasswordResetStore() *MemoryPasswordResetStore {
+	return &MemoryPasswordResetStore{tokens: map[string]memResetToken{}}
+}
+
+func (s *MemoryPasswordResetStore) Save(_ context.Context, tokenHash []byte, userID uuid.UUID, expiresAt time.Time) error {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.tokens {
+		if !v.expires.After(now) {
+			delete(s.tokens, k)
+		}
+	}
+	s.tokens[string(tokenHash)] = memResetToken{userID: userID, expires: expiresAt}
+	return nil
+}
+
+func (s *MemoryPasswordResetStore) Consume(_ context.Context, tokenHash []byte, now time.Time) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[string(tokenHash)]
+	if !ok {
+		return uuid.Nil, apperr.ErrResetTokenInvalid
+	}
+	delete(s.tokens, string(tokenHash))
+	if !t.expires.After(now) {
+		return uuid.Nil, apperr.ErrResetTokenInvalid
+	}
+	return t.userID, nil
+}
+
+func (s *MemoryPasswordResetStore) DeleteByUser(_ context.Context, userID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.tokens {
+		if v.userID == userID {
+			delete(s.tokens, k)
+		}
+	}
+	return nil
+}
+
+var _ ports.PasswordResetStore = (*MemoryPasswordResetStore)(nil)
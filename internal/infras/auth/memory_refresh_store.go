@@ -0,0 +1,205 @@
+package auth
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+	"gostartkit/pkg/logger"
+)
+
+// MemoryRefreshStore is an in-process RefreshTokenStore with the same family/session semantics as the Redis store.
+// It is per-instance and lost on restart: use it for local development and tests, not for multi-replica deployments.
+type MemoryRefreshStore struct {
+	mu       sync.Mutex
+	live     map[string]memRefreshToken // token -> owner
+	used     map[string]memRefreshToken // rotated token -> owner, kept until expiry for reuse detection
+	sessions map[string]*memSession     // family id -> session
+}
+
+type memRefreshToken struct {
+	userID  string
+	family  string
+	expires time.Time
+}
+
+type memSession struct {
+	ports.RefreshSession
+	tokens map[string]struct{} // live tokens of the family
+}
+
+func NewMemoryRefreshStore() *MemoryRefreshStore {
+	return &MemoryRefreshStore{
+		live:     map[string]memRefreshToken{},
+		used:     map[string]memRefreshToken{},
+		sessions: map[string]*memSession{},
+	}
+}
+
+func (s *MemoryRefreshStore) Issue(_ context.Context, userID string, ttlSeconds int, meta ports.SessionMeta) (string, error) {
+	token, err := secureRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	family, err := secureRandomToken(16)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	exp := now.Add(time.Duration(ttlSeconds) * time.Second)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeLocked(now)
+	s.live[token] = memRefreshToken{userID: userID, family: family, expires: exp}
+	s.sessions[family] = &memSession{
+		RefreshSession: ports.RefreshSession{
+			ID:          family,
+			UserID:      userID,
+			CreatedAt:   now.UTC(),
+			LastUsedAt:  now.UTC(),
+			ExpiresAt:   exp.UTC(),
+			IP:          meta.IP,
+			UserAgent:   truncate(meta.UserAgent, maxUserAgentLen),
+			DeviceLabel: meta.DeviceLabel,
+		},
+		tokens: map[string]struct{}{token: {}},
+	}
+	return token, nil
+}
+
+func (s *MemoryRefreshStore) Rotate(_ context.Context, oldToken string, ttlSeconds int, meta ports.SessionMeta) (string, string, error) {
+	newToken, err := secureRandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	now := time.Now()
+	exp := now.Add(time.Duration(ttlSeconds) * time.Second)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeLocked(now)
+	rt, ok := s.live[oldToken]
+	if !ok {
+		if used, wasUsed := s.used[oldToken]; wasUsed {
+			s.revokeFamilyLocked(used.family)
+			logger.L().Warn("security_event", "event", "refresh_token_reuse", "user_id", used.userID, "family", used.family, "action", "family_revoked")
+			return "", "", apperr.ErrRefreshTokenReused
+		}
+		return "", "", apperr.ErrInvalidRefreshToken
+	}
+	delete(s.live, oldToken)
+	s.used[oldToken] = memRefreshToken{userID: rt.userID, family: rt.family, expires: exp}
+	s.live[newToken] = memRefreshToken{userID: rt.userID, family: rt.family, expires: exp}
+	if sess, ok := s.sessions[rt.family]; ok {
+		delete(sess.tokens, oldToken)
+		sess.tokens[newToken] = struct{}{}
+		sess.LastUsedAt = now.UTC()
+		sess.ExpiresAt = exp.UTC()
+		sess.IP = meta.IP
+		sess.UserAgent = truncate(meta.UserAgent, maxUserAgentLen)
+	}
+	return newToken, rt.userID, nil
+}
+
+func (s *MemoryRefreshStore) Revoke(_ context.Context, token string) error {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeLocked(now)
+	rt, ok := s.live[token]
+	if !ok {
+		return apperr.ErrInvalidRefreshToken
+	}
+	delete(s.live, token)
+	if sess, ok := s.sessions[rt.family]; ok {
+		delete(sess.tokens, token)
+		if len(sess.tokens) == 0 {
+			delete(s.sessions, rt.family)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryRefreshStore) Validate(_ context.Context, token string) (string, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rt, ok := s.live[token]
+	if !ok || !now.Before(rt.expires) {
+		return "", apperr.ErrInvalidRefreshToken
+	}
+	return rt.userID, nil
+}
+
+func (s *MemoryRefreshStore) ListByUser(_ context.Context, userID string) ([]ports.RefreshSession, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeLocked(now)
+	out := []ports.RefreshSession{}
+	for _, sess := range s.sessions {
+		if sess.UserID == userID {
+			out = append(out, sess.RefreshSession)
+		}
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].LastUsedAt.After(out[b].LastUsedAt) })
+	return out, nil
+}
+
+func (s *MemoryRefreshStore) RevokeSession(_ context.Context, userID, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[sessionID]
+	if !ok || sess.UserID != userID {
+		return apperr.ErrSessionNotFound
+	}
+	s.revokeFamilyLocked(sessionID)
+	return nil
+}
+
+func (s *MemoryRefreshStore) RevokeAll(_ context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for fam, sess := range s.sessions {
+		if sess.UserID == userID {
+			s.revokeFamilyLocked(fam)
+		}
+	}
+	return nil
+}
+
+// revokeFamilyLocked deletes every live token of the family and its session.
+func (s *MemoryRefreshStore) revokeFamilyLocked(family string) {
+	if sess, ok := s.sessions[family]; ok {
+		for t := range sess.tokens {
+			delete(s.live, t)
+		}
+		delete(s.sessions, family)
+	}
+}
+
+// purgeLocked drops expired tokens and sessions (the equivalent of Redis key TTLs).
+func (s *MemoryRefreshStore) purgeLocked(now time.Time) {
+	for t, rt := range s.live {
+		if !now.Before(rt.expires) {
+			delete(s.live, t)
+			if sess, ok := s.sessions[rt.family]; ok {
+				delete(sess.tokens, t)
+			}
+		}
+	}
+	for t, rt := range s.used {
+		if !now.Before(rt.expires) {
+			delete(s.used, t)
+		}
+	}
+	for fam, sess := range s.sessions {
+		if !now.Before(sess.ExpiresAt) || len(sess.tokens) == 0 {
+			delete(s.sessions, fam)
+		}
+	}
+}
+
+var _ ports.RefreshTokenStore = (*MemoryRefreshStore)(nil)
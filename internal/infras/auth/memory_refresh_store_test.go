@@ -0,0 +1,16 @@
+package auth
+
+import (
+	"testing"
+
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/application/ports/portstest"
+
+	"github.com/google/uuid"
+)
+
+func TestMemoryRefreshStore_Conformance(t *testing.T) {
+	portstest.TestRefreshTokenStore(t, func(t *testing.T) (ports.RefreshTokenStore, func() string) {
+		return NewMemoryRefreshStore(), uuid.NewString
+	})
+}
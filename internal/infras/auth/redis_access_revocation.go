@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"gostartkit/internal/application/ports"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisAccessRevocationStore implements AccessTokenRevocationStore using Redis. IsRevoked returns Redis errors;
+// whether the request then fails open or closed is the caller's choice (AUTH_REVOCATION_FAIL_CLOSED).
+// Keys:
+//   - revoked_jti:<jti> => 1 (TTL=remaining token lifetime)
+//   - revoked_user:<userID> => unix cutoff; tokens with iat <= cutoff are revoked (TTL=max token lifetime)
+type RedisAccessRevocationStore struct{ client *redis.Client }
+
+func NewRedisAccessRevocationStore(addr, password string, db int) *RedisAccessRevocationStore {
+	return &RedisAccessRevocationStore{client: redis.NewClient(&redis.Options{Addr: addr, Password: password, DB: db})}
+}
+
+func (s *RedisAccessRevocationStore) RevokeJTI(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil // already expired; nothing to deny
+	}
+	return s.client.Set(ctx, revokedJTIKey(jti), 1, ttl).Err()
+}
+
+func (s *RedisAccessRevocationStore) RevokeUser(ctx context.Context, userID string, before time.Time, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return s.client.Set(ctx, revokedUserKey(userID), before.Unix(), ttl).Err()
+}
+
+func (s *RedisAccessRevocationStore) IsRevoked(ctx context.Context, jti, userID string, issuedAt time.Time) (bool, error) {
+	pipe := s.client.Pipeline()
+	var jtiCmd *redis.IntCmd
+	if jti != "" {
+		jtiCmd = pipe.Exists(ctx, revokedJTIKey(jti))
+	}
+	userCmd := pipe.Get(ctx, revokedUserKey(userID))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return false, err
+	}
+	if jtiCmd != nil && jtiCmd.Val() > 0 {
+		return true, nil
+	}
+	if v, err := userCmd.Result(); err == nil {
+		cutoff, perr := strconv.ParseInt(v, 10, 64)
+		if perr == nil && issuedAt.Unix() < cutoff {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func revokedJTIKey(jti string) string     { return "revoked_jti:" + jti }
+func revokedUserKey(userID string) string { return "revoked_user:" + userID }
+
+var _ ports.AccessTokenRevocationStore = (*RedisAccessRevocationStore)(nil)
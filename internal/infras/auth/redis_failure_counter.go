@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gostartkit/internal/application/ports"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisFailureCounter implements FailureCounter using Redis.
+// Keys:
+//   - failures:<key> => n (TTL=window, set by the first failure)
+type RedisFailureCounter struct{ client *redis.Client }
+
+func NewRedisFailureCounter(addr, password string, db int) *RedisFailureCounter {
+	return &RedisFailureCounter{client: redis.NewClient(&redis.Options{Addr: addr, Password: password, DB: db})}
+}
+
+// addFailureScript sets the TTL together with the first increment, so a counter never outlives its window.
+var addFailureScript = redis.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+if n == 1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return n
+`)
+
+func (s *RedisFailureCounter) Count(ctx context.Context, key string) (int, error) {
+	n, err := s.client.Get(ctx, failureCounterKey(key)).Int()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return n, err
+}
+
+func (s *RedisFailureCounter) Add(ctx context.Context, key string, window time.Duration) (int, error) {
+	return addFailureScript.Run(ctx, s.client, []string{failureCounterKey(key)}, window.Milliseconds()).Int()
+}
+
+func (s *RedisFailureCounter) Reset(ctx context.Context, key string) error {
+	return s.client.Del(ctx, failureCounterKey(key)).Err()
+}
+
+func failureCounterKey(key string) string { return "failures:" + key }
+
+var _ ports.FailureCounter = (*RedisFailureCounter)(nil)
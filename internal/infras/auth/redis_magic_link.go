@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisMagicLinkStore implements MagicLinkStore using Redis.
+// Keys:
+//   - magic_link:<token hash> => JSON MagicLink (TTL=link lifetime); removed with GETDEL so each link is used once
+type RedisMagicLinkStore struct{ client *redis.Client }
+
+func NewRedisMagicLinkStore(addr, password string, db int) *RedisMagicLinkStore {
+	return &RedisMagicLinkStore{client: redis.NewClient(&redis.Options{Addr: addr, Password: password, DB: db})}
+}
+
+func (s *RedisMagicLinkStore) Save(ctx context.Context, tokenHash string, link ports.MagicLink, ttl time.Duration) error {
+	b, err := json.Marshal(link)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, magicLinkKey(tokenHash), b, ttl).Err()
+}
+
+func (s *RedisMagicLinkStore) Consume(ctx context.Context, tokenHash string) (ports.MagicLink, error) {
+	b, err := s.client.GetDel(ctx, magicLinkKey(tokenHash)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return ports.MagicLink{}, apperr.ErrMagicLinkInvalid
+	}
+	if err != nil {
+		return ports.MagicLink{}, err
+	}
+	var link ports.MagicLink
+	if err := json.Unmarshal(b, &link); err != nil {
+		return ports.MagicLink{}, apperr.ErrMagicLinkInvalid
+	}
+	return link, nil
+}
+
+func magicLinkKey(tokenHash string) string { return "magic_link:" + tokenHash }
+
+var _ ports.MagicLinkStore = (*RedisMagicLinkStore)(nil)
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisMFAChallengeStore implements MFAChallengeStore using Redis.
+// Keys:
+//   - mfa_challenge:<token> => hash{data: JSON MFAChallenge, failures: n} (TTL=challenge lifetime)
+type RedisMFAChallengeStore struct{ client *redis.Client }
+
+func NewRedisMFAChallengeStore(addr, password string, db int) *RedisMFAChallengeStore {
+	return &RedisMFAChallengeStore{client: redis.NewClient(&redis.Options{Addr: addr, Password: password, DB: db})}
+}
+
+// recordFailureScript only counts failures for live challenges, so an expired key is never recreated without TTL.
+var recordFailureScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+  return -1
+end
+return redis.call('HINCRBY', KEYS[1], 'failures', 1)
+`)
+
+func (s *RedisMFAChallengeStore) Save(ctx context.Context, token string, c ports.MFAChallenge, ttl time.Duration) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	key := mfaChallengeKey(token)
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key, "data", b, "failures", 0)
+	pipe.Expire(ctx, key, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (s *RedisMFAChallengeStore) Get(ctx context.Context, token string) (ports.MFAChallenge, error) {
+	b, err := s.client.HGet(ctx, mfaChallengeKey(token), "data").Bytes()
+	if errors.Is(err, redis.Nil) {
+		return ports.MFAChallenge{}, apperr.ErrMFATokenInvalid
+	}
+	if err != nil {
+		return ports.MFAChallenge{}, err
+	}
+	var c ports.MFAChallenge
+	if err := json.Unmarshal(b, &c); err != nil {
+		return ports.MFAChallenge{}, apperr.ErrMFATokenInvalid
+	}
+	return c, nil
+}
+
+func (s *RedisMFAChallengeStore) RecordFailure(ctx context.Context, token string) (int, error) {
+	n, err := recordFailureScript.Run(ctx, s.client, []string{mfaChallengeKey(token)}).Int()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, apperr.ErrMFATokenInvalid
+	}
+	return n, nil
+}
+
+func (s *RedisMFAChallengeStore) Delete(ctx context.Context, token string) error {
+	return s.client.Del(ctx, mfaChallengeKey(token)).Err()
+}
+
+func mfaChallengeKey(token string) string { return "mfa_challenge:" + token }
+
+var _ ports.MFAChallengeStore = (*RedisMFAChallengeStore)(nil)
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisOIDCStateStore implements OIDCStateStore using Redis.
+// Keys:
+//   - oidc_state:<state> => JSON OIDCAuthState (TTL=state lifetime); removed with GETDEL so each state is used once
+type RedisOIDCStateStore struct{ client *redis.Client }
+
+func NewRedisOIDCStateStore(addr, password string, db int) *RedisOIDCStateStore {
+	return &RedisOIDCStateStore{client: redis.NewClient(&redis.Options{Addr: addr, Password: password, DB: db})}
+}
+
+func (s *RedisOIDCStateStore) Save(ctx context.Context, state string, data ports.OIDCAuthState, ttl time.Duration) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, oidcStateKey(state), b, ttl).Err()
+}
+
+func (s *RedisOIDCStateStore) Consume(ctx context.Context, state string) (ports.OIDCAuthState, error) {
+	b, err := s.client.GetDel(ctx, oidcStateKey(state)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return ports.OIDCAuthState{}, apperr.ErrOIDCStateInvalid
+	}
+	if err != nil {
+		return ports.OIDCAuthState{}, err
+	}
+	var data ports.OIDCAuthState
+	if err := json.Unmarshal(b, &data); err != nil {
+		return ports.OIDCAuthState{}, apperr.ErrOIDCStateInvalid
+	}
+	return data, nil
+}
+
+func oidcStateKey(state string) string { return "oidc_state:" + state }
+
+var _ ports.OIDCStateStore = (*RedisOIDCStateStore)(nil)
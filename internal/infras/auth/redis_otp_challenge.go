@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisOTPChallengeStore implements OTPChallengeStore using Redis.
+// Keys:
+//   - otp:<purpose>:<subject> => hash{data: JSON OTPChallenge, failures: n} (TTL=code lifetime)
+type RedisOTPChallengeStore struct{ client *redis.Client }
+
+func NewRedisOTPChallengeStore(addr, password string, db int) *RedisOTPChallengeStore {
+	return &RedisOTPChallengeStore{client: redis.NewClient(&redis.Options{Addr: addr, Password: password, DB: db})}
+}
+
+func (s *RedisOTPChallengeStore) Save(ctx context.Context, key string, c ports.OTPChallenge, ttl time.Duration) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	rkey := otpChallengeKey(key)
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, rkey, "data", b, "failures", 0)
+	pipe.Expire(ctx, rkey, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (s *RedisOTPChallengeStore) Get(ctx context.Context, key string) (ports.OTPChallenge, error) {
+	b, err := s.client.HGet(ctx, otpChallengeKey(key), "data").Bytes()
+	if errors.Is(err, redis.Nil) {
+		return ports.OTPChallenge{}, apperr.ErrOTPInvalid
+	}
+	if err != nil {
+		return ports.OTPChallenge{}, err
+	}
+	var c ports.OTPChallenge
+	if err := json.Unmarshal(b, &c); err != nil {
+		return ports.OTPChallenge{}, apperr.ErrOTPInvalid
+	}
+	return c, nil
+}
+
+func (s *RedisOTPChallengeStore) RecordFailure(ctx context.Context, key string) (int, error) {
+	// recordFailureScript (see the MFA challenge store) never recreates an expired key without TTL
+	n, err := recordFailureScript.Run(ctx, s.client, []string{otpChallengeKey(key)}).Int()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, apperr.ErrOTPInvalid
+	}
+	return n, nil
+}
+
+func (s *RedisOTPChallengeStore) Delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, otpChallengeKey(key)).Err()
+}
+
+func otpChallengeKey(key string) string { return "otp:" + key }
+
+var _ ports.OTPChallengeStore = (*RedisOTPChallengeStore)(nil)
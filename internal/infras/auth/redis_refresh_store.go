@@ -4,69 +4,211 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"gostartkit/internal/application/apperr"
 	"gostartkit/internal/application/ports"
+	"gostartkit/pkg/logger"
 
 	"github.com/redis/go-redis/v9"
 )
 
 // RedisRefreshStore implements RefreshTokenStore using Redis.
+// Every login starts a token family (= session); rotation keeps the family and marks the old token as used.
 // Keys:
-//   - refresh:<token> => userID (TTL=token TTL)
+//   - refresh:<token> => hash {uid, fam} (TTL=token TTL)
 //   - refresh_user:<userID>:<token> => 1 (TTL=token TTL) to support revocation per user if needed
+//   - refresh_family:<fam> => set of live tokens in the family (TTL=token TTL)
+//   - refresh_used:<token> => hash {uid, fam} for rotated tokens (TTL=token TTL), used for reuse detection
+//   - refresh_session:<fam> => hash {uid, created_at, last_used_at, expires_at, ip, ua, device} (TTL=token TTL)
+//   - refresh_user_sessions:<userID> => set of family ids (TTL=longest session TTL)
+//
+// Scripts touch keys derived from their arguments, so they assume a single Redis node (not Cluster).
 type RedisRefreshStore struct{ client *redis.Client }
 
 func NewRedisRefreshStore(addr, password string, db int) *RedisRefreshStore {
 	return &RedisRefreshStore{client: redis.NewClient(&redis.Options{Addr: addr, Password: password, DB: db})}
 }
 
-func (s *RedisRefreshStore) Issue(ctx context.Context, userID string, ttlSeconds int) (string, error) {
+// maxUserAgentLen bounds stored User-Agent strings.
+const maxUserAgentLen = 256
+
+func (s *RedisRefreshStore) Issue(ctx context.Context, userID string, ttlSeconds int, meta ports.SessionMeta) (string, error) {
 	token, err := secureRandomToken(32)
 	if err != nil {
 		return "", err
 	}
+	family, err := secureRandomToken(16)
+	if err != nil {
+		return "", err
+	}
 	ttl := time.Duration(ttlSeconds) * time.Second
+	now := time.Now()
 	pipe := s.client.TxPipeline()
-	pipe.Set(ctx, refreshKey(token), userID, ttl)
+	pipe.HSet(ctx, refreshKey(token), "uid", userID, "fam", family)
+	pipe.Expire(ctx, refreshKey(token), ttl)
 	pipe.Set(ctx, userTokenKey(userID, token), 1, ttl)
+	pipe.SAdd(ctx, familyKey(family), token)
+	pipe.Expire(ctx, familyKey(family), ttl)
+	pipe.HSet(ctx, sessionKey(family),
+		"uid", userID,
+		"created_at", now.Unix(),
+		"last_used_at", now.Unix(),
+		"expires_at", now.Add(ttl).Unix(),
+		"ip", meta.IP,
+		"ua", truncate(meta.UserAgent, maxUserAgentLen),
+		"device", meta.DeviceLabel,
+	)
+	pipe.Expire(ctx, sessionKey(family), ttl)
+	pipe.SAdd(ctx, userSessionsKey(userID), family)
+	pipe.Expire(ctx, userSessionsKey(userID), ttl)
 	_, err = pipe.Exec(ctx)
 	return token, err
 }
 
-func (s *RedisRefreshStore) Rotate(ctx context.Context, oldToken string, ttlSeconds int) (string, string, error) {
-	userID, err := s.Validate(ctx, oldToken)
+// luaRevokeFamily is shared by scripts: deletes every live token, the family set and the session of fam.
+const luaRevokeFamily = `
+local function revoke_family(uid, fam)
+  local fk = 'refresh_family:' .. fam
+  for _, t in ipairs(redis.call('SMEMBERS', fk)) do
+    redis.call('DEL', 'refresh:' .. t, 'refresh_user:' .. uid .. ':' .. t)
+  end
+  redis.call('DEL', fk, 'refresh_session:' .. fam)
+  redis.call('SREM', 'refresh_user_sessions:' .. uid, fam)
+end
+`
+
+// rotateScript atomically validates the old token, marks it used and issues the new one in the same family.
+// A token that was already rotated triggers revocation of every live token in its family.
+// ARGV: oldToken, newToken, ttlSeconds, familyForLegacyToken, now, ip, ua
+// Returns {"ok", uid, fam} | {"reuse", uid, fam} | {"invalid"}
+var rotateScript = redis.NewScript(luaRevokeFamily + `
+local old, new, ttl, now = ARGV[1], ARGV[2], tonumber(ARGV[3]), tonumber(ARGV[5])
+local rk = 'refresh:' .. old
+local kind = redis.call('TYPE', rk).ok
+local uid, fam
+if kind == 'hash' then
+  uid = redis.call('HGET', rk, 'uid')
+  fam = redis.call('HGET', rk, 'fam')
+elseif kind == 'string' then
+  -- token issued before families existed: adopt it into a new family/session
+  uid = redis.call('GET', rk)
+  fam = ARGV[4]
+  redis.call('HSET', 'refresh_session:' .. fam, 'uid', uid, 'created_at', now)
+else
+  local used = 'refresh_used:' .. old
+  if redis.call('EXISTS', used) == 1 then
+    uid = redis.call('HGET', used, 'uid')
+    fam = redis.call('HGET', used, 'fam')
+    revoke_family(uid, fam)
+    return {'reuse', uid, fam}
+  end
+  return {'invalid'}
+end
+if not uid or uid == '' then
+  return {'invalid'}
+end
+local fk = 'refresh_family:' .. fam
+local sk = 'refresh_session:' .. fam
+local usk = 'refresh_user_sessions:' .. uid
+redis.call('DEL', rk, 'refresh_user:' .. uid .. ':' .. old)
+redis.call('SREM', fk, old)
+redis.call('HSET', 'refresh_used:' .. old, 'uid', uid, 'fam', fam)
+redis.call('EXPIRE', 'refresh_used:' .. old, ttl)
+redis.call('HSET', 'refresh:' .. new, 'uid', uid, 'fam', fam)
+redis.call('EXPIRE', 'refresh:' .. new, ttl)
+redis.call('SET', 'refresh_user:' .. uid .. ':' .. new, 1, 'EX', ttl)
+redis.call('SADD', fk, new)
+redis.call('EXPIRE', fk, ttl)
+redis.call('HSET', sk, 'last_used_at', now, 'expires_at', now + ttl, 'ip', ARGV[6], 'ua', ARGV[7])
+redis.call('EXPIRE', sk, ttl)
+redis.call('SADD', usk, fam)
+redis.call('EXPIRE', usk, ttl)
+return {'ok', uid, fam}
+`)
+
+func (s *RedisRefreshStore) Rotate(ctx context.Context, oldToken string, ttlSeconds int, meta ports.SessionMeta) (string, string, error) {
+	newTok, err := secureRandomToken(32)
 	if err != nil {
 		return "", "", err
 	}
-	if err := s.Revoke(ctx, oldToken); err != nil {
+	legacyFamily, err := secureRandomToken(16)
+	if err != nil {
 		return "", "", err
 	}
-	newTok, err := s.Issue(ctx, userID, ttlSeconds)
+	res, err := rotateScript.Run(ctx, s.client, nil,
+		oldToken, newTok, ttlSeconds, legacyFamily, time.Now().Unix(), meta.IP, truncate(meta.UserAgent, maxUserAgentLen),
+	).StringSlice()
 	if err != nil {
 		return "", "", err
 	}
-	return newTok, userID, nil
+	switch {
+	case len(res) == 3 && res[0] == "ok":
+		return newTok, res[1], nil
+	case len(res) == 3 && res[0] == "reuse":
+		logger.L().Warn("security_event", "event", "refresh_token_reuse", "user_id", res[1], "family", res[2], "action", "family_revoked")
+		return "", "", apperr.ErrRefreshTokenReused
+	case len(res) >= 1 && res[0] == "invalid":
+		return "", "", apperr.ErrInvalidRefreshToken
+	default:
+		return "", "", fmt.Errorf("unexpected rotate result: %v", res)
+	}
 }
 
+// revokeScript deletes a live token; the session ends when its family has no live token left.
+// ARGV: token. Returns 1 if revoked, 0 if unknown.
+var revokeScript = redis.NewScript(`
+local rk = 'refresh:' .. ARGV[1]
+local kind = redis.call('TYPE', rk).ok
+local uid, fam
+if kind == 'hash' then
+  uid = redis.call('HGET', rk, 'uid')
+  fam = redis.call('HGET', rk, 'fam')
+elseif kind == 'string' then
+  uid = redis.call('GET', rk)
+else
+  return 0
+end
+redis.call('DEL', rk, 'refresh_user:' .. uid .. ':' .. ARGV[1])
+if fam then
+  local fk = 'refresh_family:' .. fam
+  redis.call('SREM', fk, ARGV[1])
+  if redis.call('SCARD', fk) == 0 then
+    redis.call('DEL', fk, 'refresh_session:' .. fam)
+    redis.call('SREM', 'refresh_user_sessions:' .. uid, fam)
+  end
+end
+return 1
+`)
+
 func (s *RedisRefreshStore) Revoke(ctx context.Context, token string) error {
-	uid, err := s.client.Get(ctx, refreshKey(token)).Result()
+	n, err := revokeScript.Run(ctx, s.client, nil, token).Int()
 	if err != nil {
-		if err == redis.Nil {
-			return apperr.ErrInvalidRefreshToken
-		}
 		return err
 	}
-	pipe := s.client.TxPipeline()
-	pipe.Del(ctx, refreshKey(token))
-	pipe.Del(ctx, userTokenKey(uid, token))
-	_, err = pipe.Exec(ctx)
-	return err
+	if n == 0 {
+		return apperr.ErrInvalidRefreshToken
+	}
+	return nil
 }
 
 func (s *RedisRefreshStore) Validate(ctx context.Context, token string) (string, error) {
-	uid, err := s.client.Get(ctx, refreshKey(token)).Result()
+	kind, err := s.client.Type(ctx, refreshKey(token)).Result()
+	if err != nil {
+		return "", err
+	}
+	var uid string
+	switch kind {
+	case "hash":
+		uid, err = s.client.HGet(ctx, refreshKey(token), "uid").Result()
+	case "string": // issued before token families
+		uid, err = s.client.Get(ctx, refreshKey(token)).Result()
+	default:
+		return "", apperr.ErrInvalidRefreshToken
+	}
 	if err != nil {
 		if err == redis.Nil {
 			return "", apperr.ErrInvalidRefreshToken
@@ -79,8 +221,93 @@ func (s *RedisRefreshStore) Validate(ctx context.Context, token string) (string,
 	return uid, nil
 }
 
+func (s *RedisRefreshStore) ListByUser(ctx context.Context, userID string) ([]ports.RefreshSession, error) {
+	fams, err := s.client.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.RefreshSession, 0, len(fams))
+	for _, fam := range fams {
+		m, err := s.client.HGetAll(ctx, sessionKey(fam)).Result()
+		if err != nil {
+			return nil, err
+		}
+		if len(m) == 0 || m["uid"] != userID {
+			// expired session: drop the dangling index entry
+			_ = s.client.SRem(ctx, userSessionsKey(userID), fam).Err()
+			continue
+		}
+		out = append(out, ports.RefreshSession{
+			ID:          fam,
+			UserID:      userID,
+			CreatedAt:   unixField(m["created_at"]),
+			LastUsedAt:  unixField(m["last_used_at"]),
+			ExpiresAt:   unixField(m["expires_at"]),
+			IP:          m["ip"],
+			UserAgent:   m["ua"],
+			DeviceLabel: m["device"],
+		})
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].LastUsedAt.After(out[b].LastUsedAt) })
+	return out, nil
+}
+
+// revokeSessionScript ends one session if it belongs to the user. ARGV: uid, fam. Returns 1 or 0.
+var revokeSessionScript = redis.NewScript(luaRevokeFamily + `
+local uid, fam = ARGV[1], ARGV[2]
+if redis.call('HGET', 'refresh_session:' .. fam, 'uid') ~= uid then
+  return 0
+end
+revoke_family(uid, fam)
+return 1
+`)
+
+func (s *RedisRefreshStore) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	n, err := revokeSessionScript.Run(ctx, s.client, nil, userID, sessionID).Int()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperr.ErrSessionNotFound
+	}
+	return nil
+}
+
+// revokeAllScript ends every session of a user. ARGV: uid. Returns the number of sessions ended.
+var revokeAllScript = redis.NewScript(luaRevokeFamily + `
+local uid = ARGV[1]
+local fams = redis.call('SMEMBERS', 'refresh_user_sessions:' .. uid)
+for _, fam in ipairs(fams) do
+  revoke_family(uid, fam)
+end
+redis.call('DEL', 'refresh_user_sessions:' .. uid)
+return #fams
+`)
+
+func (s *RedisRefreshStore) RevokeAll(ctx context.Context, userID string) error {
+	return revokeAllScript.Run(ctx, s.client, nil, userID).Err()
+}
+
 func refreshKey(token string) string           { return "refresh:" + token }
 func userTokenKey(userID, token string) string { return "refresh_user:" + userID + ":" + token }
+func familyKey(family string) string           { return "refresh_family:" + family }
+func sessionKey(family string) string          { return "refresh_session:" + family }
+func userSessionsKey(userID string) string     { return "refresh_user_sessions:" + userID }
+
+func unixField(v string) time.Time {
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(n, 0).UTC()
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
 
 func secureRandomToken(numBytes int) (string, error) {
 	b := make([]byte, numBytes)
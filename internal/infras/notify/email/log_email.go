@@ -0,0 +1,18 @@
+package email
+
+import (
+	"context"
+
+	"gostartkit/pkg/logger"
+)
+
+// LogSender writes emails to the application log instead of sending them. For local development only:
+// bodies may contain sign-in or verification links.
+type LogSender struct{}
+
+func NewLogSender() LogSender { return LogSender{} }
+
+func (LogSender) Send(ctx context.Context, to, subject, body string) error {
+	logger.L().Info("email_logged", "to", to, "subject", subject, "body", body)
+	return nil
+}
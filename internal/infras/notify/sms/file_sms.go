@@ -0,0 +1,40 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileSender appends text messages to a file, one per line as "<RFC3339 time>\t<to>\t<message>".
+// It lets tests and local setups read one-time codes without an SMS provider.
+type FileSender struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileSender(path string) *FileSender { return &FileSender{path: path} }
+
+func (f *FileSender) Send(ctx context.Context, to, message string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if dir := filepath.Dir(f.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return err
+		}
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	line := fmt.Sprintf("%s\t%s\t%s\n", time.Now().UTC().Format(time.RFC3339), to, strings.ReplaceAll(message, "\n", " "))
+	if _, err := file.WriteString(line); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,30 @@
+package sms
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileSender_AppendsOneLinePerMessage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "sms.log")
+	s := NewFileSender(path)
+	for _, msg := range []string{"code 123456", "line one\nline two"} {
+		if err := s.Send(context.Background(), "+14155552671", msg); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", b)
+	}
+	if !strings.HasSuffix(lines[0], "\t+14155552671\tcode 123456") || !strings.HasSuffix(lines[1], "\tline one line two") {
+		t.Fatalf("unexpected content: %q", b)
+	}
+}
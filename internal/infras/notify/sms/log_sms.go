@@ -0,0 +1,18 @@
+package sms
+
+import (
+	"context"
+
+	"gostartkit/pkg/logger"
+)
+
+// LogSender writes text messages to the application log instead of sending them. For local development only:
+// messages contain one-time codes.
+type LogSender struct{}
+
+func NewLogSender() LogSender { return LogSender{} }
+
+func (LogSender) Send(ctx context.Context, to, message string) error {
+	logger.L().Info("sms_logged", "to", to, "message", message)
+	return nil
+}
@@ -0,0 +1,91 @@
+package oidc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProviderConfig describes one OpenID Connect identity provider.
+//
+// Providers are listed in a YAML file (OIDC_PROVIDERS_FILE). Values are expanded with
+// environment variables, so secrets can stay out of the file:
+//
+//	providers:
+//	  - name: google
+//	    issuer: https://accounts.google.com
+//	    client_id: ${GOOGLE_CLIENT_ID}
+//	    client_secret: ${GOOGLE_CLIENT_SECRET}
+//	    scopes: [openid, email, profile]
+//	  - name: corp
+//	    issuer: https://sso.example.com
+//	    client_id: api
+//	    client_secret: ${CORP_CLIENT_SECRET}
+//	    redirect_url: https://api.example.com/v1/auth/oidc/corp/callback
+//	    disable_signup: true
+type ProviderConfig struct {
+	Name         string   `yaml:"name"`
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	RedirectURL  string   `yaml:"redirect_url"`
+	Scopes       []string `yaml:"scopes"`
+	// DisableSignup stops unknown users from being created just in time; existing users can still link by verified email.
+	DisableSignup bool `yaml:"disable_signup"`
+}
+
+type providersFile struct {
+	Providers []ProviderConfig `yaml:"providers"`
+}
+
+// LoadProviders reads the providers file and builds a Provider for each entry.
+// redirectBase (e.g. https://api.example.com) is used for entries without redirect_url.
+// Discovery is deferred to first use, so an unreachable provider does not block startup.
+func LoadProviders(path, redirectBase string, client *http.Client) ([]*Provider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read oidc providers: %w", err)
+	}
+	var f providersFile
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &f); err != nil {
+		return nil, fmt.Errorf("parse oidc providers: %w", err)
+	}
+	seen := map[string]bool{}
+	out := make([]*Provider, 0, len(f.Providers))
+	for _, pc := range f.Providers {
+		pc.Name = strings.TrimSpace(pc.Name)
+		if seen[pc.Name] {
+			return nil, fmt.Errorf("oidc providers: duplicate name %q", pc.Name)
+		}
+		seen[pc.Name] = true
+		if pc.RedirectURL == "" && redirectBase != "" {
+			pc.RedirectURL = strings.TrimRight(redirectBase, "/") + "/v1/auth/oidc/" + pc.Name + "/callback"
+		}
+		p, err := NewProvider(pc, client)
+		if err != nil {
+			return nil, fmt.Errorf("oidc providers: %q: %w", pc.Name, err)
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func (c ProviderConfig) validate() error {
+	switch {
+	case c.Name == "":
+		return errors.New("name required")
+	case strings.ContainsAny(c.Name, "/?#% "):
+		return errors.New("name must be URL path safe")
+	case c.Issuer == "":
+		return errors.New("issuer required")
+	case c.ClientID == "":
+		return errors.New("client_id required")
+	case c.RedirectURL == "":
+		return errors.New("redirect_url required (or set OIDC_REDIRECT_BASE_URL)")
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+// Package oidctest provides a minimal in-process OpenID Connect provider for tests and local development.
+//
+// It serves discovery, JWKS, an authorize endpoint that approves immediately for the configured user,
+// and a token endpoint that enforces the PKCE verifier and client secret.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const kid = "stub-key"
+
+// User is the identity the stub signs in.
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+}
+
+// IdP is a running stub provider. Change User, Audience or Nonce between requests to shape the next ID token.
+type IdP struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+	User         User
+	// Audience overrides the aud claim (defaults to ClientID)
+	Audience string
+	// Nonce overrides the nonce claim (defaults to the one sent to /authorize)
+	Nonce string
+	// JWKSDelay slows down the JWKS endpoint, e.g. to observe concurrent fetches
+	JWKSDelay time.Duration
+
+	key          *rsa.PrivateKey
+	mu           sync.Mutex
+	codes        map[string]pendingCode
+	jwksRequests int
+}
+
+type pendingCode struct {
+	nonce       string
+	challenge   string
+	redirectURI string
+}
+
+// New starts a stub provider; call Close when done.
+func New(clientID, clientSecret string) *IdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	idp := &IdP{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		User:         User{Subject: "stub-user", Email: "stub.user@example.com", EmailVerified: true, GivenName: "Stub", FamilyName: "User"},
+		key:          key,
+		codes:        map[string]pendingCode{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/jwks", idp.jwks)
+	mux.HandleFunc("/authorize", idp.authorize)
+	mux.HandleFunc("/token", idp.token)
+	idp.Server = httptest.NewServer(mux)
+	return idp
+}
+
+// Issuer is the issuer URL to configure on the relying party.
+func (p *IdP) Issuer() string { return p.URL }
+
+func (p *IdP) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                p.URL,
+		"authorization_endpoint":                p.URL + "/authorize",
+		"token_endpoint":                        p.URL + "/token",
+		"jwks_uri":                              p.URL + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+// JWKSRequests reports how often the JWKS endpoint was called.
+func (p *IdP) JWKSRequests() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.jwksRequests
+}
+
+func (p *IdP) jwks(w http.ResponseWriter, _ *http.Request) {
+	p.mu.Lock()
+	p.jwksRequests++
+	p.mu.Unlock()
+	time.Sleep(p.JWKSDelay)
+	pub := p.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": kid,
+		"alg": "RS256",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}})
+}
+
+// authorize approves immediately and redirects back with a code, as a user clicking "allow" would.
+func (p *IdP) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != p.ClientID || q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+	code := randomString()
+	p.mu.Lock()
+	p.codes[code] = pendingCode{nonce: q.Get("nonce"), challenge: q.Get("code_challenge"), redirectURI: q.Get("redirect_uri")}
+	p.mu.Unlock()
+	target, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+	tq := target.Query()
+	tq.Set("code", code)
+	tq.Set("state", q.Get("state"))
+	target.RawQuery = tq.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func (p *IdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+	id, secret, _ := r.BasicAuth()
+	id, _ = url.QueryUnescape(id)
+	secret, _ = url.QueryUnescape(secret)
+	if id != p.ClientID || secret != p.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	pending, ok := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || pending.redirectURI != r.PostForm.Get("redirect_uri") || base64.RawURLEncoding.EncodeToString(sum[:]) != pending.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	idToken, err := p.idToken(pending.nonce)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (p *IdP) idToken(nonce string) (string, error) {
+	aud, n := p.ClientID, nonce
+	if p.Audience != "" {
+		aud = p.Audience
+	}
+	if p.Nonce != "" {
+		n = p.Nonce
+	}
+	now := time.Now()
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            p.URL,
+		"sub":            p.User.Subject,
+		"aud":            aud,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          n,
+		"email":          p.User.Email,
+		"email_verified": p.User.EmailVerified,
+		"given_name":     p.User.GivenName,
+		"family_name":    p.User.FamilyName,
+	})
+	tok.Header["kid"] = kid
+	return tok.SignedString(p.key)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,308 @@
+// Package oidc implements ports.OIDCProvider for standard OpenID Connect identity providers:
+// discovery, authorization code flow with PKCE, and ID token validation against the provider JWKS.
+package oidc
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"gostartkit/internal/application/ports"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+)
+
+// idTokenAlgorithms are the signature algorithms accepted on ID tokens. "none" and HMAC are never accepted.
+var idTokenAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+const (
+	// clockSkew tolerated on exp/iat of ID tokens
+	clockSkew = time.Minute
+	// jwksRefreshInterval throttles JWKS refetches triggered by unknown kids
+	jwksRefreshInterval = 30 * time.Second
+	maxResponseBytes    = 1 << 20
+)
+
+// Provider talks to one OpenID Connect provider.
+type Provider struct {
+	cfg    ProviderConfig
+	client *http.Client
+	// fetches shares one in-flight discovery or JWKS request between concurrent callers; mu is never held across it
+	fetches singleflight.Group
+
+	mu        sync.Mutex
+	discovery *discoveryDoc
+	keys      map[string]any // kid -> public key
+	keysAt    time.Time
+}
+
+type discoveryDoc struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewProvider validates cfg. A nil client uses a client with a 10s timeout.
+func NewProvider(cfg ProviderConfig, client *http.Client) (*Provider, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{cfg: cfg, client: client}, nil
+}
+
+func (p *Provider) Name() string { return p.cfg.Name }
+
+func (p *Provider) AllowSignup() bool { return !p.cfg.DisableSignup }
+
+func (p *Provider) AuthCodeURL(state, nonce, codeChallenge string) (string, error) {
+	d, err := p.discover(context.Background())
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(d.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("authorization endpoint: %w", err)
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (ports.OIDCIdentity, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return ports.OIDCIdentity{}, err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	if p.cfg.ClientSecret == "" {
+		form.Set("client_id", p.cfg.ClientID) // public client
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return ports.OIDCIdentity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		// client_secret_basic: credentials are form-encoded before base64 (RFC 6749 §2.3.1)
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return ports.OIDCIdentity{}, fmt.Errorf("token request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return ports.OIDCIdentity{}, fmt.Errorf("token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal(body, &e)
+		return ports.OIDCIdentity{}, fmt.Errorf("token endpoint returned %d %s", resp.StatusCode, e.Error)
+	}
+	var tr struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return ports.OIDCIdentity{}, fmt.Errorf("token response: %w", err)
+	}
+	if tr.IDToken == "" {
+		return ports.OIDCIdentity{}, errors.New("token response without id_token")
+	}
+	return p.verifyIDToken(ctx, tr.IDToken, nonce)
+}
+
+// idTokenClaims are the ID token claims we rely on. email_verified is a string at some providers.
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified flexBool `json:"email_verified"`
+	GivenName     string   `json:"given_name"`
+	FamilyName    string   `json:"family_name"`
+}
+
+func (p *Provider) verifyIDToken(ctx context.Context, raw, nonce string) (ports.OIDCIdentity, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return ports.OIDCIdentity{}, err
+	}
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(idTokenAlgorithms),
+		jwt.WithIssuer(d.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	var claims idTokenClaims
+	if _, err := parser.ParseWithClaims(raw, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.verificationKey(ctx, kid)
+	}); err != nil {
+		return ports.OIDCIdentity{}, fmt.Errorf("id token: %w", err)
+	}
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return ports.OIDCIdentity{}, errors.New("id token: nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return ports.OIDCIdentity{}, errors.New("id token: missing sub")
+	}
+	return ports.OIDCIdentity{
+		Provider:      p.cfg.Name,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+	}, nil
+}
+
+// discover fetches and caches the provider metadata. The advertised issuer must match the configured one.
+func (p *Provider) discover(ctx context.Context) (*discoveryDoc, error) {
+	p.mu.Lock()
+	cached := p.discovery
+	p.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+	v, err, _ := p.fetches.Do("discovery", func() (any, error) {
+		var d discoveryDoc
+		wellKnown := strings.TrimRight(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+		// Detached from the first caller's cancellation since other callers wait on the same fetch
+		if err := p.getJSON(context.WithoutCancel(ctx), wellKnown, &d); err != nil {
+			return nil, fmt.Errorf("oidc discovery: %w", err)
+		}
+		if strings.TrimRight(d.Issuer, "/") != strings.TrimRight(p.cfg.Issuer, "/") {
+			return nil, fmt.Errorf("oidc discovery: issuer %q does not match configured %q", d.Issuer, p.cfg.Issuer)
+		}
+		if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+			return nil, errors.New("oidc discovery: incomplete provider metadata")
+		}
+		p.mu.Lock()
+		p.discovery = &d
+		p.mu.Unlock()
+		return &d, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*discoveryDoc), nil
+}
+
+// verificationKey returns the JWKS key for kid. Unknown kids trigger a (throttled) refetch so provider
+// key rotation is picked up without a restart. The fetch runs without p.mu, so a slow provider does not
+// stall logins that only need cached keys.
+func (p *Provider) verificationKey(ctx context.Context, kid string) (any, error) {
+	p.mu.Lock()
+	k, ok := p.lookupKeyLocked(kid)
+	fresh := p.keys != nil && time.Since(p.keysAt) < jwksRefreshInterval
+	jwksURI := p.discovery.JWKSURI
+	p.mu.Unlock()
+	if ok {
+		return k, nil
+	}
+	if fresh {
+		return nil, fmt.Errorf("unknown kid: %s", kid)
+	}
+	_, err, _ := p.fetches.Do("jwks", func() (any, error) {
+		var set ports.JSONWebKeySet
+		if err := p.getJSON(context.WithoutCancel(ctx), jwksURI, &set); err != nil {
+			return nil, fmt.Errorf("fetch jwks: %w", err)
+		}
+		keys := parseKeySet(set)
+		p.mu.Lock()
+		p.keys, p.keysAt = keys, time.Now()
+		p.mu.Unlock()
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if k, ok := p.lookupKeyLocked(kid); ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown kid: %s", kid)
+}
+
+// lookupKeyLocked finds the key by kid; a token without kid is accepted only when the set has a single key.
+func (p *Provider) lookupKeyLocked(kid string) (any, bool) {
+	if kid != "" {
+		k, ok := p.keys[kid]
+		return k, ok
+	}
+	if len(p.keys) == 1 {
+		for _, k := range p.keys {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+func (p *Provider) getJSON(ctx context.Context, u string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", u, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(out)
+}
+
+// flexBool accepts both JSON booleans and the strings "true"/"false".
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	case "false", "null", "":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+	return nil
+}
+
+var _ ports.OIDCProvider = (*Provider)(nil)
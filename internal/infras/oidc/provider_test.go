@@ -0,0 +1,122 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"gostartkit/internal/infras/oidc"
+	"gostartkit/internal/infras/oidc/oidctest"
+)
+
+// authorize follows the provider's authorize redirect and returns the code it hands back.
+func authorize(t *testing.T, p *oidc.Provider, nonce, verifier string) string {
+	t.Helper()
+	sum := sha256.Sum256([]byte(verifier))
+	authURL, err := p.AuthCodeURL("st", nonce, base64.RawURLEncoding.EncodeToString(sum[:]))
+	if err != nil {
+		t.Fatalf("auth url: %v", err)
+	}
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	resp.Body.Close()
+	loc, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || loc.Query().Get("state") != "st" {
+		t.Fatalf("unexpected redirect %q", resp.Header.Get("Location"))
+	}
+	return loc.Query().Get("code")
+}
+
+func newProvider(t *testing.T, idp *oidctest.IdP) *oidc.Provider {
+	t.Helper()
+	p, err := oidc.NewProvider(oidc.ProviderConfig{
+		Name:         "stub",
+		Issuer:       idp.Issuer(),
+		ClientID:     idp.ClientID,
+		ClientSecret: idp.ClientSecret,
+		RedirectURL:  "http://localhost/v1/auth/oidc/stub/callback",
+	}, nil)
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	return p
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	idp := oidctest.New("api", "s3cret")
+	defer idp.Close()
+	p := newProvider(t, idp)
+
+	code := authorize(t, p, "n-1", "verifier-verifier-verifier-verifier-verifier")
+	ident, err := p.Exchange(context.Background(), code, "verifier-verifier-verifier-verifier-verifier", "n-1")
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	if ident.Provider != "stub" || ident.Subject != idp.User.Subject || ident.Email != idp.User.Email || !ident.EmailVerified {
+		t.Fatalf("unexpected identity %+v", ident)
+	}
+}
+
+func TestProvider_ExchangeRejects(t *testing.T) {
+	const verifier = "verifier-verifier-verifier-verifier-verifier"
+	cases := []struct {
+		name     string
+		tweak    func(*oidctest.IdP)
+		verifier string
+	}{
+		{"nonce mismatch", func(idp *oidctest.IdP) { idp.Nonce = "other" }, verifier},
+		{"wrong audience", func(idp *oidctest.IdP) { idp.Audience = "someone-else" }, verifier},
+		{"wrong pkce verifier", func(*oidctest.IdP) {}, "not-the-verifier"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			idp := oidctest.New("api", "s3cret")
+			defer idp.Close()
+			c.tweak(idp)
+			p := newProvider(t, idp)
+			code := authorize(t, p, "n-1", verifier)
+			if _, err := p.Exchange(context.Background(), code, c.verifier, "n-1"); err == nil {
+				t.Fatalf("expected exchange to fail")
+			}
+		})
+	}
+}
+
+func TestProvider_ConcurrentLoginsShareOneJWKSFetch(t *testing.T) {
+	const verifier = "verifier-verifier-verifier-verifier-verifier"
+	idp := oidctest.New("api", "s3cret")
+	defer idp.Close()
+	idp.JWKSDelay = 100 * time.Millisecond
+	p := newProvider(t, idp)
+
+	codes := make([]string, 5)
+	for i := range codes {
+		codes[i] = authorize(t, p, "n-1", verifier)
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, len(codes))
+	for i, code := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = p.Exchange(context.Background(), code, verifier, "n-1")
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("exchange %d: %v", i, err)
+		}
+	}
+	if n := idp.JWKSRequests(); n != 1 {
+		t.Fatalf("expected one JWKS fetch, got %d", n)
+	}
+}
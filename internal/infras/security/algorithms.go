@@ -0,0 +1,198 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingAlgorithm describes one supported JWT_ALG. Adding an algorithm means adding an entry to
+// algorithms; signing, validation, key loading and JWKS all go through this table.
+type signingAlgorithm struct {
+	name   string // JOSE "alg" value
+	method jwt.SigningMethod
+	// symmetric algorithms sign with JWT_SECRET and have no key files
+	symmetric bool
+	// checkKey rejects keys of the wrong type, curve or size for this algorithm
+	checkKey func(pub crypto.PublicKey) error
+}
+
+var algorithms = map[string]signingAlgorithm{
+	"HS256": {name: "HS256", method: jwt.SigningMethodHS256, symmetric: true},
+	"RS256": {name: "RS256", method: jwt.SigningMethodRS256, checkKey: rsaKeyCheck},
+	"PS256": {name: "PS256", method: jwt.SigningMethodPS256, checkKey: rsaKeyCheck},
+	"ES256": {name: "ES256", method: jwt.SigningMethodES256, checkKey: ecKeyCheck(elliptic.P256())},
+	"ES384": {name: "ES384", method: jwt.SigningMethodES384, checkKey: ecKeyCheck(elliptic.P384())},
+	"EDDSA": {name: "EdDSA", method: jwt.SigningMethodEdDSA, checkKey: edKeyCheck},
+}
+
+// lookupAlgorithm resolves a JWT_ALG value case-insensitively.
+func lookupAlgorithm(alg string) (signingAlgorithm, error) {
+	a, ok := algorithms[strings.ToUpper(strings.TrimSpace(alg))]
+	if !ok {
+		return signingAlgorithm{}, fmt.Errorf("unsupported jwt alg: %s", alg)
+	}
+	return a, nil
+}
+
+// minRSABits is the smallest RSA modulus accepted for RS256/PS256.
+const minRSABits = 2048
+
+func rsaKeyCheck(pub crypto.PublicKey) error {
+	k, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("expected RSA key, got %s", keyKind(pub))
+	}
+	if k.N.BitLen() < minRSABits {
+		return fmt.Errorf("RSA key is %d bits, need at least %d", k.N.BitLen(), minRSABits)
+	}
+	return nil
+}
+
+func ecKeyCheck(curve elliptic.Curve) func(crypto.PublicKey) error {
+	return func(pub crypto.PublicKey) error {
+		k, ok := pub.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("expected ECDSA %s key, got %s", curve.Params().Name, keyKind(pub))
+		}
+		if k.Curve != curve {
+			return fmt.Errorf("expected ECDSA %s key, got %s", curve.Params().Name, keyKind(pub))
+		}
+		return nil
+	}
+}
+
+func edKeyCheck(pub crypto.PublicKey) error {
+	if _, ok := pub.(ed25519.PublicKey); !ok {
+		return fmt.Errorf("expected Ed25519 key, got %s", keyKind(pub))
+	}
+	return nil
+}
+
+// keyKind names a key type for error messages.
+func keyKind(pub crypto.PublicKey) string {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA-%d", k.N.BitLen())
+	case *ecdsa.PublicKey:
+		return "ECDSA " + k.Curve.Params().Name
+	case ed25519.PublicKey:
+		return "Ed25519"
+	default:
+		return fmt.Sprintf("%T", pub)
+	}
+}
+
+// loadPrivateKey reads a PEM private key (PKCS#1, SEC 1 or PKCS#8) from pemStr or path and checks it fits alg.
+func loadPrivateKey(alg signingAlgorithm, path, pemStr string) (crypto.Signer, error) {
+	data := []byte(pemStr)
+	if pemStr == "" {
+		b, err := loadFileIfExists(path)
+		if err != nil {
+			return nil, err
+		}
+		data = b
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty %s private key", alg.name)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid %s private key PEM", alg.name)
+	}
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported private key type: %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key %T", key)
+	}
+	if err := alg.checkKey(signer.Public()); err != nil {
+		return nil, fmt.Errorf("private key does not match %s: %w", alg.name, err)
+	}
+	return signer, nil
+}
+
+// parsePublicKeyFile reads a PEM public key (PKIX or PKCS#1) and checks it fits alg.
+func parsePublicKeyFile(alg signingAlgorithm, path string) (crypto.PublicKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("invalid PEM in %s", path)
+	}
+	var pub any
+	switch block.Type {
+	case "PUBLIC KEY":
+		pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		pub, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported public key type in %s: %s", path, block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := alg.checkKey(pub); err != nil {
+		return nil, fmt.Errorf("public key %s does not match %s: %w", path, alg.name, err)
+	}
+	return pub, nil
+}
+
+// loadPublicKeys reads every PEM file in dir; the file name without extension is the kid.
+func loadPublicKeys(alg signingAlgorithm, dir string) (map[string]crypto.PublicKey, error) {
+	out := make(map[string]crypto.PublicKey)
+	if dir == "" {
+		return out, nil
+	}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		k, err := parsePublicKeyFile(alg, path)
+		if err != nil {
+			return err
+		}
+		out[name] = k
+		return nil
+	})
+	return out, err
+}
+
+func loadFileIfExists(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return os.ReadFile(path)
+}
+
+var errNoPublicKeys = errors.New("no public keys configured")
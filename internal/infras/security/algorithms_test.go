@@ -0,0 +1,115 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePrivateKey(t *testing.T, path string, key crypto.Signer) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	writePEM(t, path, "PRIVATE KEY", der)
+}
+
+func TestAsymmetricAlgorithms_SignAndValidate(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	cases := []struct {
+		alg string
+		key crypto.Signer
+	}{
+		{"RS256", rsaKey},
+		{"PS256", rsaKey},
+		{"ES256", p256},
+		{"ES384", p384},
+		{"EdDSA", edKey},
+	}
+	for _, c := range cases {
+		t.Run(c.alg, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "priv.pem")
+			writePrivateKey(t, path, c.key)
+			svc := NewJWTService("", 60).(*jwtService)
+			if err := svc.ConfigureAlgorithm(c.alg, "k1", path, "", ""); err != nil {
+				t.Fatalf("configure: %v", err)
+			}
+			tok, err := svc.GenerateToken("u1", "user")
+			if err != nil {
+				t.Fatalf("sign: %v", err)
+			}
+			claims, err := svc.ValidateToken(tok)
+			if err != nil || claims.Subject != "u1" {
+				t.Fatalf("validate: %v", err)
+			}
+			set := svc.PublicKeySet()
+			if len(set.Keys) != 1 || !strings.EqualFold(set.Keys[0].Alg, c.alg) {
+				t.Fatalf("unexpected jwks: %+v", set)
+			}
+		})
+	}
+}
+
+func TestConfigureAlgorithm_RejectsMismatchedKey(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	cases := []struct {
+		alg string
+		key crypto.Signer
+	}{
+		{"RS256", p256},
+		{"PS256", edKey},
+		{"ES256", p384},
+		{"ES384", rsaKey},
+		{"EdDSA", p256},
+	}
+	for _, c := range cases {
+		t.Run(c.alg, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "priv.pem")
+			writePrivateKey(t, path, c.key)
+			svc := NewJWTService("", 60).(*jwtService)
+			err := svc.ConfigureAlgorithm(c.alg, "k1", path, "", "")
+			if err == nil || !strings.Contains(err.Error(), "does not match") {
+				t.Fatalf("expected key mismatch error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestConfigureAlgorithm_RejectsWeakRSAKey(t *testing.T) {
+	weak, _ := rsa.GenerateKey(rand.Reader, 1024)
+	path := filepath.Join(t.TempDir(), "priv.pem")
+	writePrivateKey(t, path, weak)
+	svc := NewJWTService("", 60).(*jwtService)
+	if err := svc.ConfigureAlgorithm("RS256", "k1", path, "", ""); err == nil {
+		t.Fatalf("expected error for 1024-bit RSA key")
+	}
+}
+
+func TestPublicKeySet_ES256(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	svc := &jwtService{alg: algorithms["ES256"]}
+	svc.ring.Store(&keyring{publics: map[string]crypto.PublicKey{"ec1": key.Public()}})
+	set := svc.PublicKeySet()
+	if len(set.Keys) != 1 {
+		t.Fatalf("unexpected set: %+v", set)
+	}
+	k := set.Keys[0]
+	// P-256 coordinates are 32 bytes => 43 base64url characters
+	if k.Kty != "EC" || k.Crv != "P-256" || k.Alg != "ES256" || len(k.X) != 43 || len(k.Y) != 43 {
+		t.Fatalf("unexpected jwk: %+v", k)
+	}
+}
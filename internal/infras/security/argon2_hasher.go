@@ -0,0 +1,116 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2Params tunes Argon2id. Zero fields fall back to DefaultArgon2Params
+// (the OWASP-recommended m=64 MiB, t=3, p=2 profile at the time of writing).
+type Argon2Params struct {
+	MemoryKiB   uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params is used for every zero field of the configured parameters.
+var DefaultArgon2Params = Argon2Params{MemoryKiB: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32}
+
+const argon2idPrefix = "$argon2id$"
+
+var errInvalidArgon2Hash = errors.New("invalid argon2id hash")
+
+// Argon2idHasher hashes passwords with Argon2id and stores them in the PHC string format
+// ($argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>, unpadded standard base64), so the parameters
+// travel with each hash and can be raised later without breaking existing ones.
+type Argon2idHasher struct{ p Argon2Params }
+
+func NewArgon2idHasher(p Argon2Params) *Argon2idHasher {
+	d := DefaultArgon2Params
+	if p.MemoryKiB == 0 {
+		p.MemoryKiB = d.MemoryKiB
+	}
+	if p.Iterations == 0 {
+		p.Iterations = d.Iterations
+	}
+	if p.Parallelism == 0 {
+		p.Parallelism = d.Parallelism
+	}
+	if p.SaltLength == 0 {
+		p.SaltLength = d.SaltLength
+	}
+	if p.KeyLength == 0 {
+		p.KeyLength = d.KeyLength
+	}
+	return &Argon2idHasher{p: p}
+}
+
+func (a *Argon2idHasher) Hash(raw string) (string, error) {
+	salt := make([]byte, a.p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(raw), salt, a.p.Iterations, a.p.MemoryKiB, a.p.Parallelism, a.p.KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, a.p.MemoryKiB, a.p.Iterations, a.p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Compare recomputes the hash with the parameters stored in hashed, so it accepts hashes made
+// with older settings too.
+func (a *Argon2idHasher) Compare(hashed string, raw string) bool {
+	p, salt, key, err := decodeArgon2id(hashed)
+	if err != nil {
+		return false
+	}
+	got := argon2.IDKey([]byte(raw), salt, p.Iterations, p.MemoryKiB, p.Parallelism, p.KeyLength)
+	return subtle.ConstantTimeCompare(got, key) == 1
+}
+
+// NeedsRehash reports whether hashed was made with parameters other than the configured ones.
+func (a *Argon2idHasher) NeedsRehash(hashed string) bool {
+	p, _, _, err := decodeArgon2id(hashed)
+	if err != nil {
+		return true
+	}
+	return p != a.p
+}
+
+// Recognizes reports whether hashed is in this hasher's format.
+func (a *Argon2idHasher) Recognizes(hashed string) bool {
+	return strings.HasPrefix(hashed, argon2idPrefix)
+}
+
+func decodeArgon2id(hashed string) (Argon2Params, []byte, []byte, error) {
+	// "", "argon2id", "v=19", "m=..,t=..,p=..", salt, hash
+	parts := strings.Split(hashed, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Argon2Params{}, nil, nil, errInvalidArgon2Hash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2Params{}, nil, nil, errInvalidArgon2Hash
+	}
+	var p Argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.MemoryKiB, &p.Iterations, &p.Parallelism); err != nil {
+		return Argon2Params{}, nil, nil, errInvalidArgon2Hash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2Params{}, nil, nil, errInvalidArgon2Hash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 || p.Iterations == 0 || p.Parallelism == 0 {
+		return Argon2Params{}, nil, nil, errInvalidArgon2Hash
+	}
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+	return p, salt, key, nil
+}
@@ -1,6 +1,8 @@
 package security
 
 import (
+	"strings"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -25,3 +27,21 @@ func (b *BcryptHasher) Hash(raw string) (string, error) {
 func (b *BcryptHasher) Compare(hashed string, raw string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(raw)) == nil
 }
+
+// NeedsRehash reports whether hashed was made with a different cost than the configured one.
+func (b *BcryptHasher) NeedsRehash(hashed string) bool {
+	cost, err := bcrypt.Cost([]byte(hashed))
+	if err != nil {
+		return true
+	}
+	want := b.cost
+	if want <= 0 {
+		want = bcrypt.DefaultCost
+	}
+	return cost != want
+}
+
+// Recognizes reports whether hashed is a bcrypt hash ($2a$, $2b$ or $2y$).
+func (b *BcryptHasher) Recognizes(hashed string) bool {
+	return strings.HasPrefix(hashed, "$2a$") || strings.HasPrefix(hashed, "$2b$") || strings.HasPrefix(hashed, "$2y$")
+}
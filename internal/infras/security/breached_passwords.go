@@ -0,0 +1,298 @@
+package security
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+
+	"gostartkit/internal/application/ports"
+)
+
+// hibpHashLen is the length of an uppercase hex SHA-1 digest in the HaveIBeenPwned password files.
+const hibpHashLen = 40
+
+// hibpReadChunk is read around a probe offset; it holds a partial line plus one full "HASH:COUNT" line.
+const hibpReadChunk = 256
+
+var errHIBPFormat = errors.New("not a HaveIBeenPwned SHA-1 file (expected sorted HASH:COUNT lines)")
+
+// HIBPFile checks passwords against the HaveIBeenPwned "SHA-1, ordered by hash" download without loading it:
+// every lookup is a binary search over the file. Safe for concurrent use.
+type HIBPFile struct {
+	f        *os.File
+	size     int64
+	minCount int
+}
+
+// OpenHIBPFile opens a HASH:COUNT file sorted by hash. Passwords seen fewer than minCount times are allowed (minCount <= 0 means 1).
+func OpenHIBPFile(path string, minCount int) (*HIBPFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	h := &HIBPFile{f: f, size: st.Size(), minCount: max(minCount, 1)}
+	if _, _, err := h.lineAt(0); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return h, nil
+}
+
+// Close releases the file.
+func (h *HIBPFile) Close() error { return h.f.Close() }
+
+func (h *HIBPFile) IsBreached(password string) (bool, error) {
+	count, err := h.Count(password)
+	if err != nil {
+		return false, err
+	}
+	return count >= h.minCount, nil
+}
+
+// Count returns how often password appears in the corpus (0 when absent).
+func (h *HIBPFile) Count(password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	target := []byte(hex.EncodeToString(sum[:]))
+	target = bytes.ToUpper(target)
+
+	// Invariant: the target line, if present, starts within [lo, hi)
+	lo, hi := int64(0), h.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, line, err := h.lineAt(mid)
+		if err != nil {
+			return 0, err
+		}
+		if start >= hi {
+			hi = mid
+			continue
+		}
+		switch c := bytes.Compare(bytes.ToUpper(line[:hibpHashLen]), target); {
+		case c == 0:
+			n, err := strconv.Atoi(string(line[hibpHashLen+1:]))
+			if err != nil {
+				return 0, errHIBPFormat
+			}
+			return n, nil
+		case c < 0:
+			lo = start + int64(len(line)) + 1
+		default:
+			hi = mid
+		}
+	}
+	return 0, nil
+}
+
+// lineAt returns the first line starting at or after off, with "\r\n" trimmed. start is h.size when there is none.
+func (h *HIBPFile) lineAt(off int64) (int64, []byte, error) {
+	readFrom := off
+	if off > 0 {
+		// Read from the previous byte so a line starting exactly at off is recognized by the preceding newline
+		readFrom = off - 1
+	}
+	buf := make([]byte, hibpReadChunk)
+	n, err := h.f.ReadAt(buf, readFrom)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, nil, err
+	}
+	buf = buf[:n]
+	start := readFrom
+	if off > 0 {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			return h.size, nil, nil
+		}
+		buf = buf[i+1:]
+		start += int64(i) + 1
+	}
+	if len(buf) == 0 {
+		return h.size, nil, nil
+	}
+	if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+		buf = buf[:i]
+	}
+	line := bytes.TrimRight(buf, "\r")
+	if len(line) < hibpHashLen+2 || line[hibpHashLen] != ':' {
+		return 0, nil, errHIBPFormat
+	}
+	return start, line, nil
+}
+
+// bloomMagic starts a serialized BreachBloom; it is followed by a version byte.
+const bloomMagic = "GSKBLOOM"
+
+const bloomVersion = 1
+
+// BreachBloom is a bloom filter of breached SHA-1 hashes. It answers in memory with no false negatives and a
+// small, configurable false-positive rate; the prevalence threshold is fixed when the filter is built.
+type BreachBloom struct {
+	bits     []byte
+	m        uint64
+	k        uint32
+	minCount int
+}
+
+// BuildBreachBloom builds a filter from a HaveIBeenPwned HASH:COUNT file, keeping hashes seen at least minCount
+// times, sized for falsePositiveRate (default 0.001). The file is read twice: once to count, once to fill.
+func BuildBreachBloom(path string, minCount int, falsePositiveRate float64) (*BreachBloom, error) {
+	minCount = max(minCount, 1)
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.001
+	}
+	var n uint64
+	if err := scanHIBP(path, minCount, func([]byte) { n++ }); err != nil {
+		return nil, err
+	}
+	b := newBreachBloom(max(n, 1), falsePositiveRate, minCount)
+	var digest [sha1.Size]byte
+	err := scanHIBP(path, minCount, func(hash []byte) {
+		if _, err := hex.Decode(digest[:], hash); err == nil {
+			b.add(digest)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func newBreachBloom(n uint64, p float64, minCount int) *BreachBloom {
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	m = max(m, 64)
+	k := uint32(math.Round(float64(m) / float64(n) * math.Ln2))
+	k = min(max(k, 1), 30)
+	return &BreachBloom{bits: make([]byte, (m+7)/8), m: m, k: k, minCount: minCount}
+}
+
+// scanHIBP calls fn with the hex hash of every line whose count is at least minCount.
+func scanHIBP(path string, minCount int, fn func(hash []byte)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := bytes.TrimRight(sc.Bytes(), "\r")
+		if len(line) == 0 {
+			continue
+		}
+		if len(line) < hibpHashLen+2 || line[hibpHashLen] != ':' {
+			return errHIBPFormat
+		}
+		count, err := strconv.Atoi(string(line[hibpHashLen+1:]))
+		if err != nil {
+			return errHIBPFormat
+		}
+		if count >= minCount {
+			fn(line[:hibpHashLen])
+		}
+	}
+	return sc.Err()
+}
+
+// MinCount is the prevalence threshold the filter was built with.
+func (b *BreachBloom) MinCount() int { return b.minCount }
+
+func (b *BreachBloom) IsBreached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	h1, h2 := bloomHashes(sum)
+	for i := uint64(0); i < uint64(b.k); i++ {
+		idx := (h1 + i*h2) % b.m
+		if b.bits[idx/8]&(1<<(idx%8)) == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (b *BreachBloom) add(digest [sha1.Size]byte) {
+	h1, h2 := bloomHashes(digest)
+	for i := uint64(0); i < uint64(b.k); i++ {
+		idx := (h1 + i*h2) % b.m
+		b.bits[idx/8] |= 1 << (idx % 8)
+	}
+}
+
+// bloomHashes derives the two double-hashing seeds from the SHA-1 digest, which is already uniformly distributed.
+func bloomHashes(digest [sha1.Size]byte) (uint64, uint64) {
+	return binary.BigEndian.Uint64(digest[0:8]), binary.BigEndian.Uint64(digest[8:16]) | 1
+}
+
+// WriteTo serializes the filter for ReadBreachBloom.
+func (b *BreachBloom) WriteTo(w io.Writer) (int64, error) {
+	hdr := make([]byte, 0, len(bloomMagic)+1+8+4+4)
+	hdr = append(hdr, bloomMagic...)
+	hdr = append(hdr, bloomVersion)
+	hdr = binary.BigEndian.AppendUint64(hdr, b.m)
+	hdr = binary.BigEndian.AppendUint32(hdr, b.k)
+	hdr = binary.BigEndian.AppendUint32(hdr, uint32(b.minCount))
+	n, err := w.Write(hdr)
+	if err != nil {
+		return int64(n), err
+	}
+	m, err := w.Write(b.bits)
+	return int64(n + m), err
+}
+
+// ReadBreachBloom loads a filter written by WriteTo.
+func ReadBreachBloom(r io.Reader) (*BreachBloom, error) {
+	hdr := make([]byte, len(bloomMagic)+1+8+4+4)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return nil, fmt.Errorf("read bloom header: %w", err)
+	}
+	if string(hdr[:len(bloomMagic)]) != bloomMagic || hdr[len(bloomMagic)] != bloomVersion {
+		return nil, errors.New("not a breached-password bloom filter")
+	}
+	p := hdr[len(bloomMagic)+1:]
+	b := &BreachBloom{
+		m:        binary.BigEndian.Uint64(p[0:8]),
+		k:        binary.BigEndian.Uint32(p[8:12]),
+		minCount: int(binary.BigEndian.Uint32(p[12:16])),
+	}
+	if b.m == 0 || b.k == 0 || b.k > 30 {
+		return nil, errors.New("corrupt bloom filter header")
+	}
+	b.bits = make([]byte, (b.m+7)/8)
+	if _, err := io.ReadFull(r, b.bits); err != nil {
+		return nil, fmt.Errorf("read bloom bits: %w", err)
+	}
+	return b, nil
+}
+
+// OpenBreachedPasswords loads path as a serialized BreachBloom when it starts with the bloom header, and as a
+// HaveIBeenPwned HASH:COUNT file otherwise. minCount only applies to HIBP files; a bloom filter keeps the
+// threshold it was built with.
+func OpenBreachedPasswords(path string, minCount int) (ports.BreachedPasswordChecker, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	head := make([]byte, len(bloomMagic))
+	if _, err := io.ReadFull(f, head); err == nil && string(head) == bloomMagic {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return ReadBreachBloom(bufio.NewReader(f))
+	}
+	return OpenHIBPFile(path, minCount)
+}
+
+var (
+	_ ports.BreachedPasswordChecker = (*HIBPFile)(nil)
+	_ ports.BreachedPasswordChecker = (*BreachBloom)(nil)
+)
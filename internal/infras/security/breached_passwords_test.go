@@ -0,0 +1,118 @@
+package security
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// writeHIBPFile writes a sorted HASH:COUNT file with CRLF line endings like the official download,
+// plus filler hashes so the binary search crosses many lines.
+func writeHIBPFile(t *testing.T, counts map[string]int) string {
+	t.Helper()
+	var lines []string
+	for pw, n := range counts {
+		sum := sha1.Sum([]byte(pw))
+		lines = append(lines, fmt.Sprintf("%s:%d", strings.ToUpper(hex.EncodeToString(sum[:])), n))
+	}
+	for i := 0; i < 500; i++ {
+		sum := sha1.Sum([]byte(fmt.Sprintf("filler-%d", i)))
+		lines = append(lines, fmt.Sprintf("%s:%d", strings.ToUpper(hex.EncodeToString(sum[:])), i+1))
+	}
+	sort.Strings(lines)
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestHIBPFile_LookupAndThreshold(t *testing.T) {
+	path := writeHIBPFile(t, map[string]int{"Password123!": 250000, "Tr0ub4dor&3x": 3})
+	h, err := OpenHIBPFile(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	if n, err := h.Count("Password123!"); err != nil || n != 250000 {
+		t.Fatalf("count: %d %v", n, err)
+	}
+	if n, _ := h.Count("filler-0"); n != 1 {
+		t.Fatalf("expected filler-0 seen once, got %d", n)
+	}
+	if breached, _ := h.IsBreached("Password123!"); !breached {
+		t.Fatalf("expected a common password to be breached")
+	}
+	// Seen only 3 times, below the threshold of 10
+	if breached, _ := h.IsBreached("Tr0ub4dor&3x"); breached {
+		t.Fatalf("expected a rare password to pass the threshold")
+	}
+	if breached, _ := h.IsBreached("never-in-any-breach-4711"); breached {
+		t.Fatalf("expected unknown password to pass")
+	}
+	for i := 0; i < 500; i += 37 {
+		if n, _ := h.Count(fmt.Sprintf("filler-%d", i)); n != i+1 {
+			t.Fatalf("filler-%d: got count %d", i, n)
+		}
+	}
+}
+
+func TestOpenHIBPFile_RejectsOtherFormats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("password\nqwerty\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenHIBPFile(path, 1); err == nil {
+		t.Fatalf("expected a plain word list to be rejected")
+	}
+}
+
+func TestBreachBloom_BuildSerializeAndDetect(t *testing.T) {
+	path := writeHIBPFile(t, map[string]int{"Password123!": 250000, "Tr0ub4dor&3x": 3})
+	b, err := BuildBreachBloom(path, 10, 0.0001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := b.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	bloomPath := filepath.Join(t.TempDir(), "pwned.bloom")
+	if err := os.WriteFile(bloomPath, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	checker, err := OpenBreachedPasswords(bloomPath, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, ok := checker.(*BreachBloom)
+	if !ok || loaded.MinCount() != 10 {
+		t.Fatalf("expected a bloom filter built with min count 10, got %T %+v", checker, checker)
+	}
+	if breached, _ := loaded.IsBreached("Password123!"); !breached {
+		t.Fatalf("bloom filters have no false negatives")
+	}
+	if breached, _ := loaded.IsBreached("filler-499"); !breached {
+		t.Fatalf("expected filler-499 (count 500) in the filter")
+	}
+	if breached, _ := loaded.IsBreached("never-in-any-breach-4711"); breached {
+		t.Fatalf("unexpected false positive at a 0.01%% rate")
+	}
+
+	// Plain HIBP files are detected too
+	checker, err = OpenBreachedPasswords(path, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := checker.(*HIBPFile); !ok {
+		t.Fatalf("expected *HIBPFile, got %T", checker)
+	}
+}
@@ -0,0 +1,91 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"sort"
+	"time"
+
+	"gostartkit/internal/application/ports"
+)
+
+// PublicKeySet returns every configured verification key (next, active and retiring) as a JWK, sorted by kid.
+// HS256 secrets are symmetric and are never published, so HS256 yields an empty set.
+func (j *jwtService) PublicKeySet() ports.JSONWebKeySet {
+	set := ports.JSONWebKeySet{Keys: []ports.JSONWebKey{}}
+	ring := j.keys()
+	now := time.Now()
+	for kid, pk := range ring.publics {
+		if !ring.usable(kid, now) {
+			continue
+		}
+		if jwk, ok := publicJWK(j.alg.name, kid, pk); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	sort.Slice(set.Keys, func(a, b int) bool { return set.Keys[a].Kid < set.Keys[b].Kid })
+	return set
+}
+
+// publicJWK encodes a verification key; alg is the configured JOSE algorithm (RS256 and PS256 share RSA keys).
+func publicJWK(alg, kid string, pub crypto.PublicKey) (ports.JSONWebKey, bool) {
+	switch pk := pub.(type) {
+	case *rsa.PublicKey:
+		return rsaJWK(alg, kid, pk), true
+	case *ecdsa.PublicKey:
+		return ecJWK(alg, kid, pk)
+	case ed25519.PublicKey:
+		return ed25519JWK(kid, pk), true
+	default:
+		return ports.JSONWebKey{}, false
+	}
+}
+
+func rsaJWK(alg, kid string, pk *rsa.PublicKey) ports.JSONWebKey {
+	return ports.JSONWebKey{
+		Kty: "RSA",
+		Kid: kid,
+		Alg: alg,
+		Use: "sig",
+		N:   b64url(pk.N.Bytes()),
+		E:   b64url(big.NewInt(int64(pk.E)).Bytes()),
+	}
+}
+
+// ecJWK encodes x and y as fixed-size big-endian coordinates (RFC 7518 §6.2.1).
+func ecJWK(alg, kid string, pk *ecdsa.PublicKey) (ports.JSONWebKey, bool) {
+	pub, err := pk.ECDH()
+	if err != nil {
+		return ports.JSONWebKey{}, false
+	}
+	point := pub.Bytes() // 0x04 || X || Y
+	size := (len(point) - 1) / 2
+	return ports.JSONWebKey{
+		Kty: "EC",
+		Kid: kid,
+		Alg: alg,
+		Use: "sig",
+		Crv: pk.Curve.Params().Name,
+		X:   b64url(point[1 : 1+size]),
+		Y:   b64url(point[1+size:]),
+	}, true
+}
+
+func ed25519JWK(kid string, pk ed25519.PublicKey) ports.JSONWebKey {
+	return ports.JSONWebKey{
+		Kty: "OKP",
+		Kid: kid,
+		Alg: "EdDSA",
+		Use: "sig",
+		Crv: "Ed25519",
+		X:   b64url(pk),
+	}
+}
+
+func b64url(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+var _ ports.KeySetPublisher = (*jwtService)(nil)
@@ -0,0 +1,67 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestPublicKeySet_RS256(t *testing.T) {
+	dir := t.TempDir()
+	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
+	privDER, _ := x509.MarshalPKCS8PrivateKey(priv)
+	writePEM(t, filepath.Join(dir, "priv.pem"), "PRIVATE KEY", privDER)
+	pubDir := filepath.Join(dir, "pub")
+	_ = os.Mkdir(pubDir, 0o700)
+	pubDER, _ := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	writePEM(t, filepath.Join(pubDir, "k1.pem"), "PUBLIC KEY", pubDER)
+
+	svc := NewJWTService("", 60).(*jwtService)
+	if err := svc.ConfigureAlgorithm("RS256", "k1", filepath.Join(dir, "priv.pem"), "", pubDir); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	set := svc.PublicKeySet()
+	if len(set.Keys) != 1 {
+		t.Fatalf("expected 1 key, got %d", len(set.Keys))
+	}
+	k := set.Keys[0]
+	if k.Kid != "k1" || k.Kty != "RSA" || k.Alg != "RS256" || k.Use != "sig" || k.E != "AQAB" || k.N == "" {
+		t.Fatalf("unexpected jwk: %+v", k)
+	}
+}
+
+func TestPublicKeySet_EdDSA(t *testing.T) {
+	dir := t.TempDir()
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKIXPublicKey(pub)
+	writePEM(t, filepath.Join(dir, "ed1.pem"), "PUBLIC KEY", der)
+	keys, err := loadPublicKeys(algorithms["EDDSA"], dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	svc := &jwtService{alg: algorithms["EDDSA"]}
+	svc.ring.Store(&keyring{publics: keys})
+	set := svc.PublicKeySet()
+	if len(set.Keys) != 1 || set.Keys[0].Kty != "OKP" || set.Keys[0].Crv != "Ed25519" || set.Keys[0].X != b64url(pub) {
+		t.Fatalf("unexpected set: %+v", set)
+	}
+}
+
+func TestPublicKeySet_HS256IsEmpty(t *testing.T) {
+	svc := NewJWTService("secret", 60).(*jwtService)
+	if n := len(svc.PublicKeySet().Keys); n != 0 {
+		t.Fatalf("HS256 must not publish keys, got %d", n)
+	}
+}
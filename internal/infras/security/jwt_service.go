@@ -1,23 +1,23 @@
 package security
 
 import (
-	"crypto/ed25519"
-	"crypto/rsa"
-	"crypto/x509"
-	"encoding/pem"
 	"errors"
 	"fmt"
-	"io/fs"
-	"os"
-	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"gostartkit/internal/application/ports"
+
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type JWTService interface {
 	GenerateToken(userID string, role string) (string, error)
+	// GenerateClientToken issues a token for an OAuth2 client (client_credentials grant):
+	// subject and client_id are the client ID, there is no role, and scope lists the granted scopes.
+	GenerateClientToken(clientID string, scopes []string, ttl time.Duration) (string, error)
 	ValidateToken(tokenStr string) (*AppClaims, error)
 }
 
@@ -27,22 +27,19 @@ type jwtService struct {
 	issuer         string
 	audience       string
 	leeway         time.Duration
-	// signing algorithm and key id
-	alg string // HS256 (default), RS256, EDDSA
-	kid string
+	// signing algorithm (see algorithms.go); HS256 by default
+	alg signingAlgorithm
 	// HS256 secret
 	hsSecret string
-	// RS256 keys
-	rsPrivate *rsa.PrivateKey
-	rsPublics map[string]*rsa.PublicKey // kid -> key
-	// EdDSA keys
-	edPrivate ed25519.PrivateKey
-	edPublics map[string]ed25519.PublicKey // kid -> key
+	// key sources, kept so Reload can rebuild the keyring from disk
+	src keySources
+	// ring holds the current asymmetric key set; swapped atomically on reload
+	ring atomic.Pointer[keyring]
 }
 
 func NewJWTService(secret string, expireSec int) JWTService {
 	return &jwtService{
-		alg:            "HS256",
+		alg:            algorithms["HS256"],
 		hsSecret:       secret,
 		expireDuration: time.Duration(expireSec) * time.Second,
 	}
@@ -61,128 +58,72 @@ func (j *jwtService) SetMeta(iss, aud string, leewaySec int) {
 type AppClaims struct {
 	jwt.RegisteredClaims
 	Role string `json:"role"`
+	// ClientID is set on tokens issued to OAuth2 clients (service principals); empty for user tokens
+	ClientID string `json:"client_id,omitempty"`
+	// Scope is the space-separated list of granted scopes (RFC 8693 / RFC 9068 "scope" claim)
+	Scope string `json:"scope,omitempty"`
 }
 
+// IsClient reports whether the token was issued to a service principal rather than a user.
+func (c *AppClaims) IsClient() bool { return c.ClientID != "" }
+
+// Scopes returns the granted scopes as a slice.
+func (c *AppClaims) Scopes() []string { return strings.Fields(c.Scope) }
+
 func (j *jwtService) GenerateToken(userID string, role string) (string, error) {
-	now := time.Now()
+	claims := AppClaims{RegisteredClaims: j.registeredClaims(userID, j.expireDuration), Role: role}
+	return j.sign(claims)
+}
+
+func (j *jwtService) GenerateClientToken(clientID string, scopes []string, ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		ttl = j.expireDuration
+	}
 	claims := AppClaims{
-		RegisteredClaims: jwt.RegisteredClaims{
-			Subject:   userID,
-			ExpiresAt: jwt.NewNumericDate(now.Add(j.expireDuration)),
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-			Issuer:    j.issuer,
-			Audience:  jwt.ClaimStrings{j.audience},
-		},
-		Role: role,
+		RegisteredClaims: j.registeredClaims(clientID, ttl),
+		ClientID:         clientID,
+		Scope:            strings.Join(scopes, " "),
 	}
+	return j.sign(claims)
+}
 
-	var method jwt.SigningMethod
-	switch strings.ToUpper(j.alg) {
-	case "HS256":
-		method = jwt.SigningMethodHS256
-	case "RS256":
-		method = jwt.SigningMethodRS256
-	case "EDDSA":
-		method = jwt.SigningMethodEdDSA
-	default:
-		return "", fmt.Errorf("unsupported jwt alg: %s", j.alg)
+func (j *jwtService) registeredClaims(subject string, ttl time.Duration) jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Subject:   subject,
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Issuer:    j.issuer,
+		Audience:  jwt.ClaimStrings{j.audience},
 	}
-	token := jwt.NewWithClaims(method, claims)
+}
+
+func (j *jwtService) sign(claims AppClaims) (string, error) {
+	token := jwt.NewWithClaims(j.alg.method, claims)
 	// Always set type header
 	token.Header["typ"] = "JWT"
-	if j.kid != "" {
-		token.Header["kid"] = j.kid
+	ring := j.keys()
+	if ring.kid != "" {
+		token.Header["kid"] = ring.kid
 	}
-	switch strings.ToUpper(j.alg) {
-	case "HS256":
+	if j.alg.symmetric {
 		return token.SignedString([]byte(j.hsSecret))
-	case "RS256":
-		if j.rsPrivate == nil {
-			return "", errors.New("missing RS256 private key")
-		}
-		return token.SignedString(j.rsPrivate)
-	case "EDDSA":
-		if j.edPrivate == nil {
-			return "", errors.New("missing EdDSA private key")
-		}
-		return token.SignedString(j.edPrivate)
 	}
-	return "", errors.New("unreachable")
+	if ring.private == nil {
+		return "", fmt.Errorf("missing %s private key", j.alg.name)
+	}
+	return token.SignedString(ring.private)
 }
 
 func (j *jwtService) ValidateToken(tokenStr string) (*AppClaims, error) {
-	var methods []string
-	switch strings.ToUpper(j.alg) {
-	case "HS256":
-		methods = []string{jwt.SigningMethodHS256.Alg()}
-	case "RS256":
-		methods = []string{jwt.SigningMethodRS256.Alg()}
-	case "EDDSA":
-		methods = []string{jwt.SigningMethodEdDSA.Alg()}
-	default:
-		return nil, fmt.Errorf("unsupported jwt alg: %s", j.alg)
-	}
 	parser := jwt.NewParser(
-		jwt.WithValidMethods(methods),
+		jwt.WithValidMethods([]string{j.alg.method.Alg()}),
 		jwt.WithIssuedAt(),
 		jwt.WithLeeway(j.leeway),
 	)
-	token, err := parser.ParseWithClaims(tokenStr, &AppClaims{}, func(token *jwt.Token) (interface{}, error) {
-		switch strings.ToUpper(j.alg) {
-		case "HS256":
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("unexpected signing method")
-			}
-			return []byte(j.hsSecret), nil
-		case "RS256":
-			if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-				return nil, errors.New("unexpected signing method")
-			}
-			kid, _ := token.Header["kid"].(string)
-			if kid != "" {
-				if pk, ok := j.rsPublics[kid]; ok {
-					return pk, nil
-				}
-				return nil, fmt.Errorf("unknown kid: %s", kid)
-			}
-			switch len(j.rsPublics) {
-			case 0:
-				return nil, errors.New("no RS256 public keys configured")
-			case 1:
-				for _, pk := range j.rsPublics { // return the only key
-					return pk, nil
-				}
-			default:
-				return nil, errors.New("kid required when multiple RS256 public keys are configured")
-			}
-			return nil, errors.New("unreachable")
-		case "EDDSA":
-			if _, ok := token.Method.(*jwt.SigningMethodEd25519); !ok {
-				return nil, errors.New("unexpected signing method")
-			}
-			kid, _ := token.Header["kid"].(string)
-			if kid != "" {
-				if pk, ok := j.edPublics[kid]; ok {
-					return pk, nil
-				}
-				return nil, fmt.Errorf("unknown kid: %s", kid)
-			}
-			switch len(j.edPublics) {
-			case 0:
-				return nil, errors.New("no EdDSA public keys configured")
-			case 1:
-				for _, pk := range j.edPublics {
-					return pk, nil
-				}
-			default:
-				return nil, errors.New("kid required when multiple EdDSA public keys are configured")
-			}
-			return nil, errors.New("unreachable")
-		}
-		return nil, errors.New("unreachable")
-	})
-
+	token, err := parser.ParseWithClaims(tokenStr, &AppClaims{}, j.verificationKey)
 	if err != nil {
 		return nil, err
 	}
@@ -207,6 +148,34 @@ func (j *jwtService) ValidateToken(tokenStr string) (*AppClaims, error) {
 	return nil, errors.New("invalid token")
 }
 
+// verificationKey picks the key for a parsed token: the HS256 secret, or the public key named by kid.
+// Without a kid the only configured key is used, so single-key setups keep working.
+func (j *jwtService) verificationKey(token *jwt.Token) (interface{}, error) {
+	if j.alg.symmetric {
+		return []byte(j.hsSecret), nil
+	}
+	ring := j.keys()
+	kid, _ := token.Header["kid"].(string)
+	if kid != "" {
+		if pk, ok := ring.publics[kid]; ok && ring.usable(kid, time.Now()) {
+			return pk, nil
+		}
+		return nil, fmt.Errorf("unknown kid: %s", kid)
+	}
+	switch len(ring.publics) {
+	case 0:
+		return nil, errNoPublicKeys
+	case 1:
+		for only, pk := range ring.publics { // the only key, unless it is past its retire_at
+			if !ring.usable(only, time.Now()) {
+				return nil, fmt.Errorf("key %s is retired", only)
+			}
+			return pk, nil
+		}
+	}
+	return nil, fmt.Errorf("kid required when multiple %s public keys are configured", j.alg.name)
+}
+
 func containsAudience(aud jwt.ClaimStrings, target string) bool {
 	for _, a := range aud {
 		if a == target {
@@ -216,198 +185,55 @@ func containsAudience(aud jwt.ClaimStrings, target string) bool {
 	return false
 }
 
-// ConfigureAlgorithm sets algorithm, kid, and keys (for RS256/EdDSA). For HS256, ensure hsSecret is set.
+// ConfigureAlgorithm sets algorithm, kid, and keys (for asymmetric algorithms). For HS256, ensure hsSecret is set.
+// Keys that don't fit the algorithm (type, curve, size) are rejected here, so misconfiguration fails startup.
+// The key sources are remembered so Reload can pick up rotated keys later.
 func (j *jwtService) ConfigureAlgorithm(alg, kid, privateKeyPath, privateKeyPEM, publicKeysDir string) error {
-	j.alg = strings.ToUpper(strings.TrimSpace(alg))
-	j.kid = strings.TrimSpace(kid)
-	switch j.alg {
-	case "HS256":
+	a, err := lookupAlgorithm(alg)
+	if err != nil {
+		return err
+	}
+	j.alg = a
+	j.src.kid = strings.TrimSpace(kid)
+	j.src.privateKeyPath = privateKeyPath
+	j.src.privateKeyPEM = privateKeyPEM
+	j.src.publicKeysDir = publicKeysDir
+	if a.symmetric {
 		if j.hsSecret == "" {
 			return errors.New("JWT_SECRET required for HS256")
 		}
+		j.ring.Store(&keyring{kid: j.src.kid})
 		return nil
-	case "RS256":
-		pk, err := loadRSAPrivateKey(privateKeyPath, privateKeyPEM)
-		if err != nil {
-			return err
-		}
-		j.rsPrivate = pk
-		pubs, err := loadRSAPublicKeys(publicKeysDir)
-		if err != nil {
-			return err
-		}
-		j.rsPublics = pubs
-		return nil
-	case "EDDSA":
-		edPriv, err := loadEdPrivateKey(privateKeyPath, privateKeyPEM)
-		if err != nil {
-			return err
-		}
-		j.edPrivate = edPriv
-		pubs, err := loadEdPublicKeys(publicKeysDir)
-		if err != nil {
-			return err
-		}
-		j.edPublics = pubs
-		return nil
-	default:
-		return fmt.Errorf("unsupported jwt alg: %s", alg)
 	}
+	return j.Reload()
 }
 
-func loadFileIfExists(path string) ([]byte, error) {
-	if path == "" {
-		return nil, nil
-	}
-	return os.ReadFile(path)
-}
+// SetKeyManifest switches key loading to a manifest file that lists several keys with their
+// rotation state (see keyring.go). Call before ConfigureAlgorithm.
+func (j *jwtService) SetKeyManifest(path string) { j.src.manifestPath = strings.TrimSpace(path) }
 
-func loadRSAPrivateKey(path, pemStr string) (*rsa.PrivateKey, error) {
-	var data []byte
-	if pemStr != "" {
-		data = []byte(pemStr)
-	} else {
-		b, err := loadFileIfExists(path)
-		if err != nil {
-			return nil, err
-		}
-		data = b
-	}
-	if len(data) == 0 {
-		return nil, errors.New("empty RSA private key")
-	}
-	block, _ := pem.Decode(data)
-	if block == nil {
-		return nil, errors.New("invalid RSA private key PEM")
-	}
-	var key any
-	var err error
-	switch block.Type {
-	case "RSA PRIVATE KEY":
-		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
-	case "PRIVATE KEY":
-		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
-	default:
-		return nil, fmt.Errorf("unsupported RSA key type: %s", block.Type)
-	}
+// VerifyAccessToken is ValidateToken in the shape of ports.AccessTokenVerifier.
+func (j *jwtService) VerifyAccessToken(token string) (ports.AccessTokenInfo, error) {
+	claims, err := j.ValidateToken(token)
 	if err != nil {
-		return nil, err
-	}
-	rsaKey, ok := key.(*rsa.PrivateKey)
-	if !ok {
-		return nil, errors.New("not an RSA private key")
-	}
-	return rsaKey, nil
-}
-
-func loadRSAPublicKeys(dir string) (map[string]*rsa.PublicKey, error) {
-	out := make(map[string]*rsa.PublicKey)
-	if dir == "" {
-		return out, nil
-	}
-	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil || d.IsDir() {
-			return err
-		}
-		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
-		b, err := os.ReadFile(path)
-		if err != nil {
-			return err
-		}
-		block, _ := pem.Decode(b)
-		if block == nil {
-			return fmt.Errorf("invalid PEM in %s", path)
-		}
-		var pub any
-		switch block.Type {
-		case "PUBLIC KEY":
-			pub, err = x509.ParsePKIXPublicKey(block.Bytes)
-		case "RSA PUBLIC KEY":
-			pub, err = x509.ParsePKCS1PublicKey(block.Bytes)
-		default:
-			return fmt.Errorf("unsupported public key type in %s: %s", path, block.Type)
-		}
-		if err != nil {
-			return err
-		}
-		switch k := pub.(type) {
-		case *rsa.PublicKey:
-			out[name] = k
-		default:
-			return fmt.Errorf("non-RSA public key in %s", path)
-		}
-		return nil
-	})
-	return out, err
-}
-
-func loadEdPrivateKey(path, pemStr string) (ed25519.PrivateKey, error) {
-	var data []byte
-	if pemStr != "" {
-		data = []byte(pemStr)
-	} else {
-		b, err := loadFileIfExists(path)
-		if err != nil {
-			return nil, err
-		}
-		data = b
+		return ports.AccessTokenInfo{}, err
 	}
-	if len(data) == 0 {
-		return nil, errors.New("empty Ed private key")
+	info := ports.AccessTokenInfo{
+		ID:       claims.ID,
+		Subject:  claims.Subject,
+		Role:     claims.Role,
+		ClientID: claims.ClientID,
+		Scopes:   claims.Scopes(),
+		Issuer:   claims.Issuer,
+		Audience: claims.Audience,
 	}
-	block, _ := pem.Decode(data)
-	if block == nil {
-		return nil, errors.New("invalid Ed private key PEM")
-	}
-	var key any
-	var err error
-	switch block.Type {
-	case "PRIVATE KEY":
-		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
-	default:
-		return nil, fmt.Errorf("unsupported Ed key type: %s", block.Type)
-	}
-	if err != nil {
-		return nil, err
+	if claims.IssuedAt != nil {
+		info.IssuedAt = claims.IssuedAt.Time
 	}
-	edKey, ok := key.(ed25519.PrivateKey)
-	if !ok {
-		return nil, errors.New("not an Ed25519 private key")
+	if claims.ExpiresAt != nil {
+		info.ExpiresAt = claims.ExpiresAt.Time
 	}
-	return edKey, nil
+	return info, nil
 }
 
-func loadEdPublicKeys(dir string) (map[string]ed25519.PublicKey, error) {
-	out := make(map[string]ed25519.PublicKey)
-	if dir == "" {
-		return out, nil
-	}
-	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil || d.IsDir() {
-			return err
-		}
-		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
-		b, err := os.ReadFile(path)
-		if err != nil {
-			return err
-		}
-		block, _ := pem.Decode(b)
-		if block == nil {
-			return fmt.Errorf("invalid PEM in %s", path)
-		}
-		if block.Type != "PUBLIC KEY" {
-			return fmt.Errorf("unsupported public key type in %s: %s", path, block.Type)
-		}
-		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
-		if err != nil {
-			return err
-		}
-		k, ok := pub.(ed25519.PublicKey)
-		if !ok {
-			return fmt.Errorf("non-Ed25519 public key in %s", path)
-		}
-		out[name] = k
-		return nil
-	})
-	return out, err
-}
+var _ ports.AccessTokenVerifier = (*jwtService)(nil)
@@ -0,0 +1,32 @@
+package security
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGenerateClientToken_CarriesClientAndScopes(t *testing.T) {
+	svc := NewJWTService("test-secret", 60)
+	tok, err := svc.GenerateClientToken("svc-billing", []string{"users:read", "tokens:revoke"}, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	claims, err := svc.ValidateToken(tok)
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if !claims.IsClient() || claims.Subject != "svc-billing" || claims.Role != "" {
+		t.Fatalf("unexpected client claims: %+v", claims)
+	}
+	if got := claims.Scopes(); len(got) != 2 || got[0] != "users:read" || got[1] != "tokens:revoke" {
+		t.Fatalf("unexpected scopes %v", got)
+	}
+	if ttl := claims.ExpiresAt.Sub(claims.IssuedAt.Time); ttl != 5*time.Minute {
+		t.Fatalf("expected 5m lifetime, got %v", ttl)
+	}
+
+	userTok, _ := svc.GenerateToken("u1", "user")
+	if claims, err := svc.ValidateToken(userTok); err != nil || claims.IsClient() || len(claims.Scopes()) != 0 {
+		t.Fatalf("user token must not look like a client token: %+v %v", claims, err)
+	}
+}
@@ -0,0 +1,236 @@
+package security
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KeyState describes where a key is in its rotation lifecycle.
+//   - next: published and accepted for verification, not yet used for signing (pre-publish before promotion)
+//   - active: the single key used to sign new tokens
+//   - retiring: no longer signs, still verifies tokens issued before rotation until retire_at (if set)
+type KeyState string
+
+const (
+	KeyStateNext     KeyState = "next"
+	KeyStateActive   KeyState = "active"
+	KeyStateRetiring KeyState = "retiring"
+)
+
+func (s KeyState) IsValid() bool {
+	switch s {
+	case KeyStateNext, KeyStateActive, KeyStateRetiring:
+		return true
+	default:
+		return false
+	}
+}
+
+// keySources remembers where keys come from so they can be re-read on reload.
+type keySources struct {
+	kid            string
+	privateKeyPath string
+	privateKeyPEM  string
+	publicKeysDir  string
+	manifestPath   string
+}
+
+// keyring is an immutable snapshot of the asymmetric keys; a reload builds a new one and swaps it in.
+type keyring struct {
+	kid      string // active signing kid
+	private  crypto.Signer
+	publics  map[string]crypto.PublicKey // kid -> key
+	states   map[string]KeyState
+	retireAt map[string]time.Time
+}
+
+// usable reports whether kid may still verify tokens at the given time.
+func (r *keyring) usable(kid string, now time.Time) bool {
+	if t, ok := r.retireAt[kid]; ok && !t.IsZero() && now.After(t) {
+		return false
+	}
+	return true
+}
+
+var emptyKeyring = &keyring{}
+
+// keys returns the current keyring (never nil).
+func (j *jwtService) keys() *keyring {
+	if r := j.ring.Load(); r != nil {
+		return r
+	}
+	return emptyKeyring
+}
+
+// Reload re-reads key material from the configured sources and swaps it in atomically.
+// On any error the previously loaded keys stay in effect, so a bad file never breaks validation.
+func (j *jwtService) Reload() error {
+	var (
+		ring *keyring
+		err  error
+	)
+	if j.alg.symmetric {
+		return nil // HS256: nothing to reload
+	}
+	if j.src.manifestPath != "" {
+		ring, err = loadManifestKeyring(j.alg, j.src.manifestPath)
+	} else {
+		ring, err = j.loadLegacyKeyring()
+	}
+	if err != nil {
+		return err
+	}
+	j.ring.Store(ring)
+	return nil
+}
+
+// KeyStates returns kid -> state for the currently loaded keys (for logging/diagnostics).
+func (j *jwtService) KeyStates() map[string]KeyState {
+	ring := j.keys()
+	out := make(map[string]KeyState, len(ring.states))
+	for k, v := range ring.states {
+		out[k] = v
+	}
+	return out
+}
+
+// loadLegacyKeyring builds a keyring from JWT_PRIVATE_KEY_* (active) and JWT_PUBLIC_KEYS_DIR (verification only).
+func (j *jwtService) loadLegacyKeyring() (*keyring, error) {
+	pk, err := loadPrivateKey(j.alg, j.src.privateKeyPath, j.src.privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	pubs, err := loadPublicKeys(j.alg, j.src.publicKeysDir)
+	if err != nil {
+		return nil, err
+	}
+	ring := &keyring{kid: j.src.kid, private: pk, publics: pubs, states: map[string]KeyState{}, retireAt: map[string]time.Time{}}
+	if ring.kid != "" {
+		if _, ok := pubs[ring.kid]; !ok {
+			pubs[ring.kid] = pk.Public()
+		}
+	}
+	for kid := range pubs {
+		ring.states[kid] = KeyStateRetiring
+	}
+	if ring.kid != "" {
+		ring.states[ring.kid] = KeyStateActive
+	}
+	return ring, nil
+}
+
+// Key manifest format (paths are relative to the manifest file):
+//
+//	keys:
+//	  - kid: "2025-09"
+//	    state: next
+//	    public_key: 2025-09.pub.pem
+//	  - kid: "2025-08"
+//	    state: active
+//	    private_key: 2025-08.pem
+//	  - kid: "2025-07"
+//	    state: retiring
+//	    public_key: 2025-07.pub.pem
+//	    retire_at: 2025-08-08T00:00:00Z
+type keyManifest struct {
+	Keys []keyManifestEntry `yaml:"keys"`
+}
+
+type keyManifestEntry struct {
+	Kid        string    `yaml:"kid"`
+	State      KeyState  `yaml:"state"`
+	PrivateKey string    `yaml:"private_key"`
+	PublicKey  string    `yaml:"public_key"`
+	RetireAt   time.Time `yaml:"retire_at"`
+}
+
+func loadManifestKeyring(alg signingAlgorithm, path string) (*keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key manifest: %w", err)
+	}
+	var m keyManifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse key manifest: %w", err)
+	}
+	base := filepath.Dir(path)
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(base, p)
+	}
+	ring := &keyring{
+		publics:  map[string]crypto.PublicKey{},
+		states:   map[string]KeyState{},
+		retireAt: map[string]time.Time{},
+	}
+	now := time.Now()
+	for _, e := range m.Keys {
+		kid := strings.TrimSpace(e.Kid)
+		if kid == "" {
+			return nil, errors.New("key manifest: entry without kid")
+		}
+		if _, dup := ring.states[kid]; dup {
+			return nil, fmt.Errorf("key manifest: duplicate kid %q", kid)
+		}
+		if !e.State.IsValid() {
+			return nil, fmt.Errorf("key manifest: kid %q has invalid state %q", kid, e.State)
+		}
+		if e.State == KeyStateActive {
+			if ring.kid != "" {
+				return nil, fmt.Errorf("key manifest: more than one active key (%q, %q)", ring.kid, kid)
+			}
+			if e.PrivateKey == "" {
+				return nil, fmt.Errorf("key manifest: active key %q needs private_key", kid)
+			}
+			ring.kid = kid
+		}
+		if e.State == KeyStateRetiring && !e.RetireAt.IsZero() && now.After(e.RetireAt) {
+			continue // fully retired: tokens it signed have expired
+		}
+		if err := ring.addManifestKey(alg, kid, e.State == KeyStateActive, resolve(e.PrivateKey), resolve(e.PublicKey)); err != nil {
+			return nil, fmt.Errorf("key manifest: kid %q: %w", kid, err)
+		}
+		ring.states[kid] = e.State
+		ring.retireAt[kid] = e.RetireAt
+	}
+	if ring.kid == "" {
+		return nil, errors.New("key manifest: no active key")
+	}
+	return ring, nil
+}
+
+// addManifestKey loads one entry. The public key is derived from the private key when public_key is omitted.
+func (r *keyring) addManifestKey(alg signingAlgorithm, kid string, active bool, privPath, pubPath string) error {
+	if privPath == "" && pubPath == "" {
+		return errors.New("private_key or public_key required")
+	}
+	var pub crypto.PublicKey
+	if privPath != "" {
+		priv, err := loadPrivateKey(alg, privPath, "")
+		if err != nil {
+			return err
+		}
+		if active {
+			r.private = priv
+		}
+		pub = priv.Public()
+	}
+	if pubPath != "" {
+		k, err := parsePublicKeyFile(alg, pubPath)
+		if err != nil {
+			return err
+		}
+		pub = k
+	}
+	r.publics[kid] = pub
+	return nil
+}
@@ -0,0 +1,122 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func writeEdKey(t *testing.T, dir, kid string) {
+	t.Helper()
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(priv)
+	writePEM(t, filepath.Join(dir, kid+".pem"), "PRIVATE KEY", der)
+}
+
+func writeManifest(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+}
+
+func TestReload_RotationKeepsRetiringKeyValid(t *testing.T) {
+	dir := t.TempDir()
+	writeEdKey(t, dir, "k1")
+	writeEdKey(t, dir, "k2")
+	manifest := filepath.Join(dir, "keys.yaml")
+	writeManifest(t, manifest, `keys:
+  - kid: k1
+    state: active
+    private_key: k1.pem
+  - kid: k2
+    state: next
+    private_key: k2.pem
+`)
+	svc := NewJWTService("", 60).(*jwtService)
+	svc.SetKeyManifest(manifest)
+	if err := svc.ConfigureAlgorithm("EdDSA", "", "", "", ""); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	oldTok, err := svc.GenerateToken("u1", "user")
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if n := len(svc.PublicKeySet().Keys); n != 2 {
+		t.Fatalf("next key should be published, got %d keys", n)
+	}
+
+	// Promote k2, retire k1
+	writeManifest(t, manifest, `keys:
+  - kid: k2
+    state: active
+    private_key: k2.pem
+  - kid: k1
+    state: retiring
+    private_key: k1.pem
+`)
+	if err := svc.Reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if _, err := svc.ValidateToken(oldTok); err != nil {
+		t.Fatalf("token from retiring key must still validate: %v", err)
+	}
+	newTok, _ := svc.GenerateToken("u1", "user")
+	if _, err := svc.ValidateToken(newTok); err != nil {
+		t.Fatalf("new token: %v", err)
+	}
+	if svc.KeyStates()["k2"] != KeyStateActive {
+		t.Fatalf("k2 should be active: %v", svc.KeyStates())
+	}
+
+	// A broken key file is rejected and the loaded keys stay in effect
+	writeManifest(t, filepath.Join(dir, "k2.pem"), "not a pem")
+	if err := svc.Reload(); err == nil {
+		t.Fatalf("expected reload error for bad key file")
+	}
+	if _, err := svc.ValidateToken(newTok); err != nil {
+		t.Fatalf("previous keys must survive failed reload: %v", err)
+	}
+}
+
+func TestReload_ManifestRequiresSingleActive(t *testing.T) {
+	dir := t.TempDir()
+	writeEdKey(t, dir, "k1")
+	manifest := filepath.Join(dir, "keys.yaml")
+	writeManifest(t, manifest, `keys:
+  - kid: k1
+    state: next
+    private_key: k1.pem
+`)
+	svc := NewJWTService("", 60).(*jwtService)
+	svc.SetKeyManifest(manifest)
+	if err := svc.ConfigureAlgorithm("EdDSA", "", "", "", ""); err == nil {
+		t.Fatalf("expected error without active key")
+	}
+}
+
+func TestVerificationKey_KidlessTokenHonoursRetireAt(t *testing.T) {
+	a, err := lookupAlgorithm("EdDSA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	svc := &jwtService{alg: a}
+	kidless := &jwt.Token{Header: map[string]interface{}{"alg": "EdDSA"}}
+
+	svc.ring.Store(&keyring{publics: map[string]crypto.PublicKey{"k1": pub}, retireAt: map[string]time.Time{"k1": time.Now().Add(time.Hour)}})
+	if _, err := svc.verificationKey(kidless); err != nil {
+		t.Fatalf("key before retire_at must verify: %v", err)
+	}
+	svc.ring.Store(&keyring{publics: map[string]crypto.PublicKey{"k1": pub}, retireAt: map[string]time.Time{"k1": time.Now().Add(-time.Minute)}})
+	if _, err := svc.verificationKey(kidless); err == nil {
+		t.Fatalf("expected the only key to be refused after its retire_at")
+	}
+}
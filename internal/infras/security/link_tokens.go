@@ -0,0 +1,70 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gostartkit/internal/application/ports"
+)
+
+// ErrLinkTokenInvalid is returned for malformed, tampered or expired link tokens.
+var ErrLinkTokenInvalid = errors.New("link token invalid")
+
+// HMACLinkTokens signs link tokens with HMAC-SHA256.
+// Token layout: base64url(subject) "." expiry (unix seconds) "." base64url(mac).
+type HMACLinkTokens struct{ key []byte }
+
+// NewHMACLinkTokens requires a secret of at least 32 bytes.
+func NewHMACLinkTokens(secret string) (*HMACLinkTokens, error) {
+	if len(secret) < 32 {
+		return nil, fmt.Errorf("link signing secret must be at least 32 bytes, got %d", len(secret))
+	}
+	return &HMACLinkTokens{key: []byte(secret)}, nil
+}
+
+func (t *HMACLinkTokens) Sign(purpose, subject string, expiresAt time.Time) (string, error) {
+	if purpose == "" || subject == "" {
+		return "", errors.New("link token: purpose and subject are required")
+	}
+	exp := strconv.FormatInt(expiresAt.Unix(), 10)
+	sub := base64.RawURLEncoding.EncodeToString([]byte(subject))
+	return sub + "." + exp + "." + base64.RawURLEncoding.EncodeToString(t.mac(purpose, subject, exp)), nil
+}
+
+func (t *HMACLinkTokens) Verify(purpose, token string, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrLinkTokenInvalid
+	}
+	subject, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", ErrLinkTokenInvalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(mac, t.mac(purpose, string(subject), parts[1])) {
+		return "", ErrLinkTokenInvalid
+	}
+	exp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || !now.Before(time.Unix(exp, 0)) {
+		return "", ErrLinkTokenInvalid
+	}
+	return string(subject), nil
+}
+
+func (t *HMACLinkTokens) mac(purpose, subject, exp string) []byte {
+	m := hmac.New(sha256.New, t.key)
+	m.Write([]byte(purpose))
+	m.Write([]byte{0})
+	m.Write([]byte(subject))
+	m.Write([]byte{0})
+	m.Write([]byte(exp))
+	return m.Sum(nil)
+}
+
+var _ ports.LinkTokens = (*HMACLinkTokens)(nil)
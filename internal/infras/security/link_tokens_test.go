@@ -0,0 +1,33 @@
+package security
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHMACLinkTokens(t *testing.T) {
+	lt, err := NewHMACLinkTokens("0123456789abcdef0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	tok, err := lt.Sign("verify_email", "user|a@example.com", now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub, err := lt.Verify("verify_email", tok, now); err != nil || sub != "user|a@example.com" {
+		t.Fatalf("verify: %v %q", err, sub)
+	}
+	if _, err := lt.Verify("magic_link", tok, now); err == nil {
+		t.Fatalf("expected token for another purpose to be rejected")
+	}
+	if _, err := lt.Verify("verify_email", tok, now.Add(time.Hour)); err == nil {
+		t.Fatalf("expected expired token to be rejected")
+	}
+	if _, err := lt.Verify("verify_email", "x"+tok, now); err == nil {
+		t.Fatalf("expected tampered token to be rejected")
+	}
+	if _, err := NewHMACLinkTokens("short"); err == nil {
+		t.Fatalf("expected short secret to be rejected")
+	}
+}
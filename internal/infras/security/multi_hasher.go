@@ -0,0 +1,47 @@
+package security
+
+// FormatHasher is a password hasher that can tell its own hashes apart and spot outdated parameters.
+type FormatHasher interface {
+	Hash(raw string) (string, error)
+	Compare(hashed string, raw string) bool
+	NeedsRehash(hashed string) bool
+	Recognizes(hashed string) bool
+}
+
+// MultiHasher hashes new passwords with the primary hasher and verifies stored hashes with whichever
+// hasher recognizes their format, so switching algorithms does not lock out existing users.
+// NeedsRehash is true for hashes of a legacy algorithm or with outdated primary parameters.
+type MultiHasher struct {
+	primary FormatHasher
+	legacy  []FormatHasher
+}
+
+func NewMultiHasher(primary FormatHasher, legacy ...FormatHasher) *MultiHasher {
+	return &MultiHasher{primary: primary, legacy: legacy}
+}
+
+func (m *MultiHasher) Hash(raw string) (string, error) { return m.primary.Hash(raw) }
+
+func (m *MultiHasher) Compare(hashed string, raw string) bool {
+	h := m.hasherFor(hashed)
+	return h != nil && h.Compare(hashed, raw)
+}
+
+func (m *MultiHasher) NeedsRehash(hashed string) bool {
+	if !m.primary.Recognizes(hashed) {
+		return true
+	}
+	return m.primary.NeedsRehash(hashed)
+}
+
+func (m *MultiHasher) hasherFor(hashed string) FormatHasher {
+	if m.primary.Recognizes(hashed) {
+		return m.primary
+	}
+	for _, h := range m.legacy {
+		if h.Recognizes(hashed) {
+			return h
+		}
+	}
+	return nil
+}
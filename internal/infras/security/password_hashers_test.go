@@ -0,0 +1,67 @@
+package security
+
+import (
+	"strings"
+	"testing"
+)
+
+// Small parameters keep the tests fast; production uses DefaultArgon2Params.
+var testArgon2 = Argon2Params{MemoryKiB: 1024, Iterations: 1, Parallelism: 1}
+
+func TestArgon2idHasher_HashCompareAndParams(t *testing.T) {
+	h := NewArgon2idHasher(testArgon2)
+	long := strings.Repeat("a", 100)
+	hashed, err := h.Hash(long)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hashed, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Fatalf("unexpected format %q", hashed)
+	}
+	if !h.Compare(hashed, long) {
+		t.Fatalf("expected password to match")
+	}
+	// Unlike bcrypt, bytes past 72 count
+	if h.Compare(hashed, strings.Repeat("a", 99)+"b") {
+		t.Fatalf("expected a different long password to fail")
+	}
+	if h.NeedsRehash(hashed) {
+		t.Fatalf("fresh hash should not need a rehash")
+	}
+	stronger := NewArgon2idHasher(Argon2Params{MemoryKiB: 2048, Iterations: 1, Parallelism: 1})
+	if !stronger.NeedsRehash(hashed) || !stronger.Compare(hashed, long) {
+		t.Fatalf("expected old parameters to verify but need a rehash")
+	}
+	if h.Compare("$argon2id$v=19$m=1024,t=1,p=1$bad$bad", long) {
+		t.Fatalf("expected malformed hash to fail")
+	}
+}
+
+func TestMultiHasher_UpgradesBcrypt(t *testing.T) {
+	bc := NewBcryptHasher(4)
+	legacy, err := bc.Hash("s3cret-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMultiHasher(NewArgon2idHasher(testArgon2), bc)
+	if !m.Compare(legacy, "s3cret-pass") || m.Compare(legacy, "wrong") {
+		t.Fatalf("expected bcrypt hashes to keep verifying")
+	}
+	if !m.NeedsRehash(legacy) {
+		t.Fatalf("expected bcrypt hash to need an upgrade")
+	}
+	upgraded, err := m.Hash("s3cret-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.NeedsRehash(upgraded) || !m.Compare(upgraded, "s3cret-pass") {
+		t.Fatalf("expected new hash to be current: %q", upgraded)
+	}
+	if m.Compare("plain-text", "plain-text") {
+		t.Fatalf("unrecognized formats must not verify")
+	}
+	// bcrypt as primary flags a cost change
+	if !NewMultiHasher(NewBcryptHasher(5)).NeedsRehash(legacy) {
+		t.Fatalf("expected cost change to need a rehash")
+	}
+}
@@ -0,0 +1,58 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AESGCMBox encrypts small secrets (e.g. TOTP seeds) with AES-256-GCM.
+// Ciphertext layout: version byte (1) | 12-byte nonce | sealed data.
+type AESGCMBox struct{ aead cipher.AEAD }
+
+const secretBoxVersion = 1
+
+// NewAESGCMBox takes a base64 (standard or URL) encoded 32-byte key, e.g. from `openssl rand -base64 32`.
+func NewAESGCMBox(encodedKey string) (*AESGCMBox, error) {
+	encodedKey = strings.TrimSpace(encodedKey)
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		key, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(encodedKey, "="))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode encryption key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCMBox{aead: aead}, nil
+}
+
+func (b *AESGCMBox) Seal(plaintext []byte) ([]byte, error) {
+	out := make([]byte, 1+b.aead.NonceSize(), 1+b.aead.NonceSize()+len(plaintext)+b.aead.Overhead())
+	out[0] = secretBoxVersion
+	if _, err := rand.Read(out[1:]); err != nil {
+		return nil, err
+	}
+	return b.aead.Seal(out, out[1:], plaintext, nil), nil
+}
+
+func (b *AESGCMBox) Open(ciphertext []byte) ([]byte, error) {
+	n := b.aead.NonceSize()
+	if len(ciphertext) < 1+n || ciphertext[0] != secretBoxVersion {
+		return nil, errors.New("secretbox: malformed ciphertext")
+	}
+	return b.aead.Open(nil, ciphertext[1:1+n], ciphertext[1+n:], nil)
+}
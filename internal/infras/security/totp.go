@@ -0,0 +1,77 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP implements RFC 6238 with the parameters every authenticator app supports:
+// HMAC-SHA1, 6 digits, 30 second steps. One step of clock drift is accepted either way.
+type TOTP struct {
+	period int64
+	digits int
+	skew   int64
+}
+
+func NewTOTP() *TOTP { return &TOTP{period: 30, digits: 6, skew: 1} }
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a 160-bit secret (the RFC 4226 recommended length) in base32.
+func (t *TOTP) GenerateSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// KeyURI follows the Key Uri Format used by Google Authenticator and compatible apps.
+func (t *TOTP) KeyURI(issuer, account, secret string) string {
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(t.digits))
+	q.Set("period", fmt.Sprint(t.period))
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	return "otpauth://totp/" + label + "?" + q.Encode()
+}
+
+func (t *TOTP) Verify(secret, code string, at time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(code) != t.digits {
+		return 0, false
+	}
+	current := at.Unix() / t.period
+	for step := current - t.skew; step <= current+t.skew; step++ {
+		if subtle.ConstantTimeCompare([]byte(t.code(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// code computes the HOTP value (RFC 4226 §5.3) for counter step.
+func (t *TOTP) code(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	bin := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < t.digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", t.digits, bin%mod)
+}
@@ -0,0 +1,68 @@
+package security
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// RFC 6238 Appendix B test vectors (SHA-1, 8 digits) for the ASCII key "12345678901234567890".
+func TestTOTP_RFC6238Vectors(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	totp := &TOTP{period: 30, digits: 8, skew: 0}
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+	}
+	for _, c := range cases {
+		step, ok := totp.Verify(secret, c.code, time.Unix(c.unix, 0))
+		if !ok || step != c.unix/30 {
+			t.Fatalf("t=%d: expected %s to verify (step=%d ok=%v)", c.unix, c.code, step, ok)
+		}
+	}
+}
+
+func TestTOTP_SkewAndRejection(t *testing.T) {
+	totp := NewTOTP()
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, _ := totpEncoding.DecodeString(secret)
+	now := time.Unix(1_700_000_000, 0)
+	prev := totp.code(key, now.Unix()/30-1)
+	if _, ok := totp.Verify(secret, prev, now); !ok {
+		t.Fatalf("expected previous step to be accepted within skew")
+	}
+	old := totp.code(key, now.Unix()/30-3)
+	if _, ok := totp.Verify(secret, old, now); ok {
+		t.Fatalf("expected code outside skew to be rejected")
+	}
+}
+
+func TestAESGCMBox_RoundTrip(t *testing.T) {
+	box, err := NewAESGCMBox("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, err := box.Seal([]byte("JBSWY3DPEHPK3PXP"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := box.Open(ct)
+	if err != nil || string(pt) != "JBSWY3DPEHPK3PXP" {
+		t.Fatalf("open: %v %q", err, pt)
+	}
+	ct[len(ct)-1] ^= 1
+	if _, err := box.Open(ct); err == nil {
+		t.Fatalf("expected tampered ciphertext to fail")
+	}
+	if _, err := NewAESGCMBox("c2hvcnQ="); err == nil {
+		t.Fatalf("expected short key to be rejected")
+	}
+}
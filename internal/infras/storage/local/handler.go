@@ -0,0 +1,108 @@
+package local
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+)
+
+// WithMaxUploadBytes caps bodies accepted by presigned PUT URLs (0 = no limit).
+func (s *Storage) WithMaxUploadBytes(n int64) *Storage {
+	s.maxUpload = n
+	return s
+}
+
+// ServeHTTP serves presigned URLs: GET/HEAD download and PUT uploads the object named by the request path,
+// which is relative to the base URL (mount it with http.StripPrefix). Anything without a valid, unexpired
+// signature for that method and key gets 403.
+func (s *Storage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	if ports.CheckObjectKey(key) != nil {
+		http.Error(w, "invalid object key", http.StatusBadRequest)
+		return
+	}
+	method := r.Method
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	if method != http.MethodGet && method != http.MethodPut {
+		w.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	q := r.URL.Query()
+	if !s.verify(method, key, q.Get("expires"), q.Get("signature"), time.Now()) {
+		http.Error(w, "invalid or expired signature", http.StatusForbidden)
+		return
+	}
+	if method == http.MethodPut {
+		s.serveUpload(w, r, key)
+		return
+	}
+	s.serveDownload(w, r, key)
+}
+
+// serveDownload always sends the object as an attachment the browser may not sniff: uploaded content types are
+// chosen by whoever holds a PUT link, so rendering them inline on this origin would allow stored XSS.
+func (s *Storage) serveDownload(w http.ResponseWriter, r *http.Request, key string) {
+	info, err := s.Stat(r.Context(), key)
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	f, err := s.root.Open(path.Join(objectsDir, key))
+	if err != nil {
+		writeStorageError(w, mapNotExist(err))
+		return
+	}
+	defer f.Close()
+	w.Header().Set("Content-Type", info.ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(key)}))
+	w.Header().Set("Cache-Control", "private, no-store")
+	if info.ETag != "" {
+		w.Header().Set("ETag", info.ETag)
+	}
+	http.ServeContent(w, r, path.Base(key), info.ModTime, f)
+}
+
+func (s *Storage) serveUpload(w http.ResponseWriter, r *http.Request, key string) {
+	body := r.Body
+	if s.maxUpload > 0 {
+		if r.ContentLength > s.maxUpload {
+			http.Error(w, "upload too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		body = http.MaxBytesReader(w, r.Body, s.maxUpload)
+	}
+	if _, err := s.Put(r.Context(), key, body, r.ContentLength, r.Header.Get("Content-Type")); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "upload too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		writeStorageError(w, err)
+		return
+	}
+	if info, err := s.Stat(r.Context(), key); err == nil {
+		w.Header().Set("ETag", info.ETag)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func writeStorageError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperr.ErrObjectNotFound):
+		http.Error(w, "object not found", http.StatusNotFound)
+	case errors.Is(err, apperr.ErrInvalidObjectKey):
+		http.Error(w, "invalid object key", http.StatusBadRequest)
+	default:
+		http.Error(w, "storage error", http.StatusInternalServerError)
+	}
+}
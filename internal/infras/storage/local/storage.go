@@ -0,0 +1,333 @@
+// Package local stores objects on the local filesystem and serves them through signed, expiring URLs.
+package local
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+)
+
+// Layout under the root directory: object bodies, their metadata, and partially written uploads.
+const (
+	objectsDir = "objects"
+	metaDir    = "meta"
+	tmpDir     = "tmp"
+)
+
+// Storage implements ports.ObjectStorage on a directory. Keys are checked with ports.CheckObjectKey and all
+// file access goes through an os.Root, so neither ".." nor symlinks can reach outside the directory.
+// Downloads and uploads by URL are served by ServeHTTP (see handler.go).
+type Storage struct {
+	root    *os.Root
+	baseURL string
+	secret  []byte
+	// maxUpload caps presigned PUT bodies (0 = no limit)
+	maxUpload int64
+}
+
+// New opens (creating if needed) dir as the storage root. baseURL is the public URL where ServeHTTP is
+// mounted, e.g. https://api.example.com/v1/files; secret (at least 32 bytes) signs the URLs.
+func New(dir, baseURL string, secret []byte) (*Storage, error) {
+	if len(secret) < 32 {
+		return nil, errors.New("local storage: signing secret must be at least 32 bytes")
+	}
+	if _, err := url.Parse(baseURL); err != nil || baseURL == "" {
+		return nil, fmt.Errorf("local storage: invalid base URL %q", baseURL)
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range []string{objectsDir, metaDir, tmpDir} {
+		if err := os.MkdirAll(filepath.Join(abs, sub), 0o700); err != nil {
+			return nil, fmt.Errorf("local storage: %w", err)
+		}
+	}
+	root, err := os.OpenRoot(abs)
+	if err != nil {
+		return nil, fmt.Errorf("local storage: %w", err)
+	}
+	return &Storage{root: root, baseURL: strings.TrimRight(baseURL, "/"), secret: secret}, nil
+}
+
+// Close releases the root directory handle.
+func (s *Storage) Close() error { return s.root.Close() }
+
+// meta is stored as JSON next to the object body, under metaDir.
+type meta struct {
+	ContentType string `json:"content_type"`
+	ETag        string `json:"etag"`
+}
+
+func (s *Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	if err := ports.CheckObjectKey(key); err != nil {
+		return "", err
+	}
+	tmpName, err := randomName()
+	if err != nil {
+		return "", err
+	}
+	tmp := path.Join(tmpDir, tmpName)
+	f, err := s.root.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = s.root.Remove(tmp) }()
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), ctxReader{ctx, r})
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+	if size >= 0 && n != size {
+		return "", fmt.Errorf("local storage: got %d bytes, expected %d", n, size)
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	m, err := json.Marshal(meta{ContentType: contentType, ETag: `"` + hex.EncodeToString(h.Sum(nil)) + `"`})
+	if err != nil {
+		return "", err
+	}
+	if err := s.mkdirParents(key); err != nil {
+		return "", err
+	}
+	if err := s.writeFile(path.Join(metaDir, key), m); err != nil {
+		return "", err
+	}
+	if err := s.root.Rename(tmp, path.Join(objectsDir, key)); err != nil {
+		return "", err
+	}
+	return s.baseURL + "/" + escapeKey(key), nil
+}
+
+func (s *Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := ports.CheckObjectKey(key); err != nil {
+		return nil, err
+	}
+	f, err := s.root.Open(path.Join(objectsDir, key))
+	if err != nil {
+		return nil, mapNotExist(err)
+	}
+	if fi, err := f.Stat(); err != nil || fi.IsDir() {
+		_ = f.Close()
+		return nil, apperr.ErrObjectNotFound
+	}
+	return f, nil
+}
+
+func (s *Storage) Delete(ctx context.Context, key string) error {
+	if err := ports.CheckObjectKey(key); err != nil {
+		return err
+	}
+	if err := s.root.Remove(path.Join(objectsDir, key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := s.root.Remove(path.Join(metaDir, key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	// Drop directories left empty; Remove fails on the first one that still has entries
+	for dir := path.Dir(key); dir != "."; dir = path.Dir(dir) {
+		if s.root.Remove(path.Join(objectsDir, dir)) != nil {
+			break
+		}
+		_ = s.root.Remove(path.Join(metaDir, dir))
+	}
+	return nil
+}
+
+func (s *Storage) Stat(ctx context.Context, key string) (ports.ObjectInfo, error) {
+	if err := ports.CheckObjectKey(key); err != nil {
+		return ports.ObjectInfo{}, err
+	}
+	fi, err := s.root.Stat(path.Join(objectsDir, key))
+	if err != nil {
+		return ports.ObjectInfo{}, mapNotExist(err)
+	}
+	if fi.IsDir() {
+		return ports.ObjectInfo{}, apperr.ErrObjectNotFound
+	}
+	return s.info(key, fi), nil
+}
+
+func (s *Storage) List(ctx context.Context, prefix string) ([]ports.ObjectInfo, error) {
+	var out []ports.ObjectInfo
+	err := fs.WalkDir(s.root.FS(), objectsDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		key := strings.TrimPrefix(p, objectsDir+"/")
+		if d.IsDir() {
+			// Skip subtrees that cannot contain a match
+			if p != objectsDir && !strings.HasPrefix(key+"/", prefix) && !strings.HasPrefix(prefix, key+"/") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		out = append(out, s.info(key, fi))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+func (s *Storage) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.presign("GET", key, ttl)
+}
+
+func (s *Storage) PresignPut(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.presign("PUT", key, ttl)
+}
+
+func (s *Storage) presign(method, key string, ttl time.Duration) (string, error) {
+	if err := ports.CheckObjectKey(key); err != nil {
+		return "", err
+	}
+	if ttl <= 0 {
+		return "", errors.New("local storage: presign ttl must be positive")
+	}
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	q := url.Values{"expires": {expires}, "signature": {s.sign(method, key, expires)}}
+	return s.baseURL + "/" + escapeKey(key) + "?" + q.Encode(), nil
+}
+
+// sign binds the method, key and expiry, so a download URL cannot be reused for an upload or another key.
+func (s *Storage) sign(method, key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(method + "\n" + key + "\n" + expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks a presigned URL's signature and expiry.
+func (s *Storage) verify(method, key, expires, signature string, now time.Time) bool {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > exp {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(s.sign(method, key, expires)))
+}
+
+func (s *Storage) info(key string, fi fs.FileInfo) ports.ObjectInfo {
+	info := ports.ObjectInfo{Key: key, Size: fi.Size(), ModTime: fi.ModTime().UTC()}
+	if b, err := fs.ReadFile(s.root.FS(), path.Join(metaDir, key)); err == nil {
+		var m meta
+		if json.Unmarshal(b, &m) == nil {
+			info.ContentType, info.ETag = m.ContentType, m.ETag
+		}
+	}
+	return info
+}
+
+// mkdirParents creates the parent directories of key under objectsDir and metaDir, refusing existing
+// parents that are not plain directories.
+func (s *Storage) mkdirParents(key string) error {
+	for _, base := range []string{objectsDir, metaDir} {
+		dir := base
+		for _, seg := range strings.Split(path.Dir(key), "/") {
+			if seg == "." {
+				break
+			}
+			dir = path.Join(dir, seg)
+			err := s.root.Mkdir(dir, 0o700)
+			if errors.Is(err, fs.ErrExist) {
+				// Parents must be real directories, not symlinks, even ones that point back inside the root
+				fi, lerr := s.root.Lstat(dir)
+				if lerr != nil {
+					return lerr
+				}
+				if !fi.IsDir() {
+					return fmt.Errorf("local storage: %s is not a directory", dir)
+				}
+				err = nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Storage) writeFile(name string, data []byte) error {
+	f, err := s.root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func mapNotExist(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return apperr.ErrObjectNotFound
+	}
+	return err
+}
+
+func escapeKey(key string) string {
+	segs := strings.Split(key, "/")
+	for i, seg := range segs {
+		segs[i] = url.PathEscape(seg)
+	}
+	return strings.Join(segs, "/")
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ctxReader stops a long copy once the context is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c ctxReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+var _ ports.ObjectStorage = (*Storage)(nil)
@@ -0,0 +1,131 @@
+package local_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/application/ports/portstest"
+	"gostartkit/internal/infras/storage/local"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+// newServed returns a store whose signed URLs are served by a test server.
+func newServed(t *testing.T, dir string) *local.Storage {
+	t.Helper()
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	store, err := local.New(dir, srv.URL+"/files", secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	mux.Handle("/files/", http.StripPrefix("/files", store))
+	return store
+}
+
+func TestLocalStorage_Conformance(t *testing.T) {
+	store := newServed(t, t.TempDir())
+	portstest.TestObjectStorage(t, func(t *testing.T) ports.ObjectStorage { return store })
+}
+
+func TestLocalStorage_RefusesTamperedAndExpiredURLs(t *testing.T) {
+	store := newServed(t, t.TempDir())
+	ctx := context.Background()
+	for _, k := range []string{"a.txt", "b.txt"} {
+		if _, err := store.Put(ctx, k, strings.NewReader(k), -1, "text/plain"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	signed, _ := store.PresignGet(ctx, "a.txt", time.Minute)
+	other := strings.Replace(signed, "/a.txt?", "/b.txt?", 1)
+	u, _ := url.Parse(signed)
+	q := u.Query()
+	q.Set("expires", "9999999999")
+	u.RawQuery = q.Encode()
+	expired, _ := store.PresignGet(ctx, "a.txt", time.Nanosecond)
+	time.Sleep(1100 * time.Millisecond)
+
+	for name, link := range map[string]string{"other key": other, "extended expiry": u.String(), "expired": expired} {
+		resp, err := http.Get(link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("%s: expected 403, got %d", name, resp.StatusCode)
+		}
+	}
+	traversal := strings.Replace(signed, "/a.txt?", "/..%2Fsecret?", 1)
+	resp, err := http.Get(traversal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected a traversal key refused, got %d", resp.StatusCode)
+	}
+}
+
+func TestLocalStorage_StaysInsideRoot(t *testing.T) {
+	base := t.TempDir()
+	outside := filepath.Join(base, "outside")
+	if err := os.MkdirAll(outside, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store := newServed(t, filepath.Join(base, "root"))
+	// A symlink planted inside the root must not lead out of it
+	if err := os.Symlink(outside, filepath.Join(base, "root", "objects", "link")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := store.Get(ctx, "link/secret.txt"); err == nil {
+		t.Fatalf("expected reads through an escaping symlink to fail")
+	}
+	if _, err := store.Put(ctx, "link/new.txt", strings.NewReader("x"), 1, ""); err == nil {
+		t.Fatalf("expected writes through an escaping symlink to fail")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "new.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("file written outside the root: %v", err)
+	}
+	if _, err := store.Get(ctx, "../outside/secret.txt"); !errors.Is(err, apperr.ErrInvalidObjectKey) {
+		t.Fatalf("expected ErrInvalidObjectKey, got %v", err)
+	}
+}
+
+func TestLocalStorage_DownloadsAreNeverRenderedInline(t *testing.T) {
+	store := newServed(t, t.TempDir())
+	ctx := context.Background()
+	if _, err := store.Put(ctx, "uploads/page.html", strings.NewReader("<script>alert(1)</script>"), -1, "text/html"); err != nil {
+		t.Fatal(err)
+	}
+	link, _ := store.PresignGet(ctx, "uploads/page.html", time.Minute)
+	resp, err := http.Get(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Fatalf("X-Content-Type-Options = %q", got)
+	}
+	if got := resp.Header.Get("Content-Disposition"); got != `attachment; filename=page.html` {
+		t.Fatalf("Content-Disposition = %q", got)
+	}
+}
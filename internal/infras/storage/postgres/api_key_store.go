@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+	pstore "gostartkit/internal/infras/storage/postgres/sqlc"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// APIKeyStore implements ports.APIKeyStore on the api_keys table.
+type APIKeyStore struct {
+	q *pstore.Queries
+}
+
+func NewAPIKeyStore(pool *pgxpool.Pool) *APIKeyStore {
+	return &APIKeyStore{q: pstore.New(pool)}
+}
+
+func (s *APIKeyStore) Create(ctx context.Context, k ports.APIKey) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	return s.q.CreateAPIKey(cctx, pstore.CreateAPIKeyParams{
+		ID:         k.ID,
+		UserID:     k.UserID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		SecretHash: k.SecretHash,
+		Scopes:     nonNilScopes(k.Scopes),
+		ExpiresAt:  nullableTime(k.ExpiresAt),
+		CreatedAt:  k.CreatedAt.UTC(),
+	})
+}
+
+func (s *APIKeyStore) GetByPrefix(ctx context.Context, prefix string) (ports.APIKey, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	row, err := s.q.GetAPIKeyByPrefix(cctx, prefix)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ports.APIKey{}, apperr.ErrAPIKeyNotFound
+		}
+		return ports.APIKey{}, err
+	}
+	return toAPIKey(row), nil
+}
+
+func (s *APIKeyStore) Get(ctx context.Context, userID, id uuid.UUID) (ports.APIKey, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	row, err := s.q.GetAPIKey(cctx, pstore.GetAPIKeyParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ports.APIKey{}, apperr.ErrAPIKeyNotFound
+		}
+		return ports.APIKey{}, err
+	}
+	return toAPIKey(row), nil
+}
+
+func (s *APIKeyStore) ListByUser(ctx context.Context, userID uuid.UUID) ([]ports.APIKey, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	rows, err := s.q.ListAPIKeysByUser(cctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.APIKey, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toAPIKey(row))
+	}
+	return out, nil
+}
+
+func (s *APIKeyStore) Update(ctx context.Context, userID, id uuid.UUID, name string, scopes []string) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	n, err := s.q.UpdateAPIKey(cctx, pstore.UpdateAPIKeyParams{ID: id, UserID: userID, Name: name, Scopes: nonNilScopes(scopes)})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperr.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (s *APIKeyStore) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	n, err := s.q.DeleteAPIKey(cctx, pstore.DeleteAPIKeyParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperr.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (s *APIKeyStore) Touch(ctx context.Context, id uuid.UUID, at time.Time) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	return s.q.TouchAPIKey(cctx, pstore.TouchAPIKeyParams{ID: id, LastUsedAt: pgtype.Timestamptz{Time: at.UTC(), Valid: true}})
+}
+
+func toAPIKey(row pstore.ApiKey) ports.APIKey {
+	k := ports.APIKey{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		Name:       row.Name,
+		Prefix:     row.Prefix,
+		SecretHash: row.SecretHash,
+		Scopes:     row.Scopes,
+		CreatedAt:  row.CreatedAt,
+	}
+	if row.ExpiresAt.Valid {
+		t := row.ExpiresAt.Time
+		k.ExpiresAt = &t
+	}
+	if row.LastUsedAt.Valid {
+		t := row.LastUsedAt.Time
+		k.LastUsedAt = &t
+	}
+	return k
+}
+
+// nonNilScopes keeps the NOT NULL scopes column happy: a nil slice would be sent as NULL.
+func nonNilScopes(scopes []string) []string {
+	if scopes == nil {
+		return []string{}
+	}
+	return scopes
+}
+
+var _ ports.APIKeyStore = (*APIKeyStore)(nil)
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gostartkit/internal/application/ports"
+	domuser "gostartkit/internal/domain/user"
+	pstore "gostartkit/internal/infras/storage/postgres/sqlc"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IdentityStore implements ports.ExternalIdentityStore on the user_identities table.
+type IdentityStore struct {
+	q *pstore.Queries
+}
+
+func NewIdentityStore(pool *pgxpool.Pool) *IdentityStore {
+	return &IdentityStore{q: pstore.New(pool)}
+}
+
+func (s *IdentityStore) FindUserID(ctx context.Context, provider, subject string) (uuid.UUID, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	id, err := s.q.GetUserIdentityUserID(cctx, pstore.GetUserIdentityUserIDParams{Provider: provider, Subject: subject})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, domuser.ErrUserNotFound
+		}
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+// Link records the identity; linking an already linked (provider, subject) is a no-op.
+func (s *IdentityStore) Link(ctx context.Context, provider, subject string, userID uuid.UUID, email string) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	return s.q.CreateUserIdentity(cctx, pstore.CreateUserIdentityParams{
+		Provider:  provider,
+		Subject:   subject,
+		UserID:    userID,
+		Email:     email,
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+func (s *IdentityStore) ListByUser(ctx context.Context, userID uuid.UUID) ([]ports.ExternalIdentity, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	rows, err := s.q.ListUserIdentitiesByUser(cctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.ExternalIdentity, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, ports.ExternalIdentity{Provider: row.Provider, Subject: row.Subject, Email: row.Email, CreatedAt: row.CreatedAt})
+	}
+	return out, nil
+}
+
+var _ ports.ExternalIdentityStore = (*IdentityStore)(nil)
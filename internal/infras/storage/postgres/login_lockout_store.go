@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gostartkit/internal/application/ports"
+	pstore "gostartkit/internal/infras/storage/postgres/sqlc"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LoginLockoutStore implements ports.LoginLockoutStore on the login_lockouts table.
+type LoginLockoutStore struct {
+	q *pstore.Queries
+}
+
+func NewLoginLockoutStore(pool *pgxpool.Pool) *LoginLockoutStore {
+	return &LoginLockoutStore{q: pstore.New(pool)}
+}
+
+func (s *LoginLockoutStore) Get(ctx context.Context, userID uuid.UUID) (ports.LoginLockout, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	row, err := s.q.GetLoginLockout(cctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ports.LoginLockout{}, nil
+		}
+		return ports.LoginLockout{}, err
+	}
+	return toLoginLockout(row), nil
+}
+
+func (s *LoginLockoutStore) RecordFailure(ctx context.Context, userID uuid.UUID, at, windowStart time.Time) (ports.LoginLockout, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	row, err := s.q.RecordLoginFailure(cctx, pstore.RecordLoginFailureParams{
+		UserID:       userID,
+		LastFailedAt: at.UTC(),
+		WindowStart:  windowStart.UTC(),
+	})
+	if err != nil {
+		return ports.LoginLockout{}, err
+	}
+	return toLoginLockout(row), nil
+}
+
+func (s *LoginLockoutStore) Lock(ctx context.Context, userID uuid.UUID, until time.Time) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	return s.q.LockLogin(cctx, pstore.LockLoginParams{UserID: userID, LockedUntil: pgtype.Timestamptz{Time: until.UTC(), Valid: true}})
+}
+
+func (s *LoginLockoutStore) Reset(ctx context.Context, userID uuid.UUID) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	_, err := s.q.DeleteLoginLockout(cctx, userID)
+	return err
+}
+
+func toLoginLockout(row pstore.LoginLockout) ports.LoginLockout {
+	out := ports.LoginLockout{FailedAttempts: int(row.FailedAttempts), LastFailedAt: row.LastFailedAt}
+	if row.LockedUntil.Valid {
+		out.LockedUntil = row.LockedUntil.Time
+	}
+	return out
+}
+
+var _ ports.LoginLockoutStore = (*LoginLockoutStore)(nil)
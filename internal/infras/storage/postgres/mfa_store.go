@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+	pstore "gostartkit/internal/infras/storage/postgres/sqlc"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MFAStore implements ports.MFAStore on the user_mfa and user_mfa_recovery_codes tables.
+type MFAStore struct {
+	pool *pgxpool.Pool
+	q    *pstore.Queries
+}
+
+func NewMFAStore(pool *pgxpool.Pool) *MFAStore {
+	return &MFAStore{pool: pool, q: pstore.New(pool)}
+}
+
+func (s *MFAStore) Get(ctx context.Context, userID uuid.UUID) (ports.MFAEnrollment, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	row, err := s.q.GetUserMFA(cctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ports.MFAEnrollment{}, apperr.ErrMFANotEnrolled
+		}
+		return ports.MFAEnrollment{}, err
+	}
+	return ports.MFAEnrollment{
+		UserID:           row.UserID,
+		SecretCiphertext: row.SecretCiphertext,
+		Confirmed:        row.ConfirmedAt.Valid,
+		LastUsedStep:     row.LastUsedStep,
+	}, nil
+}
+
+func (s *MFAStore) SavePending(ctx context.Context, userID uuid.UUID, secretCiphertext []byte) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	return s.q.UpsertPendingUserMFA(cctx, pstore.UpsertPendingUserMFAParams{
+		UserID:           userID,
+		SecretCiphertext: secretCiphertext,
+		CreatedAt:        time.Now().UTC(),
+	})
+}
+
+// Confirm activates the enrollment and replaces the recovery codes in one transaction.
+func (s *MFAStore) Confirm(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes [][]byte) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	now := time.Now().UTC()
+	return pgx.BeginFunc(cctx, s.pool, func(tx pgx.Tx) error {
+		q := s.q.WithTx(tx)
+		n, err := q.ConfirmUserMFA(cctx, pstore.ConfirmUserMFAParams{
+			UserID:       userID,
+			ConfirmedAt:  pgtype.Timestamptz{Time: now, Valid: true},
+			LastUsedStep: step,
+			UpdatedAt:    now,
+		})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return apperr.ErrMFAAlreadyEnabled
+		}
+		if err := q.DeleteMFARecoveryCodes(cctx, userID); err != nil {
+			return err
+		}
+		for _, h := range recoveryCodeHashes {
+			if err := q.CreateMFARecoveryCode(cctx, pstore.CreateMFARecoveryCodeParams{UserID: userID, CodeHash: h, CreatedAt: now}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *MFAStore) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	n, err := s.q.UseUserMFAStep(cctx, pstore.UseUserMFAStepParams{UserID: userID, LastUsedStep: step})
+	return n > 0, err
+}
+
+func (s *MFAStore) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash []byte) (bool, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	n, err := s.q.UseMFARecoveryCode(cctx, pstore.UseMFARecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: codeHash,
+		UsedAt:   pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+	})
+	return n > 0, err
+}
+
+func (s *MFAStore) Delete(ctx context.Context, userID uuid.UUID) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	return pgx.BeginFunc(cctx, s.pool, func(tx pgx.Tx) error {
+		q := s.q.WithTx(tx)
+		if err := q.DeleteMFARecoveryCodes(cctx, userID); err != nil {
+			return err
+		}
+		return q.DeleteUserMFA(cctx, userID)
+	})
+}
+
+var _ ports.MFAStore = (*MFAStore)(nil)
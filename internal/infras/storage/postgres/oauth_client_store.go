@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+	pstore "gostartkit/internal/infras/storage/postgres/sqlc"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OAuthClientStore implements ports.OAuthClientStore on the oauth_clients table.
+type OAuthClientStore struct {
+	q *pstore.Queries
+}
+
+func NewOAuthClientStore(pool *pgxpool.Pool) *OAuthClientStore {
+	return &OAuthClientStore{q: pstore.New(pool)}
+}
+
+func (s *OAuthClientStore) Create(ctx context.Context, c ports.OAuthClient) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	return s.q.CreateOAuthClient(cctx, pstore.CreateOAuthClientParams{
+		ClientID:   c.ClientID,
+		Name:       c.Name,
+		SecretHash: c.SecretHash,
+		Scopes:     nonNilScopes(c.Scopes),
+		CreatedAt:  c.CreatedAt.UTC(),
+	})
+}
+
+func (s *OAuthClientStore) Get(ctx context.Context, clientID string) (ports.OAuthClient, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	row, err := s.q.GetOAuthClient(cctx, clientID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ports.OAuthClient{}, apperr.ErrOAuthClientNotFound
+		}
+		return ports.OAuthClient{}, err
+	}
+	return toOAuthClient(row), nil
+}
+
+func (s *OAuthClientStore) List(ctx context.Context) ([]ports.OAuthClient, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	rows, err := s.q.ListOAuthClients(cctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.OAuthClient, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toOAuthClient(row))
+	}
+	return out, nil
+}
+
+func (s *OAuthClientStore) Delete(ctx context.Context, clientID string) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	n, err := s.q.DeleteOAuthClient(cctx, clientID)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperr.ErrOAuthClientNotFound
+	}
+	return nil
+}
+
+func toOAuthClient(row pstore.OauthClient) ports.OAuthClient {
+	return ports.OAuthClient{
+		ClientID:   row.ClientID,
+		Name:       row.Name,
+		SecretHash: row.SecretHash,
+		Scopes:     row.Scopes,
+		CreatedAt:  row.CreatedAt,
+	}
+}
+
+var _ ports.OAuthClientStore = (*OAuthClientStore)(nil)
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+	pstore "gostartkit/internal/infras/storage/postgres/sqlc"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PasswordResetStore implements ports.PasswordResetStore on the password_reset_tokens table.
+type PasswordResetStore struct {
+	q *pstore.Queries
+}
+
+func NewPasswordResetStore(pool *pgxpool.Pool) *PasswordResetStore {
+	return &PasswordResetStore{q: pstore.New(pool)}
+}
+
+// Save also purges the user's used and expired tokens, which keeps the table small without a cleanup job.
+func (s *PasswordResetStore) Save(ctx context.Context, tokenHash []byte, userID uuid.UUID, expiresAt time.Time) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	now := time.Now().UTC()
+	if err := s.q.DeleteStalePasswordResetTokens(cctx, pstore.DeleteStalePasswordResetTokensParams{UserID: userID, ExpiresAt: now}); err != nil {
+		return err
+	}
+	return s.q.CreatePasswordResetToken(cctx, pstore.CreatePasswordResetTokenParams{
+		TokenHash: tokenHash,
+		UserID:    userID,
+		ExpiresAt: expiresAt.UTC(),
+		CreatedAt: now,
+	})
+}
+
+func (s *PasswordResetStore) Consume(ctx context.Context, tokenHash []byte, now time.Time) (uuid.UUID, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	id, err := s.q.ConsumePasswordResetToken(cctx, pstore.ConsumePasswordResetTokenParams{
+		TokenHash: tokenHash,
+		UsedAt:    pgtype.Timestamptz{Time: now.UTC(), Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, apperr.ErrResetTokenInvalid
+		}
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+func (s *PasswordResetStore) DeleteByUser(ctx context.Context, userID uuid.UUID) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	return s.q.DeletePasswordResetTokensByUser(cctx, userID)
+}
+
+var _ ports.PasswordResetStore = (*PasswordResetStore)(nil)
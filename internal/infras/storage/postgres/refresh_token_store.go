@@ -0,0 +1,283 @@
+package postgres
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+	domuser "gostartkit/internal/domain/user"
+	pstore "gostartkit/internal/infras/storage/postgres/sqlc"
+	"gostartkit/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RefreshTokenStore implements ports.RefreshTokenStore on PostgreSQL.
+// Tables (see migrations/0003_refresh_tokens.up.sql):
+//   - refresh_sessions: one row per login (token family) with device metadata
+//   - refresh_tokens: SHA-256 of each token; rotated tokens keep their row (used_at set) until expiry for reuse detection
+//
+// Rotation runs in a transaction holding a row lock on the presented token, so concurrent rotations of the
+// same token cannot both succeed. Expired rows are removed by PurgeExpired.
+type RefreshTokenStore struct {
+	pool *pgxpool.Pool
+	q    *pstore.Queries
+}
+
+func NewRefreshTokenStore(pool *pgxpool.Pool) *RefreshTokenStore {
+	return &RefreshTokenStore{pool: pool, q: pstore.New(pool)}
+}
+
+// maxUserAgentLen bounds stored User-Agent strings.
+const maxUserAgentLen = 256
+
+func (s *RefreshTokenStore) Issue(ctx context.Context, userID string, ttlSeconds int, meta ports.SessionMeta) (string, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return "", domuser.ErrInvalidID
+	}
+	token, err := newRefreshToken()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now().UTC()
+	exp := now.Add(time.Duration(ttlSeconds) * time.Second)
+	sessionID := uuid.New()
+
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	err = pgx.BeginFunc(cctx, s.pool, func(tx pgx.Tx) error {
+		q := s.q.WithTx(tx)
+		if err := q.CreateRefreshSession(cctx, pstore.CreateRefreshSessionParams{
+			ID:          sessionID,
+			UserID:      uid,
+			CreatedAt:   now,
+			LastUsedAt:  now,
+			ExpiresAt:   exp,
+			Ip:          meta.IP,
+			UserAgent:   truncate(meta.UserAgent, maxUserAgentLen),
+			DeviceLabel: meta.DeviceLabel,
+		}); err != nil {
+			return err
+		}
+		return q.CreateRefreshToken(cctx, pstore.CreateRefreshTokenParams{
+			TokenHash: hashRefreshToken(token),
+			SessionID: sessionID,
+			UserID:    uid,
+			CreatedAt: now,
+			ExpiresAt: exp,
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *RefreshTokenStore) Rotate(ctx context.Context, oldToken string, ttlSeconds int, meta ports.SessionMeta) (string, string, error) {
+	newToken, err := newRefreshToken()
+	if err != nil {
+		return "", "", err
+	}
+	now := time.Now().UTC()
+	exp := now.Add(time.Duration(ttlSeconds) * time.Second)
+
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	var (
+		userID uuid.UUID
+		reused bool
+		family uuid.UUID
+	)
+	err = pgx.BeginFunc(cctx, s.pool, func(tx pgx.Tx) error {
+		q := s.q.WithTx(tx)
+		row, err := q.GetRefreshTokenForUpdate(cctx, hashRefreshToken(oldToken))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return apperr.ErrInvalidRefreshToken
+			}
+			return err
+		}
+		if !row.ExpiresAt.After(now) {
+			return apperr.ErrInvalidRefreshToken
+		}
+		userID, family = row.UserID, row.SessionID
+		if row.UsedAt.Valid {
+			// Replay of a rotated token: end the whole session (cascades to its tokens)
+			reused = true
+			return q.DeleteRefreshSession(cctx, row.SessionID)
+		}
+		if err := q.MarkRefreshTokenUsed(cctx, pstore.MarkRefreshTokenUsedParams{
+			TokenHash: row.TokenHash,
+			UsedAt:    pgtype.Timestamptz{Time: now, Valid: true},
+		}); err != nil {
+			return err
+		}
+		if err := q.CreateRefreshToken(cctx, pstore.CreateRefreshTokenParams{
+			TokenHash: hashRefreshToken(newToken),
+			SessionID: row.SessionID,
+			UserID:    row.UserID,
+			CreatedAt: now,
+			ExpiresAt: exp,
+		}); err != nil {
+			return err
+		}
+		return q.TouchRefreshSession(cctx, pstore.TouchRefreshSessionParams{
+			ID:         row.SessionID,
+			LastUsedAt: now,
+			ExpiresAt:  exp,
+			Ip:         meta.IP,
+			UserAgent:  truncate(meta.UserAgent, maxUserAgentLen),
+		})
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if reused {
+		logger.L().Warn("security_event", "event", "refresh_token_reuse", "user_id", userID.String(), "family", family.String(), "action", "family_revoked")
+		return "", "", apperr.ErrRefreshTokenReused
+	}
+	return newToken, userID.String(), nil
+}
+
+func (s *RefreshTokenStore) Revoke(ctx context.Context, token string) error {
+	now := time.Now().UTC()
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	return pgx.BeginFunc(cctx, s.pool, func(tx pgx.Tx) error {
+		q := s.q.WithTx(tx)
+		sessionID, err := q.DeleteLiveRefreshToken(cctx, pstore.DeleteLiveRefreshTokenParams{
+			TokenHash: hashRefreshToken(token),
+			ExpiresAt: now,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return apperr.ErrInvalidRefreshToken
+			}
+			return err
+		}
+		// The session ends once it has no live token left
+		return q.DeleteRefreshSessionIfDrained(cctx, pstore.DeleteRefreshSessionIfDrainedParams{ID: sessionID, ExpiresAt: now})
+	})
+}
+
+func (s *RefreshTokenStore) Validate(ctx context.Context, token string) (string, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	uid, err := s.q.GetLiveRefreshTokenUserID(cctx, pstore.GetLiveRefreshTokenUserIDParams{
+		TokenHash: hashRefreshToken(token),
+		ExpiresAt: time.Now().UTC(),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", apperr.ErrInvalidRefreshToken
+		}
+		return "", err
+	}
+	return uid.String(), nil
+}
+
+func (s *RefreshTokenStore) ListByUser(ctx context.Context, userID string) ([]ports.RefreshSession, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domuser.ErrInvalidID
+	}
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	rows, err := s.q.ListRefreshSessionsByUser(cctx, pstore.ListRefreshSessionsByUserParams{UserID: uid, ExpiresAt: time.Now().UTC()})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.RefreshSession, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, ports.RefreshSession{
+			ID:          r.ID.String(),
+			UserID:      r.UserID.String(),
+			CreatedAt:   r.CreatedAt,
+			LastUsedAt:  r.LastUsedAt,
+			ExpiresAt:   r.ExpiresAt,
+			IP:          r.Ip,
+			UserAgent:   r.UserAgent,
+			DeviceLabel: r.DeviceLabel,
+		})
+	}
+	return out, nil
+}
+
+func (s *RefreshTokenStore) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return domuser.ErrInvalidID
+	}
+	sid, err := uuid.Parse(sessionID)
+	if err != nil {
+		return apperr.ErrSessionNotFound
+	}
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	n, err := s.q.DeleteRefreshSessionForUser(cctx, pstore.DeleteRefreshSessionForUserParams{ID: sid, UserID: uid})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperr.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (s *RefreshTokenStore) RevokeAll(ctx context.Context, userID string) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return domuser.ErrInvalidID
+	}
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	return s.q.DeleteRefreshSessionsByUser(cctx, uid)
+}
+
+// PurgeExpired deletes expired tokens and sessions; it returns the number of rows removed.
+func (s *RefreshTokenStore) PurgeExpired(ctx context.Context) (int64, error) {
+	now := time.Now().UTC()
+	cctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	tokens, err := s.q.DeleteExpiredRefreshTokens(cctx, now)
+	if err != nil {
+		return 0, err
+	}
+	sessions, err := s.q.DeleteExpiredRefreshSessions(cctx, now)
+	if err != nil {
+		return tokens, err
+	}
+	return tokens + sessions, nil
+}
+
+// hashRefreshToken returns the at-rest form of a token; the plaintext is never stored.
+func hashRefreshToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
+
+var _ ports.RefreshTokenStore = (*RefreshTokenStore)(nil)
@@ -0,0 +1,33 @@
+-- name: CreateAPIKey :exec
+INSERT INTO api_keys (id, user_id, name, prefix, secret_hash, scopes, expires_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetAPIKeyByPrefix :one
+SELECT id, user_id, name, prefix, secret_hash, scopes, expires_at, last_used_at, created_at
+FROM api_keys
+WHERE prefix = $1;
+
+-- name: GetAPIKey :one
+SELECT id, user_id, name, prefix, secret_hash, scopes, expires_at, last_used_at, created_at
+FROM api_keys
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListAPIKeysByUser :many
+SELECT id, user_id, name, prefix, secret_hash, scopes, expires_at, last_used_at, created_at
+FROM api_keys
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: UpdateAPIKey :execrows
+UPDATE api_keys
+SET name = $3, scopes = $4
+WHERE id = $1 AND user_id = $2;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys SET last_used_at = $2 WHERE id = $1;
+
+-- name: DeleteAPIKey :execrows
+DELETE FROM api_keys WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteAPIKeysByUser :exec
+DELETE FROM api_keys WHERE user_id = $1;
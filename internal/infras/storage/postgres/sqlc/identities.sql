@@ -0,0 +1,18 @@
+-- name: GetUserIdentityUserID :one
+SELECT user_id
+FROM user_identities
+WHERE provider = $1 AND subject = $2;
+
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (provider, subject, user_id, email, created_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (provider, subject) DO NOTHING;
+
+-- name: DeleteUserIdentitiesByUser :exec
+DELETE FROM user_identities WHERE user_id = $1;
+
+-- name: ListUserIdentitiesByUser :many
+SELECT provider, subject, user_id, email, created_at
+FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at, provider;
@@ -0,0 +1,24 @@
+-- name: GetLoginLockout :one
+SELECT user_id, failed_attempts, last_failed_at, locked_until
+FROM login_lockouts
+WHERE user_id = $1;
+
+-- name: RecordLoginFailure :one
+-- Failures older than window_start (and not covered by a recent lock) no longer count.
+INSERT INTO login_lockouts (user_id, failed_attempts, last_failed_at)
+VALUES ($1, 1, $2)
+ON CONFLICT (user_id) DO UPDATE SET
+  failed_attempts = CASE
+    WHEN login_lockouts.last_failed_at < sqlc.arg(window_start)::timestamptz
+      AND (login_lockouts.locked_until IS NULL OR login_lockouts.locked_until < sqlc.arg(window_start)::timestamptz)
+    THEN 1
+    ELSE login_lockouts.failed_attempts + 1
+  END,
+  last_failed_at = EXCLUDED.last_failed_at
+RETURNING user_id, failed_attempts, last_failed_at, locked_until;
+
+-- name: LockLogin :exec
+UPDATE login_lockouts SET locked_until = $2 WHERE user_id = $1;
+
+-- name: DeleteLoginLockout :execrows
+DELETE FROM login_lockouts WHERE user_id = $1;
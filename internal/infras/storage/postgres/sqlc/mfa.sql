@@ -0,0 +1,38 @@
+-- name: GetUserMFA :one
+SELECT user_id, secret_ciphertext, confirmed_at, last_used_step, created_at, updated_at
+FROM user_mfa
+WHERE user_id = $1;
+
+-- name: UpsertPendingUserMFA :exec
+INSERT INTO user_mfa (user_id, secret_ciphertext, created_at, updated_at)
+VALUES ($1, $2, $3, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET secret_ciphertext = EXCLUDED.secret_ciphertext,
+    last_used_step    = 0,
+    updated_at        = EXCLUDED.updated_at
+WHERE user_mfa.confirmed_at IS NULL;
+
+-- name: ConfirmUserMFA :execrows
+UPDATE user_mfa
+SET confirmed_at = $2, last_used_step = $3, updated_at = $4
+WHERE user_id = $1 AND confirmed_at IS NULL;
+
+-- name: UseUserMFAStep :execrows
+UPDATE user_mfa
+SET last_used_step = $2
+WHERE user_id = $1 AND last_used_step < $2;
+
+-- name: DeleteUserMFA :exec
+DELETE FROM user_mfa WHERE user_id = $1;
+
+-- name: CreateMFARecoveryCode :exec
+INSERT INTO user_mfa_recovery_codes (user_id, code_hash, created_at)
+VALUES ($1, $2, $3);
+
+-- name: UseMFARecoveryCode :execrows
+UPDATE user_mfa_recovery_codes
+SET used_at = $3
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: DeleteMFARecoveryCodes :exec
+DELETE FROM user_mfa_recovery_codes WHERE user_id = $1;
@@ -0,0 +1,16 @@
+-- name: CreateOAuthClient :exec
+INSERT INTO oauth_clients (client_id, name, secret_hash, scopes, created_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetOAuthClient :one
+SELECT client_id, name, secret_hash, scopes, created_at
+FROM oauth_clients
+WHERE client_id = $1;
+
+-- name: ListOAuthClients :many
+SELECT client_id, name, secret_hash, scopes, created_at
+FROM oauth_clients
+ORDER BY created_at DESC;
+
+-- name: DeleteOAuthClient :execrows
+DELETE FROM oauth_clients WHERE client_id = $1;
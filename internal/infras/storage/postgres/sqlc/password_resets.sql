@@ -0,0 +1,16 @@
+-- name: CreatePasswordResetToken :exec
+INSERT INTO password_reset_tokens (token_hash, user_id, expires_at, created_at)
+VALUES ($1, $2, $3, $4);
+
+-- name: ConsumePasswordResetToken :one
+UPDATE password_reset_tokens
+SET used_at = $2
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+RETURNING user_id;
+
+-- name: DeletePasswordResetTokensByUser :exec
+DELETE FROM password_reset_tokens WHERE user_id = $1;
+
+-- name: DeleteStalePasswordResetTokens :exec
+DELETE FROM password_reset_tokens
+WHERE user_id = $1 AND (used_at IS NOT NULL OR expires_at <= $2);
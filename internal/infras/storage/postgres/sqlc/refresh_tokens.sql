@@ -0,0 +1,64 @@
+-- name: CreateRefreshSession :exec
+INSERT INTO refresh_sessions (id, user_id, created_at, last_used_at, expires_at, ip, user_agent, device_label)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: CreateRefreshToken :exec
+INSERT INTO refresh_tokens (token_hash, session_id, user_id, created_at, expires_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetRefreshTokenForUpdate :one
+SELECT token_hash, session_id, user_id, created_at, expires_at, used_at
+FROM refresh_tokens
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: GetLiveRefreshTokenUserID :one
+SELECT user_id
+FROM refresh_tokens
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2;
+
+-- name: MarkRefreshTokenUsed :exec
+UPDATE refresh_tokens SET used_at = $2 WHERE token_hash = $1;
+
+-- name: TouchRefreshSession :exec
+UPDATE refresh_sessions
+SET last_used_at = $2,
+    expires_at   = $3,
+    ip           = $4,
+    user_agent   = $5
+WHERE id = $1;
+
+-- name: DeleteLiveRefreshToken :one
+DELETE FROM refresh_tokens
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+RETURNING session_id;
+
+-- name: DeleteRefreshSessionIfDrained :exec
+DELETE FROM refresh_sessions s
+WHERE s.id = $1
+  AND NOT EXISTS (
+    SELECT 1 FROM refresh_tokens t
+    WHERE t.session_id = s.id AND t.used_at IS NULL AND t.expires_at > $2
+  );
+
+-- name: DeleteRefreshSession :exec
+DELETE FROM refresh_sessions WHERE id = $1;
+
+-- name: DeleteRefreshSessionForUser :execrows
+DELETE FROM refresh_sessions WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteRefreshSessionsByUser :exec
+DELETE FROM refresh_sessions WHERE user_id = $1;
+
+-- name: ListRefreshSessionsByUser :many
+SELECT id, user_id, created_at, last_used_at, expires_at, ip, user_agent, device_label
+FROM refresh_sessions
+WHERE user_id = $1 AND expires_at > $2
+ORDER BY last_used_at DESC;
+
+-- name: DeleteExpiredRefreshTokens :execrows
+DELETE FROM refresh_tokens WHERE expires_at <= $1;
+
+-- name: DeleteExpiredRefreshSessions :execrows
+DELETE FROM refresh_sessions WHERE expires_at <= $1;
+
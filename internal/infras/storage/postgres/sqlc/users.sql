@@ -1,33 +1,77 @@
+-- Soft-deleted users (deleted_at set) are hidden from every read except GetUserByIDIncludingDeleted and
+-- ListUsersPage with include_deleted.
+
 -- name: CreateUser :exec
-INSERT INTO users (id, first_name, last_name, email, password, role, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+INSERT INTO users (id, first_name, last_name, email, password, role, created_at, updated_at, email_verified_at, phone, phone_verified_at, password_changed_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
 
 -- name: GetUserByID :one
-SELECT id, first_name, last_name, email, password, role, created_at, updated_at
+SELECT id, first_name, last_name, email, password, role, created_at, updated_at, email_verified_at, phone, phone_verified_at, disabled_at, deleted_at, erased_at, password_changed_at
+FROM users
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetUserByIDIncludingDeleted :one
+SELECT id, first_name, last_name, email, password, role, created_at, updated_at, email_verified_at, phone, phone_verified_at, disabled_at, deleted_at, erased_at, password_changed_at
 FROM users
 WHERE id = $1;
 
 -- name: GetUserByEmail :one
-SELECT id, first_name, last_name, email, password, role, created_at, updated_at
+SELECT id, first_name, last_name, email, password, role, created_at, updated_at, email_verified_at, phone, phone_verified_at, disabled_at, deleted_at, erased_at, password_changed_at
 FROM users
-WHERE email = $1;
+WHERE email = $1 AND deleted_at IS NULL;
 
--- name: ListUsers :many
-SELECT id, first_name, last_name, email, password, role, created_at, updated_at
+-- name: GetUserByPhone :one
+SELECT id, first_name, last_name, email, password, role, created_at, updated_at, email_verified_at, phone, phone_verified_at, disabled_at, deleted_at, erased_at, password_changed_at
 FROM users
-ORDER BY created_at DESC;
+WHERE phone = $1 AND deleted_at IS NULL;
 
--- name: UpdateUser :exec
+-- name: ListUsersPage :many
+-- Keyset pagination, newest first: pass the last row's (created_at, id) as the cursor to continue.
+SELECT id, first_name, last_name, email, password, role, created_at, updated_at, email_verified_at, phone, phone_verified_at, disabled_at, deleted_at, erased_at, password_changed_at
+FROM users
+WHERE (sqlc.narg(role)::text IS NULL OR role = sqlc.narg(role)::text)
+  AND (sqlc.narg(email_prefix)::text IS NULL OR lower(email) LIKE sqlc.narg(email_prefix)::text || '%')
+  AND (sqlc.narg(created_from)::timestamptz IS NULL OR created_at >= sqlc.narg(created_from)::timestamptz)
+  AND (sqlc.narg(created_to)::timestamptz IS NULL OR created_at < sqlc.narg(created_to)::timestamptz)
+  AND (sqlc.narg(after_created_at)::timestamptz IS NULL
+       OR (created_at, id) < (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id)::uuid))
+  AND (sqlc.arg(include_deleted)::bool OR deleted_at IS NULL)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: UpdateUser :execrows
 UPDATE users
 SET first_name = $2,
     last_name  = $3,
     email      = $4,
     password   = $5,
     role       = $6,
-    updated_at = $7
-WHERE id = $1;
+    updated_at = $7,
+    email_verified_at = $8,
+    phone      = $9,
+    phone_verified_at = $10,
+    disabled_at = $11,
+    password_changed_at = $12
+WHERE id = $1 AND deleted_at IS NULL;
 
--- name: DeleteUser :exec
-DELETE FROM users WHERE id = $1;
+-- name: UpdateUserPasswordHash :execrows
+-- Touches only the hash, so it cannot undo concurrent changes to other columns (e.g. disabled_at). The password
+-- itself is unchanged, so password_changed_at stays.
+UPDATE users SET password = $2, updated_at = $3 WHERE id = $1 AND deleted_at IS NULL;
 
+-- name: SoftDeleteUser :execrows
+UPDATE users SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL;
 
+-- name: EraseUser :execrows
+-- Anonymizes personal data; the row and its ID stay for foreign keys. Also marks the user deleted.
+UPDATE users
+SET first_name = $2,
+    last_name  = $3,
+    email      = $4,
+    password   = '',
+    email_verified_at = NULL,
+    phone      = NULL,
+    phone_verified_at = NULL,
+    deleted_at = COALESCE(deleted_at, $5),
+    erased_at  = $5
+WHERE id = $1;
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	domuser "gostartkit/internal/domain/user"
@@ -11,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -27,47 +29,46 @@ func (r *UserRepository) Save(ctx context.Context, u *domuser.User) error {
 	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 	err := r.q.CreateUser(cctx, pstore.CreateUserParams{
-		ID:        u.ID,
-		FirstName: u.FirstName,
-		LastName:  u.LastName,
-		Email:     u.Email.String(),
-		Password:  u.Password,
-		Role:      string(u.Role),
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:                u.ID,
+		FirstName:         u.FirstName,
+		LastName:          u.LastName,
+		Email:             u.Email.String(),
+		Password:          u.Password,
+		Role:              string(u.Role),
+		CreatedAt:         u.CreatedAt,
+		UpdatedAt:         u.UpdatedAt,
+		EmailVerifiedAt:   nullableTime(u.EmailVerifiedAt),
+		Phone:             nullablePhone(u.Phone),
+		PhoneVerifiedAt:   nullableTime(u.PhoneVerifiedAt),
+		PasswordChangedAt: passwordChangedAt(u),
 	})
+	return mapUniqueViolation(err)
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domuser.User, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	row, err := r.q.GetUserByID(cctx, id)
 	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) {
-			if pgErr.Code == "23505" { // unique_violation
-				return domuser.ErrEmailAlreadyExists
-			}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domuser.ErrUserNotFound
 		}
-		return err
+		return nil, err
 	}
-	return nil
+	return toDomainUser(row), nil
 }
 
-func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domuser.User, error) {
+func (r *UserRepository) GetByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*domuser.User, error) {
 	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
-	row, err := r.q.GetUserByID(cctx, id)
+	row, err := r.q.GetUserByIDIncludingDeleted(cctx, id)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domuser.ErrUserNotFound
 		}
 		return nil, err
 	}
-	return &domuser.User{
-		ID:        row.ID,
-		FirstName: row.FirstName,
-		LastName:  row.LastName,
-		Email:     domuser.Email(row.Email),
-		Password:  row.Password,
-		Role:      domuser.Role(row.Role),
-		CreatedAt: row.CreatedAt,
-		UpdatedAt: row.UpdatedAt,
-	}, nil
+	return toDomainUser(row), nil
 }
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email domuser.Email) (*domuser.User, error) {
@@ -80,38 +81,44 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email domuser.Email) (*
 		}
 		return nil, err
 	}
-	return &domuser.User{
-		ID:        row.ID,
-		FirstName: row.FirstName,
-		LastName:  row.LastName,
-		Email:     domuser.Email(row.Email),
-		Password:  row.Password,
-		Role:      domuser.Role(row.Role),
-		CreatedAt: row.CreatedAt,
-		UpdatedAt: row.UpdatedAt,
-	}, nil
+	return toDomainUser(row), nil
 }
 
-func (r *UserRepository) GetAll(ctx context.Context) ([]*domuser.User, error) {
+func (r *UserRepository) GetByPhone(ctx context.Context, phone domuser.Phone) (*domuser.User, error) {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	row, err := r.q.GetUserByPhone(cctx, nullablePhone(phone))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domuser.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return toDomainUser(row), nil
+}
+
+func (r *UserRepository) List(ctx context.Context, filter domuser.ListFilter) ([]*domuser.User, error) {
 	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
-	rows, err := r.q.ListUsers(cctx)
+	params := pstore.ListUsersPageParams{
+		Role:           pgtype.Text{String: string(filter.Role), Valid: filter.Role != ""},
+		EmailPrefix:    pgtype.Text{String: escapeLike(strings.ToLower(filter.EmailPrefix)), Valid: filter.EmailPrefix != ""},
+		CreatedFrom:    pgtype.Timestamptz{Time: filter.CreatedFrom, Valid: !filter.CreatedFrom.IsZero()},
+		CreatedTo:      pgtype.Timestamptz{Time: filter.CreatedTo, Valid: !filter.CreatedTo.IsZero()},
+		PageLimit:      int32(filter.Limit),
+		IncludeDeleted: filter.IncludeDeleted,
+	}
+	if filter.After != nil {
+		params.AfterCreatedAt = pgtype.Timestamptz{Time: filter.After.CreatedAt, Valid: true}
+		params.AfterID = pgtype.UUID{Bytes: filter.After.ID, Valid: true}
+	}
+	rows, err := r.q.ListUsersPage(cctx, params)
 	if err != nil {
 		return nil, err
 	}
 	out := make([]*domuser.User, 0, len(rows))
 	for _, row := range rows {
-		u := &domuser.User{
-			ID:        row.ID,
-			FirstName: row.FirstName,
-			LastName:  row.LastName,
-			Email:     domuser.Email(row.Email),
-			Password:  row.Password,
-			Role:      domuser.Role(row.Role),
-			CreatedAt: row.CreatedAt,
-			UpdatedAt: row.UpdatedAt,
-		}
-		out = append(out, u)
+		out = append(out, toDomainUser(row))
 	}
 	return out, nil
 }
@@ -119,19 +126,175 @@ func (r *UserRepository) GetAll(ctx context.Context) ([]*domuser.User, error) {
 func (r *UserRepository) Update(ctx context.Context, u *domuser.User) error {
 	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
-	return r.q.UpdateUser(cctx, pstore.UpdateUserParams{
-		ID:        u.ID,
-		FirstName: u.FirstName,
-		LastName:  u.LastName,
-		Email:     u.Email.String(),
-		Password:  u.Password,
-		Role:      string(u.Role),
-		UpdatedAt: u.UpdatedAt, // kept for explicitness; DB trigger also updates this
+	n, err := r.q.UpdateUser(cctx, pstore.UpdateUserParams{
+		ID:                u.ID,
+		FirstName:         u.FirstName,
+		LastName:          u.LastName,
+		Email:             u.Email.String(),
+		Password:          u.Password,
+		Role:              string(u.Role),
+		UpdatedAt:         u.UpdatedAt, // kept for explicitness; DB trigger also updates this
+		EmailVerifiedAt:   nullableTime(u.EmailVerifiedAt),
+		Phone:             nullablePhone(u.Phone),
+		PhoneVerifiedAt:   nullableTime(u.PhoneVerifiedAt),
+		DisabledAt:        nullableTime(u.DisabledAt),
+		PasswordChangedAt: passwordChangedAt(u),
 	})
+	if err != nil {
+		return mapUniqueViolation(err)
+	}
+	if n == 0 {
+		return domuser.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdatePasswordHash(ctx context.Context, id uuid.UUID, hash string, at time.Time) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	n, err := r.q.UpdateUserPasswordHash(cctx, pstore.UpdateUserPasswordHashParams{ID: id, Password: hash, UpdatedAt: at})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domuser.ErrUserNotFound
+	}
+	return nil
 }
 
+// Delete marks the user deleted and removes its sign-in methods in one transaction.
 func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
-	return r.q.DeleteUser(cctx, id)
+	return pgx.BeginFunc(cctx, r.pool, func(tx pgx.Tx) error {
+		q := r.q.WithTx(tx)
+		n, err := q.SoftDeleteUser(cctx, pstore.SoftDeleteUserParams{ID: id, DeletedAt: pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true}})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return domuser.ErrUserNotFound
+		}
+		return deleteSignInMethods(cctx, q, id)
+	})
+}
+
+// Erase anonymizes the user row (deleted or not) and removes its sign-in methods in one transaction.
+func (r *UserRepository) Erase(ctx context.Context, u *domuser.User) error {
+	cctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	erasedAt := time.Now().UTC()
+	if u.ErasedAt != nil {
+		erasedAt = *u.ErasedAt
+	}
+	return pgx.BeginFunc(cctx, r.pool, func(tx pgx.Tx) error {
+		q := r.q.WithTx(tx)
+		n, err := q.EraseUser(cctx, pstore.EraseUserParams{
+			ID:        u.ID,
+			FirstName: u.FirstName,
+			LastName:  u.LastName,
+			Email:     u.Email.String(),
+			ErasedAt:  pgtype.Timestamptz{Time: erasedAt, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return domuser.ErrUserNotFound
+		}
+		return deleteSignInMethods(cctx, q, u.ID)
+	})
+}
+
+// deleteSignInMethods removes everything that could authenticate the user or still holds personal data about
+// them (session IPs and user agents, provider emails). Rows in other tables keep pointing at the user ID.
+func deleteSignInMethods(ctx context.Context, q *pstore.Queries, id uuid.UUID) error {
+	for _, del := range []func(context.Context, uuid.UUID) error{
+		q.DeleteUserIdentitiesByUser,
+		q.DeleteMFARecoveryCodes,
+		q.DeleteUserMFA,
+		q.DeleteAPIKeysByUser,
+		q.DeletePasswordResetTokensByUser,
+		q.DeleteRefreshSessionsByUser,
+	} {
+		if err := del(ctx, id); err != nil {
+			return err
+		}
+	}
+	_, err := q.DeleteLoginLockout(ctx, id)
+	return err
+}
+
+// passwordChangedAt falls back to the creation time for users built without going through user.NewUser.
+func passwordChangedAt(u *domuser.User) time.Time {
+	if u.PasswordChangedAt.IsZero() {
+		return u.CreatedAt
+	}
+	return u.PasswordChangedAt
+}
+
+func toDomainUser(row pstore.User) *domuser.User {
+	u := &domuser.User{
+		ID:                row.ID,
+		FirstName:         row.FirstName,
+		LastName:          row.LastName,
+		Email:             domuser.Email(row.Email),
+		Password:          row.Password,
+		Role:              domuser.Role(row.Role),
+		CreatedAt:         row.CreatedAt,
+		UpdatedAt:         row.UpdatedAt,
+		PasswordChangedAt: row.PasswordChangedAt,
+	}
+	if row.EmailVerifiedAt.Valid {
+		t := row.EmailVerifiedAt.Time
+		u.EmailVerifiedAt = &t
+	}
+	if row.Phone.Valid {
+		u.Phone = domuser.Phone(row.Phone.String)
+	}
+	if row.PhoneVerifiedAt.Valid {
+		t := row.PhoneVerifiedAt.Time
+		u.PhoneVerifiedAt = &t
+	}
+	if row.DisabledAt.Valid {
+		t := row.DisabledAt.Time
+		u.DisabledAt = &t
+	}
+	if row.DeletedAt.Valid {
+		t := row.DeletedAt.Time
+		u.DeletedAt = &t
+	}
+	if row.ErasedAt.Valid {
+		t := row.ErasedAt.Time
+		u.ErasedAt = &t
+	}
+	return u
+}
+
+// mapUniqueViolation turns unique_violation errors into the matching domain error.
+func mapUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		if pgErr.ConstraintName == "users_phone_key" {
+			return domuser.ErrPhoneAlreadyExists
+		}
+		return domuser.ErrEmailAlreadyExists
+	}
+	return err
+}
+
+// escapeLike makes s match literally inside a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func nullablePhone(p domuser.Phone) pgtype.Text {
+	return pgtype.Text{String: p.String(), Valid: p != ""}
+}
+
+func nullableTime(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
 }
@@ -0,0 +1,230 @@
+package s3
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fakeS3 is an in-memory, path-style S3 endpoint for one bucket. It checks SigV4 signatures (header and
+// presigned) with the same signer, enforces the minimum multipart part size and pages listings two keys at
+// a time, so the client's pagination and multipart paths run without a real service.
+type fakeS3 struct {
+	bucket string
+	signer signer
+
+	mu      sync.Mutex
+	objects map[string]fakeObject
+	uploads map[string]map[int][]byte
+	nextID  int
+	// completed counts finished multipart uploads
+	completed int
+}
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+	modTime     time.Time
+}
+
+func newFakeS3(bucket, accessKey, secretKey string) *fakeS3 {
+	return &fakeS3{
+		bucket:  bucket,
+		signer:  signer{accessKey: accessKey, secretKey: secretKey, region: defaultRegion},
+		objects: map[string]fakeObject{},
+		uploads: map[string]map[int][]byte{},
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		fakeError(w, http.StatusBadRequest, "IncompleteBody")
+		return
+	}
+	if !f.authorized(r, body) {
+		fakeError(w, http.StatusForbidden, "SignatureDoesNotMatch")
+		return
+	}
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != f.bucket {
+		fakeError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	q := r.URL.Query()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case key == "" && r.Method == http.MethodPut:
+		fakeError(w, http.StatusConflict, "BucketAlreadyOwnedByYou")
+	case key == "" && r.Method == http.MethodGet:
+		f.list(w, q.Get("prefix"), q.Get("continuation-token"))
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		f.nextID++
+		id := strconv.Itoa(f.nextID)
+		f.uploads[id] = map[int][]byte{}
+		writeXML(w, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			UploadID string   `xml:"UploadId"`
+		}{UploadID: id})
+	case r.Method == http.MethodPut && q.Has("uploadId"):
+		parts, ok := f.uploads[q.Get("uploadId")]
+		if !ok {
+			fakeError(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		n, _ := strconv.Atoi(q.Get("partNumber"))
+		parts[n] = body
+		w.Header().Set("ETag", etag(body))
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		f.complete(w, key, q.Get("uploadId"), body)
+	case r.Method == http.MethodDelete && q.Has("uploadId"):
+		delete(f.uploads, q.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		f.objects[key] = fakeObject{data: body, contentType: r.Header.Get("Content-Type"), modTime: time.Now()}
+		w.Header().Set("ETag", etag(body))
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		obj, ok := f.objects[key]
+		if !ok {
+			fakeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+		w.Header().Set("ETag", etag(obj.data))
+		w.Header().Set("Last-Modified", obj.modTime.UTC().Format(http.TimeFormat))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(obj.data)
+		}
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func (f *fakeS3) complete(w http.ResponseWriter, key, uploadID string, body []byte) {
+	parts, ok := f.uploads[uploadID]
+	if !ok {
+		fakeError(w, http.StatusNotFound, "NoSuchUpload")
+		return
+	}
+	var req completeUpload
+	if err := xml.Unmarshal(body, &req); err != nil || len(req.Parts) == 0 {
+		fakeError(w, http.StatusBadRequest, "MalformedXML")
+		return
+	}
+	var data []byte
+	for i, p := range req.Parts {
+		part, ok := parts[p.PartNumber]
+		if !ok || p.PartNumber != i+1 || p.ETag != etag(part) {
+			fakeError(w, http.StatusBadRequest, "InvalidPart")
+			return
+		}
+		if i < len(req.Parts)-1 && len(part) < minPartSize {
+			fakeError(w, http.StatusBadRequest, "EntityTooSmall")
+			return
+		}
+		data = append(data, part...)
+	}
+	delete(f.uploads, uploadID)
+	f.objects[key] = fakeObject{data: data, contentType: "application/octet-stream", modTime: time.Now()}
+	f.completed++
+	writeXML(w, struct {
+		XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+		Key     string
+	}{Key: key})
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, prefix, token string) {
+	var keys []string
+	for k := range f.objects {
+		if strings.HasPrefix(k, prefix) && k > token {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	type content struct {
+		Key          string
+		LastModified time.Time
+		ETag         string
+		Size         int
+	}
+	page := struct {
+		XMLName               xml.Name `xml:"ListBucketResult"`
+		Contents              []content
+		IsTruncated           bool
+		NextContinuationToken string `xml:",omitempty"`
+	}{}
+	for i, k := range keys {
+		if i == 2 {
+			page.IsTruncated, page.NextContinuationToken = true, keys[i-1]
+			break
+		}
+		obj := f.objects[k]
+		page.Contents = append(page.Contents, content{Key: k, LastModified: obj.modTime.UTC(), ETag: etag(obj.data), Size: len(obj.data)})
+	}
+	writeXML(w, page)
+}
+
+// authorized recomputes the request's signature, from the Authorization header or the presigned query.
+func (f *fakeS3) authorized(r *http.Request, body []byte) bool {
+	u := &url.URL{Scheme: "http", Host: r.Host, Path: r.URL.Path, RawQuery: r.URL.RawQuery}
+	q := r.URL.Query()
+	if sig := q.Get("X-Amz-Signature"); sig != "" {
+		date, err := time.Parse(amzDateFormat, q.Get("X-Amz-Date"))
+		expires, _ := strconv.Atoi(q.Get("X-Amz-Expires"))
+		if err != nil || time.Now().After(date.Add(time.Duration(expires)*time.Second)) {
+			return false
+		}
+		for k := range q {
+			if strings.HasPrefix(k, "X-Amz-") {
+				q.Del(k)
+			}
+		}
+		u.RawQuery = q.Encode()
+		want, err := url.Parse(f.signer.presign(r.Method, u, time.Duration(expires)*time.Second, date))
+		return err == nil && want.Query().Get("X-Amz-Signature") == sig
+	}
+	date, err := time.Parse(amzDateFormat, r.Header.Get("X-Amz-Date"))
+	if err != nil || r.Header.Get("X-Amz-Content-Sha256") != hashHex(body) {
+		return false
+	}
+	clone, _ := http.NewRequest(r.Method, u.String(), bytes.NewReader(body))
+	clone.URL = u
+	for k, v := range r.Header {
+		if k != "Authorization" {
+			clone.Header[k] = v
+		}
+	}
+	f.signer.sign(clone, hashHex(body), date)
+	return clone.Header.Get("Authorization") == r.Header.Get("Authorization")
+}
+
+func etag(b []byte) string {
+	sum := md5.Sum(b)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func writeXML(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(v)
+}
+
+func fakeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
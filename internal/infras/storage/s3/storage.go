@@ -0,0 +1,402 @@
+// Package s3 stores objects in Amazon S3 or an S3-compatible service (MinIO, Cloudflare R2, ...) through
+// the REST API, signed with AWS Signature Version 4.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+)
+
+const (
+	defaultRegion   = "us-east-1"
+	defaultPartSize = 8 << 20
+	// minPartSize and maxParts are S3 multipart limits; the last part may be smaller.
+	minPartSize = 5 << 20
+	maxParts    = 10000
+)
+
+// Config selects the bucket and credentials.
+type Config struct {
+	// Endpoint is the service URL, e.g. https://s3.eu-west-1.amazonaws.com or http://localhost:9000
+	Endpoint string
+	// Region used in signatures (default us-east-1, which MinIO also uses)
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle addresses objects as <endpoint>/<bucket>/<key> (MinIO) instead of <bucket>.<endpoint host>/<key>
+	PathStyle bool
+	// PartSize is the multipart chunk size; larger uploads are sent in parts (default 8 MiB, minimum 5 MiB)
+	PartSize int64
+	// HTTPClient defaults to a client with a 60 second timeout per request
+	HTTPClient *http.Client
+}
+
+// Storage implements ports.ObjectStorage on one bucket.
+type Storage struct {
+	endpoint  *url.URL
+	bucket    string
+	pathStyle bool
+	partSize  int64
+	client    *http.Client
+	signer    signer
+}
+
+func New(cfg Config) (*Storage, error) {
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("s3: invalid endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("s3: bucket, access key and secret key are required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = defaultRegion
+	}
+	if cfg.PartSize <= 0 {
+		cfg.PartSize = defaultPartSize
+	}
+	if cfg.PartSize < minPartSize {
+		return nil, fmt.Errorf("s3: part size must be at least %d bytes", minPartSize)
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 60 * time.Second}
+	}
+	return &Storage{
+		endpoint:  endpoint,
+		bucket:    cfg.Bucket,
+		pathStyle: cfg.PathStyle,
+		partSize:  cfg.PartSize,
+		client:    cfg.HTTPClient,
+		signer:    signer{accessKey: cfg.AccessKey, secretKey: cfg.SecretKey, region: cfg.Region},
+	}, nil
+}
+
+// EnsureBucket creates the bucket unless it already exists and belongs to these credentials.
+func (s *Storage) EnsureBucket(ctx context.Context) error {
+	var body []byte
+	if s.signer.region != defaultRegion {
+		body = []byte(`<CreateBucketConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><LocationConstraint>` +
+			s.signer.region + `</LocationConstraint></CreateBucketConfiguration>`)
+	}
+	resp, err := s.do(ctx, http.MethodPut, s.objectURL("", nil), body, nil)
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.Code == "BucketAlreadyOwnedByYou" {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return drain(resp)
+}
+
+// Put sends content up to PartSize in one request and anything larger as a multipart upload, so memory use
+// stays at one part regardless of the object size.
+func (s *Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	if err := ports.CheckObjectKey(key); err != nil {
+		return "", err
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	first, more, err := s.readPart(r)
+	if err != nil {
+		return "", err
+	}
+	if !more {
+		if size >= 0 && int64(len(first)) != size {
+			return "", fmt.Errorf("s3: got %d bytes, expected %d", len(first), size)
+		}
+		resp, err := s.do(ctx, http.MethodPut, s.objectURL(key, nil), first, http.Header{"Content-Type": {contentType}})
+		if err != nil {
+			return "", err
+		}
+		if err := drain(resp); err != nil {
+			return "", err
+		}
+	} else if err := s.putMultipart(ctx, key, first, r, size, contentType); err != nil {
+		return "", err
+	}
+	return s.objectURL(key, nil).String(), nil
+}
+
+func (s *Storage) putMultipart(ctx context.Context, key string, first []byte, r io.Reader, size int64, contentType string) (err error) {
+	resp, err := s.do(ctx, http.MethodPost, s.objectURL(key, url.Values{"uploads": {""}}), nil, http.Header{"Content-Type": {contentType}})
+	if err != nil {
+		return err
+	}
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := decodeXML(resp, &initiated); err != nil {
+		return err
+	}
+	uploadID := initiated.UploadID
+	defer func() {
+		if err != nil {
+			// Parts of an unfinished upload are billed until aborted
+			actx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+			defer cancel()
+			if resp, aerr := s.do(actx, http.MethodDelete, s.objectURL(key, url.Values{"uploadId": {uploadID}}), nil, nil); aerr == nil {
+				_ = drain(resp)
+			}
+		}
+	}()
+
+	complete := completeUpload{}
+	var total int64
+	part, more := first, true
+	for n := 1; len(part) > 0; n++ {
+		if n > maxParts {
+			return fmt.Errorf("s3: object exceeds %d parts of %d bytes", maxParts, s.partSize)
+		}
+		q := url.Values{"partNumber": {strconv.Itoa(n)}, "uploadId": {uploadID}}
+		resp, err := s.do(ctx, http.MethodPut, s.objectURL(key, q), part, nil)
+		if err != nil {
+			return err
+		}
+		etag := resp.Header.Get("ETag")
+		if err := drain(resp); err != nil {
+			return err
+		}
+		complete.Parts = append(complete.Parts, completedPart{PartNumber: n, ETag: etag})
+		total += int64(len(part))
+		if !more {
+			break
+		}
+		if part, more, err = s.readPart(r); err != nil {
+			return err
+		}
+	}
+	if size >= 0 && total != size {
+		return fmt.Errorf("s3: got %d bytes, expected %d", total, size)
+	}
+	body, err := xml.Marshal(complete)
+	if err != nil {
+		return err
+	}
+	resp, err = s.do(ctx, http.MethodPost, s.objectURL(key, url.Values{"uploadId": {uploadID}}), body, http.Header{"Content-Type": {"application/xml"}})
+	if err != nil {
+		return err
+	}
+	// CompleteMultipartUpload can fail after answering 200; the error is then in the body
+	var result struct {
+		XMLName xml.Name
+		Code    string
+		Message string
+	}
+	if err := decodeXML(resp, &result); err != nil {
+		return err
+	}
+	if result.XMLName.Local == "Error" {
+		return &Error{Status: http.StatusOK, Code: result.Code, Message: result.Message}
+	}
+	return nil
+}
+
+// readPart reads up to PartSize bytes; more reports whether the reader may hold further data.
+func (s *Storage) readPart(r io.Reader) (part []byte, more bool, err error) {
+	buf := make([]byte, s.partSize)
+	n, err := io.ReadFull(r, buf)
+	switch {
+	case err == nil:
+		return buf, true, nil
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return buf[:n], false, nil
+	default:
+		return nil, false, err
+	}
+}
+
+func (s *Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := ports.CheckObjectKey(key); err != nil {
+		return nil, err
+	}
+	resp, err := s.do(ctx, http.MethodGet, s.objectURL(key, nil), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *Storage) Delete(ctx context.Context, key string) error {
+	if err := ports.CheckObjectKey(key); err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodDelete, s.objectURL(key, nil), nil, nil)
+	if err != nil {
+		return err
+	}
+	return drain(resp)
+}
+
+func (s *Storage) Stat(ctx context.Context, key string) (ports.ObjectInfo, error) {
+	if err := ports.CheckObjectKey(key); err != nil {
+		return ports.ObjectInfo{}, err
+	}
+	resp, err := s.do(ctx, http.MethodHead, s.objectURL(key, nil), nil, nil)
+	if err != nil {
+		return ports.ObjectInfo{}, err
+	}
+	_ = drain(resp)
+	modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return ports.ObjectInfo{
+		Key:         key,
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+		ModTime:     modTime.UTC(),
+		ETag:        resp.Header.Get("ETag"),
+	}, nil
+}
+
+// List pages through ListObjectsV2 until the listing is complete. S3 listings carry no content type.
+func (s *Storage) List(ctx context.Context, prefix string) ([]ports.ObjectInfo, error) {
+	var out []ports.ObjectInfo
+	q := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	for {
+		resp, err := s.do(ctx, http.MethodGet, s.objectURL("", q), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Contents []struct {
+				Key          string
+				LastModified time.Time
+				ETag         string
+				Size         int64
+			}
+			IsTruncated           bool
+			NextContinuationToken string
+		}
+		if err := decodeXML(resp, &page); err != nil {
+			return nil, err
+		}
+		for _, c := range page.Contents {
+			out = append(out, ports.ObjectInfo{Key: c.Key, Size: c.Size, ModTime: c.LastModified.UTC(), ETag: c.ETag})
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return out, nil
+		}
+		q.Set("continuation-token", page.NextContinuationToken)
+	}
+}
+
+func (s *Storage) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.presign(http.MethodGet, key, ttl)
+}
+
+func (s *Storage) PresignPut(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.presign(http.MethodPut, key, ttl)
+}
+
+func (s *Storage) presign(method, key string, ttl time.Duration) (string, error) {
+	if err := ports.CheckObjectKey(key); err != nil {
+		return "", err
+	}
+	if ttl < time.Second || ttl > maxPresignTTL {
+		return "", fmt.Errorf("s3: presign ttl must be between 1s and %s", maxPresignTTL)
+	}
+	return s.signer.presign(method, s.objectURL(key, nil), ttl, time.Now()), nil
+}
+
+// objectURL addresses key in the bucket; an empty key addresses the bucket itself.
+func (s *Storage) objectURL(key string, q url.Values) *url.URL {
+	u := *s.endpoint
+	if s.pathStyle {
+		u.Path = s.endpoint.Path + "/" + s.bucket
+		if key != "" {
+			u.Path += "/" + key
+		}
+	} else {
+		u.Host = s.bucket + "." + s.endpoint.Host
+		u.Path = s.endpoint.Path + "/" + key
+	}
+	// Send the path exactly as it is signed
+	u.RawPath = canonicalURI(&u)
+	u.RawQuery = canonicalQuery(q)
+	return &u
+}
+
+// do signs and sends one request. Non-2xx answers become *Error, or apperr.ErrObjectNotFound for missing keys.
+func (s *Storage) do(ctx context.Context, method string, u *url.URL, body []byte, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	payloadHash := emptyPayloadHash
+	if len(body) > 0 {
+		payloadHash = hashHex(body)
+	}
+	s.signer.sign(req, payloadHash, time.Now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	apiErr := &Error{Status: resp.StatusCode}
+	_ = xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(apiErr)
+	if resp.StatusCode == http.StatusNotFound && (apiErr.Code == "NoSuchKey" || method == http.MethodHead) {
+		return nil, apperr.ErrObjectNotFound
+	}
+	return nil, apiErr
+}
+
+// Error is an S3 error response.
+type Error struct {
+	Status  int    `xml:"-"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("s3: status %d", e.Status)
+	}
+	return fmt.Sprintf("s3: %s: %s (status %d)", e.Code, e.Message, e.Status)
+}
+
+type completeUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+type completedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+func decodeXML(resp *http.Response, v any) error {
+	defer resp.Body.Close()
+	if err := xml.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("s3: decode response: %w", err)
+	}
+	return nil
+}
+
+func drain(resp *http.Response) error {
+	_, err := io.Copy(io.Discard, resp.Body)
+	if cerr := resp.Body.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+var _ ports.ObjectStorage = (*Storage)(nil)
@@ -0,0 +1,82 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/application/ports/portstest"
+)
+
+func newFakeStorage(t *testing.T, secretKey string) (*Storage, *fakeS3) {
+	t.Helper()
+	fake := newFakeS3("bucket", "access", "secret")
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	store, err := New(Config{Endpoint: srv.URL, Bucket: "bucket", AccessKey: "access", SecretKey: secretKey, PathStyle: true, PartSize: minPartSize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, fake
+}
+
+func TestStorage_Conformance(t *testing.T) {
+	store, fake := newFakeStorage(t, "secret")
+	if err := store.EnsureBucket(context.Background()); err != nil {
+		t.Fatalf("ensure bucket: %v", err)
+	}
+	portstest.TestObjectStorage(t, func(t *testing.T) ports.ObjectStorage { return store })
+	if fake.completed == 0 {
+		t.Fatalf("expected the large object to go through a multipart upload")
+	}
+	if len(fake.uploads) != 0 {
+		t.Fatalf("expected no unfinished multipart uploads, got %d", len(fake.uploads))
+	}
+}
+
+func TestStorage_SurfacesServiceErrors(t *testing.T) {
+	store, _ := newFakeStorage(t, "wrong-secret")
+	_, err := store.Put(context.Background(), "a.txt", strings.NewReader("x"), 1, "")
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden || apiErr.Code != "SignatureDoesNotMatch" {
+		t.Fatalf("expected a SignatureDoesNotMatch error, got %v", err)
+	}
+}
+
+func TestStorage_ValidatesConfigAndTTL(t *testing.T) {
+	for name, cfg := range map[string]Config{
+		"no endpoint":    {Bucket: "b", AccessKey: "a", SecretKey: "s"},
+		"bad scheme":     {Endpoint: "ftp://host", Bucket: "b", AccessKey: "a", SecretKey: "s"},
+		"no bucket":      {Endpoint: "http://host", AccessKey: "a", SecretKey: "s"},
+		"tiny part size": {Endpoint: "http://host", Bucket: "b", AccessKey: "a", SecretKey: "s", PartSize: 1 << 20},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+	store, _ := newFakeStorage(t, "secret")
+	for _, ttl := range []time.Duration{0, 8 * 24 * time.Hour} {
+		if _, err := store.PresignGet(context.Background(), "a.txt", ttl); err == nil {
+			t.Fatalf("ttl %s: expected an error", ttl)
+		}
+	}
+}
+
+func TestObjectURL_Addressing(t *testing.T) {
+	virtual, err := New(Config{Endpoint: "https://s3.eu-west-1.amazonaws.com", Region: "eu-west-1", Bucket: "b", AccessKey: "a", SecretKey: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := virtual.objectURL("dir/a b+c.txt", nil).String(); got != "https://b.s3.eu-west-1.amazonaws.com/dir/a%20b%2Bc.txt" {
+		t.Fatalf("virtual-hosted URL: %s", got)
+	}
+	path, _ := New(Config{Endpoint: "http://localhost:9000/", Bucket: "b", AccessKey: "a", SecretKey: "s", PathStyle: true})
+	if got := path.objectURL("dir/x.txt", nil).String(); got != "http://localhost:9000/b/dir/x.txt" {
+		t.Fatalf("path-style URL: %s", got)
+	}
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/usecase/userusecase"
+	"gostartkit/internal/interfaces/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler lets a signed-in user manage their own API keys.
+type APIKeyHandler struct {
+	keys userusecase.APIKeyUsecases
+}
+
+func NewAPIKeyHandler(keys userusecase.APIKeyUsecases) *APIKeyHandler {
+	return &APIKeyHandler{keys: keys}
+}
+
+// Create issues a key; the full key is in this response only.
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	req := c.MustGet("req").(dto.CreateAPIKeyRequest)
+	res, err := h.keys.Create(c.Request.Context(), userID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.Created(c, res)
+}
+
+func (h *APIKeyHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	res, err := h.keys.List(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, res)
+}
+
+func (h *APIKeyHandler) Get(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	res, err := h.keys.Get(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, res)
+}
+
+// Update renames a key or replaces its scopes.
+func (h *APIKeyHandler) Update(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	req := c.MustGet("req").(dto.UpdateAPIKeyRequest)
+	res, err := h.keys.Update(c.Request.Context(), userID, c.Param("id"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, res)
+}
+
+// Delete revokes a key immediately.
+func (h *APIKeyHandler) Delete(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	if err := h.keys.Delete(c.Request.Context(), userID, c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"deleted": true})
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"mime"
+	"net/http"
+
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/usecase/userusecase"
+	"gostartkit/internal/interfaces/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DataExportHandler serves the caller a copy of their personal data.
+type DataExportHandler struct {
+	uc userusecase.DataExportUsecases
+}
+
+func NewDataExportHandler(uc userusecase.DataExportUsecases) *DataExportHandler {
+	return &DataExportHandler{uc: uc}
+}
+
+// Export downloads the export as a JSON or zip attachment, or answers 202 when it is too large and will be emailed.
+func (h *DataExportHandler) Export(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	req := c.MustGet("query").(dto.DataExportRequest)
+	res, err := h.uc.Export(c.Request.Context(), userID, req.Format)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if res.Pending {
+		response.Accepted(c, dto.DataExportPendingResponse{Status: "pending", Delivery: "email"})
+		return
+	}
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": res.FileName}))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, res.ContentType, res.Content)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/usecase/userusecase"
+	"gostartkit/internal/interfaces/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailVerificationHandler confirms email addresses from emailed links.
+type EmailVerificationHandler struct {
+	uc userusecase.EmailVerificationUsecases
+}
+
+func NewEmailVerificationHandler(uc userusecase.EmailVerificationUsecases) *EmailVerificationHandler {
+	return &EmailVerificationHandler{uc: uc}
+}
+
+// Verify marks the email address in the token as verified.
+func (h *EmailVerificationHandler) Verify(c *gin.Context) {
+	req := c.MustGet("req").(dto.VerifyEmailRequest)
+	if err := h.uc.Verify(c.Request.Context(), req); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"verified": true})
+}
+
+// Resend answers the same way for every address so callers cannot tell whether the email is registered.
+func (h *EmailVerificationHandler) Resend(c *gin.Context) {
+	req := c.MustGet("req").(dto.ResendVerificationRequest)
+	if err := h.uc.Resend(c.Request.Context(), req); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"sent": true})
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"gostartkit/internal/interfaces/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondError maps an application/domain error through response.FromError and writes the envelope.
+func respondError(c *gin.Context, err error) {
+	status, code, msg := response.FromError(err)
+	switch status {
+	case 400:
+		response.BadRequest(c, code, msg)
+	case 401:
+		response.Unauthorized(c, code, msg)
+	case 403:
+		response.Forbidden(c, code, msg)
+	case 404:
+		response.NotFound(c, code, msg)
+	case 409:
+		response.Conflict(c, code, msg)
+	case 429:
+		response.TooManyRequests(c, code, msg)
+	case 503:
+		response.ServiceUnavailable(c, code, msg)
+	default:
+		response.InternalError(c, code, msg)
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/usecase/userusecase"
+	"gostartkit/internal/interfaces/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	magicLinkNonceCookie     = "magic_link_nonce"
+	magicLinkNonceCookiePath = "/v1/auth/magic-link"
+)
+
+// MagicLinkHandler serves passwordless sign-in. The nonce returned by the use case is kept in an HttpOnly
+// cookie, so the emailed link only works in the browser that asked for it.
+type MagicLinkHandler struct {
+	uc           userusecase.MagicLinkLogin
+	ttl          time.Duration
+	secureCookie bool
+}
+
+func NewMagicLinkHandler(uc userusecase.MagicLinkLogin, ttl time.Duration, secureCookie bool) *MagicLinkHandler {
+	return &MagicLinkHandler{uc: uc, ttl: ttl, secureCookie: secureCookie}
+}
+
+// Request answers the same way for every address so callers cannot tell whether the email is registered.
+func (h *MagicLinkHandler) Request(c *gin.Context) {
+	req := c.MustGet("req").(dto.MagicLinkRequest)
+	res, err := h.uc.Request(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.setNonceCookie(c, res.Nonce, int(h.ttl.Seconds()))
+	response.OK(c, gin.H{"sent": true})
+}
+
+// Consume exchanges the emailed token for tokens, exactly like POST /v1/auth/login.
+func (h *MagicLinkHandler) Consume(c *gin.Context) {
+	req := c.MustGet("req").(dto.MagicLinkConsumeRequest)
+	req.Nonce, _ = c.Cookie(magicLinkNonceCookie)
+	req.IP = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+	resp, err := h.uc.Consume(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.setNonceCookie(c, "", -1)
+	response.OK(c, resp)
+}
+
+func (h *MagicLinkHandler) setNonceCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(magicLinkNonceCookie, value, maxAge, magicLinkNonceCookiePath, "", h.secureCookie || c.Request.TLS != nil, true)
+}
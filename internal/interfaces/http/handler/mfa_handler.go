@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/usecase/userusecase"
+	"gostartkit/internal/interfaces/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFAHandler exposes TOTP enrollment for signed-in users and the second login step.
+type MFAHandler struct{ uc userusecase.MFAUsecases }
+
+func NewMFAHandler(uc userusecase.MFAUsecases) *MFAHandler { return &MFAHandler{uc: uc} }
+
+// Enroll starts (or restarts) an unconfirmed enrollment and returns the secret and otpauth URI.
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	res, err := h.uc.Enroll(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, res)
+}
+
+// Confirm activates the enrollment with a first code and returns the recovery codes (shown once).
+func (h *MFAHandler) Confirm(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	req := c.MustGet("req").(dto.MFACodeRequest)
+	res, err := h.uc.Confirm(c.Request.Context(), userID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, res)
+}
+
+// Disable removes the factor; requires a current TOTP or recovery code.
+func (h *MFAHandler) Disable(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	req := c.MustGet("req").(dto.MFACodeRequest)
+	if err := h.uc.Disable(c.Request.Context(), userID, req); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"disabled": true})
+}
+
+// Verify completes a login that returned mfa_required; the response matches POST /v1/auth/login.
+func (h *MFAHandler) Verify(c *gin.Context) {
+	req := c.MustGet("req").(dto.MFAVerifyRequest)
+	resp, err := h.uc.Verify(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, resp)
+}
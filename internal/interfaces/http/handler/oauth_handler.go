@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"net/url"
+
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/usecase/userusecase"
+	"gostartkit/internal/interfaces/http/middleware"
+	"gostartkit/internal/interfaces/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OAuthHandler serves the OAuth2 endpoints for services (token, introspection, revocation) and
+// admin management of registered clients.
+type OAuthHandler struct {
+	tokens     userusecase.OAuthClientCredentials
+	admin      userusecase.OAuthClientAdmin
+	introspect userusecase.TokenIntrospection
+}
+
+func NewOAuthHandler(tokens userusecase.OAuthClientCredentials, admin userusecase.OAuthClientAdmin, introspect userusecase.TokenIntrospection) *OAuthHandler {
+	return &OAuthHandler{tokens: tokens, admin: admin, introspect: introspect}
+}
+
+// Token is the RFC 6749 token endpoint for grant_type=client_credentials. Clients authenticate with
+// HTTP Basic (client_secret_basic) or client_id/client_secret form fields (client_secret_post).
+// Responses use the RFC 6749 JSON shape rather than the API envelope.
+func (h *OAuthHandler) Token(c *gin.Context) {
+	if !parseOAuthForm(c) {
+		return
+	}
+	switch c.PostForm("grant_type") {
+	case "client_credentials":
+	case "":
+		oauthError(c, 400, "invalid_request", "grant_type is required")
+		return
+	default:
+		oauthError(c, 400, "unsupported_grant_type", "only client_credentials is supported")
+		return
+	}
+	clientID, secret, ok := clientCredentials(c)
+	if !ok {
+		oauthError(c, 400, "invalid_request", "use exactly one client authentication method")
+		return
+	}
+	res, err := h.tokens.IssueToken(c.Request.Context(), dto.ClientCredentialsRequest{ClientID: clientID, ClientSecret: secret, Scope: c.PostForm("scope")})
+	if err != nil {
+		oauthRespondError(c, err)
+		return
+	}
+	c.JSON(200, res)
+}
+
+// Introspect is the RFC 7662 endpoint: it tells an authenticated client whether an access or refresh
+// token is active and returns its standard claims.
+func (h *OAuthHandler) Introspect(c *gin.Context) {
+	if !parseOAuthForm(c) {
+		return
+	}
+	clientID, secret, ok := clientCredentials(c)
+	if !ok {
+		oauthError(c, 400, "invalid_request", "use exactly one client authentication method")
+		return
+	}
+	token := c.PostForm("token")
+	if token == "" {
+		oauthError(c, 400, "invalid_request", "token is required")
+		return
+	}
+	res, err := h.introspect.Introspect(c.Request.Context(), dto.TokenIntrospectionRequest{
+		ClientID:      clientID,
+		ClientSecret:  secret,
+		Token:         token,
+		TokenTypeHint: c.PostForm("token_type_hint"),
+	})
+	if err != nil {
+		oauthRespondError(c, err)
+		return
+	}
+	c.JSON(200, res)
+}
+
+// Revoke is the RFC 7009 endpoint. It answers 200 with an empty body whether or not the token was valid.
+func (h *OAuthHandler) Revoke(c *gin.Context) {
+	if !parseOAuthForm(c) {
+		return
+	}
+	clientID, secret, ok := clientCredentials(c)
+	if !ok {
+		oauthError(c, 400, "invalid_request", "use exactly one client authentication method")
+		return
+	}
+	token := c.PostForm("token")
+	if token == "" {
+		oauthError(c, 400, "invalid_request", "token is required")
+		return
+	}
+	err := h.introspect.Revoke(c.Request.Context(), dto.TokenRevocationRequest{
+		ClientID:      clientID,
+		ClientSecret:  secret,
+		Token:         token,
+		TokenTypeHint: c.PostForm("token_type_hint"),
+	})
+	if err != nil {
+		oauthRespondError(c, err)
+		return
+	}
+	c.Status(200)
+}
+
+// CreateClient registers a service; the client secret is in this response only.
+// The client may only get scopes the caller itself holds.
+func (h *OAuthHandler) CreateClient(c *gin.Context) {
+	req := c.MustGet("req").(dto.CreateOAuthClientRequest)
+	res, err := h.admin.CreateClient(c.Request.Context(), req, func(scope string) bool {
+		return middleware.HasPermission(c, scope)
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.Created(c, res)
+}
+
+func (h *OAuthHandler) ListClients(c *gin.Context) {
+	res, err := h.admin.ListClients(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, res)
+}
+
+// DeleteClient removes a client; it can no longer obtain tokens.
+func (h *OAuthHandler) DeleteClient(c *gin.Context) {
+	if err := h.admin.DeleteClient(c.Request.Context(), c.Param("client_id")); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"deleted": true})
+}
+
+// parseOAuthForm sets the no-store headers OAuth responses need and parses the form body;
+// it writes an invalid_request error and returns false when the body cannot be parsed.
+func parseOAuthForm(c *gin.Context) bool {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	if err := c.Request.ParseForm(); err != nil {
+		oauthError(c, 400, "invalid_request", "request body must be application/x-www-form-urlencoded")
+		return false
+	}
+	return true
+}
+
+// clientCredentials reads client authentication from HTTP Basic or the form body. ok is false when
+// both are used (RFC 6749 section 2.3) or the Basic credentials are not form-urlencoded correctly.
+func clientCredentials(c *gin.Context) (clientID, secret string, ok bool) {
+	user, pass, basic := c.Request.BasicAuth()
+	if !basic {
+		return c.PostForm("client_id"), c.PostForm("client_secret"), true
+	}
+	if c.PostForm("client_secret") != "" {
+		return "", "", false
+	}
+	// Basic credentials are form-urlencoded before base64 encoding (RFC 6749 section 2.3.1)
+	clientID, err := url.QueryUnescape(user)
+	if err != nil {
+		return "", "", false
+	}
+	secret, err = url.QueryUnescape(pass)
+	if err != nil {
+		return "", "", false
+	}
+	return clientID, secret, true
+}
+
+// oauthRespondError maps an application error to an RFC 6749 section 5.2 error response.
+// The response codes used for these errors (invalid_client, invalid_scope, unauthorized_client, server_error) are the RFC's.
+func oauthRespondError(c *gin.Context, err error) {
+	status, code, msg := response.FromError(err)
+	if status == 401 {
+		c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+	oauthError(c, status, code, msg)
+}
+
+func oauthError(c *gin.Context, status int, code, description string) {
+	c.JSON(status, gin.H{"error": code, "error_description": description})
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/application/usecase/userusecase"
+	"gostartkit/internal/interfaces/http/middleware"
+	"gostartkit/pkg/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeClientCredentials struct{}
+
+func (fakeClientCredentials) IssueToken(_ context.Context, in dto.ClientCredentialsRequest) (*dto.OAuthTokenResponse, error) {
+	if in.ClientID != "svc_a" || in.ClientSecret != "s3cr+t" {
+		return nil, apperr.ErrInvalidClient
+	}
+	return &dto.OAuthTokenResponse{AccessToken: "tok", TokenType: "Bearer", ExpiresIn: 60, Scope: in.Scope}, nil
+}
+
+func postToken(t *testing.T, form url.Values, basicUser, basicPass string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	h := NewOAuthHandler(fakeClientCredentials{}, nil, nil)
+	r := gin.New()
+	r.POST("/oauth/token", h.Token)
+	req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if basicUser != "" {
+		req.SetBasicAuth(url.QueryEscape(basicUser), url.QueryEscape(basicPass))
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var body map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	return w, body
+}
+
+func TestOAuthHandler_Token(t *testing.T) {
+	w, body := postToken(t, url.Values{"grant_type": {"client_credentials"}, "scope": {"users:read"}}, "svc_a", "s3cr+t")
+	if w.Code != http.StatusOK || body["access_token"] != "tok" || body["scope"] != "users:read" || w.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("basic auth: %d %v", w.Code, body)
+	}
+	w, body = postToken(t, url.Values{"grant_type": {"client_credentials"}, "client_id": {"svc_a"}, "client_secret": {"s3cr+t"}}, "", "")
+	if w.Code != http.StatusOK || body["token_type"] != "Bearer" {
+		t.Fatalf("form auth: %d %v", w.Code, body)
+	}
+
+	w, body = postToken(t, url.Values{"grant_type": {"client_credentials"}}, "svc_a", "nope")
+	if w.Code != http.StatusUnauthorized || body["error"] != "invalid_client" || w.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("wrong secret: %d %v", w.Code, body)
+	}
+	w, body = postToken(t, url.Values{"grant_type": {"password"}}, "svc_a", "s3cr+t")
+	if w.Code != http.StatusBadRequest || body["error"] != "unsupported_grant_type" {
+		t.Fatalf("grant type: %d %v", w.Code, body)
+	}
+	w, body = postToken(t, url.Values{"grant_type": {"client_credentials"}, "client_secret": {"s3cr+t"}}, "svc_a", "s3cr+t")
+	if w.Code != http.StatusBadRequest || body["error"] != "invalid_request" {
+		t.Fatalf("two auth methods: %d %v", w.Code, body)
+	}
+}
+
+type fakeIntrospection struct{ revoked []string }
+
+func (fakeIntrospection) Introspect(_ context.Context, in dto.TokenIntrospectionRequest) (*dto.TokenIntrospectionResponse, error) {
+	if in.ClientID != "svc_a" {
+		return nil, apperr.ErrInvalidClient
+	}
+	return &dto.TokenIntrospectionResponse{Active: in.Token == "live", Subject: "u1"}, nil
+}
+
+func (f *fakeIntrospection) Revoke(_ context.Context, in dto.TokenRevocationRequest) error {
+	f.revoked = append(f.revoked, in.Token)
+	return nil
+}
+
+func TestOAuthHandler_IntrospectAndRevoke(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fi := &fakeIntrospection{}
+	h := NewOAuthHandler(nil, nil, fi)
+	r := gin.New()
+	r.POST("/oauth/introspect", h.Introspect)
+	r.POST("/oauth/revoke", h.Revoke)
+	post := func(path string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth("svc_a", "secret")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/oauth/introspect", url.Values{"token": {"live"}})
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"active":true`) {
+		t.Fatalf("introspect: %d %s", w.Code, w.Body.String())
+	}
+	if w := post("/oauth/introspect", url.Values{}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected missing token to be invalid_request, got %d", w.Code)
+	}
+	if w := post("/oauth/revoke", url.Values{"token": {"rt"}, "token_type_hint": {"refresh_token"}}); w.Code != http.StatusOK || w.Body.Len() != 0 {
+		t.Fatalf("revoke: %d %s", w.Code, w.Body.String())
+	}
+	if len(fi.revoked) != 1 || fi.revoked[0] != "rt" {
+		t.Fatalf("expected token to reach the use case, got %v", fi.revoked)
+	}
+}
+
+type fakeOAuthClientStore struct{ clients []ports.OAuthClient }
+
+func (f *fakeOAuthClientStore) Create(_ context.Context, c ports.OAuthClient) error {
+	f.clients = append(f.clients, c)
+	return nil
+}
+func (f *fakeOAuthClientStore) Get(context.Context, string) (ports.OAuthClient, error) {
+	return ports.OAuthClient{}, apperr.ErrOAuthClientNotFound
+}
+func (f *fakeOAuthClientStore) List(context.Context) ([]ports.OAuthClient, error) {
+	return f.clients, nil
+}
+func (f *fakeOAuthClientStore) Delete(context.Context, string) error { return nil }
+
+func TestOAuthHandler_CreateClientRefusesScopeEscalation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rbac.AddRolePermissions("oauth_test_operator", "oauth_clients:write", "users:read")
+	store := &fakeOAuthClientStore{}
+	h := NewOAuthHandler(nil, userusecase.NewOAuthClientUseCase(store, nil, 0), nil)
+	create := func(scopes []string, principal func(*gin.Context)) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.POST("/clients", func(c *gin.Context) { principal(c); c.Next() },
+			middleware.RequirePermissions("oauth_clients:write"),
+			func(c *gin.Context) {
+				c.Set("req", dto.CreateOAuthClientRequest{Name: "svc", Scopes: scopes})
+				c.Next()
+			},
+			h.CreateClient)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/clients", nil))
+		return w
+	}
+	operator := func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, "u1")
+		c.Set(middleware.ContextKeyUserRole, "oauth_test_operator")
+	}
+	// A service token that may only register clients
+	service := func(c *gin.Context) {
+		c.Set(middleware.ContextKeyClientID, "svc_ops")
+		c.Set(middleware.ContextKeyAuthMethod, middleware.AuthMethodClientCredentials)
+		c.Set(middleware.ContextKeyScopes, []string{"oauth_clients:write"})
+	}
+
+	for _, scopes := range [][]string{{"*"}, {"users:write"}, {"users:*"}} {
+		if w := create(scopes, operator); w.Code != http.StatusForbidden {
+			t.Fatalf("operator granting %v: expected 403, got %d %s", scopes, w.Code, w.Body.String())
+		}
+	}
+	if w := create([]string{"users:read"}, service); w.Code != http.StatusForbidden {
+		t.Fatalf("service token granting a scope it lacks: expected 403, got %d", w.Code)
+	}
+	if len(store.clients) != 0 {
+		t.Fatalf("expected no client to be registered, got %d", len(store.clients))
+	}
+	if w := create([]string{"users:read"}, operator); w.Code != http.StatusCreated {
+		t.Fatalf("operator granting a held scope: expected 201, got %d %s", w.Code, w.Body.String())
+	}
+}
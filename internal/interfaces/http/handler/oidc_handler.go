@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/usecase/userusecase"
+	"gostartkit/internal/interfaces/http/middleware"
+	"gostartkit/internal/interfaces/http/response"
+	"gostartkit/internal/interfaces/http/validation"
+	"gostartkit/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	oidcStateCookie     = "oidc_state"
+	oidcStateCookiePath = "/v1/auth/oidc"
+)
+
+// OIDCHandler runs the browser side of OpenID Connect login. The state parameter is also set as an
+// HttpOnly cookie so a callback is only accepted in the browser that started the login.
+type OIDCHandler struct {
+	uc           userusecase.OIDCLogin
+	stateTTL     time.Duration
+	secureCookie bool
+}
+
+func NewOIDCHandler(uc userusecase.OIDCLogin, stateTTL time.Duration, secureCookie bool) *OIDCHandler {
+	return &OIDCHandler{uc: uc, stateTTL: stateTTL, secureCookie: secureCookie}
+}
+
+// Start redirects to the provider, or returns the authorization URL as JSON when the client asks for JSON.
+func (h *OIDCHandler) Start(c *gin.Context) {
+	res, err := h.uc.Start(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.setStateCookie(c, res.State, int(h.stateTTL.Seconds()))
+	if strings.Contains(c.GetHeader("Accept"), "application/json") {
+		response.OK(c, res)
+		return
+	}
+	c.Redirect(http.StatusFound, res.AuthorizationURL)
+}
+
+// Callback completes the login and returns our own tokens, exactly like POST /v1/auth/login.
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	cookie, _ := c.Cookie(oidcStateCookie)
+	h.setStateCookie(c, "", -1)
+	if c.Query("error") != "" {
+		// The user denied consent or the provider rejected the request
+		logger.L().Info("oidc_provider_error", "provider", c.Param("provider"), "error", c.Query("error"))
+		respondError(c, apperr.ErrOIDCLoginFailed)
+		return
+	}
+	var req dto.OIDCCallbackRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		code, msg := validation.MapBindJSONErrorWithLocale(middleware.GetLocale(c), err)
+		response.BadRequest(c, code, msg)
+		return
+	}
+	if cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(req.State)) != 1 {
+		respondError(c, apperr.ErrOIDCStateInvalid)
+		return
+	}
+	req.Provider = c.Param("provider")
+	req.IP = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+	resp, err := h.uc.Callback(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, apperr.ErrOIDCLoginFailed) {
+			logger.L().Warn("oidc_login_failed", "provider", req.Provider, "error", err)
+		}
+		respondError(c, err)
+		return
+	}
+	response.OK(c, resp)
+}
+
+func (h *OIDCHandler) setStateCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode) // Lax: the provider redirect back is a top-level GET
+	c.SetCookie(oidcStateCookie, value, maxAge, oidcStateCookiePath, "", h.secureCookie || c.Request.TLS != nil, true)
+}
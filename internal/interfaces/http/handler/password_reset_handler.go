@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/usecase/userusecase"
+	"gostartkit/internal/interfaces/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasswordResetHandler serves the forgot/reset password flow.
+type PasswordResetHandler struct {
+	uc userusecase.PasswordResetUsecases
+}
+
+func NewPasswordResetHandler(uc userusecase.PasswordResetUsecases) *PasswordResetHandler {
+	return &PasswordResetHandler{uc: uc}
+}
+
+// Forgot answers the same way for every address so callers cannot tell whether the email is registered.
+func (h *PasswordResetHandler) Forgot(c *gin.Context) {
+	req := c.MustGet("req").(dto.ForgotPasswordRequest)
+	if err := h.uc.Forgot(c.Request.Context(), req); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"sent": true})
+}
+
+// Reset sets the new password; existing sessions are signed out.
+func (h *PasswordResetHandler) Reset(c *gin.Context) {
+	req := c.MustGet("req").(dto.ResetPasswordRequest)
+	if err := h.uc.Reset(c.Request.Context(), req); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"reset": true})
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"gostartkit/internal/application/usecase/userusecase"
+	"gostartkit/internal/interfaces/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHandler exposes the caller's own refresh-token sessions.
+type SessionHandler struct{ uc userusecase.SessionUsecases }
+
+func NewSessionHandler(uc userusecase.SessionUsecases) *SessionHandler {
+	return &SessionHandler{uc: uc}
+}
+
+// List returns the active sessions of the authenticated user, most recently used first.
+func (h *SessionHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	sessions, err := h.uc.List(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, sessions)
+}
+
+// Revoke ends one session of the authenticated user (e.g. "sign out that device").
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	if err := h.uc.Revoke(c.Request.Context(), userID, c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"revoked": true})
+}
+
+// LogoutAll ends every session of the authenticated user, including the current one.
+func (h *SessionHandler) LogoutAll(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	if err := h.uc.LogoutAll(c.Request.Context(), userID); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"revoked": true})
+}
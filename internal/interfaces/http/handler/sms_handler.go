@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/usecase/userusecase"
+	"gostartkit/internal/interfaces/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SMSHandler exposes phone number verification for signed-in users and login with SMS codes.
+type SMSHandler struct {
+	phone userusecase.PhoneUsecases
+	login userusecase.SMSLogin
+}
+
+func NewSMSHandler(phone userusecase.PhoneUsecases, login userusecase.SMSLogin) *SMSHandler {
+	return &SMSHandler{phone: phone, login: login}
+}
+
+// StartPhone texts a code to the new number; the number is saved once the code is confirmed.
+func (h *SMSHandler) StartPhone(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	req := c.MustGet("req").(dto.PhoneRequest)
+	if err := h.phone.StartVerification(c.Request.Context(), userID, req); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"sent": true})
+}
+
+// ConfirmPhone saves the number with the code received by SMS and returns the updated user.
+func (h *SMSHandler) ConfirmPhone(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	req := c.MustGet("req").(dto.SMSCodeRequest)
+	res, err := h.phone.ConfirmVerification(c.Request.Context(), userID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, res)
+}
+
+// RemovePhone clears the user's phone number; SMS login stops working for the account.
+func (h *SMSHandler) RemovePhone(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	if err := h.phone.Remove(c.Request.Context(), userID); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"removed": true})
+}
+
+// SendLoginCode answers the same way for every number so callers cannot tell whether it is registered.
+func (h *SMSHandler) SendLoginCode(c *gin.Context) {
+	req := c.MustGet("req").(dto.PhoneRequest)
+	if err := h.login.SendLoginCode(c.Request.Context(), req); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"sent": true})
+}
+
+// Login exchanges a code for tokens, exactly like POST /v1/auth/login.
+func (h *SMSHandler) Login(c *gin.Context) {
+	req := c.MustGet("req").(dto.SMSLoginRequest)
+	req.IP = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+	resp, err := h.login.Login(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, resp)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"time"
+
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/usecase/userusecase"
+	"gostartkit/internal/interfaces/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokenAdminHandler exposes admin operations on issued access tokens.
+type TokenAdminHandler struct{ revoker userusecase.AccessRevoker }
+
+func NewTokenAdminHandler(revoker userusecase.AccessRevoker) *TokenAdminHandler {
+	return &TokenAdminHandler{revoker: revoker}
+}
+
+// RevokeAccess denylists access tokens by jti or for every token of a user.
+func (h *TokenAdminHandler) RevokeAccess(c *gin.Context) {
+	req := c.MustGet("req").(dto.RevokeAccessRequest)
+	var err error
+	if req.UserID != "" {
+		err = h.revoker.RevokeByUser(c.Request.Context(), req.UserID)
+	} else {
+		var exp time.Time
+		if req.ExpiresAt != nil {
+			exp = *req.ExpiresAt
+		}
+		err = h.revoker.RevokeByJTI(c.Request.Context(), req.JTI, exp)
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"revoked": true})
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/usecase/userusecase"
+	domuser "gostartkit/internal/domain/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeRevoker records revocations; err, when set, fails every call.
+type fakeRevoker struct {
+	jti       string
+	expiresAt time.Time
+	userID    string
+	err       error
+}
+
+func (f *fakeRevoker) RevokeByJTI(_ context.Context, jti string, expiresAt time.Time) error {
+	f.jti, f.expiresAt = jti, expiresAt
+	return f.err
+}
+
+func (f *fakeRevoker) RevokeByUser(_ context.Context, userID string) error {
+	f.userID = userID
+	return f.err
+}
+
+var _ userusecase.AccessRevoker = (*fakeRevoker)(nil)
+
+func serveRevoke(t *testing.T, f *fakeRevoker, body string) (int, adminEnvelope) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	h := NewTokenAdminHandler(f)
+	r := gin.New()
+	r.POST("/revoke", func(c *gin.Context) {
+		var req dto.RevokeAccessRequest
+		if err := json.Unmarshal([]byte(body), &req); err != nil {
+			t.Fatalf("bad test body: %v", err)
+		}
+		c.Set("req", req)
+		h.RevokeAccess(c)
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/revoke", nil))
+	var env adminEnvelope
+	if err := json.Unmarshal(w.Body.Bytes(), &env); err != nil {
+		t.Fatalf("invalid JSON %q", w.Body.String())
+	}
+	return w.Code, env
+}
+
+func TestTokenAdminHandler_RevokeByJTI(t *testing.T) {
+	f := &fakeRevoker{}
+	status, env := serveRevoke(t, f, `{"jti":"abc","expires_at":"2030-01-02T03:04:05Z"}`)
+	if status != http.StatusOK || string(env.Data) != `{"revoked":true}` {
+		t.Fatalf("expected revoked, got %d %s", status, env.Data)
+	}
+	if f.jti != "abc" || !f.expiresAt.Equal(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)) || f.userID != "" {
+		t.Fatalf("expected a jti revocation with the given expiry, got %+v", f)
+	}
+
+	f = &fakeRevoker{}
+	if status, _ := serveRevoke(t, f, `{"jti":"abc"}`); status != http.StatusOK || !f.expiresAt.IsZero() {
+		t.Fatalf("expected a zero expiry when none is sent, got %d %v", status, f.expiresAt)
+	}
+}
+
+func TestTokenAdminHandler_RevokeByUser(t *testing.T) {
+	f := &fakeRevoker{}
+	status, _ := serveRevoke(t, f, `{"user_id":"`+adminTestUserID+`"}`)
+	if status != http.StatusOK || f.userID != adminTestUserID || f.jti != "" {
+		t.Fatalf("expected a user revocation, got %d %+v", status, f)
+	}
+}
+
+func TestTokenAdminHandler_Errors(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{domuser.ErrUserNotFound, http.StatusNotFound, "not_found"},
+		{apperr.ErrRevocationStoreNotConfigured, http.StatusServiceUnavailable, "not_configured"},
+	}
+	for _, tc := range cases {
+		status, env := serveRevoke(t, &fakeRevoker{err: tc.err}, `{"user_id":"`+adminTestUserID+`"}`)
+		if status != tc.status || env.Error.Code != tc.code {
+			t.Fatalf("%v: expected %d %s, got %d %q", tc.err, tc.status, tc.code, status, env.Error.Code)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/usecase/userusecase"
+	"gostartkit/internal/interfaces/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserAdminHandler exposes admin operations on user accounts.
+type UserAdminHandler struct {
+	users    userusecase.UserAdmin
+	lockouts userusecase.LoginLockoutAdmin
+}
+
+// NewUserAdminHandler builds the handler; lockouts may be nil when login lockout is not wired.
+func NewUserAdminHandler(users userusecase.UserAdmin, lockouts userusecase.LoginLockoutAdmin) *UserAdminHandler {
+	return &UserAdminHandler{users: users, lockouts: lockouts}
+}
+
+// List returns one page of users; paging details are in meta.
+func (h *UserAdminHandler) List(c *gin.Context) {
+	req := c.MustGet("query").(dto.ListUsersRequest)
+	page, err := h.users.List(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OKWithMeta(c, page.Users, page.Page)
+}
+
+func (h *UserAdminHandler) Get(c *gin.Context) {
+	u, err := h.users.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, u)
+}
+
+// Update changes the user's name and/or role.
+func (h *UserAdminHandler) Update(c *gin.Context) {
+	req := c.MustGet("req").(dto.AdminUpdateUserRequest)
+	u, err := h.users.Update(c.Request.Context(), c.GetString("user_id"), c.Param("id"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, u)
+}
+
+// Disable blocks sign-in and ends the user's sessions.
+func (h *UserAdminHandler) Disable(c *gin.Context) {
+	u, err := h.users.Disable(c.Request.Context(), c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, u)
+}
+
+func (h *UserAdminHandler) Enable(c *gin.Context) {
+	u, err := h.users.Enable(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, u)
+}
+
+func (h *UserAdminHandler) Delete(c *gin.Context) {
+	if err := h.users.Delete(c.Request.Context(), c.GetString("user_id"), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"deleted": true})
+}
+
+// Erase anonymizes the user's personal data (GDPR erasure) and keeps the ID.
+func (h *UserAdminHandler) Erase(c *gin.Context) {
+	u, err := h.users.Erase(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, u)
+}
+
+// Unlock lifts a failed-login lockout and clears the failure count.
+func (h *UserAdminHandler) Unlock(c *gin.Context) {
+	if err := h.lockouts.Unlock(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"unlocked": true})
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/application/usecase/userusecase"
+	domuser "gostartkit/internal/domain/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+const adminTestUserID = "7d3c2a8e-5f1b-4e0a-9c6d-2b8f4a1e3c5d"
+
+// fakeUserAdmin knows one user, adminTestUserID; other IDs are not found.
+type fakeUserAdmin struct {
+	disabled bool
+	deleted  bool
+	listed   dto.ListUsersRequest
+}
+
+func (f *fakeUserAdmin) user(id string) (*dto.AdminUserResponse, error) {
+	if id != adminTestUserID || f.deleted {
+		return nil, domuser.ErrUserNotFound
+	}
+	return &dto.AdminUserResponse{UserResponse: dto.UserResponse{Email: "user@example.com"}, Role: "user", Disabled: f.disabled}, nil
+}
+
+func (f *fakeUserAdmin) List(_ context.Context, in dto.ListUsersRequest) (*dto.AdminUserPage, error) {
+	f.listed = in
+	u, _ := f.user(adminTestUserID)
+	return &dto.AdminUserPage{Users: []dto.AdminUserResponse{*u}, Page: dto.PageMeta{Limit: in.Limit, NextCursor: "next", HasMore: true}}, nil
+}
+
+func (f *fakeUserAdmin) Get(_ context.Context, id string) (*dto.AdminUserResponse, error) {
+	return f.user(id)
+}
+
+func (f *fakeUserAdmin) Update(_ context.Context, _, id string, _ dto.AdminUpdateUserRequest) (*dto.AdminUserResponse, error) {
+	return f.user(id)
+}
+
+func (f *fakeUserAdmin) Disable(_ context.Context, _, id string) (*dto.AdminUserResponse, error) {
+	if _, err := f.user(id); err != nil {
+		return nil, err
+	}
+	f.disabled = true
+	return f.user(id)
+}
+
+func (f *fakeUserAdmin) Enable(_ context.Context, id string) (*dto.AdminUserResponse, error) {
+	if _, err := f.user(id); err != nil {
+		return nil, err
+	}
+	f.disabled = false
+	return f.user(id)
+}
+
+func (f *fakeUserAdmin) Delete(_ context.Context, _, id string) error {
+	if _, err := f.user(id); err != nil {
+		return err
+	}
+	f.deleted = true
+	return nil
+}
+
+func (f *fakeUserAdmin) Erase(_ context.Context, id string) (*dto.AdminUserResponse, error) {
+	return f.user(id)
+}
+
+var _ userusecase.UserAdmin = (*fakeUserAdmin)(nil)
+
+type adminEnvelope struct {
+	Data  json.RawMessage `json:"data"`
+	Meta  dto.PageMeta    `json:"meta"`
+	Error struct {
+		Code string `json:"code"`
+	} `json:"error"`
+}
+
+func newUserAdminRouter(f *fakeUserAdmin) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewUserAdminHandler(f, nil)
+	r := gin.New()
+	r.GET("/users", func(c *gin.Context) {
+		var q dto.ListUsersRequest
+		_ = c.ShouldBindQuery(&q)
+		c.Set("query", q)
+		h.List(c)
+	})
+	r.POST("/users/:id/disable", h.Disable)
+	r.POST("/users/:id/enable", h.Enable)
+	r.DELETE("/users/:id", h.Delete)
+	return r
+}
+
+func serveAdmin(t *testing.T, r *gin.Engine, method, path string) (int, adminEnvelope) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	var env adminEnvelope
+	if err := json.Unmarshal(w.Body.Bytes(), &env); err != nil {
+		t.Fatalf("%s %s: invalid JSON %q", method, path, w.Body.String())
+	}
+	return w.Code, env
+}
+
+func TestUserAdminHandler_List(t *testing.T) {
+	f := &fakeUserAdmin{}
+	status, env := serveAdmin(t, newUserAdminRouter(f), http.MethodGet, "/users?limit=10&role=user")
+	if status != http.StatusOK || !env.Meta.HasMore || env.Meta.NextCursor != "next" || env.Meta.Limit != 10 {
+		t.Fatalf("expected a page with paging meta, got %d %+v", status, env.Meta)
+	}
+	var users []dto.AdminUserResponse
+	if err := json.Unmarshal(env.Data, &users); err != nil || len(users) != 1 || users[0].Email != "user@example.com" {
+		t.Fatalf("expected the users as data, got %s", env.Data)
+	}
+	if f.listed.Role != "user" || f.listed.Limit != 10 {
+		t.Fatalf("expected query filters to reach the use case, got %+v", f.listed)
+	}
+}
+
+func TestUserAdminHandler_DisableEnable(t *testing.T) {
+	f := &fakeUserAdmin{}
+	r := newUserAdminRouter(f)
+	var u dto.AdminUserResponse
+
+	status, env := serveAdmin(t, r, http.MethodPost, "/users/"+adminTestUserID+"/disable")
+	if status != http.StatusOK || json.Unmarshal(env.Data, &u) != nil || !u.Disabled {
+		t.Fatalf("disable: %d %s", status, env.Data)
+	}
+	status, env = serveAdmin(t, r, http.MethodPost, "/users/"+adminTestUserID+"/enable")
+	if status != http.StatusOK || json.Unmarshal(env.Data, &u) != nil || u.Disabled {
+		t.Fatalf("enable: %d %s", status, env.Data)
+	}
+	if status, env := serveAdmin(t, r, http.MethodPost, "/users/unknown/disable"); status != http.StatusNotFound || env.Error.Code != "not_found" {
+		t.Fatalf("disable unknown user: expected 404 not_found, got %d %q", status, env.Error.Code)
+	}
+}
+
+func TestUserAdminHandler_Delete(t *testing.T) {
+	f := &fakeUserAdmin{}
+	r := newUserAdminRouter(f)
+	status, env := serveAdmin(t, r, http.MethodDelete, "/users/"+adminTestUserID)
+	if status != http.StatusOK || string(env.Data) != `{"deleted":true}` || !f.deleted {
+		t.Fatalf("delete: %d %s", status, env.Data)
+	}
+	if status, env := serveAdmin(t, r, http.MethodDelete, "/users/"+adminTestUserID); status != http.StatusNotFound || env.Error.Code != "not_found" {
+		t.Fatalf("second delete: expected 404 not_found, got %d %q", status, env.Error.Code)
+	}
+}
@@ -35,21 +35,11 @@ func (h *UserHandler) Register(c *gin.Context) {
 
 func (h *UserHandler) Login(c *gin.Context) {
 	req := c.MustGet("req").(dto.LoginRequest)
+	req.IP = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
 	resp, err := h.uc.Login(c.Request.Context(), req)
 	if err != nil {
-		status, code, msg := response.FromError(err)
-		switch status {
-		case 400:
-			response.BadRequest(c, code, msg)
-		case 401:
-			response.Unauthorized(c, code, msg)
-		case 404:
-			response.NotFound(c, code, msg)
-		case 409:
-			response.Conflict(c, code, msg)
-		default:
-			response.InternalError(c, code, msg)
-		}
+		respondError(c, err)
 		return
 	}
 	response.OK(c, resp)
@@ -57,29 +47,17 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 // Refresh exchanges a valid refresh token for a new access token (and rotated refresh token if applicable).
 func (h *UserHandler) Refresh(c *gin.Context) {
-	var body struct {
-		RefreshToken string `json:"refresh_token" binding:"required"`
-	}
+	var body dto.RefreshRequest
 	if err := c.ShouldBindJSON(&body); err != nil {
 		code, msg := validation.MapBindJSONErrorWithLocale(middleware.GetLocale(c), err)
 		response.BadRequest(c, code, msg)
 		return
 	}
-	resp, err := h.uc.Refresh(c.Request.Context(), body.RefreshToken)
+	body.IP = c.ClientIP()
+	body.UserAgent = c.Request.UserAgent()
+	resp, err := h.uc.Refresh(c.Request.Context(), body)
 	if err != nil {
-		status, code, msg := response.FromError(err)
-		switch status {
-		case 400:
-			response.BadRequest(c, code, msg)
-		case 401:
-			response.Unauthorized(c, code, msg)
-		case 404:
-			response.NotFound(c, code, msg)
-		case 409:
-			response.Conflict(c, code, msg)
-		default:
-			response.InternalError(c, code, msg)
-		}
+		respondError(c, err)
 		return
 	}
 	response.OK(c, resp)
@@ -96,19 +74,7 @@ func (h *UserHandler) Logout(c *gin.Context) {
 		return
 	}
 	if err := h.uc.Logout(c.Request.Context(), body.RefreshToken); err != nil {
-		status, code, msg := response.FromError(err)
-		switch status {
-		case 400:
-			response.BadRequest(c, code, msg)
-		case 401:
-			response.Unauthorized(c, code, msg)
-		case 404:
-			response.NotFound(c, code, msg)
-		case 409:
-			response.Conflict(c, code, msg)
-		default:
-			response.InternalError(c, code, msg)
-		}
+		respondError(c, err)
 		return
 	}
 	response.OK(c, gin.H{"revoked": true})
@@ -133,6 +99,23 @@ func (h *UserHandler) GetMe(c *gin.Context) {
 	response.OK(c, res)
 }
 
+// UpdateMe applies a partial profile update. An email change answers with pending_email until the link
+// sent to the new address is opened.
+func (h *UserHandler) UpdateMe(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Unauthorized(c, "unauthorized", "missing user context")
+		return
+	}
+	req := c.MustGet("req").(dto.UpdateMeRequest)
+	res, err := h.uc.UpdateMe(c.Request.Context(), userID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, res)
+}
+
 func (h *UserHandler) ChangePassword(c *gin.Context) {
 	userID := c.GetString("user_id")
 	if userID == "" {
@@ -22,10 +22,15 @@ func (ucStub) Register(context.Context, dto.CreateUserRequest) (*dto.UserRespons
 func (ucStub) Login(context.Context, dto.LoginRequest) (*dto.LoginResponse, error) {
 	return &dto.LoginResponse{AccessToken: "token", User: dto.UserResponse{Email: "user@example.com"}}, nil
 }
-func (ucStub) GetMe(context.Context, string) (*dto.UserResponse, error)                { return nil, nil }
+func (ucStub) GetMe(context.Context, string) (*dto.UserResponse, error) { return nil, nil }
+func (ucStub) UpdateMe(context.Context, string, dto.UpdateMeRequest) (*dto.UpdateMeResponse, error) {
+	return nil, nil
+}
 func (ucStub) ChangePassword(context.Context, string, dto.ChangePasswordRequest) error { return nil }
-func (ucStub) Refresh(context.Context, string) (*dto.LoginResponse, error)             { return nil, nil }
-func (ucStub) Logout(context.Context, string) error                                    { return nil }
+func (ucStub) Refresh(context.Context, dto.RefreshRequest) (*dto.LoginResponse, error) {
+	return nil, nil
+}
+func (ucStub) Logout(context.Context, string) error { return nil }
 
 func TestLogin_Handler_ExternalPackage(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"context"
+	"gostartkit/internal/application/apperr"
 	"gostartkit/internal/application/dto"
 	"gostartkit/internal/application/usecase/userusecase"
 
@@ -23,11 +24,16 @@ func (fakeUserUC) Login(_ context.Context, input dto.LoginRequest) (*dto.LoginRe
 	return &dto.LoginResponse{AccessToken: "token", User: dto.UserResponse{Email: input.Email}}, nil
 }
 func (fakeUserUC) GetMe(_ context.Context, _ string) (*dto.UserResponse, error) { return nil, nil }
+func (fakeUserUC) UpdateMe(_ context.Context, _ string, _ dto.UpdateMeRequest) (*dto.UpdateMeResponse, error) {
+	return nil, nil
+}
 func (fakeUserUC) ChangePassword(_ context.Context, _ string, _ dto.ChangePasswordRequest) error {
 	return nil
 }
-func (fakeUserUC) Refresh(_ context.Context, _ string) (*dto.LoginResponse, error) { return nil, nil }
-func (fakeUserUC) Logout(_ context.Context, _ string) error                        { return nil }
+func (fakeUserUC) Refresh(_ context.Context, _ dto.RefreshRequest) (*dto.LoginResponse, error) {
+	return nil, nil
+}
+func (fakeUserUC) Logout(_ context.Context, _ string) error { return nil }
 
 var _ userusecase.UserUsecases = (*fakeUserUC)(nil)
 
@@ -57,3 +63,73 @@ func TestUserHandler_Login_OK(t *testing.T) {
 		t.Fatalf("expected 200, got %d", w.Code)
 	}
 }
+
+// failingUserUC fails Login, Refresh and Logout with err.
+type failingUserUC struct {
+	fakeUserUC
+	err error
+}
+
+func (f failingUserUC) Login(context.Context, dto.LoginRequest) (*dto.LoginResponse, error) {
+	return nil, f.err
+}
+
+func (f failingUserUC) Refresh(context.Context, dto.RefreshRequest) (*dto.LoginResponse, error) {
+	return nil, f.err
+}
+
+func (f failingUserUC) Logout(context.Context, string) error { return f.err }
+
+// serveUserRoute registers route on a fresh engine, sends body and returns the status and error code.
+func serveUserRoute(t *testing.T, path string, handle gin.HandlerFunc, body any) (int, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST(path, handle)
+	raw, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var env struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &env)
+	return w.Code, env.Error.Code
+}
+
+func TestUserHandler_Login_EmailVerificationRequired(t *testing.T) {
+	h := NewUserHandler(failingUserUC{err: apperr.ErrEmailVerificationRequired})
+	login := func(c *gin.Context) {
+		c.Set("req", dto.LoginRequest{Email: "user@example.com", Password: "pass"})
+		h.Login(c)
+	}
+	status, code := serveUserRoute(t, "/v1/auth/login", login, nil)
+	if status != http.StatusForbidden || code != "email_verification_required" {
+		t.Fatalf("expected 403 email_verification_required, got %d %q", status, code)
+	}
+}
+
+func TestUserHandler_AccountDisabled(t *testing.T) {
+	h := NewUserHandler(failingUserUC{err: apperr.ErrAccountDisabled})
+	login := func(c *gin.Context) {
+		c.Set("req", dto.LoginRequest{Email: "user@example.com", Password: "pass"})
+		h.Login(c)
+	}
+	tests := []struct {
+		name   string
+		handle gin.HandlerFunc
+	}{
+		{"login", login},
+		{"refresh", h.Refresh},
+		{"logout", h.Logout},
+	}
+	for _, tt := range tests {
+		status, code := serveUserRoute(t, "/"+tt.name, tt.handle, map[string]string{"refresh_token": "rt"})
+		if status != http.StatusForbidden || code != "account_disabled" {
+			t.Fatalf("%s: expected 403 account_disabled, got %d %q", tt.name, status, code)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gostartkit/internal/application/ports"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WellKnownHandler serves public discovery documents (JWKS, OpenID configuration).
+// Responses are plain JSON (no envelope) because consumers are standard JOSE/OIDC libraries.
+type WellKnownHandler struct {
+	keys    ports.KeySetPublisher
+	issuer  string
+	jwksURI string
+	maxAge  int
+}
+
+// NewWellKnownHandler creates the handler. jwks_uri is built from issuer when it is an absolute URL,
+// otherwise from publicBaseURL; request headers are never used, so a spoofed Host cannot redirect
+// cached discovery documents. maxAgeSec controls Cache-Control for both documents; if non-positive, 300 seconds is used.
+func NewWellKnownHandler(keys ports.KeySetPublisher, issuer, publicBaseURL string, maxAgeSec int) *WellKnownHandler {
+	if maxAgeSec <= 0 {
+		maxAgeSec = 300
+	}
+	return &WellKnownHandler{keys: keys, issuer: issuer, jwksURI: jwksURI(issuer, publicBaseURL), maxAge: maxAgeSec}
+}
+
+// JWKS returns the active verification keys.
+func (h *WellKnownHandler) JWKS(c *gin.Context) {
+	h.setCacheHeaders(c)
+	c.JSON(http.StatusOK, h.keys.PublicKeySet())
+}
+
+// OpenIDConfiguration returns a minimal discovery document pointing at the JWKS.
+func (h *WellKnownHandler) OpenIDConfiguration(c *gin.Context) {
+	algs := make([]string, 0)
+	seen := map[string]struct{}{}
+	for _, k := range h.keys.PublicKeySet().Keys {
+		if _, ok := seen[k.Alg]; ok || k.Alg == "" {
+			continue
+		}
+		seen[k.Alg] = struct{}{}
+		algs = append(algs, k.Alg)
+	}
+	h.setCacheHeaders(c)
+	c.JSON(http.StatusOK, gin.H{
+		"issuer":                                h.issuer,
+		"jwks_uri":                              h.jwksURI,
+		"id_token_signing_alg_values_supported": algs,
+		"subject_types_supported":               []string{"public"},
+		"response_types_supported":              []string{"token"},
+	})
+}
+
+func (h *WellKnownHandler) setCacheHeaders(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(h.maxAge))
+}
+
+// jwksURI prefers an absolute issuer URL, then the configured public base URL. Without either it falls
+// back to the path alone, which clients resolve against the discovery document's own URL.
+func jwksURI(issuer, publicBaseURL string) string {
+	base := ""
+	switch {
+	case strings.HasPrefix(issuer, "https://") || strings.HasPrefix(issuer, "http://"):
+		base = issuer
+	case publicBaseURL != "":
+		base = publicBaseURL
+	}
+	return strings.TrimSuffix(base, "/") + "/.well-known/jwks.json"
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gostartkit/internal/application/ports"
+
+	"github.com/gin-gonic/gin"
+)
+
+type staticKeySet ports.JSONWebKeySet
+
+func (s staticKeySet) PublicKeySet() ports.JSONWebKeySet { return ports.JSONWebKeySet(s) }
+
+func TestWellKnown_JWKSURIIgnoresRequestHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keys := staticKeySet{Keys: []ports.JSONWebKey{{Kty: "RSA", Kid: "k1", Alg: "RS256"}}}
+	cases := []struct {
+		name, issuer, base, want string
+	}{
+		{"absolute issuer", "https://auth.example.com/", "https://api.example.com", "https://auth.example.com/.well-known/jwks.json"},
+		{"public base url", "app", "https://api.example.com/", "https://api.example.com/.well-known/jwks.json"},
+		{"nothing configured", "app", "", "/.well-known/jwks.json"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewWellKnownHandler(keys, tc.issuer, tc.base, 0)
+			r := gin.New()
+			r.GET("/.well-known/openid-configuration", h.OpenIDConfiguration)
+			req := httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil)
+			req.Host = "evil.example"
+			req.Header.Set("X-Forwarded-Proto", "https")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			var doc struct {
+				Issuer  string   `json:"issuer"`
+				JWKSURI string   `json:"jwks_uri"`
+				Algs    []string `json:"id_token_signing_alg_values_supported"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if doc.JWKSURI != tc.want {
+				t.Fatalf("jwks_uri = %q, want %q", doc.JWKSURI, tc.want)
+			}
+			if doc.Issuer != tc.issuer || len(doc.Algs) != 1 || doc.Algs[0] != "RS256" {
+				t.Fatalf("unexpected document %+v", doc)
+			}
+			if got := w.Header().Get("Cache-Control"); got != "public, max-age=300" {
+				t.Fatalf("Cache-Control = %q", got)
+			}
+		})
+	}
+}
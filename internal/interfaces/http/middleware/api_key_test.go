@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testKeyPrefix = "gsk_"
+
+// fakeKeys knows one live key; "gsk_revoked" and "gsk_expired" fail like keys the store refuses.
+func fakeKeys(calls *int) APIKeyAuthenticator {
+	return func(_ context.Context, key string) (APIKeyPrincipal, error) {
+		*calls++
+		if key != "gsk_live" {
+			return APIKeyPrincipal{}, errors.New("api_key_invalid")
+		}
+		return APIKeyPrincipal{UserID: "key-owner", Role: "user", KeyID: "k1", Scopes: []string{"users:read"}}, nil
+	}
+}
+
+// fakeJWT accepts the token "jwt" as user "jwt-user" and records whether it was consulted.
+func fakeJWT(calls *int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		*calls++
+		if extractBearerToken(c.GetHeader("Authorization")) != "jwt" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Set(ContextKeyUserID, "jwt-user")
+		c.Set(ContextKeyUserRole, "user")
+		c.Next()
+	}
+}
+
+// whoAmI answers with the principal and auth method the middleware left in the context.
+func whoAmI(c *gin.Context) {
+	c.String(http.StatusOK, c.GetString(ContextKeyUserID)+"|"+c.GetString(ContextKeyAuthMethod)+"|"+strings.Join(c.GetStringSlice(ContextKeyScopes), ","))
+}
+
+func serveWithHeaders(r *gin.Engine, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestJWTOrAPIKeyAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		name      string
+		headers   map[string]string
+		status    int
+		body      string
+		jwtCalled bool
+	}{
+		{"jwt bearer", map[string]string{"Authorization": "Bearer jwt"}, http.StatusOK, "jwt-user||", true},
+		{"key as bearer", map[string]string{"Authorization": "Bearer gsk_live"}, http.StatusOK, "key-owner|api_key|users:read", false},
+		{"X-API-Key header", map[string]string{HeaderAPIKey: "gsk_live"}, http.StatusOK, "key-owner|api_key|users:read", false},
+		// A key header wins over a JWT, so the JWT is never consulted
+		{"key header beats jwt", map[string]string{HeaderAPIKey: "gsk_live", "Authorization": "Bearer jwt"}, http.StatusOK, "key-owner|api_key|users:read", false},
+		{"revoked key", map[string]string{"Authorization": "Bearer gsk_revoked"}, http.StatusUnauthorized, "", false},
+		{"expired key", map[string]string{HeaderAPIKey: "gsk_expired"}, http.StatusUnauthorized, "", false},
+		// A bad key header is not retried as a JWT
+		{"bad key with valid jwt", map[string]string{HeaderAPIKey: "gsk_revoked", "Authorization": "Bearer jwt"}, http.StatusUnauthorized, "", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var jwtCalls, keyCalls int
+			r := gin.New()
+			r.GET("/", JWTOrAPIKeyAuth(fakeJWT(&jwtCalls), testKeyPrefix, fakeKeys(&keyCalls)), whoAmI)
+			w := serveWithHeaders(r, tc.headers)
+			if w.Code != tc.status {
+				t.Fatalf("status: got %d, want %d (%s)", w.Code, tc.status, w.Body.String())
+			}
+			if tc.status == http.StatusOK && w.Body.String() != tc.body {
+				t.Fatalf("principal: got %q, want %q", w.Body.String(), tc.body)
+			}
+			if tc.status == http.StatusUnauthorized && !strings.Contains(w.Header().Get("WWW-Authenticate"), "invalid_token") {
+				t.Fatalf("expected a WWW-Authenticate challenge, got %q", w.Header().Get("WWW-Authenticate"))
+			}
+			if (jwtCalls > 0) != tc.jwtCalled {
+				t.Fatalf("jwt consulted: got %v, want %v", jwtCalls > 0, tc.jwtCalled)
+			}
+			if (keyCalls > 0) == tc.jwtCalled {
+				t.Fatalf("key authenticator consulted: got %v, want %v", keyCalls > 0, !tc.jwtCalled)
+			}
+		})
+	}
+}
+
+func TestDenyAPIKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var jwtCalls, keyCalls int
+	r := gin.New()
+	r.GET("/", JWTOrAPIKeyAuth(fakeJWT(&jwtCalls), testKeyPrefix, fakeKeys(&keyCalls)), DenyAPIKeys(), whoAmI)
+
+	if w := serveWithHeaders(r, map[string]string{HeaderAPIKey: "gsk_live"}); w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), `"forbidden"`) {
+		t.Fatalf("api key: expected 403 forbidden, got %d %s", w.Code, w.Body.String())
+	}
+	if w := serveWithHeaders(r, map[string]string{"Authorization": "Bearer gsk_live"}); w.Code != http.StatusForbidden {
+		t.Fatalf("api key as bearer: expected 403, got %d", w.Code)
+	}
+	if w := serveWithHeaders(r, map[string]string{"Authorization": "Bearer jwt"}); w.Code != http.StatusOK || w.Body.String() != "jwt-user||" {
+		t.Fatalf("jwt: expected 200, got %d %s", w.Code, w.Body.String())
+	}
+}
@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	resp "gostartkit/internal/interfaces/http/response"
 
@@ -12,17 +14,50 @@ const (
 	ContextKeyUserID    = "user_id"
 	ContextKeyUserRole  = "user_role"
 	ContextKeyJWTClaims = "jwt_claims"
+	// ContextKeyClientID is set instead of ContextKeyUserID for OAuth2 client (service) tokens.
+	ContextKeyClientID = "client_id"
+
+	AuthMethodClientCredentials = "client_credentials"
 )
 
 // TokenValidator validates a token string and returns subject (userID) and role.
 type TokenValidator func(token string) (subject string, role string, err error)
 
+// AccessClaims is the subset of access-token claims the HTTP layer works with.
+// It is stored in context under ContextKeyJWTClaims.
+type AccessClaims struct {
+	Subject   string
+	Role      string
+	ID        string // jti
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	// ClientID and Scopes are set for tokens issued to OAuth2 clients; such tokens have no user or role.
+	ClientID string
+	Scopes   []string
+}
+
+// ClaimsValidator validates a token string and returns its claims.
+type ClaimsValidator func(token string) (AccessClaims, error)
+
+// RevocationChecker reports whether validated claims have been revoked (denylisted).
+// Returning an error rejects the request; callers decide fail-open/closed by what they return.
+type RevocationChecker func(ctx context.Context, claims AccessClaims) (revoked bool, err error)
+
 func JWTAuth(validator TokenValidator) gin.HandlerFunc {
+	return JWTAuthWithClaims(func(token string) (AccessClaims, error) {
+		subject, role, err := validator(token)
+		return AccessClaims{Subject: subject, Role: role}, err
+	}, nil)
+}
+
+// JWTAuthWithClaims is JWTAuth with full claims and an optional revocation check (nil = no check).
+func JWTAuthWithClaims(validator ClaimsValidator, revoked RevocationChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := strings.TrimSpace(c.GetHeader("Authorization"))
 		if authHeader == "" {
 			c.Header("WWW-Authenticate", `Bearer realm="api", error="invalid_request", error_description="missing Authorization header"`)
 			resp.Unauthorized(c, resp.CodeUnauthorized, "missing or invalid token")
+			c.Abort()
 			return
 		}
 
@@ -30,18 +65,43 @@ func JWTAuth(validator TokenValidator) gin.HandlerFunc {
 		if tokenStr == "" {
 			c.Header("WWW-Authenticate", `Bearer realm="api", error="invalid_request", error_description="expected Bearer token"`)
 			resp.Unauthorized(c, resp.CodeUnauthorized, "missing or invalid token")
+			c.Abort()
 			return
 		}
 
-		subject, role, err := validator(tokenStr)
+		claims, err := validator(tokenStr)
 		if err != nil {
 			c.Header("WWW-Authenticate", `Bearer realm="api", error="invalid_token", error_description="token invalid or expired"`)
 			resp.Unauthorized(c, resp.CodeUnauthorized, "invalid token")
+			c.Abort()
 			return
 		}
+		if revoked != nil {
+			isRevoked, err := revoked(c.Request.Context(), claims)
+			if err != nil || isRevoked {
+				c.Header("WWW-Authenticate", `Bearer realm="api", error="invalid_token", error_description="token revoked"`)
+				resp.Unauthorized(c, resp.CodeUnauthorized, "invalid token")
+				c.Abort()
+				return
+			}
+		}
 
-		c.Set(ContextKeyUserID, subject)
-		c.Set(ContextKeyUserRole, role)
+		c.Set(ContextKeyJWTClaims, claims)
+		if claims.ClientID != "" {
+			// Service principal: no user_id/user_role, so user handlers cannot mistake it for a user;
+			// RequirePermissions checks its scopes instead of a role
+			scopes := claims.Scopes
+			if scopes == nil {
+				scopes = []string{}
+			}
+			c.Set(ContextKeyClientID, claims.ClientID)
+			c.Set(ContextKeyAuthMethod, AuthMethodClientCredentials)
+			c.Set(ContextKeyScopes, scopes)
+			c.Next()
+			return
+		}
+		c.Set(ContextKeyUserID, claims.Subject)
+		c.Set(ContextKeyUserRole, claims.Role)
 		c.Next()
 	}
 }
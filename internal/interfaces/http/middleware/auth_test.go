@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// claimsFor accepts "user-token" and "client-token"; anything else is invalid.
+func claimsFor(token string) (AccessClaims, error) {
+	switch token {
+	case "user-token":
+		return AccessClaims{Subject: "u1", Role: "user", ID: "jti-user"}, nil
+	case "client-token":
+		return AccessClaims{Subject: "svc", ID: "jti-client", ClientID: "svc", Scopes: []string{"users:read"}}, nil
+	}
+	return AccessClaims{}, errors.New("invalid token")
+}
+
+func serveJWT(revoked RevocationChecker, token string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", JWTAuthWithClaims(claimsFor, revoked), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(ContextKeyUserID)+"|"+c.GetString(ContextKeyClientID))
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestJWTAuthWithClaims_Revocation(t *testing.T) {
+	denylist := func(_ context.Context, c AccessClaims) (bool, error) { return c.ID == "jti-user", nil }
+
+	w := serveJWT(denylist, "user-token")
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Header().Get("WWW-Authenticate"), "token revoked") {
+		t.Fatalf("revoked token: expected 401 with a revoked challenge, got %d %q", w.Code, w.Header().Get("WWW-Authenticate"))
+	}
+	if w := serveJWT(denylist, "client-token"); w.Code != http.StatusOK || w.Body.String() != "|svc" {
+		t.Fatalf("other token: expected 200 as the client, got %d %s", w.Code, w.Body.String())
+	}
+	// The checker decides fail-open/closed: an error it returns rejects the request
+	failing := func(context.Context, AccessClaims) (bool, error) { return false, errors.New("redis down") }
+	if w := serveJWT(failing, "user-token"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("failed check: expected 401, got %d", w.Code)
+	}
+	if w := serveJWT(nil, "user-token"); w.Code != http.StatusOK || w.Body.String() != "u1|" {
+		t.Fatalf("no checker: expected 200 as the user, got %d %s", w.Code, w.Body.String())
+	}
+	if w := serveJWT(denylist, "forged"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("invalid token: expected 401, got %d", w.Code)
+	}
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -10,7 +11,7 @@ import (
 	"golang.org/x/time/rate"
 )
 
-// simple in-memory rate limiter per IP and path
+// simple in-memory rate limiter per IP (or other key) and path
 type limiterKey struct {
 	ip   string
 	path string
@@ -106,3 +107,33 @@ func RateLimitForPath(targetPath string, rps float64, burst int) gin.HandlerFunc
 		c.Next()
 	}
 }
+
+// RateLimitByKey limits requests per key (e.g. the email or phone number in the request body) for the routes it
+// is attached to (in-memory, single instance). It must run after the middleware that makes the key available;
+// requests without a key pass. name separates the buckets of routes sharing an extractor.
+func RateLimitByKey(name string, rps float64, burst int, extract func(*gin.Context) string) gin.HandlerFunc {
+	if rps <= 0 || burst <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	store := newRateStore()
+	return func(c *gin.Context) {
+		key := strings.ToLower(strings.TrimSpace(extract(c)))
+		if key == "" {
+			c.Next()
+			return
+		}
+		if !store.get(key, name, rate.Limit(rps), burst).AllowN(time.Now(), 1) {
+			retryAfterSec := int(1.0 / rps)
+			if retryAfterSec < 1 {
+				retryAfterSec = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(retryAfterSec))
+			c.Header("X-RateLimit-Limit", strconv.FormatFloat(rps, 'f', -1, 64))
+			c.Header("X-RateLimit-Remaining", "0")
+			c.Header("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Duration(retryAfterSec)*time.Second).Unix(), 10))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": gin.H{"code": "too_many_requests", "message": "too many requests"}})
+			return
+		}
+		c.Next()
+	}
+}
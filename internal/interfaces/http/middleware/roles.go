@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	resp "gostartkit/internal/interfaces/http/response"
 	"gostartkit/pkg/logger"
 	"gostartkit/pkg/rbac"
 	"net/http"
@@ -30,20 +31,18 @@ func RequireRoles(allowedRoles ...string) gin.HandlerFunc {
 }
 
 // RequirePermissions checks if the user's role grants any of the required permissions (RBAC policy).
+// Requests authenticated with an API key additionally need a key scope granting that permission;
+// OAuth2 client tokens have no role and are authorized by their scopes alone.
 func RequirePermissions(perms ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		roleVal, exists := c.Get(ContextKeyUserRole)
-		if !exists {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
-			return
-		}
-		role, _ := roleVal.(string)
-		if !rbac.RoleExists(role) {
-			// Warn about unknown role in policy to help misconfig detection
-			logger.L().Warn("unknown_role", "role", role, "request_id", c.GetString(ContextKeyRequestID))
+		if c.GetString(ContextKeyAuthMethod) != AuthMethodClientCredentials {
+			if role := c.GetString(ContextKeyUserRole); role != "" && !rbac.RoleExists(role) {
+				// Warn about unknown role in policy to help misconfig detection
+				logger.L().Warn("unknown_role", "role", role, "request_id", c.GetString(ContextKeyRequestID))
+			}
 		}
 		for _, p := range perms {
-			if rbac.HasPermission(role, p) {
+			if HasPermission(c, p) {
 				c.Next()
 				return
 			}
@@ -51,3 +50,34 @@ func RequirePermissions(perms ...string) gin.HandlerFunc {
 		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 	}
 }
+
+// HasPermission reports whether the authenticated caller holds perm, which may itself be a pattern such as
+// "users:*": the role must grant it and, for API keys and client tokens, a scope must grant it too.
+func HasPermission(c *gin.Context, perm string) bool {
+	scopes, scoped := c.Get(ContextKeyScopes)
+	granted, _ := scopes.([]string)
+	if c.GetString(ContextKeyAuthMethod) == AuthMethodClientCredentials {
+		return rbac.AnyMatches(granted, perm)
+	}
+	role, exists := c.Get(ContextKeyUserRole)
+	if !exists {
+		return false
+	}
+	name, _ := role.(string)
+	if !rbac.HasPermission(name, perm) {
+		return false
+	}
+	return !scoped || rbac.AnyMatches(granted, perm)
+}
+
+// RequireUser rejects service principals (OAuth2 client tokens) on routes that act on the signed-in user.
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString(ContextKeyUserID) == "" && c.GetString(ContextKeyClientID) != "" {
+			resp.Forbidden(c, resp.CodeUserTokenRequired, resp.MsgUserTokenRequired)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// principal is what an auth middleware would leave in the context.
+type principal struct {
+	userID, role, clientID, method string
+	scopes                         []string
+}
+
+func (p principal) set(c *gin.Context) {
+	if p.userID != "" {
+		c.Set(ContextKeyUserID, p.userID)
+	}
+	if p.role != "" {
+		c.Set(ContextKeyUserRole, p.role)
+	}
+	if p.clientID != "" {
+		c.Set(ContextKeyClientID, p.clientID)
+	}
+	if p.method != "" {
+		c.Set(ContextKeyAuthMethod, p.method)
+	}
+	if p.scopes != nil {
+		c.Set(ContextKeyScopes, p.scopes)
+	}
+	c.Next()
+}
+
+func client(scopes ...string) principal {
+	return principal{clientID: "svc", method: AuthMethodClientCredentials, scopes: append([]string{}, scopes...)}
+}
+
+func serveAs(p principal, guards ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	handlers := append([]gin.HandlerFunc{p.set}, guards...)
+	r.GET("/", append(handlers, func(c *gin.Context) { c.Status(http.StatusOK) })...)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	return w
+}
+
+func TestRequirePermissions(t *testing.T) {
+	cases := []struct {
+		name   string
+		who    principal
+		perm   string
+		status int
+	}{
+		{"client with exact scope", client("users:read"), "users:read", http.StatusOK},
+		{"client with prefix wildcard", client("users:*"), "users:read", http.StatusOK},
+		{"client with global wildcard", client("*"), "users:delete", http.StatusOK},
+		{"client with other scope", client("orders:read"), "users:read", http.StatusForbidden},
+		{"client wildcard does not cross prefixes", client("users:*"), "usersx:read", http.StatusForbidden},
+		{"client without scopes", client(), "users:read", http.StatusForbidden},
+		// A client token claiming a role still gets nothing beyond its scopes
+		{"client role is ignored", principal{role: "admin", clientID: "svc", method: AuthMethodClientCredentials, scopes: []string{}}, "users:read", http.StatusForbidden},
+		{"user role grants", principal{userID: "u1", role: "admin"}, "users:delete", http.StatusOK},
+		{"user role lacks", principal{userID: "u1", role: "user"}, "users:delete", http.StatusForbidden},
+		{"api key needs a scope too", principal{userID: "u1", role: "admin", method: AuthMethodAPIKey, scopes: []string{"users:read"}}, "users:delete", http.StatusForbidden},
+		{"api key scope within role", principal{userID: "u1", role: "admin", method: AuthMethodAPIKey, scopes: []string{"users:*"}}, "users:delete", http.StatusOK},
+		{"no principal", principal{}, "users:read", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if w := serveAs(tc.who, RequirePermissions(tc.perm)); w.Code != tc.status {
+				t.Fatalf("got %d, want %d", w.Code, tc.status)
+			}
+		})
+	}
+}
+
+func TestRequirePermissions_AnyOf(t *testing.T) {
+	if w := serveAs(client("orders:read"), RequirePermissions("users:read", "orders:read")); w.Code != http.StatusOK {
+		t.Fatalf("expected one matching permission to suffice, got %d", w.Code)
+	}
+}
+
+func TestRequireUser(t *testing.T) {
+	w := serveAs(client("*"), RequireUser())
+	if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), `"user_token_required"`) {
+		t.Fatalf("client token: expected 403 user_token_required, got %d %s", w.Code, w.Body.String())
+	}
+	if w := serveAs(principal{userID: "u1", role: "user"}, RequireUser()); w.Code != http.StatusOK {
+		t.Fatalf("user token: expected 200, got %d", w.Code)
+	}
+	if w := serveAs(principal{userID: "u1", role: "user", method: AuthMethodAPIKey, scopes: []string{}}, RequireUser()); w.Code != http.StatusOK {
+		t.Fatalf("api key: expected 200 (DenyAPIKeys handles keys), got %d", w.Code)
+	}
+}
@@ -42,5 +42,22 @@ func ValidateJSON[T any](ctxKey string, maxBodyBytes int64) gin.HandlerFunc {
 	}
 }
 
+// ValidateQuery binds and validates query-string parameters (form tags) into a typed payload stored under ctxKey.
+func ValidateQuery[T any](ctxKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload T
+		if err := c.ShouldBindQuery(&payload); err != nil {
+			if localized := validation.MapValidationErrorsWithLocale(GetLocale(c), err); len(localized) > 0 {
+				resp.BadRequestWithDetails(c, resp.CodeInvalidRequest, resp.MsgInvalidQuery, localized)
+				return
+			}
+			resp.BadRequest(c, resp.CodeInvalidRequest, resp.MsgInvalidQuery)
+			return
+		}
+		c.Set(ctxKey, payload)
+		c.Next()
+	}
+}
+
 // parseLocale extracts the primary language code from Accept-Language header.
 // locale parsing now delegated to pkg/i18n
@@ -13,14 +13,56 @@ const (
 	CodeServerError         = "server_error"
 	CodePayloadTooLarge     = "payload_too_large"
 	CodeTooManyRequests     = "too_many_requests"
+	CodeForbidden           = "forbidden"
+	CodeNotConfigured       = "not_configured"
+	CodeOIDCStateInvalid    = "oidc_state_invalid"
+	CodeOIDCLoginFailed     = "oidc_login_failed"
+	CodeEmailNotVerified    = "email_not_verified"
+	CodeSignupNotAllowed    = "signup_not_allowed"
+	CodeInvalidMFAToken     = "invalid_mfa_token"
+	CodeInvalidMFACode      = "invalid_mfa_code"
+	CodeMFANotEnrolled      = "mfa_not_enrolled"
+	CodeMFARequired         = "mfa_required"
+	// CodeEmailVerificationRequired differs from CodeEmailNotVerified (an unverified address at an OIDC provider)
+	CodeEmailVerificationRequired = "email_verification_required"
+	CodeInvalidVerificationToken  = "invalid_verification_token"
+	CodeInvalidResetToken         = "invalid_reset_token"
+	CodeInvalidMagicLink          = "invalid_magic_link"
+	CodeInvalidOTP                = "invalid_otp"
+	CodeInvalidAPIKey             = "invalid_api_key"
+	CodeInvalidScope              = "invalid_scope"
+	CodeUserTokenRequired         = "user_token_required"
+	CodeInvalidClient             = "invalid_client"
+	CodeUnauthorizedClient        = "unauthorized_client"
+	CodeAccountDisabled           = "account_disabled"
+	CodeInvalidCurrentPassword    = "invalid_current_password"
 )
 
 const (
-	MsgInvalidJSON         = "invalid JSON payload"
-	MsgInvalidCredentials  = "email or password is incorrect"
-	MsgInvalidRefreshToken = "refresh token invalid or expired"
-	MsgNotFound            = "resource not found"
-	MsgServerError         = "internal error"
-	MsgPayloadTooLarge     = "request body exceeds limit"
-	MsgTooManyRequests     = "too many requests"
+	MsgInvalidJSON               = "invalid JSON payload"
+	MsgInvalidQuery              = "invalid query parameters"
+	MsgInvalidCredentials        = "email or password is incorrect"
+	MsgInvalidRefreshToken       = "refresh token invalid or expired"
+	MsgNotFound                  = "resource not found"
+	MsgServerError               = "internal error"
+	MsgPayloadTooLarge           = "request body exceeds limit"
+	MsgTooManyRequests           = "too many requests"
+	MsgForbidden                 = "forbidden"
+	MsgNotConfigured             = "feature not configured"
+	MsgOIDCStateInvalid          = "login request expired or invalid"
+	MsgOIDCLoginFailed           = "sign-in with the identity provider failed"
+	MsgInvalidMFAToken           = "MFA session invalid or expired; sign in again"
+	MsgInvalidMFACode            = "verification code is incorrect"
+	MsgEmailVerificationRequired = "confirm your email address before signing in"
+	MsgInvalidVerificationToken  = "verification link invalid or expired"
+	MsgInvalidResetToken         = "password reset link invalid, used or expired"
+	MsgInvalidOTP                = "code is incorrect or expired; request a new one"
+	MsgInvalidMagicLink          = "sign-in link invalid, used or expired; open it in the browser where you requested it"
+	MsgInvalidAPIKey             = "API key invalid or expired"
+	MsgInvalidScope              = "scopes must be permissions such as users:read, users:* or *"
+	MsgInvalidClient             = "client authentication failed"
+	MsgScopeNotHeld              = "you cannot grant a scope you do not hold"
+	MsgUserTokenRequired         = "this endpoint acts on a user; service (client) tokens cannot use it"
+	MsgAccountDisabled           = "this account is disabled"
+	MsgInvalidCurrentPassword    = "current password is incorrect"
 )
@@ -14,12 +14,74 @@ func FromError(err error) (status int, code, message string) {
 		return 401, CodeInvalidCredentials, MsgInvalidCredentials
 	case errors.Is(err, apperr.ErrInvalidRefreshToken):
 		return 401, CodeInvalidRefreshToken, MsgInvalidRefreshToken
+	case errors.Is(err, apperr.ErrMFATokenInvalid):
+		return 401, CodeInvalidMFAToken, MsgInvalidMFAToken
+	case errors.Is(err, apperr.ErrMFACodeInvalid):
+		return 401, CodeInvalidMFACode, MsgInvalidMFACode
+	case errors.Is(err, apperr.ErrMFANotEnrolled):
+		return 400, CodeMFANotEnrolled, "start MFA enrollment first"
+	case errors.Is(err, apperr.ErrMFAAlreadyEnabled):
+		return 409, CodeConflict, "MFA already enabled"
+	case errors.Is(err, apperr.ErrMFARequired):
+		return 403, CodeMFARequired, "MFA is required for this account"
+	case errors.Is(err, apperr.ErrEmailVerificationRequired):
+		return 403, CodeEmailVerificationRequired, MsgEmailVerificationRequired
+	case errors.Is(err, apperr.ErrVerificationTokenInvalid):
+		return 400, CodeInvalidVerificationToken, MsgInvalidVerificationToken
+	case errors.Is(err, apperr.ErrResetTokenInvalid):
+		return 400, CodeInvalidResetToken, MsgInvalidResetToken
+	case errors.Is(err, apperr.ErrMagicLinkInvalid):
+		return 401, CodeInvalidMagicLink, MsgInvalidMagicLink
+	case errors.Is(err, apperr.ErrOTPInvalid):
+		return 401, CodeInvalidOTP, MsgInvalidOTP
+	case errors.Is(err, apperr.ErrAPIKeyInvalid):
+		return 401, CodeInvalidAPIKey, MsgInvalidAPIKey
+	case errors.Is(err, apperr.ErrInvalidClient):
+		return 401, CodeInvalidClient, MsgInvalidClient
+	case errors.Is(err, apperr.ErrUnauthorizedClient):
+		return 400, CodeUnauthorizedClient, "the client is not allowed to act on this token"
+	case errors.Is(err, apperr.ErrAccountDisabled):
+		return 403, CodeAccountDisabled, MsgAccountDisabled
+	case errors.Is(err, apperr.ErrSelfAdminChange):
+		return 409, CodeConflict, "administrators cannot disable, delete or demote themselves"
+	case errors.Is(err, apperr.ErrLastAdmin):
+		return 409, CodeConflict, "the last administrator cannot be disabled, deleted or demoted"
+	case errors.Is(err, apperr.ErrInvalidCursor):
+		return 400, CodeInvalidRequest, "invalid pagination cursor"
+	case errors.Is(err, apperr.ErrScopeNotHeld):
+		return 403, CodeForbidden, MsgScopeNotHeld
+	case errors.Is(err, apperr.ErrInvalidScope):
+		return 400, CodeInvalidScope, MsgInvalidScope
+	case errors.Is(err, domuser.ErrInvalidPassword):
+		return 400, CodeInvalidCurrentPassword, MsgInvalidCurrentPassword
 	case errors.Is(err, domuser.ErrUserNotFound):
 		return 404, CodeNotFound, MsgNotFound
+	case errors.Is(err, apperr.ErrSessionNotFound):
+		return 404, CodeNotFound, "session not found"
+	case errors.Is(err, apperr.ErrOIDCProviderNotFound):
+		return 404, CodeNotFound, "identity provider not found"
+	case errors.Is(err, apperr.ErrAPIKeyNotFound):
+		return 404, CodeNotFound, "API key not found"
+	case errors.Is(err, apperr.ErrOAuthClientNotFound):
+		return 404, CodeNotFound, "OAuth client not found"
+	case errors.Is(err, apperr.ErrOIDCStateInvalid):
+		return 400, CodeOIDCStateInvalid, MsgOIDCStateInvalid
+	case errors.Is(err, apperr.ErrOIDCLoginFailed):
+		return 401, CodeOIDCLoginFailed, MsgOIDCLoginFailed
+	case errors.Is(err, apperr.ErrOIDCEmailNotVerified):
+		return 403, CodeEmailNotVerified, "email address at the identity provider is not verified"
+	case errors.Is(err, apperr.ErrOIDCSignupNotAllowed):
+		return 403, CodeSignupNotAllowed, "no account is linked to this identity"
 	case errors.Is(err, domuser.ErrEmailAlreadyExists):
 		return 409, CodeConflict, "email already exists"
-	case errors.Is(err, domuser.ErrInvalidEmail), errors.Is(err, domuser.ErrInvalidRole):
+	case errors.Is(err, domuser.ErrPhoneAlreadyExists):
+		return 409, CodeConflict, "phone number already in use"
+	case errors.Is(err, domuser.ErrInvalidEmail), errors.Is(err, domuser.ErrInvalidRole), errors.Is(err, domuser.ErrInvalidID),
+		errors.Is(err, domuser.ErrInvalidPhone), errors.Is(err, domuser.ErrInvalidFirstName), errors.Is(err, domuser.ErrInvalidLastName):
 		return 400, CodeInvalidRequest, "invalid request"
+	case errors.Is(err, apperr.ErrRefreshStoreNotConfigured), errors.Is(err, apperr.ErrRevocationStoreNotConfigured),
+		errors.Is(err, apperr.ErrEmailChangeNotConfigured):
+		return 503, CodeNotConfigured, MsgNotConfigured
 	default:
 		return 500, CodeServerError, MsgServerError
 	}
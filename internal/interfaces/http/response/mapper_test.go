@@ -2,6 +2,7 @@ package response
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"gostartkit/internal/application/apperr"
@@ -16,6 +17,34 @@ func TestFromError_Mapping(t *testing.T) {
 		{apperr.ErrInvalidCredentials, 401},
 		{apperr.ErrInvalidRefreshToken, 401},
 		{domuser.ErrUserNotFound, 404},
+		{domuser.ErrInvalidPassword, 400},
+		{apperr.ErrSessionNotFound, 404},
+		{apperr.ErrOIDCStateInvalid, 400},
+		{fmt.Errorf("%w: bad nonce", apperr.ErrOIDCLoginFailed), 401},
+		{apperr.ErrOIDCSignupNotAllowed, 403},
+		{apperr.ErrMFACodeInvalid, 401},
+		{apperr.ErrMFARequired, 403},
+		{apperr.ErrMFAAlreadyEnabled, 409},
+		{apperr.ErrEmailVerificationRequired, 403},
+		{apperr.ErrVerificationTokenInvalid, 400},
+		{apperr.ErrResetTokenInvalid, 400},
+		{apperr.ErrMagicLinkInvalid, 401},
+		{apperr.ErrOTPInvalid, 401},
+		{apperr.ErrAPIKeyInvalid, 401},
+		{apperr.ErrAPIKeyNotFound, 404},
+		{apperr.ErrInvalidScope, 400},
+		{apperr.ErrScopeNotHeld, 403},
+		{apperr.ErrInvalidClient, 401},
+		{apperr.ErrOAuthClientNotFound, 404},
+		{apperr.ErrUnauthorizedClient, 400},
+		{apperr.ErrAccountDisabled, 403},
+		{apperr.ErrSelfAdminChange, 409},
+		{apperr.ErrLastAdmin, 409},
+		{apperr.ErrInvalidCursor, 400},
+		{apperr.ErrEmailChangeNotConfigured, 503},
+		{domuser.ErrPhoneAlreadyExists, 409},
+		{domuser.ErrInvalidPhone, 400},
+		{domuser.ErrInvalidFirstName, 400},
 		{domuser.ErrEmailAlreadyExists, 409},
 		{errors.New("x"), 500},
 	}
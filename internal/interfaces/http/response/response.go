@@ -22,10 +22,20 @@ func OK(c *gin.Context, data any) {
 	c.JSON(http.StatusOK, Envelope{Data: data})
 }
 
+// OKWithMeta sends 200 with data plus meta, e.g. paging information for list endpoints.
+func OKWithMeta(c *gin.Context, data, meta any) {
+	c.JSON(http.StatusOK, Envelope{Data: data, Meta: meta})
+}
+
 func Created(c *gin.Context, data any) {
 	c.JSON(http.StatusCreated, Envelope{Data: data})
 }
 
+// Accepted sends 202 for work that continues after the response, e.g. a background export.
+func Accepted(c *gin.Context, data any) {
+	c.JSON(http.StatusAccepted, Envelope{Data: data})
+}
+
 func BadRequest(c *gin.Context, code, msg string) {
 	c.JSON(http.StatusBadRequest, Envelope{Error: &ErrorBody{Code: code, Message: msg}})
 }
@@ -34,6 +44,15 @@ func Unauthorized(c *gin.Context, code, msg string) {
 	c.JSON(http.StatusUnauthorized, Envelope{Error: &ErrorBody{Code: code, Message: msg}})
 }
 
+func Forbidden(c *gin.Context, code, msg string) {
+	c.JSON(http.StatusForbidden, Envelope{Error: &ErrorBody{Code: code, Message: msg}})
+}
+
+// ServiceUnavailable sends 503 with standard envelope (feature not configured / dependency down).
+func ServiceUnavailable(c *gin.Context, code, msg string) {
+	c.JSON(http.StatusServiceUnavailable, Envelope{Error: &ErrorBody{Code: code, Message: msg}})
+}
+
 func InternalError(c *gin.Context, code, msg string) {
 	c.JSON(http.StatusInternalServerError, Envelope{Error: &ErrorBody{Code: code, Message: msg}})
 }
@@ -0,0 +1,18 @@
+package router
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/config"
+	"gostartkit/internal/interfaces/http/handler"
+	"gostartkit/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAdminTokenRoutes mounts admin token management under /v1/admin/tokens.
+func RegisterAdminTokenRoutes(r *gin.Engine, h *handler.TokenAdminHandler, cfg *config.Config, authMiddleware ...gin.HandlerFunc) {
+	tokens := r.Group("/v1/admin/tokens")
+	tokens.Use(authMiddleware...)
+	tokens.Use(middleware.RequirePermissions("tokens:revoke"))
+	tokens.POST("/revoke", middleware.ValidateJSON[dto.RevokeAccessRequest]("req", cfg.HTTP.MaxBodyBytes), h.RevokeAccess)
+}
@@ -0,0 +1,29 @@
+package router
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/config"
+	"gostartkit/internal/interfaces/http/handler"
+	"gostartkit/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAdminUserRoutes mounts admin user management under /v1/admin/users.
+// Reads need users:read and changes users:write. unlock is only mounted when login lockout is wired.
+func RegisterAdminUserRoutes(r *gin.Engine, h *handler.UserAdminHandler, cfg *config.Config, withUnlock bool, authMiddleware ...gin.HandlerFunc) {
+	users := r.Group("/v1/admin/users")
+	users.Use(authMiddleware...)
+	read := middleware.RequirePermissions("users:read")
+	write := middleware.RequirePermissions("users:write")
+	users.GET("", read, middleware.ValidateQuery[dto.ListUsersRequest]("query"), h.List)
+	users.GET("/:id", read, h.Get)
+	users.PATCH("/:id", write, middleware.ValidateJSON[dto.AdminUpdateUserRequest]("req", cfg.HTTP.MaxBodyBytes), h.Update)
+	users.DELETE("/:id", write, h.Delete)
+	users.POST("/:id/disable", write, h.Disable)
+	users.POST("/:id/enable", write, h.Enable)
+	users.POST("/:id/erase", write, h.Erase)
+	if withUnlock {
+		users.POST("/:id/unlock", write, h.Unlock)
+	}
+}
@@ -0,0 +1,23 @@
+package router
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/config"
+	"gostartkit/internal/interfaces/http/handler"
+	"gostartkit/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAPIKeyRoutes mounts API key management under /v1/auth/api-keys. The routes need an
+// interactive user login: API keys (so a key cannot mint more keys) and service tokens are rejected.
+func RegisterAPIKeyRoutes(r *gin.Engine, h *handler.APIKeyHandler, cfg *config.Config, authMiddleware ...gin.HandlerFunc) {
+	keys := r.Group("/v1/auth/api-keys")
+	keys.Use(authMiddleware...)
+	keys.Use(middleware.RequireUser(), middleware.DenyAPIKeys())
+	keys.POST("", middleware.ValidateJSON[dto.CreateAPIKeyRequest]("req", cfg.HTTP.MaxBodyBytes), h.Create)
+	keys.GET("", h.List)
+	keys.GET("/:id", h.Get)
+	keys.PATCH("/:id", middleware.ValidateJSON[dto.UpdateAPIKeyRequest]("req", cfg.HTTP.MaxBodyBytes), h.Update)
+	keys.DELETE("/:id", h.Delete)
+}
@@ -54,10 +54,40 @@ func registerAuthRoutes(v1 *gin.RouterGroup, userHandler *handler.UserHandler, c
 	if len(authMiddleware) > 0 {
 		protected := auth.Group("")
 		protected.Use(authMiddleware...)
+		protected.Use(middleware.RequireUser())
 		protected.GET("/me", userHandler.GetMe)
-		protected.POST("/change-password", middleware.ValidateJSON[dto.ChangePasswordRequest]("req", cfg.HTTP.MaxBodyBytes), userHandler.ChangePassword)
+		// A leaked API key must not be enough to take over the account
+		protected.PATCH("/me", append([]gin.HandlerFunc{middleware.DenyAPIKeys()}, updateMeHandlers(cfg, userHandler)...)...)
+		protected.POST("/change-password", middleware.DenyAPIKeys(), middleware.ValidateJSON[dto.ChangePasswordRequest]("req", cfg.HTTP.MaxBodyBytes), userHandler.ChangePassword)
 		return
 	}
 	auth.GET("/me", userHandler.GetMe)
+	auth.PATCH("/me", updateMeHandlers(cfg, userHandler)...)
 	auth.POST("/change-password", middleware.ValidateJSON[dto.ChangePasswordRequest]("req", cfg.HTTP.MaxBodyBytes), userHandler.ChangePassword)
 }
+
+// updateMeHandlers validates PATCH /v1/auth/me. Requests that change the email send mail, so they share the
+// email rate limit per IP and, with Redis, per requested address; name-only edits are not limited.
+func updateMeHandlers(cfg *config.Config, userHandler *handler.UserHandler) []gin.HandlerFunc {
+	onEmailChange := func(limit gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			if c.MustGet("req").(dto.UpdateMeRequest).Email == nil {
+				c.Next()
+				return
+			}
+			limit(c)
+		}
+	}
+	rps, burst := emailRateLimit(cfg)
+	handlers := []gin.HandlerFunc{
+		middleware.ValidateJSON[dto.UpdateMeRequest]("req", cfg.HTTP.MaxBodyBytes),
+		onEmailChange(middleware.RateLimitForPath("/v1/auth/me", rps, burst)),
+	}
+	if cfg.RedisAddr != "" {
+		rl := ratelimit.NewRedisLimiter(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB).WithFailClosed(cfg.HTTP.LoginRateLimitFailClosed)
+		handlers = append(handlers, onEmailChange(rl.LimitEmail(rps, burst, func(c *gin.Context) string {
+			return *c.MustGet("req").(dto.UpdateMeRequest).Email
+		})))
+	}
+	return append(handlers, userHandler.UpdateMe)
+}
@@ -0,0 +1,32 @@
+package router
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/config"
+	"gostartkit/internal/infras/ratelimit"
+	"gostartkit/internal/interfaces/http/handler"
+	"gostartkit/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterDataExportRoutes mounts the personal data export under /v1/auth/me. Exports are expensive, so they
+// share the email rate limit per user (across instances with Redis, per instance without).
+func RegisterDataExportRoutes(r *gin.Engine, h *handler.DataExportHandler, cfg *config.Config, authMiddleware ...gin.HandlerFunc) {
+	rps, burst := emailRateLimit(cfg)
+	userOf := func(c *gin.Context) string { return c.GetString("user_id") }
+	perUser := middleware.RateLimitByKey("/v1/auth/me/export", rps, burst, userOf)
+	if cfg.RedisAddr != "" {
+		rl := ratelimit.NewRedisLimiter(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB).WithFailClosed(cfg.HTTP.LoginRateLimitFailClosed)
+		perUser = rl.LimitEmail(rps, burst, func(c *gin.Context) string {
+			if id := userOf(c); id != "" {
+				return "export:" + id
+			}
+			return ""
+		})
+	}
+
+	me := r.Group("/v1/auth/me")
+	me.Use(authMiddleware...)
+	me.GET("/export", perUser, middleware.ValidateQuery[dto.DataExportRequest]("query"), h.Export)
+}
@@ -0,0 +1,48 @@
+package router
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/config"
+	"gostartkit/internal/infras/ratelimit"
+	"gostartkit/internal/interfaces/http/handler"
+	"gostartkit/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Fallbacks for HTTP_EMAIL_RATELIMIT_*: endpoints that send email are always rate limited.
+const (
+	defaultEmailRateLimitRPS   = 0.05
+	defaultEmailRateLimitBurst = 3
+)
+
+// RegisterEmailVerificationRoutes mounts the public verification endpoints under /v1/auth.
+// Resending is limited per IP and, with Redis, per email address as well.
+func RegisterEmailVerificationRoutes(r *gin.Engine, h *handler.EmailVerificationHandler, cfg *config.Config) {
+	auth := r.Group("/v1/auth")
+	auth.POST("/verify-email", middleware.ValidateJSON[dto.VerifyEmailRequest]("req", cfg.HTTP.MaxBodyBytes), h.Verify)
+
+	rps, burst := emailRateLimit(cfg)
+	resend := []gin.HandlerFunc{
+		middleware.RateLimitForPath("/v1/auth/resend-verification", rps, burst),
+		middleware.ValidateJSON[dto.ResendVerificationRequest]("req", cfg.HTTP.MaxBodyBytes),
+	}
+	if cfg.RedisAddr != "" {
+		rl := ratelimit.NewRedisLimiter(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB).WithFailClosed(cfg.HTTP.LoginRateLimitFailClosed)
+		resend = append(resend, rl.LimitEmail(rps, burst, func(c *gin.Context) string {
+			return c.MustGet("req").(dto.ResendVerificationRequest).Email
+		}))
+	}
+	auth.POST("/resend-verification", append(resend, h.Resend)...)
+}
+
+func emailRateLimit(cfg *config.Config) (float64, int) {
+	rps, burst := cfg.HTTP.EmailRateLimitRPS, cfg.HTTP.EmailRateLimitBurst
+	if rps <= 0 {
+		rps = defaultEmailRateLimitRPS
+	}
+	if burst <= 0 {
+		burst = defaultEmailRateLimitBurst
+	}
+	return rps, burst
+}
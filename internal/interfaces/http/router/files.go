@@ -0,0 +1,19 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SignedFilesPath is where signed object links are served when objects are stored locally.
+const SignedFilesPath = "/v1/files"
+
+// RegisterSignedFileRoutes mounts h under SignedFilesPath for downloads (GET/HEAD) and uploads (PUT). h sees
+// the object key as its path and authorizes each request by its signature, so no auth middleware applies.
+func RegisterSignedFileRoutes(r *gin.Engine, h http.Handler) {
+	files := gin.WrapH(http.StripPrefix(SignedFilesPath, h))
+	r.GET(SignedFilesPath+"/*key", files)
+	r.HEAD(SignedFilesPath+"/*key", files)
+	r.PUT(SignedFilesPath+"/*key", files)
+}
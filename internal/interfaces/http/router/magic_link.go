@@ -0,0 +1,37 @@
+package router
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/config"
+	"gostartkit/internal/infras/ratelimit"
+	"gostartkit/internal/interfaces/http/handler"
+	"gostartkit/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterMagicLinkRoutes mounts passwordless sign-in under /v1/auth/magic-link.
+// Requesting a link sends email and shares the email rate limit; consuming shares the login rate limit when configured.
+func RegisterMagicLinkRoutes(r *gin.Engine, h *handler.MagicLinkHandler, cfg *config.Config) {
+	auth := r.Group("/v1/auth")
+
+	rps, burst := emailRateLimit(cfg)
+	request := []gin.HandlerFunc{
+		middleware.RateLimitForPath("/v1/auth/magic-link", rps, burst),
+		middleware.ValidateJSON[dto.MagicLinkRequest]("req", cfg.HTTP.MaxBodyBytes),
+	}
+	if cfg.RedisAddr != "" {
+		rl := ratelimit.NewRedisLimiter(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB).WithFailClosed(cfg.HTTP.LoginRateLimitFailClosed)
+		request = append(request, rl.LimitEmail(rps, burst, func(c *gin.Context) string {
+			return c.MustGet("req").(dto.MagicLinkRequest).Email
+		}))
+	}
+	auth.POST("/magic-link", append(request, h.Request)...)
+
+	consume := []gin.HandlerFunc{}
+	if cfg.HTTP.LoginRateLimitRPS > 0 && cfg.HTTP.LoginRateLimitBurst > 0 {
+		consume = append(consume, middleware.RateLimitForPath("/v1/auth/magic-link/consume", cfg.HTTP.LoginRateLimitRPS, cfg.HTTP.LoginRateLimitBurst))
+	}
+	consume = append(consume, middleware.ValidateJSON[dto.MagicLinkConsumeRequest]("req", cfg.HTTP.MaxBodyBytes), h.Consume)
+	auth.POST("/magic-link/consume", consume...)
+}
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/config"
+	"gostartkit/internal/interfaces/http/handler"
+	"gostartkit/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterMFARoutes mounts TOTP enrollment (authenticated) and the login verification step under /v1/auth/mfa.
+// Every route that checks a code shares the login rate limit settings: verify is the second half of a login,
+// and confirm and disable must not let a stolen access token guess codes quickly.
+func RegisterMFARoutes(r *gin.Engine, h *handler.MFAHandler, cfg *config.Config, authMiddleware ...gin.HandlerFunc) {
+	mfa := r.Group("/v1/auth/mfa")
+	limited := func(path string, next ...gin.HandlerFunc) []gin.HandlerFunc {
+		if cfg.HTTP.LoginRateLimitRPS > 0 && cfg.HTTP.LoginRateLimitBurst > 0 {
+			next = append([]gin.HandlerFunc{middleware.RateLimitForPath(path, cfg.HTTP.LoginRateLimitRPS, cfg.HTTP.LoginRateLimitBurst)}, next...)
+		}
+		return next
+	}
+	mfa.POST("/verify", limited("/v1/auth/mfa/verify", middleware.ValidateJSON[dto.MFAVerifyRequest]("req", cfg.HTTP.MaxBodyBytes), h.Verify)...)
+
+	protected := mfa.Group("")
+	protected.Use(authMiddleware...)
+	protected.POST("/enroll", h.Enroll)
+	protected.POST("/confirm", limited("/v1/auth/mfa/confirm", middleware.ValidateJSON[dto.MFACodeRequest]("req", cfg.HTTP.MaxBodyBytes), h.Confirm)...)
+	protected.POST("/disable", limited("/v1/auth/mfa/disable", middleware.ValidateJSON[dto.MFACodeRequest]("req", cfg.HTTP.MaxBodyBytes), h.Disable)...)
+}
@@ -0,0 +1,35 @@
+package router
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/config"
+	"gostartkit/internal/interfaces/http/handler"
+	"gostartkit/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterOAuthRoutes mounts the OAuth2 endpoints for services under /oauth (token; introspect and revoke
+// when withIntrospection is set) and client registration under /v1/admin/oauth-clients
+// (permissions oauth_clients:read / oauth_clients:write). Every /oauth endpoint checks a client secret, so
+// each is rate limited like login. Creating and deleting clients needs an interactive
+// user login, so an API key or service token holding oauth_clients:write cannot mint new credentials.
+func RegisterOAuthRoutes(r *gin.Engine, h *handler.OAuthHandler, cfg *config.Config, withIntrospection bool, authMiddleware ...gin.HandlerFunc) {
+	limited := func(path string, next gin.HandlerFunc) []gin.HandlerFunc {
+		if cfg.HTTP.LoginRateLimitRPS > 0 && cfg.HTTP.LoginRateLimitBurst > 0 {
+			return []gin.HandlerFunc{middleware.RateLimitForPath(path, cfg.HTTP.LoginRateLimitRPS, cfg.HTTP.LoginRateLimitBurst), next}
+		}
+		return []gin.HandlerFunc{next}
+	}
+	r.POST("/oauth/token", limited("/oauth/token", h.Token)...)
+	if withIntrospection {
+		r.POST("/oauth/introspect", limited("/oauth/introspect", h.Introspect)...)
+		r.POST("/oauth/revoke", limited("/oauth/revoke", h.Revoke)...)
+	}
+
+	clients := r.Group("/v1/admin/oauth-clients")
+	clients.Use(authMiddleware...)
+	clients.GET("", middleware.RequirePermissions("oauth_clients:read"), h.ListClients)
+	clients.POST("", middleware.RequireUser(), middleware.DenyAPIKeys(), middleware.RequirePermissions("oauth_clients:write"), middleware.ValidateJSON[dto.CreateOAuthClientRequest]("req", cfg.HTTP.MaxBodyBytes), h.CreateClient)
+	clients.DELETE("/:client_id", middleware.RequireUser(), middleware.DenyAPIKeys(), middleware.RequirePermissions("oauth_clients:write"), h.DeleteClient)
+}
@@ -0,0 +1,14 @@
+package router
+
+import (
+	"gostartkit/internal/interfaces/http/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterOIDCRoutes mounts OpenID Connect login under /v1/auth/oidc/:provider.
+func RegisterOIDCRoutes(r *gin.Engine, h *handler.OIDCHandler) {
+	oidc := r.Group("/v1/auth/oidc/:provider")
+	oidc.GET("/start", h.Start)
+	oidc.GET("/callback", h.Callback)
+}
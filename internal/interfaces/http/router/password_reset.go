@@ -0,0 +1,37 @@
+package router
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/config"
+	"gostartkit/internal/infras/ratelimit"
+	"gostartkit/internal/interfaces/http/handler"
+	"gostartkit/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPasswordResetRoutes mounts the public forgot/reset password endpoints under /v1/auth.
+// Forgot sends email and shares the email rate limit; reset shares the login rate limit when configured.
+func RegisterPasswordResetRoutes(r *gin.Engine, h *handler.PasswordResetHandler, cfg *config.Config) {
+	auth := r.Group("/v1/auth")
+
+	rps, burst := emailRateLimit(cfg)
+	forgot := []gin.HandlerFunc{
+		middleware.RateLimitForPath("/v1/auth/forgot-password", rps, burst),
+		middleware.ValidateJSON[dto.ForgotPasswordRequest]("req", cfg.HTTP.MaxBodyBytes),
+	}
+	if cfg.RedisAddr != "" {
+		rl := ratelimit.NewRedisLimiter(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB).WithFailClosed(cfg.HTTP.LoginRateLimitFailClosed)
+		forgot = append(forgot, rl.LimitEmail(rps, burst, func(c *gin.Context) string {
+			return c.MustGet("req").(dto.ForgotPasswordRequest).Email
+		}))
+	}
+	auth.POST("/forgot-password", append(forgot, h.Forgot)...)
+
+	reset := []gin.HandlerFunc{}
+	if cfg.HTTP.LoginRateLimitRPS > 0 && cfg.HTTP.LoginRateLimitBurst > 0 {
+		reset = append(reset, middleware.RateLimitForPath("/v1/auth/reset-password", cfg.HTTP.LoginRateLimitRPS, cfg.HTTP.LoginRateLimitBurst))
+	}
+	reset = append(reset, middleware.ValidateJSON[dto.ResetPasswordRequest]("req", cfg.HTTP.MaxBodyBytes), h.Reset)
+	auth.POST("/reset-password", reset...)
+}
@@ -0,0 +1,16 @@
+package router
+
+import (
+	"gostartkit/internal/interfaces/http/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterSessionRoutes mounts self-service session management under /v1/auth.
+func RegisterSessionRoutes(r *gin.Engine, h *handler.SessionHandler, authMiddleware ...gin.HandlerFunc) {
+	auth := r.Group("/v1/auth")
+	auth.Use(authMiddleware...)
+	auth.GET("/sessions", h.List)
+	auth.DELETE("/sessions/:id", h.Revoke)
+	auth.POST("/logout-all", h.LogoutAll)
+}
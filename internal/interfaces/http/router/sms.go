@@ -0,0 +1,56 @@
+package router
+
+import (
+	"gostartkit/internal/application/dto"
+	"gostartkit/internal/config"
+	"gostartkit/internal/infras/ratelimit"
+	"gostartkit/internal/interfaces/http/handler"
+	"gostartkit/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterSMSRoutes mounts phone verification under /v1/auth/me/phone (authenticated) and, when
+// SMS_LOGIN_ENABLED is set, SMS login under /v1/auth/sms. Sending a code shares the email rate limit
+// (per IP, and per number: across instances with Redis, per instance without); entering a code shares the login rate limit.
+func RegisterSMSRoutes(r *gin.Engine, h *handler.SMSHandler, cfg *config.Config, authMiddleware ...gin.HandlerFunc) {
+	rps, burst := emailRateLimit(cfg)
+	var rl *ratelimit.RedisLimiter
+	if cfg.RedisAddr != "" {
+		rl = ratelimit.NewRedisLimiter(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB).WithFailClosed(cfg.HTTP.LoginRateLimitFailClosed)
+	}
+	phoneOf := func(c *gin.Context) string { return c.MustGet("req").(dto.PhoneRequest).Phone }
+	perNumber := func(name string) gin.HandlerFunc {
+		if rl != nil {
+			return rl.LimitEmail(rps, burst, phoneOf)
+		}
+		return middleware.RateLimitByKey(name, rps, burst, phoneOf)
+	}
+	limitCodes := func(path string, next ...gin.HandlerFunc) []gin.HandlerFunc {
+		if cfg.HTTP.LoginRateLimitRPS > 0 && cfg.HTTP.LoginRateLimitBurst > 0 {
+			next = append([]gin.HandlerFunc{middleware.RateLimitForPath(path, cfg.HTTP.LoginRateLimitRPS, cfg.HTTP.LoginRateLimitBurst)}, next...)
+		}
+		return next
+	}
+
+	phone := r.Group("/v1/auth/me/phone")
+	phone.Use(authMiddleware...)
+	phone.POST("",
+		middleware.RateLimitForPath("/v1/auth/me/phone", rps, burst),
+		middleware.ValidateJSON[dto.PhoneRequest]("req", cfg.HTTP.MaxBodyBytes),
+		perNumber("/v1/auth/me/phone"),
+		h.StartPhone)
+	phone.POST("/verify", limitCodes("/v1/auth/me/phone/verify", middleware.ValidateJSON[dto.SMSCodeRequest]("req", cfg.HTTP.MaxBodyBytes), h.ConfirmPhone)...)
+	phone.DELETE("", h.RemovePhone)
+
+	if !cfg.SMS.LoginEnabled {
+		return
+	}
+	sms := r.Group("/v1/auth/sms")
+	sms.POST("/send",
+		middleware.RateLimitForPath("/v1/auth/sms/send", rps, burst),
+		middleware.ValidateJSON[dto.PhoneRequest]("req", cfg.HTTP.MaxBodyBytes),
+		perNumber("/v1/auth/sms/send"),
+		h.SendLoginCode)
+	sms.POST("/login", limitCodes("/v1/auth/sms/login", middleware.ValidateJSON[dto.SMSLoginRequest]("req", cfg.HTTP.MaxBodyBytes), h.Login)...)
+}
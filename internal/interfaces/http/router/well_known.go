@@ -0,0 +1,14 @@
+package router
+
+import (
+	"gostartkit/internal/interfaces/http/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterWellKnownRoutes mounts public discovery documents under /.well-known.
+func RegisterWellKnownRoutes(r *gin.Engine, h *handler.WellKnownHandler) {
+	wk := r.Group("/.well-known")
+	wk.GET("/jwks.json", h.JWKS)
+	wk.GET("/openid-configuration", h.OpenIDConfiguration)
+}
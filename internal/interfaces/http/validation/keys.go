@@ -8,6 +8,8 @@ const (
 	KeyMinLen               MsgKey = "min_len"
 	KeyEmail                MsgKey = "email"
 	KeyStrongPassword       MsgKey = "strong_password"
+	KeyBreachedPassword     MsgKey = "breached_password"
+	KeyPhone                MsgKey = "phone"
 	KeyMalformedJSONAt      MsgKey = "malformed_json_at"
 	KeyInvalidTypeForField  MsgKey = "invalid_type_for_field"
 	KeyInvalidValueForField MsgKey = "invalid_value_for_field"
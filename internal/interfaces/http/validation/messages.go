@@ -15,6 +15,10 @@ func MsgEmail(field string) string { return renderKey(KeyEmail, field, "") }
 
 func MsgStrongPassword(field string) string { return renderKey(KeyStrongPassword, field, "") }
 
+func MsgBreachedPassword(field string) string { return renderKey(KeyBreachedPassword, field, "") }
+
+func MsgPhone(field string) string { return renderKey(KeyPhone, field, "") }
+
 func MsgMalformedJSONAt(offset int64) string {
 	return renderKey(KeyMalformedJSONAt, "", fmt.Sprintf("%d", offset))
 }
@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"strings"
 
+	"gostartkit/internal/application/ports"
 	appval "gostartkit/pkg/validator"
 
 	"github.com/gin-gonic/gin/binding"
@@ -49,8 +50,14 @@ func init() {
 		return resp.CodeInvalidRequest, MsgEmail(fe.Field())
 	})
 	RegisterTagFormatter("strong_password", func(fe validator.FieldError) (string, string) {
+		if failedOnlyBreachCheck(fe) {
+			return resp.CodeInvalidRequest, MsgBreachedPassword(fe.Field())
+		}
 		return resp.CodeInvalidRequest, MsgStrongPassword(fe.Field())
 	})
+	RegisterTagFormatter("e164_phone", func(fe validator.FieldError) (string, string) {
+		return resp.CodeInvalidRequest, MsgPhone(fe.Field())
+	})
 
 	// Register custom validators with Gin's validator engine so tags can be used in DTOs
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
@@ -67,6 +74,7 @@ func init() {
 		})
 		_ = v.RegisterValidation("strict_email", StrictEmailValidator())
 		_ = v.RegisterValidation("strong_password", StrongPasswordValidator())
+		_ = v.RegisterValidation("e164_phone", E164PhoneValidator())
 	}
 }
 
@@ -158,12 +166,43 @@ func StrictEmailValidator() validator.Func {
 	}
 }
 
-// StrongPasswordValidator checks baseline strong password rules via pkg/validator.
+// E164PhoneValidator checks phone numbers in E.164 form via pkg/validator.
+func E164PhoneValidator() validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		v := fl.Field().String()
+		return appval.IsE164Phone(v)
+	}
+}
+
+// breachedPasswords is consulted by strong_password when set (see SetBreachedPasswordChecker).
+var breachedPasswords ports.BreachedPasswordChecker
+
+// SetBreachedPasswordChecker makes strong_password also refuse passwords known from data breaches.
+// Call it once at startup, before serving requests; nil disables the check.
+func SetBreachedPasswordChecker(c ports.BreachedPasswordChecker) { breachedPasswords = c }
+
+// StrongPasswordValidator checks baseline strong password rules via pkg/validator, then the breached-password
+// checker if one is set. A failing checker does not block the password (fail-open).
 func StrongPasswordValidator() validator.Func {
 	return func(fl validator.FieldLevel) bool {
 		v := fl.Field().String()
-		return appval.IsStrongPassword(v)
+		return appval.IsStrongPassword(v) && !isBreachedPassword(v)
+	}
+}
+
+func isBreachedPassword(pw string) bool {
+	if breachedPasswords == nil {
+		return false
 	}
+	breached, err := breachedPasswords.IsBreached(pw)
+	return err == nil && breached
+}
+
+// failedOnlyBreachCheck reports whether a strong_password error came from the breach check, i.e. the
+// password itself meets the baseline rules, so the message can say why it was refused.
+func failedOnlyBreachCheck(fe validator.FieldError) bool {
+	pw, ok := fe.Value().(string)
+	return ok && appval.IsStrongPassword(pw)
 }
 
 // MapValidationErrors maps all validation errors into a list of FieldError for richer client responses.
@@ -204,7 +243,13 @@ func MapValidationErrorsWithLocale(locale string, err error) []FieldError {
 		case "email", "strict_email":
 			msg = renderKeyLocale(locale, KeyEmail, field, "")
 		case "strong_password":
-			msg = renderKeyLocale(locale, KeyStrongPassword, field, "")
+			if failedOnlyBreachCheck(fe) {
+				msg = renderKeyLocale(locale, KeyBreachedPassword, field, "")
+			} else {
+				msg = renderKeyLocale(locale, KeyStrongPassword, field, "")
+			}
+		case "e164_phone":
+			msg = renderKeyLocale(locale, KeyPhone, field, "")
 		default:
 			msg = renderKeyLocale(locale, KeyInvalidValueForField, field, "")
 		}
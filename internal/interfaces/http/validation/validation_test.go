@@ -4,7 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"strings"
 	"testing"
+
+	"gostartkit/internal/application/ports"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 )
 
 func TestMapBindJSONError_EmptyBody(t *testing.T) {
@@ -33,3 +39,31 @@ func TestIsBodyTooLarge(t *testing.T) {
 		t.Fatalf("expected true")
 	}
 }
+
+func TestStrongPassword_BreachedPasswordMessage(t *testing.T) {
+	breached := "Correct-Horse-42!"
+	SetBreachedPasswordChecker(ports.BreachedPasswordCheckFunc(func(pw string) (bool, error) { return pw == breached, nil }))
+	defer SetBreachedPasswordChecker(nil)
+
+	type req struct {
+		Password string `json:"password" binding:"required,strong_password"`
+	}
+	v := binding.Validator.Engine().(*validator.Validate)
+
+	err := v.Struct(req{Password: breached})
+	fes := MapValidationErrorsWithLocale("en", err)
+	if len(fes) != 1 || !strings.Contains(fes[0].Message, "data breaches") {
+		t.Fatalf("expected breached-password message, got %+v", fes)
+	}
+	if _, msg := MapBindJSONError(err); !strings.Contains(msg, "data breaches") {
+		t.Fatalf("expected breached-password message, got %q", msg)
+	}
+	// Weak passwords keep the strength message
+	fes = MapValidationErrorsWithLocale("en", v.Struct(req{Password: "short"}))
+	if len(fes) != 1 || !strings.Contains(fes[0].Message, "stronger password") {
+		t.Fatalf("expected strength message, got %+v", fes)
+	}
+	if err := v.Struct(req{Password: "Unlisted-Horse-42!"}); err != nil {
+		t.Fatalf("expected an unlisted strong password to pass, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+	pgstore "gostartkit/internal/infras/storage/postgres"
+
+	"github.com/google/uuid"
+)
+
+func TestPostgres_APIKeyStore_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	pool := openMigratedPool(t)
+	u := saveTestUser(t, pgstore.NewUserRepository(pool))
+	store := pgstore.NewAPIKeyStore(pool)
+
+	exp := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Microsecond)
+	k := ports.APIKey{
+		ID:         uuid.New(),
+		UserID:     u.ID,
+		Name:       "ci",
+		Prefix:     uuid.NewString()[:8],
+		SecretHash: []byte("hash"),
+		Scopes:     []string{"users:read"},
+		ExpiresAt:  &exp,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := store.Create(ctx, k); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	got, err := store.GetByPrefix(ctx, k.Prefix)
+	if err != nil || got.ID != k.ID || string(got.SecretHash) != "hash" || got.ExpiresAt == nil || !got.ExpiresAt.Equal(exp) {
+		t.Fatalf("get by prefix: %v %+v", err, got)
+	}
+	if _, err := store.Get(ctx, uuid.New(), k.ID); !errors.Is(err, apperr.ErrAPIKeyNotFound) {
+		t.Fatalf("expected other user's lookup to miss, got %v", err)
+	}
+
+	if err := store.Update(ctx, u.ID, k.ID, "deploy", nil); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := store.Touch(ctx, k.ID, time.Now()); err != nil {
+		t.Fatalf("touch: %v", err)
+	}
+	list, err := store.ListByUser(ctx, u.ID)
+	if err != nil || len(list) != 1 || list[0].Name != "deploy" || len(list[0].Scopes) != 0 || list[0].LastUsedAt == nil {
+		t.Fatalf("list: %v %+v", err, list)
+	}
+
+	if err := store.Delete(ctx, u.ID, k.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := store.Delete(ctx, u.ID, k.ID); !errors.Is(err, apperr.ErrAPIKeyNotFound) {
+		t.Fatalf("expected second delete to miss, got %v", err)
+	}
+}
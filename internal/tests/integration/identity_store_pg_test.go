@@ -0,0 +1,41 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domuser "gostartkit/internal/domain/user"
+	pgstore "gostartkit/internal/infras/storage/postgres"
+
+	"github.com/google/uuid"
+)
+
+func TestPostgres_IdentityStore_LinkAndFind(t *testing.T) {
+	ctx := context.Background()
+	pool := openMigratedPool(t)
+	u := saveTestUser(t, pgstore.NewUserRepository(pool))
+	store := pgstore.NewIdentityStore(pool)
+	subject := uuid.NewString()
+
+	if _, err := store.FindUserID(ctx, "stub", subject); !errors.Is(err, domuser.ErrUserNotFound) {
+		t.Fatalf("expected not found before link, got %v", err)
+	}
+	if err := store.Link(ctx, "stub", subject, u.ID, u.Email.String()); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	// Linking again is a no-op
+	if err := store.Link(ctx, "stub", subject, u.ID, u.Email.String()); err != nil {
+		t.Fatalf("relink: %v", err)
+	}
+	got, err := store.FindUserID(ctx, "stub", subject)
+	if err != nil || got != u.ID {
+		t.Fatalf("find: %v got=%s", err, got)
+	}
+	list, err := store.ListByUser(ctx, u.ID)
+	if err != nil || len(list) != 1 || list[0].Provider != "stub" || list[0].Subject != subject || list[0].Email != u.Email.String() {
+		t.Fatalf("list: %v got=%+v", err, list)
+	}
+}
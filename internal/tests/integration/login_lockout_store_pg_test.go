@@ -0,0 +1,49 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pgstore "gostartkit/internal/infras/storage/postgres"
+)
+
+func TestPostgres_LoginLockoutStore_CountsLocksAndResets(t *testing.T) {
+	ctx := context.Background()
+	pool := openMigratedPool(t)
+	u := saveTestUser(t, pgstore.NewUserRepository(pool))
+	store := pgstore.NewLoginLockoutStore(pool)
+
+	if state, err := store.Get(ctx, u.ID); err != nil || state.FailedAttempts != 0 || !state.LockedUntil.IsZero() {
+		t.Fatalf("expected empty state, got %+v (%v)", state, err)
+	}
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	for i := 1; i <= 3; i++ {
+		state, err := store.RecordFailure(ctx, u.ID, now, now.Add(-time.Hour))
+		if err != nil || state.FailedAttempts != i {
+			t.Fatalf("failure %d: got %+v (%v)", i, state, err)
+		}
+	}
+	until := now.Add(15 * time.Minute)
+	if err := store.Lock(ctx, u.ID, until); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	if state, _ := store.Get(ctx, u.ID); !state.LockedUntil.Equal(until) {
+		t.Fatalf("expected locked until %s, got %+v", until, state)
+	}
+
+	// Failures outside the window restart the count, unless a lock reaches into it
+	later := now.Add(2 * time.Hour)
+	if state, _ := store.RecordFailure(ctx, u.ID, later, later.Add(-time.Hour)); state.FailedAttempts != 1 {
+		t.Fatalf("expected count restarted, got %+v", state)
+	}
+
+	if err := store.Reset(ctx, u.ID); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if state, _ := store.Get(ctx, u.ID); state.FailedAttempts != 0 || !state.LockedUntil.IsZero() {
+		t.Fatalf("expected state cleared, got %+v", state)
+	}
+}
@@ -0,0 +1,62 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gostartkit/internal/application/apperr"
+	pgstore "gostartkit/internal/infras/storage/postgres"
+)
+
+func TestPostgres_MFAStore_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	pool := openMigratedPool(t)
+	u := saveTestUser(t, pgstore.NewUserRepository(pool))
+	store := pgstore.NewMFAStore(pool)
+
+	if _, err := store.Get(ctx, u.ID); !errors.Is(err, apperr.ErrMFANotEnrolled) {
+		t.Fatalf("expected not enrolled, got %v", err)
+	}
+	if err := store.SavePending(ctx, u.ID, []byte("sealed")); err != nil {
+		t.Fatalf("save pending: %v", err)
+	}
+	if err := store.Confirm(ctx, u.ID, 10, [][]byte{[]byte("code-a"), []byte("code-b")}); err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	if err := store.Confirm(ctx, u.ID, 11, nil); !errors.Is(err, apperr.ErrMFAAlreadyEnabled) {
+		t.Fatalf("expected second confirm to fail, got %v", err)
+	}
+	e, err := store.Get(ctx, u.ID)
+	if err != nil || !e.Confirmed || e.LastUsedStep != 10 || string(e.SecretCiphertext) != "sealed" {
+		t.Fatalf("get: %v %+v", err, e)
+	}
+
+	// Steps only move forward
+	if ok, err := store.UseStep(ctx, u.ID, 10); err != nil || ok {
+		t.Fatalf("expected step 10 to be rejected: ok=%v err=%v", ok, err)
+	}
+	if ok, err := store.UseStep(ctx, u.ID, 11); err != nil || !ok {
+		t.Fatalf("expected step 11 to be accepted: ok=%v err=%v", ok, err)
+	}
+
+	// Recovery codes are single use
+	if ok, err := store.UseRecoveryCode(ctx, u.ID, []byte("code-a")); err != nil || !ok {
+		t.Fatalf("first use: ok=%v err=%v", ok, err)
+	}
+	if ok, err := store.UseRecoveryCode(ctx, u.ID, []byte("code-a")); err != nil || ok {
+		t.Fatalf("reuse: ok=%v err=%v", ok, err)
+	}
+
+	if err := store.Delete(ctx, u.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Get(ctx, u.ID); !errors.Is(err, apperr.ErrMFANotEnrolled) {
+		t.Fatalf("expected not enrolled after delete, got %v", err)
+	}
+	if ok, err := store.UseRecoveryCode(ctx, u.ID, []byte("code-b")); err != nil || ok {
+		t.Fatalf("expected recovery codes to be removed: ok=%v err=%v", ok, err)
+	}
+}
@@ -0,0 +1,57 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+	pgstore "gostartkit/internal/infras/storage/postgres"
+
+	"github.com/google/uuid"
+)
+
+func TestPostgres_OAuthClientStore_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	store := pgstore.NewOAuthClientStore(openMigratedPool(t))
+
+	c := ports.OAuthClient{
+		ClientID:   "svc_" + uuid.NewString(),
+		Name:       "billing",
+		SecretHash: []byte("hash"),
+		Scopes:     []string{"users:read"},
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := store.Create(ctx, c); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	got, err := store.Get(ctx, c.ClientID)
+	if err != nil || got.Name != "billing" || string(got.SecretHash) != "hash" || len(got.Scopes) != 1 {
+		t.Fatalf("get: %v %+v", err, got)
+	}
+	list, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	found := false
+	for _, l := range list {
+		found = found || l.ClientID == c.ClientID
+	}
+	if !found {
+		t.Fatalf("expected client in list")
+	}
+
+	if err := store.Delete(ctx, c.ClientID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Get(ctx, c.ClientID); !errors.Is(err, apperr.ErrOAuthClientNotFound) {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+	if err := store.Delete(ctx, c.ClientID); !errors.Is(err, apperr.ErrOAuthClientNotFound) {
+		t.Fatalf("expected second delete to miss, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/application/ports/portstest"
+	"gostartkit/internal/infras/storage/s3"
+)
+
+// TestS3ObjectStorage_Conformance runs against MinIO from docker-compose.test.yml (or any S3 endpoint set
+// through S3_ENDPOINT). The 5 MiB part size sends the suite's large object as a multipart upload.
+func TestS3ObjectStorage_Conformance(t *testing.T) {
+	store, err := s3.New(s3.Config{
+		Endpoint:  getenvOr("S3_ENDPOINT", "http://localhost:59000"),
+		Region:    getenvOr("S3_REGION", ""),
+		Bucket:    getenvOr("S3_BUCKET", "gostartkit-test"),
+		AccessKey: getenvOr("S3_ACCESS_KEY", "minioadmin"),
+		SecretKey: getenvOr("S3_SECRET_KEY", "minioadmin"),
+		PathStyle: true,
+		PartSize:  5 << 20,
+	})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := store.EnsureBucket(ctx); err != nil {
+		t.Fatalf("ensure bucket: %v", err)
+	}
+	portstest.TestObjectStorage(t, func(t *testing.T) ports.ObjectStorage { return store })
+}
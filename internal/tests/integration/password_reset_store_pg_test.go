@@ -0,0 +1,50 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/apperr"
+	pgstore "gostartkit/internal/infras/storage/postgres"
+)
+
+func TestPostgres_PasswordResetStore_SingleUseAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	pool := openMigratedPool(t)
+	u := saveTestUser(t, pgstore.NewUserRepository(pool))
+	store := pgstore.NewPasswordResetStore(pool)
+	now := time.Now()
+	// token_hash is the primary key; make hashes unique per run
+	hash := func(name string) []byte { return []byte(u.ID.String() + name) }
+
+	if err := store.Save(ctx, hash("hash-live"), u.ID, now.Add(time.Hour)); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := store.Save(ctx, hash("hash-expired"), u.ID, now.Add(-time.Minute)); err != nil {
+		t.Fatalf("save expired: %v", err)
+	}
+	if _, err := store.Consume(ctx, hash("hash-expired"), now); !errors.Is(err, apperr.ErrResetTokenInvalid) {
+		t.Fatalf("expected expired token to be rejected, got %v", err)
+	}
+	id, err := store.Consume(ctx, hash("hash-live"), now)
+	if err != nil || id != u.ID {
+		t.Fatalf("consume: %v id=%s", err, id)
+	}
+	if _, err := store.Consume(ctx, hash("hash-live"), now); !errors.Is(err, apperr.ErrResetTokenInvalid) {
+		t.Fatalf("expected second use to be rejected, got %v", err)
+	}
+
+	if err := store.Save(ctx, hash("hash-other"), u.ID, now.Add(time.Hour)); err != nil {
+		t.Fatalf("save other: %v", err)
+	}
+	if err := store.DeleteByUser(ctx, u.ID); err != nil {
+		t.Fatalf("delete by user: %v", err)
+	}
+	if _, err := store.Consume(ctx, hash("hash-other"), now); !errors.Is(err, apperr.ErrResetTokenInvalid) {
+		t.Fatalf("expected deleted token to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	auth "gostartkit/internal/infras/auth"
+
+	"github.com/google/uuid"
+)
+
+func TestRedisAccessRevocationStore_UserCutoff(t *testing.T) {
+	ctx := context.Background()
+	store := auth.NewRedisAccessRevocationStore(getenvOr("REDIS_ADDR", "localhost:6379"), getenvOr("REDIS_PASSWORD", ""), 0)
+	userID := uuid.NewString()
+	now := time.Now()
+
+	if err := store.RevokeUser(ctx, userID, now, time.Minute); err != nil {
+		t.Fatalf("revoke user: %v", err)
+	}
+	if ok, err := store.IsRevoked(ctx, "", userID, now.Add(-time.Second)); err != nil || !ok {
+		t.Fatalf("expected an earlier token revoked, got %v (%v)", ok, err)
+	}
+	// iat has whole seconds: a sign-in in the same second as the revocation must keep working
+	if ok, err := store.IsRevoked(ctx, "", userID, now.Truncate(time.Second)); err != nil || ok {
+		t.Fatalf("expected a token from the revocation's second to stay valid, got %v (%v)", ok, err)
+	}
+}
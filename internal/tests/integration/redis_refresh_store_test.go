@@ -4,11 +4,17 @@ package integration
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 	"time"
 
+	"gostartkit/internal/application/apperr"
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/application/ports/portstest"
 	auth "gostartkit/internal/infras/auth"
+
+	"github.com/google/uuid"
 )
 
 func TestRedisRefreshStore_IssueValidateRevoke(t *testing.T) {
@@ -18,7 +24,7 @@ func TestRedisRefreshStore_IssueValidateRevoke(t *testing.T) {
 	db := 0
 
 	store := auth.NewRedisRefreshStore(addr, pass, db)
-	tok, err := store.Issue(ctx, "user-1", 60)
+	tok, err := store.Issue(ctx, "user-1", 60, ports.SessionMeta{})
 	if err != nil {
 		t.Fatalf("issue: %v", err)
 	}
@@ -58,6 +64,34 @@ func TestRedisRefreshStore_InvalidTokenBehaviors(t *testing.T) {
 	}
 }
 
+func TestRedisRefreshStore_RotationReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	store := auth.NewRedisRefreshStore(getenvOr("REDIS_ADDR", "localhost:6379"), getenvOr("REDIS_PASSWORD", ""), 0)
+
+	t1, err := store.Issue(ctx, "user-fam", 60, ports.SessionMeta{})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	t2, uid, err := store.Rotate(ctx, t1, 60, ports.SessionMeta{})
+	if err != nil || uid != "user-fam" {
+		t.Fatalf("rotate: %v uid=%s", err, uid)
+	}
+	// Replaying the rotated token is reuse: the whole family (t2) is revoked
+	if _, _, err := store.Rotate(ctx, t1, 60, ports.SessionMeta{}); !errors.Is(err, apperr.ErrRefreshTokenReused) {
+		t.Fatalf("expected reuse error, got %v", err)
+	}
+	if _, err := store.Validate(ctx, t2); err == nil {
+		t.Fatalf("expected family member to be revoked after reuse")
+	}
+}
+
+func TestRedisRefreshStore_Conformance(t *testing.T) {
+	store := auth.NewRedisRefreshStore(getenvOr("REDIS_ADDR", "localhost:6379"), getenvOr("REDIS_PASSWORD", ""), 0)
+	portstest.TestRefreshTokenStore(t, func(t *testing.T) (ports.RefreshTokenStore, func() string) {
+		return store, uuid.NewString
+	})
+}
+
 func getenvOr(k, def string) string {
 	if v := os.Getenv(k); v != "" {
 		return v
@@ -0,0 +1,82 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"gostartkit/internal/application/ports"
+	"gostartkit/internal/application/ports/portstest"
+	domuser "gostartkit/internal/domain/user"
+	infdb "gostartkit/internal/infras/db"
+	pgstore "gostartkit/internal/infras/storage/postgres"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestPostgres_RefreshTokenStore_PurgeExpired(t *testing.T) {
+	pool := openMigratedPool(t)
+	ctx := context.Background()
+	store := pgstore.NewRefreshTokenStore(pool)
+	// refresh_tokens references users, so create a real owner
+	uid := saveTestUser(t, pgstore.NewUserRepository(pool)).ID.String()
+
+	if _, err := store.Issue(ctx, uid, 1, ports.SessionMeta{}); err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	time.Sleep(1100 * time.Millisecond)
+	n, err := store.PurgeExpired(ctx)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if n < 2 { // the token row and its session row
+		t.Fatalf("purged rows = %d, want >= 2", n)
+	}
+}
+
+func TestPostgres_RefreshTokenStore_Conformance(t *testing.T) {
+	pool := openMigratedPool(t)
+	repo := pgstore.NewUserRepository(pool)
+	store := pgstore.NewRefreshTokenStore(pool)
+	portstest.TestRefreshTokenStore(t, func(t *testing.T) (ports.RefreshTokenStore, func() string) {
+		return store, func() string { return saveTestUser(t, repo).ID.String() }
+	})
+}
+
+// openMigratedPool runs migrations and opens a pool closed at test end.
+func openMigratedPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	url := infdb.BuildPostgresURL(
+		getenvOr("DB_HOST", "localhost"), getenvOr("DB_PORT", "5432"),
+		getenvOr("DB_USER", "gostartkit"), getenvOr("DB_PASSWORD", "devpassword"),
+		getenvOr("DB_NAME", "gostartkit"), getenvOr("DB_SSLMODE", "disable"),
+	)
+	_, filename, _, _ := runtime.Caller(0)
+	infdb.RunMigrations(url, filepath.Join(filepath.Dir(filename), "../../..", "migrations"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	pool, err := pgstore.NewPGXPool(ctx, url, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+// saveTestUser inserts a throwaway user (deleted at test end) to satisfy foreign keys.
+func saveTestUser(t *testing.T, repo *pgstore.UserRepository) *domuser.User {
+	t.Helper()
+	email, _ := domuser.NewEmail("refresh-" + uuid.NewString() + "@example.com")
+	u := domuser.NewUser("Refresh", "Test", email, "hashed:pass", domuser.RoleUser)
+	if err := repo.Save(context.Background(), u); err != nil {
+		t.Fatalf("save user: %v", err)
+	}
+	t.Cleanup(func() { _ = repo.Delete(context.Background(), u.ID) })
+	return u
+}
@@ -4,8 +4,11 @@ package integration
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +16,7 @@ import (
 	infdb "gostartkit/internal/infras/db"
 	pgstore "gostartkit/internal/infras/storage/postgres"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -38,7 +42,7 @@ func TestPostgres_UserRepository_CRUD(t *testing.T) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	pool, err := pgstore.NewPGXPool(ctx, url)
+	pool, err := pgstore.NewPGXPool(ctx, url, 0, 0, 0)
 	if err != nil {
 		t.Fatalf("open db: %v", err)
 	}
@@ -63,9 +67,14 @@ func TestPostgres_UserRepository_CRUD(t *testing.T) {
 		t.Fatalf("email mismatch: %v != %v", got.Email, email)
 	}
 
+	if got.IsEmailVerified() {
+		t.Fatalf("new user must not be verified")
+	}
+
 	// Update
 	got.FirstName = "It2"
 	got.UpdatedAt = time.Now()
+	got.MarkEmailVerified(time.Now())
 	if err := repo.Update(ctx, got); err != nil {
 		t.Fatalf("update: %v", err)
 	}
@@ -78,10 +87,180 @@ func TestPostgres_UserRepository_CRUD(t *testing.T) {
 	if byEmail.FirstName != "It2" {
 		t.Fatalf("expected updated first name, got %s", byEmail.FirstName)
 	}
+	if !byEmail.IsEmailVerified() {
+		t.Fatalf("expected email_verified_at to be persisted")
+	}
+
+	// A rehash keeps password_changed_at; a new password moves it
+	changedAt := byEmail.PasswordChangedAt
+	if err := repo.UpdatePasswordHash(ctx, u.ID, "v2:pass", time.Now()); err != nil {
+		t.Fatalf("update password hash: %v", err)
+	}
+	if rehashed, _ := repo.GetByID(ctx, u.ID); rehashed.Password != "v2:pass" || !rehashed.PasswordChangedAt.Equal(changedAt) {
+		t.Fatalf("expected only the hash to change, got %q at %v (was %v)", rehashed.Password, rehashed.PasswordChangedAt, changedAt)
+	}
+	byEmail.SetPassword("v2:new", time.Now().Add(time.Second))
+	if err := repo.Update(ctx, byEmail); err != nil {
+		t.Fatalf("update password: %v", err)
+	}
+	if changed, _ := repo.GetByID(ctx, u.ID); !changed.PasswordChangedAt.After(changedAt) {
+		t.Fatalf("expected password_changed_at to move, got %v (was %v)", changed.PasswordChangedAt, changedAt)
+	}
 
 	// List
-	all, err := repo.GetAll(ctx)
+	all, err := repo.List(ctx, domuser.ListFilter{Limit: 10})
 	if err != nil || len(all) == 0 {
-		t.Fatalf("get all: %v len=%d", err, len(all))
+		t.Fatalf("list: %v len=%d", err, len(all))
+	}
+}
+
+func TestPostgres_UserRepository_Phone(t *testing.T) {
+	t.Parallel()
+	pool := openMigratedPool(t)
+	repo := pgstore.NewUserRepository(pool)
+	ctx := context.Background()
+	a, b := saveTestUser(t, repo), saveTestUser(t, repo)
+
+	// Unique per run: the database outlives the test
+	phone := domuser.Phone(fmt.Sprintf("+1%010d", time.Now().UnixNano()%1e10))
+	if _, err := repo.GetByPhone(ctx, phone); !errors.Is(err, domuser.ErrUserNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	a.SetVerifiedPhone(phone, time.Now())
+	if err := repo.Update(ctx, a); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	got, err := repo.GetByPhone(ctx, phone)
+	if err != nil || got.ID != a.ID || got.PhoneVerifiedAt == nil {
+		t.Fatalf("get by phone: %v %+v", err, got)
+	}
+
+	b.SetVerifiedPhone(phone, time.Now())
+	if err := repo.Update(ctx, b); !errors.Is(err, domuser.ErrPhoneAlreadyExists) {
+		t.Fatalf("expected phone conflict, got %v", err)
+	}
+
+	a.RemovePhone(time.Now())
+	if err := repo.Update(ctx, a); err != nil {
+		t.Fatalf("remove phone: %v", err)
+	}
+	if got, err := repo.GetByID(ctx, a.ID); err != nil || got.HasPhone() || got.PhoneVerifiedAt != nil {
+		t.Fatalf("expected phone to be cleared: %v %+v", err, got)
+	}
+}
+
+func TestPostgres_UserRepository_ListAndDisable(t *testing.T) {
+	t.Parallel()
+	pool := openMigratedPool(t)
+	repo := pgstore.NewUserRepository(pool)
+	ctx := context.Background()
+
+	// A unique prefix keeps rows from other tests out of the listing
+	prefix := "list-" + uuid.NewString()[:8]
+	var ids []uuid.UUID
+	for i := 0; i < 3; i++ {
+		email, _ := domuser.NewEmail(fmt.Sprintf("%s-%d@example.com", prefix, i))
+		u := domuser.NewUser("List", "Test", email, "hashed:pass", domuser.RoleUser)
+		if err := repo.Save(ctx, u); err != nil {
+			t.Fatalf("save user: %v", err)
+		}
+		t.Cleanup(func() { _ = repo.Delete(context.Background(), u.ID) })
+		ids = append(ids, u.ID)
+	}
+
+	first, err := repo.List(ctx, domuser.ListFilter{EmailPrefix: strings.ToUpper(prefix), Limit: 2})
+	if err != nil || len(first) != 2 {
+		t.Fatalf("first page: %v len=%d", err, len(first))
+	}
+	last := first[1]
+	rest, err := repo.List(ctx, domuser.ListFilter{EmailPrefix: prefix, Limit: 2, After: &domuser.ListPosition{CreatedAt: last.CreatedAt, ID: last.ID}})
+	if err != nil || len(rest) != 1 {
+		t.Fatalf("second page: %v len=%d", err, len(rest))
+	}
+	seen := map[uuid.UUID]bool{first[0].ID: true, first[1].ID: true, rest[0].ID: true}
+	for _, id := range ids {
+		if !seen[id] {
+			t.Fatalf("user %s missing from the pages", id)
+		}
+	}
+	if admins, err := repo.List(ctx, domuser.ListFilter{EmailPrefix: prefix, Role: domuser.RoleAdmin, Limit: 10}); err != nil || len(admins) != 0 {
+		t.Fatalf("expected no admins: %v len=%d", err, len(admins))
+	}
+	// "_" must match literally, not as a LIKE wildcard
+	if none, err := repo.List(ctx, domuser.ListFilter{EmailPrefix: prefix[:4] + "_", Limit: 10}); err != nil || len(none) != 0 {
+		t.Fatalf("expected the wildcard to be escaped: %v len=%d", err, len(none))
+	}
+
+	u := rest[0]
+	u.Disable(time.Now())
+	if err := repo.Update(ctx, u); err != nil {
+		t.Fatalf("disable: %v", err)
+	}
+	if got, err := repo.GetByID(ctx, u.ID); err != nil || !got.IsDisabled() {
+		t.Fatalf("expected disabled_at persisted: %v %+v", err, got)
+	}
+}
+
+func TestPostgres_UserRepository_SoftDeleteAndErase(t *testing.T) {
+	t.Parallel()
+	pool := openMigratedPool(t)
+	repo := pgstore.NewUserRepository(pool)
+	ctx := context.Background()
+	u := saveTestUser(t, repo)
+	phone := domuser.Phone(fmt.Sprintf("+1%010d", time.Now().UnixNano()%1e10))
+	u.SetVerifiedPhone(phone, time.Now())
+	if err := repo.Update(ctx, u); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	if err := repo.Delete(ctx, u.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := repo.Delete(ctx, u.ID); !errors.Is(err, domuser.ErrUserNotFound) {
+		t.Fatalf("expected a second delete to find nothing, got %v", err)
+	}
+	if _, err := repo.GetByID(ctx, u.ID); !errors.Is(err, domuser.ErrUserNotFound) {
+		t.Fatalf("expected deleted user hidden from GetByID, got %v", err)
+	}
+	if _, err := repo.GetByEmail(ctx, u.Email); !errors.Is(err, domuser.ErrUserNotFound) {
+		t.Fatalf("expected deleted user hidden from GetByEmail, got %v", err)
+	}
+	if err := repo.Update(ctx, u); !errors.Is(err, domuser.ErrUserNotFound) {
+		t.Fatalf("expected updates of a deleted user to be refused, got %v", err)
+	}
+	prefix := strings.SplitN(u.Email.String(), "@", 2)[0]
+	if rows, err := repo.List(ctx, domuser.ListFilter{EmailPrefix: prefix, Limit: 10}); err != nil || len(rows) != 0 {
+		t.Fatalf("expected deleted user left out of List: %v len=%d", err, len(rows))
+	}
+	if rows, err := repo.List(ctx, domuser.ListFilter{EmailPrefix: prefix, Limit: 10, IncludeDeleted: true}); err != nil || len(rows) != 1 || !rows[0].IsDeleted() {
+		t.Fatalf("expected deleted user with IncludeDeleted: %v len=%d", err, len(rows))
+	}
+
+	// The email and phone are free again for a new account
+	email := u.Email
+	again := domuser.NewUser("Again", "Test", email, "hashed:pass", domuser.RoleUser)
+	if err := repo.Save(ctx, again); err != nil {
+		t.Fatalf("re-register with the email of a deleted user: %v", err)
+	}
+	t.Cleanup(func() { _ = repo.Delete(context.Background(), again.ID) })
+	again.SetVerifiedPhone(phone, time.Now())
+	if err := repo.Update(ctx, again); err != nil {
+		t.Fatalf("reuse the phone of a deleted user: %v", err)
+	}
+
+	deleted, err := repo.GetByIDIncludingDeleted(ctx, u.ID)
+	if err != nil || !deleted.IsDeleted() || deleted.Email != email {
+		t.Fatalf("expected the deleted row to keep its data until erased: %v %+v", err, deleted)
+	}
+	deleted.Erase(time.Now())
+	if err := repo.Erase(ctx, deleted); err != nil {
+		t.Fatalf("erase: %v", err)
+	}
+	erased, err := repo.GetByIDIncludingDeleted(ctx, u.ID)
+	if err != nil || !erased.IsErased() || erased.Email != domuser.TombstoneEmail(u.ID) || erased.FirstName != "" || erased.HasPhone() || erased.Password != "" {
+		t.Fatalf("expected anonymized row: %v %+v", err, erased)
+	}
+	if !erased.DeletedAt.Equal(*deleted.DeletedAt) {
+		t.Fatalf("expected erase to keep the original deletion time")
 	}
 }
@@ -0,0 +1,5 @@
+-- Drop Postgres-backed refresh tokens
+
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS refresh_sessions;
+
@@ -0,0 +1,3 @@
+-- Drop linked external identities
+
+DROP TABLE IF EXISTS user_identities;
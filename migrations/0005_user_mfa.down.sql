@@ -0,0 +1,4 @@
+-- Drop TOTP multi-factor authentication
+
+DROP TABLE IF EXISTS user_mfa_recovery_codes;
+DROP TABLE IF EXISTS user_mfa;
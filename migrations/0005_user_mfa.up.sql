@@ -0,0 +1,19 @@
+-- TOTP multi-factor authentication. Secrets are stored encrypted (AES-GCM, MFA_ENCRYPTION_KEY);
+-- recovery codes only as SHA-256 hashes.
+
+CREATE TABLE IF NOT EXISTS user_mfa (
+  user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  secret_ciphertext BYTEA NOT NULL,
+  confirmed_at TIMESTAMPTZ,
+  last_used_step BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS user_mfa_recovery_codes (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  code_hash BYTEA NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  used_at TIMESTAMPTZ,
+  PRIMARY KEY (user_id, code_hash)
+);
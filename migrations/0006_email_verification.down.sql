@@ -0,0 +1,3 @@
+-- Drop email verification
+
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
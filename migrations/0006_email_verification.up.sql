@@ -0,0 +1,5 @@
+-- Email verification. Accounts created before this migration are treated as verified.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
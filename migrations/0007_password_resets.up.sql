@@ -0,0 +1,11 @@
+-- Password reset tokens. Only SHA-256 hashes of the emailed tokens are stored.
+
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+  token_hash BYTEA PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user ON password_reset_tokens(user_id);
@@ -0,0 +1,5 @@
+-- Drop phone numbers
+
+DROP INDEX IF EXISTS users_phone_key;
+ALTER TABLE users DROP COLUMN IF EXISTS phone_verified_at;
+ALTER TABLE users DROP COLUMN IF EXISTS phone;
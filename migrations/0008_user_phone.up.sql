@@ -0,0 +1,6 @@
+-- Phone numbers (E.164). Only numbers confirmed by SMS are stored, so the unique index cannot be squatted.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_verified_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_phone_key ON users(phone) WHERE phone IS NOT NULL;
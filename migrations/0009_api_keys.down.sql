@@ -0,0 +1,3 @@
+-- Drop API keys
+
+DROP TABLE IF EXISTS api_keys;
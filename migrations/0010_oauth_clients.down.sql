@@ -0,0 +1,3 @@
+-- Drop OAuth2 clients
+
+DROP TABLE IF EXISTS oauth_clients;
@@ -0,0 +1,10 @@
+-- Registered OAuth2 clients (backend services) for the client_credentials grant.
+-- Only SHA-256 hashes of the secrets are stored; scopes are the most a client's tokens may carry.
+
+CREATE TABLE IF NOT EXISTS oauth_clients (
+  client_id TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  secret_hash BYTEA NOT NULL,
+  scopes TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
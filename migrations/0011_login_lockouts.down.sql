@@ -0,0 +1,3 @@
+-- Drop login lockout tracking
+
+DROP TABLE IF EXISTS login_lockouts;
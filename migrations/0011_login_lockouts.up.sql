@@ -0,0 +1,9 @@
+-- Failed password logins per account, for progressive backoff and temporary lockout.
+-- A row exists only while an account has recent failures; a successful login or an admin unlock deletes it.
+
+CREATE TABLE IF NOT EXISTS login_lockouts (
+  user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  failed_attempts INT NOT NULL DEFAULT 0,
+  last_failed_at TIMESTAMPTZ NOT NULL,
+  locked_until TIMESTAMPTZ
+);
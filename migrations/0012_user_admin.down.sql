@@ -0,0 +1,5 @@
+-- Revert admin user management columns and indexes
+
+DROP INDEX IF EXISTS idx_users_email_lower_prefix;
+DROP INDEX IF EXISTS idx_users_created_at_id;
+ALTER TABLE users DROP COLUMN IF EXISTS disabled_at;
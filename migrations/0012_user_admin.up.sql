@@ -0,0 +1,7 @@
+-- Admin user management: accounts can be disabled, and listings page by (created_at, id) with optional
+-- case-insensitive email prefix filters.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_created_at_id ON users(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_users_email_lower_prefix ON users(lower(email) text_pattern_ops);
@@ -0,0 +1,12 @@
+-- Revert soft delete. Deleted rows are removed first: they may share an email or phone with live users.
+
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS users_phone_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_phone_key ON users(phone) WHERE phone IS NOT NULL;
+
+DROP INDEX IF EXISTS users_email_key;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+
+ALTER TABLE users DROP COLUMN IF EXISTS erased_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
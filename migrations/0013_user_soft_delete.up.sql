@@ -0,0 +1,12 @@
+-- Soft delete and GDPR erasure: deleted users keep their row (and ID) for references and the audit trail.
+-- erased_at marks rows whose personal data has been anonymized.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at TIMESTAMPTZ;
+
+-- Deleted accounts release their email and phone number for new sign-ups
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users(email) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS users_phone_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_phone_key ON users(phone) WHERE phone IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Revert password_changed_at
+
+ALTER TABLE users DROP COLUMN IF EXISTS password_changed_at;
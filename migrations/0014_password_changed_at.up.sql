@@ -0,0 +1,4 @@
+-- password_changed_at moves when a user sets a new password (change or reset) but not when a login only
+-- rehashes the same password; pending email-change links are bound to it. Existing rows start at the migration time.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMPTZ NOT NULL DEFAULT now();
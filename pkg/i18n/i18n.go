@@ -16,7 +16,9 @@ var catalogs = map[string]map[string]string{
 		"field_required":          "field %s is required",
 		"min_len":                 "field %s must be at least %s characters",
 		"email":                   "field %s must be a valid email",
+		"phone":                   "field %s must be a phone number in E.164 format, e.g. +14155552671",
 		"strong_password":         "field %s must be a stronger password (>=12, upper/lower/digit/special, no spaces)",
+		"breached_password":       "field %s appears in known data breaches; choose a different password",
 		"malformed_json_at":       "malformed JSON at position %s",
 		"invalid_type_for_field":  "invalid type for field %s",
 		"invalid_value_for_field": "invalid value for field %s",
@@ -26,7 +28,9 @@ var catalogs = map[string]map[string]string{
 		"field_required":          "trường %s là bắt buộc",
 		"min_len":                 "trường %s phải có ít nhất %s ký tự",
 		"email":                   "trường %s phải là email hợp lệ",
+		"phone":                   "trường %s phải là số điện thoại dạng E.164, ví dụ +84912345678",
 		"strong_password":         "trường %s cần mật khẩu mạnh (>=12, có chữ hoa/thường/số/ký tự đặc biệt, không khoảng trắng)",
+		"breached_password":       "trường %s chứa mật khẩu đã bị lộ trong các vụ rò rỉ dữ liệu; hãy chọn mật khẩu khác",
 		"malformed_json_at":       "JSON không hợp lệ tại vị trí %s",
 		"invalid_type_for_field":  "sai kiểu dữ liệu cho trường %s",
 		"invalid_value_for_field": "giá trị không hợp lệ cho trường %s",
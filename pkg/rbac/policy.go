@@ -95,6 +95,17 @@ func wildcardMatch(pattern, permission string) bool {
     return false
 }
 
+// AnyMatches reports whether any pattern grants the permission, exactly or by wildcard.
+// It applies the policy's matching rules to ad-hoc grants such as API key scopes.
+func AnyMatches(patterns []string, permission string) bool {
+    for _, pattern := range patterns {
+        if pattern == permission || wildcardMatch(pattern, permission) {
+            return true
+        }
+    }
+    return false
+}
+
 // Global default policy instance
 var defaultPolicy = NewPolicy(DefaultRules())
 
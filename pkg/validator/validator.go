@@ -23,6 +23,21 @@ func IsValidEmail(input string) bool {
 	return addr.Address == s
 }
 
+// IsE164Phone reports whether input is an E.164 phone number: "+", a non-zero country code digit,
+// at most 15 digits in total and no separators. Surrounding spaces are trimmed.
+func IsE164Phone(input string) bool {
+	s := strings.TrimSpace(input)
+	if len(s) < 3 || len(s) > 16 || s[0] != '+' || s[1] == '0' {
+		return false
+	}
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // IsStrongPassword checks a password against baseline rules:
 // - length >= 12
 // - contains at least one lowercase, one uppercase, one digit, one special
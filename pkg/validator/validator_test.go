@@ -34,6 +34,21 @@ func TestIsValidEmail_Invalid(t *testing.T) {
 	}
 }
 
+func TestIsE164Phone(t *testing.T) {
+	valid := []string{"+14155552671", "+442071838750", " +84912345678 ", "+123456789012345"}
+	for _, in := range valid {
+		if !IsE164Phone(in) {
+			t.Fatalf("expected valid phone: %q", in)
+		}
+	}
+	invalid := []string{"", "+", "14155552671", "+0123456789", "+1 415 555 2671", "+1-415-555-2671", "+1234567890123456", "+1415555267a"}
+	for _, in := range invalid {
+		if IsE164Phone(in) {
+			t.Fatalf("expected invalid phone: %q", in)
+		}
+	}
+}
+
 func TestIsStrongPassword(t *testing.T) {
 	valid := []string{
 		"Aa1!aaaaaaaa",
@@ -9,6 +9,7 @@ DB_USER_DEFAULT="gostartkit"
 DB_PASSWORD_DEFAULT="devpassword"
 DB_NAME_DEFAULT="gostartkit"
 REDIS_ADDR_DEFAULT="localhost:56379"
+S3_ENDPOINT_DEFAULT="http://localhost:59000"
 
 KEEP_CONTAINERS=false
 
@@ -48,8 +49,9 @@ export DB_USER="${DB_USER:-$DB_USER_DEFAULT}"
 export DB_PASSWORD="${DB_PASSWORD:-$DB_PASSWORD_DEFAULT}"
 export DB_NAME="${DB_NAME:-$DB_NAME_DEFAULT}"
 export REDIS_ADDR="${REDIS_ADDR:-$REDIS_ADDR_DEFAULT}"
+export S3_ENDPOINT="${S3_ENDPOINT:-$S3_ENDPOINT_DEFAULT}"
 
-echo "[it] Running integration tests with env: DB=$DB_HOST:$DB_PORT, REDIS=$REDIS_ADDR"
+echo "[it] Running integration tests with env: DB=$DB_HOST:$DB_PORT, REDIS=$REDIS_ADDR, S3=$S3_ENDPOINT"
 go test -tags=integration ./internal/tests/integration "$@"
 
 EXIT_CODE=$?